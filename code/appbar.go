@@ -5,6 +5,7 @@
 package code
 
 import (
+	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/events"
 	"cogentcore.org/core/filetree"
@@ -87,6 +88,12 @@ func (cv *Code) MakeToolbar(p *tree.Plan) {
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(cv.Spell).SetIcon(icons.Spellcheck)
 	})
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(cv.ShowProblems).SetText("Problems").SetIcon(icons.Error)
+	})
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(cv.ShowTests).SetText("Tests").SetIcon(icons.Checklist)
+	})
 
 	tree.Add(p, func(w *core.Separator) {})
 
@@ -101,6 +108,7 @@ func (cv *Code) MakeToolbar(p *tree.Plan) {
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(cv.Debug).SetIcon(icons.Debug)
 	})
+	tree.AddAt(p, "launch-chooser", cv.launchChooser)
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(cv.DebugTest).SetIcon(icons.Debug)
 		w.Args[0].SetValue(cv.Settings.Debug.TestName)
@@ -211,6 +219,22 @@ func (cv *Code) OverflowMenu(m *core.Scene) {
 
 		core.NewSeparator(m)
 
+		core.NewFuncButton(m).SetFunc(cv.InsertSnippet).SetIcon(icons.CodeBlocks).
+			SetShortcut(KeyInsertSnippet.Chord())
+		core.NewFuncButton(m).SetFunc(cv.EditSnippets).SetIcon(icons.Edit)
+
+		core.NewSeparator(m)
+
+		core.NewFuncButton(m).SetFunc(cv.RecordMacro).SetIcon(icons.RadioButtonChecked).
+			SetShortcut(KeyMacroRecord.Chord())
+		core.NewFuncButton(m).SetFunc(cv.ReplayMacro).SetIcon(icons.Replay).
+			SetShortcut(KeyMacroReplay.Chord())
+		core.NewFuncButton(m).SetFunc(cv.ApplyMacroToLines).SetIcon(icons.FormatListBulleted).
+			SetShortcut(KeyMacroApplyLines.Chord())
+		core.NewFuncButton(m).SetFunc(cv.SaveMacro).SetIcon(icons.Save)
+
+		core.NewSeparator(m)
+
 		core.NewFuncButton(m).SetFunc(cv.CopyRect).SetIcon(icons.Copy).
 			SetShortcut(KeyRectCopy.Chord())
 		core.NewFuncButton(m).SetFunc(cv.CutRect).SetIcon(icons.Cut).
@@ -230,6 +254,11 @@ func (cv *Code) OverflowMenu(m *core.Scene) {
 
 		core.NewButton(m).SetText("Show completions").SetIcon(icons.CheckCircle).SetKey(keymap.Complete)
 		core.NewButton(m).SetText("Lookup symbol").SetIcon(icons.Search).SetKey(keymap.Lookup)
+		core.NewFuncButton(m).SetFunc(cv.FindDefinition).SetIcon(icons.Search)
+		core.NewFuncButton(m).SetFunc(cv.FindReferences).SetIcon(icons.ManageSearch)
+		core.NewFuncButton(m).SetFunc(cv.CallHierarchy).SetIcon(icons.Schema)
+		core.NewFuncButton(m).SetFunc(cv.TypeHierarchy).SetIcon(icons.Schema)
+		core.NewFuncButton(m).SetFunc(cv.RenameSymbol).SetIcon(icons.Edit)
 		core.NewButton(m).SetText("Jump to line").SetIcon(icons.GoToLine).SetKey(keymap.Jump)
 
 		core.NewSeparator(m)
@@ -260,8 +289,15 @@ func (cv *Code) OverflowMenu(m *core.Scene) {
 	})
 
 	core.NewButton(m).SetText("Command").SetMenu(func(m *core.Scene) {
+		core.NewFuncButton(m).SetFunc(cv.RunTestAtCursor).SetText("Run test at cursor").SetIcon(icons.PlayArrow)
+		core.NewFuncButton(m).SetFunc(cv.ClearCoverage).SetText("Clear coverage").SetIcon(icons.Close)
+		core.NewFuncButton(m).SetFunc(cv.ViewProfile).SetText("Open profile").SetIcon(icons.Speed)
+		core.NewFuncButton(m).SetFunc(cv.ProfileTest).SetText("Profile tests").SetIcon(icons.Speed)
 		core.NewFuncButton(m).SetFunc(cv.DebugAttach).SetText("Debug attach").SetIcon(icons.Debug)
+		core.NewFuncButton(m).SetFunc(cv.DebugCore).SetText("Debug core dump").SetIcon(icons.Debug)
 		core.NewFuncButton(m).SetFunc(cv.VCSUpdateAll).SetText("VCS update all").SetIcon(icons.Update)
+		core.NewFuncButton(m).SetFunc(cv.ToggleBlame).SetText("Toggle blame").SetIcon(icons.Person)
+		core.NewFuncButton(m).SetFunc(cv.ShowMerge).SetText("Resolve merge conflicts").SetIcon(icons.Merge)
 
 		core.NewSeparator(m)
 
@@ -280,10 +316,17 @@ func (cv *Code) MenuSearch(items *[]core.ChooserItem) {
 }
 
 func (cv *Code) addSearchFiles(items *[]core.ChooserItem) {
-	if cv.Files == nil {
-		return
+	for _, ft := range cv.FileTrees() {
+		if cv.Index.Fresh() && string(ft.Filepath) == cv.Index.Root {
+			cv.addSearchFilesIndex(items, ft)
+			continue
+		}
+		cv.addSearchFilesTree(items, ft)
 	}
-	cv.Files.WidgetWalkDown(func(cw core.Widget, cwb *core.WidgetBase) bool {
+}
+
+func (cv *Code) addSearchFilesTree(items *[]core.ChooserItem, ft *filetree.Tree) {
+	ft.WidgetWalkDown(func(cw core.Widget, cwb *core.WidgetBase) bool {
 		fn := filetree.AsNode(cw)
 		if fn == nil || fn.IsIrregular() {
 			return tree.Continue
@@ -322,10 +365,16 @@ func (cv *Code) addSearchFiles(items *[]core.ChooserItem) {
 }
 
 func (cv *Code) addSearchSymbols(items *[]core.ChooserItem) {
+	if cv.Index.Fresh() {
+		cv.addSearchSymbolsIndex(items)
+	}
 	tv := cv.ActiveEditor()
 	if tv == nil || tv.Lines == nil || !tv.Lines.Highlighter.UsingParse() {
 		return
 	}
+	if cv.Index.Fresh() && tv.Lines.FileInfo().Known == fileinfo.Go && cv.Index.Covers(tv.Lines.Filename()) {
+		return // already have its symbols from the index
+	}
 	_, ps := tv.Lines.ParseState()
 	if ps == nil {
 		return
@@ -7,11 +7,13 @@ package code
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 
 	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/text/textpos"
 )
 
 // ArgVarInfo has info about argument variables that fill in relevant values
@@ -35,14 +37,15 @@ var ArgVars = map[string]ArgVarInfo{
 	"{FilenameNoExt}":     {"Current file name without path and extension.", ArgVarFile},
 	"{FileDir}":           {"Name only of current file's directory.", ArgVarDir},
 	"{FileDirPath}":       {"Full path to current file's directory.", ArgVarDir},
-	"{FileDirProjectRel}": {"Path to current file's directory relative to project root.", ArgVarDir},
+	"{FileDirProjectRel}": {"Path to current file's directory relative to project root containing it.", ArgVarDir},
 
 	// Project Root dir
-	"{ProjectDir}":  {"Current project directory name, without full path.", ArgVarDir},
-	"{ProjectPath}": {"Full path to current project directory.", ArgVarDir},
+	"{ProjectDir}":  {"Current project directory name, without full path -- the root containing the current file in a multi-root project.", ArgVarDir},
+	"{ProjectPath}": {"Full path to current project directory -- the root containing the current file in a multi-root project.", ArgVarDir},
+	"{RootPath}":    {"Full path to the project root containing the current file, which is one of the project Roots in a multi-root project, or empty if the file is not in any of them.", ArgVarDir},
 
 	// BuildDir
-	"{BuildDir}":    {"Full path to BuildDir specified in project prefs -- the default Build.", ArgVarDir},
+	"{BuildDir}":    {"Full path to BuildDir specified in project prefs, or for the root containing the current file -- the default Build.", ArgVarDir},
 	"{BuildDirRel}": {"Path to BuildDir relative to project root.", ArgVarDir},
 
 	// BuildTarg
@@ -57,6 +60,9 @@ var ArgVars = map[string]ArgVarInfo{
 	"{RunExecDirPath}":    {"Full path to the directory of the run-time executable file RunExec specified in project prefs.", ArgVarDir},
 	"{RunExecDirPathRel}": {"Project-root relative path to the directory of the run-time executable file RunExec specified in project prefs.", ArgVarDir},
 
+	// TempDir
+	"{TempDir}": {"Full path to a temporary directory for the current project, for files written by commands, such as coverage profiles, which should not go in the source directories.", ArgVarDir},
+
 	// Cursor, Selection
 	"{CurLine}":      {"Cursor current line number (starts at 1).", ArgVarPos},
 	"{CurCol}":       {"Cursor current column number (starts at 0).", ArgVarPos},
@@ -74,6 +80,9 @@ var ArgVars = map[string]ArgVarInfo{
 	"{PromptFileDir}":           {"Prompt user for a file, and this is the directory name (only) of that file.", ArgVarPrompt},
 	"{PromptFileDirPath}":       {"Prompt user for a file, and this is the full path to that directory.", ArgVarPrompt},
 	"{PromptFileDirProjectRel}": {"Prompt user for a file, and this is the path of that directory relative to the project root.", ArgVarPrompt},
+	"{PromptFiles}":             {"Prompt user for a list of files, and these are the full paths to those files, as separate args.", ArgVarPrompt},
+	"{PromptChoice}":            {"Prompt user to choose one of the command Choices -- this is it.", ArgVarPrompt},
+	"{PromptConfirm}":           {"Prompt user for yes or no -- this is true or false.", ArgVarPrompt},
 	"{PromptString1}":           {"Prompt user for a string -- this is it.", ArgVarPrompt},
 	"{PromptString2}":           {"Prompt user for another string -- this is it.", ArgVarPrompt},
 	"{PromptBranch}":            {"Prompt user for a VCS branch.", ArgVarPrompt},
@@ -90,7 +99,8 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjectSettings, tv *textcore.Ed
 	}
 	av := *avp
 
-	projpath, _ := filepath.Abs(string(ppref.ProjectRoot))
+	root, inRoot := ppref.RootContaining(fpath)
+	projpath, _ := filepath.Abs(string(root.Path))
 
 	emptyPath := false
 	if fpath == "" {
@@ -113,7 +123,7 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjectSettings, tv *textcore.Ed
 	extlc := strings.ToLower(ext)
 	fnmnoext := strings.TrimSuffix(fnm, ext)
 
-	bdir, _ := filepath.Abs(string(ppref.BuildDir))
+	bdir, _ := filepath.Abs(string(root.BuildDir))
 	bdirrel, _ := filepath.Rel(projpath, bdir)
 
 	trgf, _ := filepath.Abs(string(ppref.BuildTarg))
@@ -126,6 +136,9 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjectSettings, tv *textcore.Ed
 	exepath = filepath.Clean(exepath)
 	exerel, _ := filepath.Rel(projpath, exepath)
 
+	tmpdir := filepath.Join(os.TempDir(), "cogent-code", projdir)
+	os.MkdirAll(tmpdir, 0750)
+
 	av["{FilePath}"] = fpath
 	av["{Filename}"] = fnm
 	av["{FileExt}"] = ext
@@ -137,6 +150,10 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjectSettings, tv *textcore.Ed
 
 	av["{ProjectDir}"] = projdir
 	av["{ProjectPath}"] = projpath
+	av["{RootPath}"] = ""
+	if inRoot {
+		av["{RootPath}"] = projpath
+	}
 
 	av["{BuildDir}"] = bdir
 	av["{BuildDirRel}"] = bdirrel
@@ -151,16 +168,13 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjectSettings, tv *textcore.Ed
 	av["{RunExecDirPath}"] = exepath
 	av["{RunExecDirPathRel}"] = exerel
 
+	av["{TempDir}"] = tmpdir
+
 	if tv != nil {
-		av["{CurLine}"] = fmt.Sprintf("%v", tv.CursorPos.Line)
-		av["{CurCol}"] = fmt.Sprintf("%v", tv.CursorPos.Char)                // not quite col
-		av["{SelStartLine}"] = fmt.Sprintf("%v", tv.SelectRegion.Start.Line) // check for no sel
-		av["{SelStartCol}"] = fmt.Sprintf("%v", tv.SelectRegion.Start.Char)
-		av["{SelEndLine}"] = fmt.Sprintf("%v", tv.SelectRegion.End.Line)  // check for no sel
-		av["{SelEndCol}"] = fmt.Sprintf("%v", tv.SelectRegion.Start.Char) // check for no sel
-		av["{CurSel}"] = ""                                               // todo get sel
-		av["{CurLineText}"] = ""                                          // todo get cur line
-		av["{CurWord}"] = ""                                              // todo get word
+		avp.SetCursor(tv.CursorPos, tv.SelectRegion)
+		if tv.Lines != nil {
+			avp.SetText(tv)
+		}
 	} else {
 		av["{CurLine}"] = ""
 		av["{CurCol}"] = ""
@@ -174,6 +188,59 @@ func (avp *ArgVarVals) Set(fpath string, ppref *ProjectSettings, tv *textcore.Ed
 	}
 }
 
+// SetCursor sets the values of the cursor and selection variables
+// for the given cursor position and selected region.
+func (avp *ArgVarVals) SetCursor(cur textpos.Pos, sel textpos.Region) {
+	if *avp == nil {
+		*avp = make(ArgVarVals, len(ArgVars))
+	}
+	av := *avp
+	av["{CurLine}"] = fmt.Sprintf("%v", cur.Line)
+	av["{CurCol}"] = fmt.Sprintf("%v", cur.Char)             // not quite col
+	av["{SelStartLine}"] = fmt.Sprintf("%v", sel.Start.Line) // check for no sel
+	av["{SelStartCol}"] = fmt.Sprintf("%v", sel.Start.Char)
+	av["{SelEndLine}"] = fmt.Sprintf("%v", sel.End.Line) // check for no sel
+	av["{SelEndCol}"] = fmt.Sprintf("%v", sel.End.Char)  // check for no sel
+	av["{CurSel}"] = ""                                  // see SetText
+	av["{CurLineText}"] = ""
+	av["{CurWord}"] = ""
+}
+
+// SetText sets the values of the {CurSel}, {CurLineText}, and {CurWord}
+// variables for the text of the given editor.
+func (avp *ArgVarVals) SetText(tv *textcore.Editor) {
+	av := *avp
+	if sel := tv.Selection(); sel != nil {
+		av["{CurSel}"] = string(sel.ToBytes())
+	}
+	if !tv.Lines.IsValidLine(tv.CursorPos.Line) {
+		return
+	}
+	av["{CurLineText}"] = string(tv.Lines.Line(tv.CursorPos.Line))
+	if wr := tv.Lines.WordAt(tv.CursorPos); !wr.IsNil() {
+		av["{CurWord}"] = string(tv.Lines.Region(wr.Start, wr.End).ToBytes())
+	}
+}
+
+// SetPromptFile sets the values of the {PromptFile*} variables for the given
+// file path chosen by the user, relative to the project root containing it.
+func (avp *ArgVarVals) SetPromptFile(fpath string, ppref *ProjectSettings) {
+	if *avp == nil {
+		*avp = make(ArgVarVals, len(ArgVars))
+	}
+	av := *avp
+	fpath, _ = filepath.Abs(fpath)
+	dirpath := filepath.Dir(fpath)
+	projpath, _ := filepath.Abs(string(ppref.RootFor(fpath).Path))
+	dirrel, _ := filepath.Rel(projpath, dirpath)
+
+	av["{PromptFilePath}"] = fpath
+	av["{PromptFilename}"] = filepath.Base(fpath)
+	av["{PromptFileDir}"] = filepath.Base(dirpath)
+	av["{PromptFileDirPath}"] = dirpath
+	av["{PromptFileDirProjectRel}"] = dirrel
+}
+
 // Bind replaces the variables in the given arg string with their values
 func (avp *ArgVarVals) Bind(arg string) string {
 	sz := len(arg)
@@ -246,7 +313,7 @@ func ArgVarPrompts(arg string) (map[string]struct{}, bool) {
 			if ps == nil {
 				ps = make(map[string]struct{})
 			}
-			if strings.HasPrefix(vnm, "{PromptFile") {
+			if strings.HasPrefix(vnm, "{PromptFile") && vnm != "{PromptFiles}" {
 				ps["{PromptFilename}"] = struct{}{}
 			} else {
 				ps[vnm] = struct{}{}
@@ -68,3 +68,35 @@ func TestBind(t *testing.T) {
 		t.Errorf("bind error: should have been: %v  was: %v\n", cv, bv)
 	}
 }
+
+func TestBindRoots(t *testing.T) {
+	pp := ProjectSettings{}
+	pp.ProjectRoot = "/work/app"
+	pp.BuildDir = "/work/app/cmd"
+	pp.Roots = []RootSettings{{Path: "/work/lib"}, {Path: "/work/lib/sub", BuildDir: "/work/lib/sub/build"}}
+
+	var avp ArgVarVals
+	avp.Set("/work/app/main.go", &pp, nil)
+	assert.Equal(t, "/work/app", avp.Bind("{RootPath}"))
+	assert.Equal(t, "cmd", avp.Bind("{BuildDirRel}"))
+
+	avp.Set("/work/lib/a/a.go", &pp, nil)
+	assert.Equal(t, "/work/lib", avp.Bind("{RootPath}"))
+	assert.Equal(t, "/work/lib", avp.Bind("{ProjectPath}"))
+	assert.Equal(t, "lib", avp.Bind("{ProjectDir}"))
+	assert.Equal(t, "a", avp.Bind("{FileDirProjectRel}"))
+	assert.Equal(t, "/work/lib", avp.Bind("{BuildDir}"))
+
+	avp.Set("/work/lib/sub/s.go", &pp, nil)
+	assert.Equal(t, "/work/lib/sub", avp.Bind("{RootPath}"))
+	assert.Equal(t, "build", avp.Bind("{BuildDirRel}"))
+
+	avp.Set("/work/library/x.go", &pp, nil)
+	assert.Equal(t, "", avp.Bind("{RootPath}"))
+	assert.Equal(t, "/work/app", avp.Bind("{ProjectPath}"))
+	_, in := pp.RootContaining("/work/library/x.go")
+	assert.False(t, in)
+
+	avp.Set("", &pp, nil)
+	assert.Equal(t, "/work/app", avp.Bind("{RootPath}"))
+}
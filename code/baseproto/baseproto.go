@@ -0,0 +1,186 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package baseproto provides the base protocol shared by the Language
+// Server Protocol and the Debug Adapter Protocol, where each JSON message
+// has a Content-Length header, and responses are matched to requests by id.
+// The lsp and cdap packages build their connections on it.
+package baseproto
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrClosed is returned for calls on a closed connection.
+	ErrClosed = errors.New("connection closed")
+
+	// ErrTimeout is returned for calls that time out waiting for a response.
+	ErrTimeout = errors.New("timed out")
+)
+
+// Dispatch handles an incoming message of type M on a [Conn]. It is called
+// from the connection read goroutine and must not block. It returns the id
+// of the request that the message is a response to and true, for the message
+// to be returned from [Conn.Call], or false for any other message.
+type Dispatch[M any] func(msg *M) (id int64, response bool)
+
+// Conn is a connection reading and writing JSON messages of type M
+// using the base protocol. It is symmetric, and is used for both the
+// client and (in tests) the server side.
+type Conn[M any] struct {
+	rwc      io.ReadWriteCloser
+	dispatch Dispatch[M]
+
+	wmu sync.Mutex // protects writing
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan *M
+	closed  bool
+	done    chan struct{}
+}
+
+// NewConn returns a new connection reading and writing given stream,
+// with given dispatch function for incoming messages.
+// It starts a goroutine reading incoming messages.
+func NewConn[M any](rwc io.ReadWriteCloser, dispatch Dispatch[M]) *Conn[M] {
+	c := &Conn[M]{rwc: rwc, dispatch: dispatch, pending: make(map[int64]chan *M), done: make(chan struct{})}
+	go c.readLoop()
+	return c
+}
+
+// NextID returns a new id, for messages that need a unique id or
+// sequence number, such as DAP events and responses.
+func (c *Conn[M]) NextID() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	return c.nextID
+}
+
+// Call sends the request returned by req for a new id, and waits up to
+// timeout for the response with that id. A zero timeout waits forever.
+func (c *Conn[M]) Call(req func(id int64) (*M, error), timeout time.Duration) (*M, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan *M, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	msg, err := req(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Write(msg); err != nil {
+		return nil, err
+	}
+	var tc <-chan time.Time
+	if timeout > 0 {
+		tm := time.NewTimer(timeout)
+		defer tm.Stop()
+		tc = tm.C
+	}
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-tc:
+		return nil, ErrTimeout
+	case <-c.done:
+		return nil, ErrClosed
+	}
+}
+
+// Write writes the given message.
+func (c *Conn[M]) Write(msg *M) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if _, err := fmt.Fprintf(c.rwc, "Content-Length: %d\r\n\r\n", len(b)); err != nil {
+		return err
+	}
+	_, err = c.rwc.Write(b)
+	return err
+}
+
+// Close closes the connection and the underlying stream.
+func (c *Conn[M]) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+	return c.rwc.Close()
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Conn[M]) Done() <-chan struct{} {
+	return c.done
+}
+
+// readLoop reads messages until the stream is closed.
+func (c *Conn[M]) readLoop() {
+	rd := bufio.NewReader(c.rwc)
+	for {
+		msg := new(M)
+		if err := readMessage(rd, msg); err != nil {
+			c.Close()
+			return
+		}
+		id, ok := c.dispatch(msg)
+		if !ok {
+			continue
+		}
+		c.mu.Lock()
+		ch := c.pending[id]
+		c.mu.Unlock()
+		if ch != nil {
+			ch <- msg
+		}
+	}
+}
+
+// readMessage reads one Content-Length framed message into msg.
+func readMessage(rd *bufio.Reader, msg any) error {
+	tr := textproto.NewReader(rd)
+	hdr, err := tr.ReadMIMEHeader()
+	if err != nil {
+		return err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(hdr.Get("Content-Length")))
+	if err != nil {
+		return fmt.Errorf("baseproto: invalid Content-Length: %w", err)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rd, b); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, msg)
+}
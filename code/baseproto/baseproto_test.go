@@ -0,0 +1,67 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package baseproto
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testMessage struct {
+	ID    int64  `json:"id"`
+	Reply bool   `json:"reply,omitempty"`
+	Text  string `json:"text"`
+}
+
+func TestConn(t *testing.T) {
+	cc, sc := net.Pipe()
+	var srv *Conn[testMessage]
+	srv = NewConn(sc, func(msg *testMessage) (int64, bool) {
+		if msg.Text != "ignore" {
+			go srv.Write(&testMessage{ID: msg.ID, Reply: true, Text: strings.ToUpper(msg.Text)})
+		}
+		return 0, false
+	})
+	cl := NewConn(cc, func(msg *testMessage) (int64, bool) {
+		return msg.ID, msg.Reply
+	})
+
+	resp, err := cl.Call(func(id int64) (*testMessage, error) {
+		return &testMessage{ID: id, Text: "hello"}, nil
+	}, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "HELLO", resp.Text)
+
+	_, err = cl.Call(func(id int64) (*testMessage, error) {
+		return &testMessage{ID: id, Text: "ignore"}, nil
+	}, 50*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	srv.Close()
+	select {
+	case <-cl.Done():
+	case <-time.After(time.Second):
+		t.Fatal("client not closed")
+	}
+	_, err = cl.Call(func(id int64) (*testMessage, error) {
+		return &testMessage{ID: id}, nil
+	}, 0)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestReadMessage(t *testing.T) {
+	rd := bufio.NewReader(strings.NewReader("Content-Length: 24\r\nContent-Type: x\r\n\r\n{\"id\":3,\"text\":\"a\\r\\nb\"}"))
+	msg := &testMessage{}
+	assert.NoError(t, readMessage(rd, msg))
+	assert.Equal(t, testMessage{ID: 3, Text: "a\r\nb"}, *msg)
+
+	rd = bufio.NewReader(strings.NewReader("Content-Length: x\r\n\r\n{}"))
+	assert.ErrorContains(t, readMessage(rd, msg), "invalid Content-Length")
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/styles/abilities"
+	"cogentcore.org/core/styles/units"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/syms"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/text"
+	"cogentcore.org/core/text/token"
+	"cogentcore.org/core/tree"
+)
+
+// packageSymbols returns the package-level symbols of the given lines,
+// which are the symbols shown in the [SymbolsPanel], and false if the
+// lines are not parsed. The symbols are nil if they are not yet parsed.
+func packageSymbols(ln *lines.Lines) (*syms.Symbol, bool) {
+	if ln == nil || !ln.Highlighter.UsingParse() {
+		return nil, false
+	}
+	pfs := ln.ParseFileState()
+	if pfs == nil {
+		return nil, false
+	}
+	if len(pfs.ParseState.Scopes) == 0 {
+		return nil, true
+	}
+	return pfs.ParseState.Scopes[0], true // first scope of parse state is the full set of package symbols
+}
+
+// symbolPaths returns the possible [symbolPath]s of the symbols of the
+// given package in the given file, from the outermost: a type or function,
+// then a method or field of a type. Methods and fields come before types,
+// so that the innermost path at a line is found first.
+func symbolPaths(pkg *syms.Symbol, fname string) [][]*syms.Symbol {
+	var ps [][]*syms.Symbol
+	for _, sy := range pkg.Children.Slice(true) {
+		switch sy.Kind.SubCat() {
+		case token.NameType:
+			for _, ch := range sy.Children.Slice(true) {
+				if ch.Filename != fname {
+					continue
+				}
+				if ch.Kind == token.NameMethod || (ch.Kind == token.NameField && sy.Filename == fname) {
+					ps = append(ps, []*syms.Symbol{sy, ch})
+				}
+			}
+			if sy.Filename == fname {
+				ps = append(ps, []*syms.Symbol{sy})
+			}
+		case token.NameFunction:
+			if sy.Filename == fname {
+				ps = append(ps, []*syms.Symbol{sy})
+			}
+		}
+	}
+	slices.SortStableFunc(ps, func(a, b []*syms.Symbol) int {
+		return len(b) - len(a)
+	})
+	return ps
+}
+
+// symbolPath returns the path of symbols that contain the given line,
+// from the given [symbolPaths], or nil if none do.
+func symbolPath(paths [][]*syms.Symbol, ln int) []*syms.Symbol {
+	for _, p := range paths {
+		sy := p[len(p)-1]
+		if sy.Region.Start.Line <= ln && ln <= sy.Region.End.Line {
+			return p
+		}
+	}
+	return nil
+}
+
+// stickySymbols returns the symbols in the given path that start
+// before the given top line and continue to it, which are the
+// declarations shown in the [StickyHeaders].
+func stickySymbols(path []*syms.Symbol, top int) []*syms.Symbol {
+	var st []*syms.Symbol
+	for _, sy := range path {
+		if sy.Region.Start.Line < top && top <= sy.Region.End.Line {
+			st = append(st, sy)
+		}
+	}
+	return st
+}
+
+// fileSymbols returns the types and functions of the given package
+// that are in the given file, in the order of their position in it.
+func fileSymbols(pkg *syms.Symbol, fname string) []*syms.Symbol {
+	var fs []*syms.Symbol
+	for _, sy := range pkg.Children {
+		sc := sy.Kind.SubCat()
+		if sy.Filename == fname && (sc == token.NameType || sc == token.NameFunction) {
+			fs = append(fs, sy)
+		}
+	}
+	slices.SortFunc(fs, func(a, b *syms.Symbol) int {
+		return a.Region.Start.Line - b.Region.Start.Line
+	})
+	return fs
+}
+
+// Breadcrumbs is the bar above a [TextEditor] that shows the package,
+// type, and function or method at the cursor, using the same symbols
+// as the [SymbolsPanel], each with a menu of the other symbols in its
+// scope to go to.
+type Breadcrumbs struct {
+	core.Frame
+
+	// Editor is the editor that the breadcrumbs are for.
+	Editor *TextEditor `set:"-" display:"-" json:"-" xml:"-"`
+
+	// pkg is the package-level symbols, nil if not parsed.
+	pkg *syms.Symbol
+
+	// path is the path of symbols at the cursor, from [symbolPath].
+	path []*syms.Symbol
+}
+
+func (bc *Breadcrumbs) Init() {
+	bc.Frame.Init()
+	bc.Styler(func(s *styles.Style) {
+		s.Grow.Set(1, 0)
+		s.Align.Items = styles.Center
+		s.Gap.X.Em(0.1)
+		s.Padding.Zero()
+		if bc.pkg == nil {
+			s.Display = styles.DisplayNone
+		}
+	})
+	bc.Maker(func(p *tree.Plan) {
+		if bc.pkg == nil {
+			return
+		}
+		tree.AddAt(p, "package", func(w *core.Button) {
+			bc.styleCrumb(w)
+			w.SetIcon(icons.Package)
+			w.Updater(func() {
+				w.SetText(bc.pkg.Name)
+			})
+			w.Menu = func(m *core.Scene) {
+				bc.symbolsMenu(m, fileSymbols(bc.pkg, bc.Editor.Lines.Filename()))
+			}
+		})
+		for i := range bc.path {
+			tree.AddAt(p, fmt.Sprintf("sep-%d", i), func(w *core.Icon) {
+				w.SetIcon(icons.ChevronRight)
+			})
+			tree.AddAt(p, fmt.Sprintf("symbol-%d", i), func(w *core.Button) {
+				bc.styleCrumb(w)
+				w.Updater(func() {
+					sy := bc.path[i]
+					w.SetText(sy.Name).SetIcon(symbolIcon(sy.Kind))
+				})
+				w.Menu = func(m *core.Scene) {
+					bc.symbolsMenu(m, bc.scopeSymbols(i))
+				}
+			})
+		}
+	})
+}
+
+// styleCrumb styles the given button of the breadcrumbs.
+func (bc *Breadcrumbs) styleCrumb(w *core.Button) {
+	w.Type = core.ButtonAction
+	w.Styler(func(s *styles.Style) {
+		s.Padding.Set(units.Dp(2), units.Dp(4))
+	})
+}
+
+// scopeSymbols returns the symbols to show in the menu of the symbol
+// at the given index in the path: the fields and methods of a type
+// that is the last one, or else the other symbols in its scope.
+func (bc *Breadcrumbs) scopeSymbols(i int) []*syms.Symbol {
+	sy := bc.path[i]
+	if i == len(bc.path)-1 && sy.HasChildren() {
+		return sy.Children.Slice(true)
+	}
+	if i == 0 {
+		return fileSymbols(bc.pkg, bc.Editor.Lines.Filename())
+	}
+	return bc.path[i-1].Children.Slice(true)
+}
+
+// symbolsMenu adds buttons to the given menu to select the given symbols.
+func (bc *Breadcrumbs) symbolsMenu(m *core.Scene, sys []*syms.Symbol) {
+	cv := bc.Editor.Code
+	for _, sy := range sys {
+		core.NewButton(m).SetText(sy.Label()).SetIcon(symbolIcon(sy.Kind)).OnClick(func(e events.Event) {
+			SelectSymbol(cv, *sy)
+		})
+	}
+}
+
+// StickyHeaders shows the declaration lines of the function and type that
+// enclose the first visible line of a [TextEditor] above it, which are
+// otherwise scrolled out of view, if [SettingsData.StickyHeaders] is on.
+// Clicking on a line goes to the declaration.
+type StickyHeaders struct {
+	core.Frame
+
+	// Editor is the editor that the headers are for.
+	Editor *TextEditor `set:"-" display:"-" json:"-" xml:"-"`
+
+	// symbols are the symbols whose declarations are shown,
+	// from [stickySymbols].
+	symbols []*syms.Symbol
+}
+
+func (sh *StickyHeaders) Init() {
+	sh.Frame.Init()
+	sh.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 0)
+		s.Gap.Zero()
+		s.Padding.Zero()
+		s.Background = colors.Scheme.SurfaceContainer
+		if len(sh.symbols) == 0 {
+			s.Display = styles.DisplayNone
+		}
+	})
+	sh.Maker(func(p *tree.Plan) {
+		for i := range sh.symbols {
+			tree.AddAt(p, fmt.Sprintf("header-%d", i), func(w *core.Text) {
+				w.Styler(func(s *styles.Style) {
+					s.SetAbilities(true, abilities.Clickable)
+					s.Font.Family = rich.Monospace
+					s.Text.WhiteSpace = text.WhiteSpacePre
+					s.Grow.Set(1, 0)
+					if sh.Editor != nil {
+						s.Text.TabSize = sh.Editor.Styles.Text.TabSize
+						s.Padding.Left.Dot(sh.Editor.LineNumberPixels())
+					}
+				})
+				w.Updater(func() {
+					sy := sh.symbols[i]
+					line := ""
+					if sh.Editor != nil && sh.Editor.Lines != nil && sy.Region.Start.Line < sh.Editor.Lines.NumLines() {
+						line = strings.TrimRight(string(sh.Editor.Lines.Line(sy.Region.Start.Line)), " \t{")
+					}
+					w.SetText(html.EscapeString(line))
+				})
+				w.OnClick(func(e events.Event) {
+					if sh.Editor != nil && i < len(sh.symbols) {
+						SelectSymbol(sh.Editor.Code, *sh.symbols[i])
+					}
+				})
+			})
+		}
+	})
+}
+
+// contextWidgets returns the [Breadcrumbs] and [StickyHeaders] above
+// the editor, which are nil if it is not in a [Code] text frame.
+func (ed *TextEditor) contextWidgets() (*Breadcrumbs, *StickyHeaders) {
+	if ed.Parent == nil {
+		return nil, nil
+	}
+	txnm := strings.TrimPrefix(ed.Name, "texteditor-")
+	pt := ed.Parent.AsTree()
+	bc, _ := pt.ChildByName("breadcrumbs-"+txnm, 1).(*Breadcrumbs)
+	sh, _ := pt.ChildByName("sticky-"+txnm, 2).(*StickyHeaders)
+	return bc, sh
+}
+
+// symbolPathsCache caches the [symbolPaths] of the file of a [TextEditor],
+// which only change when it is parsed again.
+type symbolPathsCache struct {
+
+	// pkg is the package-level symbols that the paths are from
+	pkg *syms.Symbol
+
+	// nsyms is the number of package-level symbols, which increases
+	// as the symbols of other files in the package are added
+	nsyms int
+
+	// fname is the file that the paths are for
+	fname string
+
+	// paths are the [symbolPaths]
+	paths [][]*syms.Symbol
+}
+
+// symbolPaths returns the [symbolPaths] of the file of the editor
+// in the given package-level symbols, computing them only if it has
+// been parsed again since the last call.
+func (ed *TextEditor) symbolPaths(pkg *syms.Symbol) [][]*syms.Symbol {
+	fname := ed.Lines.Filename()
+	sc := ed.symbols
+	if sc == nil || sc.pkg != pkg || sc.nsyms != len(pkg.Children) || sc.fname != fname {
+		sc = &symbolPathsCache{pkg: pkg, nsyms: len(pkg.Children), fname: fname, paths: symbolPaths(pkg, fname)}
+		ed.symbols = sc
+	}
+	return sc.paths
+}
+
+// needsContextUpdate updates the [Breadcrumbs] and [StickyHeaders] above
+// the editor after it is next rendered, which is needed when the cursor
+// moves, it scrolls, or the text or its parse changes.
+func (ed *TextEditor) needsContextUpdate() {
+	if ed.contextPending {
+		return
+	}
+	ed.contextPending = true
+	ed.Defer(func() {
+		ed.contextPending = false
+		ed.updateContext()
+	})
+}
+
+// updateContext updates the [Breadcrumbs] and [StickyHeaders] above
+// the editor for the current cursor position and scrolling, if changed.
+func (ed *TextEditor) updateContext() {
+	bc, sh := ed.contextWidgets()
+	if bc == nil && sh == nil {
+		return
+	}
+	var pkg *syms.Symbol
+	var path, sticky []*syms.Symbol
+	if ed.Lines != nil {
+		pkg, _ = packageSymbols(ed.Lines)
+	}
+	if pkg != nil {
+		paths := ed.symbolPaths(pkg)
+		path = symbolPath(paths, ed.CursorPos.Line)
+		if Settings.StickyHeaders {
+			top := ed.PixelToCursor(image.Point{}).Line
+			sticky = stickySymbols(symbolPath(paths, top), top)
+		}
+	}
+	if bc != nil && (bc.pkg != pkg || !slices.Equal(bc.path, path)) {
+		bc.Editor = ed
+		bc.pkg, bc.path = pkg, path
+		bc.Update()
+	}
+	if sh != nil && !slices.Equal(sh.symbols, sticky) {
+		sh.Editor = ed
+		sh.symbols = sticky
+		sh.Update()
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"cogentcore.org/core/text/parse/syms"
+	"cogentcore.org/core/text/textpos"
+	"cogentcore.org/core/text/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolPath(t *testing.T) {
+	sym := func(name string, kind token.Tokens, fname string, st, ed int) *syms.Symbol {
+		return syms.NewSymbol(name, kind, fname, textpos.NewRegion(st, 0, ed, 1))
+	}
+	pkg := sym("pkg", token.NamePackage, "", 0, 0)
+	typ := sym("T", token.NameStruct, "a.go", 2, 5)
+	field := sym("F", token.NameField, "a.go", 3, 3)
+	meth := sym("M", token.NameMethod, "a.go", 7, 10)
+	other := sym("N", token.NameMethod, "b.go", 7, 10)
+	fun := sym("f", token.NameFunction, "a.go", 12, 15)
+	typ.AddChild(field)
+	typ.AddChild(meth)
+	typ.AddChild(other)
+	pkg.AddChild(typ)
+	pkg.AddChild(fun)
+	pkg.AddChild(sym("g", token.NameFunction, "b.go", 0, 20))
+
+	apaths := symbolPaths(pkg, "a.go")
+	bpaths := symbolPaths(pkg, "b.go")
+	assert.Len(t, apaths, 4)
+	assert.Equal(t, []*syms.Symbol{typ}, symbolPath(apaths, 2))
+	assert.Equal(t, []*syms.Symbol{typ, field}, symbolPath(apaths, 3))
+	assert.Equal(t, []*syms.Symbol{typ, meth}, symbolPath(apaths, 8))
+	assert.Equal(t, []*syms.Symbol{fun}, symbolPath(apaths, 15))
+	assert.Nil(t, symbolPath(apaths, 11))
+	assert.Equal(t, []*syms.Symbol{typ, other}, symbolPath(bpaths, 8))
+
+	assert.Equal(t, []*syms.Symbol{typ, fun}, fileSymbols(pkg, "a.go"))
+
+	path := symbolPath(apaths, 8)
+	assert.Equal(t, []*syms.Symbol{meth}, stickySymbols(path, 8))
+	assert.Nil(t, stickySymbols(path, 7))
+}
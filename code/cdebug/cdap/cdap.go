@@ -0,0 +1,1292 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cdap provides a Debug Adapter Protocol (DAP) implementation of
+// the [cdebug.GiDebug] interface, which can be used with any debugger that
+// has a DAP adapter, such as debugpy, lldb-dap, or dlv dap.
+package cdap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/base/num"
+	"cogentcore.org/core/text/highlighting"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/syms"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/textcore"
+)
+
+// Adapter specifies a debug adapter command, and how to launch
+// programs with it. It is set per language in the Code Languages settings.
+type Adapter struct {
+
+	// Cmd is the debug adapter executable, e.g., lldb-dap, python3, or dlv.
+	// If empty, no debug adapter is used.
+	Cmd string
+
+	// Args are the arguments to pass to the adapter. Any {Addr} in an arg is
+	// replaced with a free local host:port, for adapters that listen on a
+	// socket, e.g., dap --listen={Addr} for dlv; otherwise, the adapter
+	// communicates over stdin / stdout.
+	Args []string
+
+	// ID is the adapter type id sent to the adapter, e.g., python or lldb.
+	ID string
+
+	// LaunchArgs are additional adapter-specific arguments for the launch
+	// (or attach) request, which override the standard program, cwd,
+	// args and (for dlv) mode values.
+	LaunchArgs map[string]any
+}
+
+// IsValid returns true if an adapter command has been specified.
+func (ad *Adapter) IsValid() bool {
+	return ad.Cmd != ""
+}
+
+// isDelve returns true if the adapter is dlv dap, which takes
+// a mode launch argument for how to debug the program.
+func (ad *Adapter) isDelve() bool {
+	return strings.TrimSuffix(filepath.Base(ad.Cmd), ".exe") == "dlv"
+}
+
+var (
+	// DefaultTimeout is the default time to wait for responses from the adapter.
+	DefaultTimeout = 10 * time.Second
+
+	// NotSupportedErr is returned for functions the adapter does not support.
+	NotSupportedErr = errors.New("not supported by the debug adapter")
+)
+
+// frameKey identifies a stack frame by thread and depth.
+type frameKey struct {
+	thread, depth int
+}
+
+// GiDap is the DAP implementation of the GiDebug interface
+type GiDap struct {
+	adapter  Adapter                  // adapter settings
+	path     string                   // path to exe
+	rootPath string                   // root path for project
+	outbuf   *lines.Lines             // console output
+	obuf     *textcore.OutputBuffer   // adapter process output buffer
+	statFunc func(stat cdebug.Status) // status function
+	params   cdebug.Params            // local copy of initial params
+	timeout  time.Duration            // time to wait for responses
+	cmd      *exec.Cmd                // command running the adapter
+	conn     *Conn                    // connection to the adapter
+
+	caps        map[string]any     // adapter capabilities
+	initialized chan struct{}      // closed on initialized event
+	initOnce    sync.Once          // for closing initialized
+	configDone  bool               // configurationDone has been sent
+	stops       chan *cdebug.State // stopped and terminated events
+
+	mu        sync.Mutex               // protects below
+	active    bool                     // handshake is complete
+	state     cdebug.State             // last state
+	curThread int                      // current thread id
+	pid       int                      // debuggee process id
+	exitCode  int                      // debuggee exit code
+	breaks    []*cdebug.Break          // all breakpoints
+	nextBreak int                      // next breakpoint id
+	frames    map[frameKey]int         // frame ids for current stop
+	refs      map[*cdebug.Variable]int // variable references for current stop
+}
+
+// NewGiDap starts the given debug adapter for given path, and project
+// root path, returning a new debugger which reports through pars.StatFunc
+// when it is ready.
+func NewGiDap(ad *Adapter, path, rootPath string, outbuf *lines.Lines, pars *cdebug.Params) (*GiDap, error) {
+	gd := &GiDap{adapter: *ad}
+	err := gd.Start(path, rootPath, outbuf, pars)
+	return gd, err
+}
+
+func (gd *GiDap) HasTasks() bool {
+	return false
+}
+
+func (gd *GiDap) WriteToConsole(msg string) {
+	if gd.outbuf == nil {
+		log.Print(msg)
+		return
+	}
+	sty := gd.outbuf.FontStyle()
+	var tlns [][]rune
+	var mlns []rich.Text
+	for _, ln := range strings.Split(strings.TrimSuffix(msg, "\n"), "\n") {
+		rl := []rune(ln)
+		tlns = append(tlns, rl)
+		mlns = append(mlns, highlighting.MarkupPathsAsLinks(rl, rich.NewText(sty, rl), 2))
+	}
+	gd.outbuf.AppendTextMarkup(tlns, mlns)
+}
+
+func (gd *GiDap) LogErr(err error) error {
+	if err == nil {
+		return err
+	}
+	gd.WriteToConsole(err.Error() + "\n")
+	return err
+}
+
+func (gd *GiDap) SetParams(params *cdebug.Params) {
+	gd.params = *params
+}
+
+// StartedCheck checks that the adapter is running properly
+func (gd *GiDap) StartedCheck() error {
+	if !gd.IsActive() {
+		return gd.LogErr(cdebug.NotStartedErr)
+	}
+	return nil
+}
+
+// setup sets the parameters for a new session.
+func (gd *GiDap) setup(path, rootPath string, outbuf *lines.Lines, pars *cdebug.Params) {
+	gd.path = path
+	gd.rootPath = rootPath
+	gd.outbuf = outbuf
+	gd.params = *pars
+	gd.statFunc = pars.StatFunc
+	if gd.timeout == 0 {
+		gd.timeout = DefaultTimeout
+	}
+	gd.initialized = make(chan struct{})
+	gd.initOnce = sync.Once{}
+	gd.configDone = false
+	gd.stops = make(chan *cdebug.State, 16)
+	gd.active = false
+	gd.breaks = nil
+}
+
+// setStatus calls the status function, if set.
+func (gd *GiDap) setStatus(stat cdebug.Status) {
+	if gd.statFunc != nil {
+		gd.statFunc(stat)
+	}
+}
+
+// Start starts the debug adapter for a given exe path
+func (gd *GiDap) Start(path, rootPath string, outbuf *lines.Lines, pars *cdebug.Params) error {
+	gd.setup(path, rootPath, outbuf, pars)
+	args := slices.Clone(gd.adapter.Args)
+	addr := ""
+	if slices.ContainsFunc(args, func(a string) bool { return strings.Contains(a, "{Addr}") }) {
+		var err error
+		addr, err = freeAddr()
+		if err != nil {
+			gd.setStatus(cdebug.Error)
+			return gd.LogErr(err)
+		}
+		for i := range args {
+			args[i] = strings.ReplaceAll(args[i], "{Addr}", addr)
+		}
+	}
+	gd.cmd = exec.Command(gd.adapter.Cmd, args...)
+	gd.cmd.Dir = gd.workDir()
+	rwc, err := gd.startCmd(addr)
+	if err != nil {
+		gd.setStatus(cdebug.Error)
+		return gd.LogErr(err)
+	}
+	gd.connect(rwc)
+	return nil
+}
+
+// StartConn starts a debug session for a given exe path with an adapter
+// that is already connected over the given stream.
+func (gd *GiDap) StartConn(rwc io.ReadWriteCloser, path, rootPath string, outbuf *lines.Lines, pars *cdebug.Params) {
+	gd.setup(path, rootPath, outbuf, pars)
+	gd.connect(rwc)
+}
+
+// startCmd starts the adapter command, returning the stream to
+// communicate with it, either stdin / stdout or a socket at addr.
+func (gd *GiDap) startCmd(addr string) (io.ReadWriteCloser, error) {
+	var out io.ReadCloser
+	var rwc io.ReadWriteCloser
+	if addr == "" {
+		in, err := gd.cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		pout, err := gd.cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		rwc = &pipeRWC{pout, in}
+		if gd.outbuf != nil {
+			out, err = gd.cmd.StderrPipe()
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if gd.outbuf != nil {
+		pout, err := gd.cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		gd.cmd.Stderr = gd.cmd.Stdout
+		out = pout
+	}
+	if err := gd.cmd.Start(); err != nil {
+		return nil, err
+	}
+	if out != nil {
+		gd.obuf = &textcore.OutputBuffer{}
+		gd.obuf.SetOutput(out).SetLines(gd.outbuf).SetMarkupFunc(func(buf *lines.Lines, out []rune) rich.Text {
+			return highlighting.MarkupPathsAsLinks(out, rich.NewText(buf.FontStyle(), out), 2)
+		})
+		go gd.obuf.MonitorOutput()
+	}
+	if addr == "" {
+		return rwc, nil
+	}
+	// wait for the adapter to start listening
+	var err error
+	for wait := time.Now().Add(gd.timeout); time.Now().Before(wait); time.Sleep(100 * time.Millisecond) {
+		var nc net.Conn
+		if nc, err = net.Dial("tcp", addr); err == nil {
+			return nc, nil
+		}
+	}
+	gd.cmd.Process.Kill()
+	return nil, err
+}
+
+// connect starts the connection over given stream, and does the
+// initialization handshake in a separate goroutine, calling the
+// status function with Ready when done.
+func (gd *GiDap) connect(rwc io.ReadWriteCloser) {
+	gd.conn = NewConn(rwc, nil, gd.handleEvent)
+	go func() {
+		if err := gd.initialize(); err != nil {
+			gd.LogErr(err)
+			gd.setStatus(cdebug.Error)
+			return
+		}
+		gd.mu.Lock()
+		gd.active = true
+		gd.mu.Unlock()
+		gd.setStatus(cdebug.Ready)
+	}()
+}
+
+// initialize sends the initialize and launch (or attach) requests,
+// and waits for the initialized event, after which breakpoints can be set.
+func (gd *GiDap) initialize() error {
+	iargs := map[string]any{
+		"clientID":             "cogentcode",
+		"clientName":           "Cogent Code",
+		"adapterID":            gd.adapter.ID,
+		"linesStartAt1":        true,
+		"columnsStartAt1":      true,
+		"pathFormat":           "path",
+		"supportsVariableType": true,
+	}
+	caps := map[string]any{}
+	if err := gd.conn.Request("initialize", iargs, &caps, gd.timeout); err != nil {
+		return err
+	}
+	gd.caps = caps
+	req, largs := gd.launchArgs()
+	go func() {
+		// note: some adapters only respond to launch after configurationDone
+		if err := gd.conn.Request(req, largs, nil, 0); err != nil && err != ErrClosed {
+			gd.LogErr(err)
+			gd.setStatus(cdebug.Error)
+		}
+	}()
+	select {
+	case <-gd.initialized:
+		return nil
+	case <-gd.conn.Done():
+		return ErrClosed
+	case <-time.After(gd.timeout):
+		return errors.New("cdap: timed out waiting for the adapter to initialize")
+	}
+}
+
+// launchArgs returns the launch or attach request and its arguments.
+// Args after a -- in the params Args are passed to the program,
+// and other Args are ignored, as they are for the debugger itself.
+// The params Dir and Env set the working directory and environment.
+// The mode is only set for dlv, as other adapters do not use it.
+func (gd *GiDap) launchArgs() (string, map[string]any) {
+	req := "launch"
+	mode := ""
+	args := map[string]any{"cwd": gd.workDir()}
+	if gd.params.Dir != "" {
+		args["cwd"] = gd.params.Dir
+	}
+	if len(gd.params.Env) > 0 {
+		env := map[string]string{}
+		for _, ev := range gd.params.Env {
+			nm, val, _ := strings.Cut(ev, "=")
+			env[nm] = val
+		}
+		args["env"] = env
+	}
+	_, pargs := gd.params.SplitArgs()
+	pargs = slices.Clone(pargs)
+	switch gd.params.Mode {
+	case cdebug.Exec:
+		args["program"] = gd.path
+		mode = "exec"
+		args["args"] = pargs
+	case cdebug.Test:
+		args["program"] = gd.path
+		mode = "test"
+		if gd.params.TestName != "" {
+			pargs = append([]string{"-test.run", gd.params.TestName}, pargs...)
+		}
+		args["args"] = pargs
+	case cdebug.Attach:
+		req = "attach"
+		args["processId"] = gd.params.PID
+		mode = "local"
+	case cdebug.Core:
+		args["program"] = gd.path
+		mode = "core"
+		args["coreFilePath"] = gd.params.CoreFile
+	}
+	if gd.adapter.isDelve() {
+		args["mode"] = mode
+	}
+	maps.Copy(args, gd.adapter.LaunchArgs)
+	return req, args
+}
+
+// workDir returns the directory to run in, which is the exe path
+// if it is a directory, and otherwise its directory.
+func (gd *GiDap) workDir() string {
+	if st, err := os.Stat(gd.path); err == nil && st.IsDir() {
+		return gd.path
+	}
+	return filepath.Dir(gd.path)
+}
+
+// supports returns true if the adapter has given capability.
+func (gd *GiDap) supports(capability string) bool {
+	b, _ := gd.caps[capability].(bool)
+	return b
+}
+
+// handleEvent handles events from the adapter.
+func (gd *GiDap) handleEvent(event string, body json.RawMessage) {
+	switch event {
+	case "initialized":
+		gd.initOnce.Do(func() { close(gd.initialized) })
+	case "stopped":
+		ev := &StoppedEvent{}
+		json.Unmarshal(body, ev)
+		gd.mu.Lock()
+		if ev.ThreadID != 0 {
+			gd.curThread = ev.ThreadID
+		}
+		st := &cdebug.State{Thread: cdebug.Thread{ID: gd.curThread}}
+		gd.state.Running = false
+		gd.mu.Unlock()
+		gd.sendStop(st)
+	case "continued":
+		gd.mu.Lock()
+		gd.state.Running = true
+		gd.mu.Unlock()
+	case "exited":
+		ev := &ExitedEvent{}
+		json.Unmarshal(body, ev)
+		gd.mu.Lock()
+		gd.exitCode = ev.ExitCode
+		gd.mu.Unlock()
+	case "terminated":
+		gd.mu.Lock()
+		st := &cdebug.State{Exited: true, ExitStatus: gd.exitCode}
+		gd.state = *st
+		gd.mu.Unlock()
+		gd.sendStop(st)
+	case "output":
+		ev := &OutputEvent{}
+		json.Unmarshal(body, ev)
+		if ev.Category != "telemetry" && ev.Output != "" {
+			gd.WriteToConsole(ev.Output)
+		}
+	case "process":
+		ev := &ProcessEvent{}
+		json.Unmarshal(body, ev)
+		gd.mu.Lock()
+		gd.pid = ev.SystemProcessID
+		gd.mu.Unlock()
+	}
+}
+
+// sendStop sends given stop state to anyone waiting for it.
+func (gd *GiDap) sendStop(st *cdebug.State) {
+	select {
+	case gd.stops <- st:
+	default:
+	}
+}
+
+// drainStops discards any stop states from prior events.
+func (gd *GiDap) drainStops() {
+	for {
+		select {
+		case <-gd.stops:
+		default:
+			return
+		}
+	}
+}
+
+// waitStop waits for the process to stop or exit, and returns the state.
+func (gd *GiDap) waitStop() *cdebug.State {
+	var st *cdebug.State
+	select {
+	case st = <-gd.stops:
+	case <-gd.conn.Done():
+		gd.mu.Lock()
+		st = &cdebug.State{Exited: true, ExitStatus: gd.exitCode}
+		gd.mu.Unlock()
+	}
+	gd.mu.Lock()
+	gd.frames = make(map[frameKey]int)
+	gd.refs = make(map[*cdebug.Variable]int)
+	gd.mu.Unlock()
+	if !st.Exited {
+		gd.locate(&st.Thread)
+	}
+	gd.mu.Lock()
+	gd.state = *st
+	gd.mu.Unlock()
+	return st
+}
+
+// locate fills in the current location of given thread.
+func (gd *GiDap) locate(th *cdebug.Thread) {
+	sf, err := gd.Stack(th.ID, 1)
+	if err != nil || len(sf) == 0 {
+		return
+	}
+	fr := sf[0]
+	th.PC, th.File, th.Line, th.FPath, th.Func = fr.PC, fr.File, fr.Line, fr.FPath, fr.Func
+}
+
+// thread returns the current thread id.
+func (gd *GiDap) thread() int {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	return gd.curThread
+}
+
+// resume sends given request to resume execution, which is instead
+// configurationDone if it has not yet been sent, to start the program.
+func (gd *GiDap) resume(command string, args any) error {
+	gd.drainStops()
+	gd.mu.Lock()
+	gd.state.Running = true
+	gd.mu.Unlock()
+	if !gd.configDone {
+		gd.configDone = true
+		command, args = "configurationDone", nil
+	}
+	err := gd.conn.Request(command, args, nil, gd.timeout)
+	if err != nil {
+		gd.mu.Lock()
+		gd.state.Running = false
+		gd.mu.Unlock()
+	}
+	return err
+}
+
+// step sends given stepping request for the current thread,
+// and waits for the process to stop.
+func (gd *GiDap) step(command string, granularity string) (*cdebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if err := gd.resume(command, &ThreadArguments{ThreadID: gd.thread(), Granularity: granularity}); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	return gd.waitStop(), nil
+}
+
+// IsActive returns whether debugger is active and ready for commands
+func (gd *GiDap) IsActive() bool {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	if gd.conn == nil || !gd.active {
+		return false
+	}
+	select {
+	case <-gd.conn.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// Returns the pid of the process we are debugging.
+func (gd *GiDap) ProcessPid() int {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	if gd.pid == 0 {
+		return -1
+	}
+	return gd.pid
+}
+
+// LastModified returns the time that the process' executable was modified.
+func (gd *GiDap) LastModified() time.Time {
+	st, err := os.Stat(gd.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return st.ModTime()
+}
+
+// Detach detaches the debugger, optionally killing the process.
+func (gd *GiDap) Detach(killProcess bool) error {
+	var err error
+	if gd.conn != nil {
+		err = gd.conn.Request("disconnect", map[string]any{"terminateDebuggee": killProcess}, nil, gd.timeout)
+		gd.conn.Close()
+	}
+	gd.stopCmd()
+	return err
+}
+
+// Disconnect closes the connection to the adapter, leaving the
+// process running.
+func (gd *GiDap) Disconnect(cont bool) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	err := gd.conn.Request("disconnect", map[string]any{"terminateDebuggee": false}, nil, gd.timeout)
+	gd.conn.Close()
+	return err
+}
+
+// stopCmd waits for the adapter process to exit, killing it if needed.
+func (gd *GiDap) stopCmd() {
+	if gd.cmd == nil || gd.cmd.Process == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		gd.cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(gd.timeout):
+		gd.cmd.Process.Kill()
+	}
+	gd.cmd = nil
+}
+
+// Restarts program.
+func (gd *GiDap) Restart() error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	if !gd.supports("supportsRestartRequest") {
+		return gd.LogErr(NotSupportedErr)
+	}
+	return gd.LogErr(gd.conn.Request("restart", nil, nil, gd.timeout))
+}
+
+// GetState returns the current debugger state.
+func (gd *GiDap) GetState() (*cdebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	st := gd.state
+	gd.mu.Unlock()
+	return &st, nil
+}
+
+// Continue resumes process execution, starting it if this is the first call.
+func (gd *GiDap) Continue(all *cdebug.AllState) <-chan *cdebug.State {
+	if err := gd.StartedCheck(); err != nil {
+		return nil
+	}
+	sc := make(chan *cdebug.State)
+	go func() {
+		defer close(sc)
+		if err := gd.resume("continue", &ThreadArguments{ThreadID: gd.thread()}); err != nil {
+			gd.LogErr(err)
+			return
+		}
+		sc <- gd.waitStop()
+	}()
+	return sc
+}
+
+// StepOver continues to the next source line, not entering function calls.
+func (gd *GiDap) StepOver() (*cdebug.State, error) {
+	return gd.step("next", "")
+}
+
+// StepInto continues to the next source line, entering function calls.
+func (gd *GiDap) StepInto() (*cdebug.State, error) {
+	return gd.step("stepIn", "")
+}
+
+// StepOut continues to the return address of the current function
+func (gd *GiDap) StepOut() (*cdebug.State, error) {
+	return gd.step("stepOut", "")
+}
+
+// StepSingle steps a single cpu instruction.
+func (gd *GiDap) StepSingle() (*cdebug.State, error) {
+	if !gd.supports("supportsSteppingGranularity") {
+		return nil, gd.LogErr(NotSupportedErr)
+	}
+	return gd.step("next", "instruction")
+}
+
+// SwitchThread switches the current thread context.
+func (gd *GiDap) SwitchThread(threadID int) (*cdebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	gd.curThread = threadID
+	st := gd.state
+	gd.mu.Unlock()
+	st.Thread = cdebug.Thread{ID: threadID}
+	gd.locate(&st.Thread)
+	return &st, nil
+}
+
+// SwitchTask is the same as SwitchThread, as there are no tasks.
+func (gd *GiDap) SwitchTask(threadID int) (*cdebug.State, error) {
+	return gd.SwitchThread(threadID)
+}
+
+// Stop suspends the process. The state is updated by the stopped event.
+func (gd *GiDap) Stop() (*cdebug.State, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	err := gd.conn.Request("pause", &ThreadArguments{ThreadID: gd.thread()}, nil, gd.timeout)
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	return gd.GetState()
+}
+
+// GetBreak gets a breakpoint by ID.
+func (gd *GiDap) GetBreak(id int) (*cdebug.Break, error) {
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	bk, _ := cdebug.BreakByID(gd.breaks, id)
+	if bk == nil {
+		return nil, fmt.Errorf("breakpoint %d not found", id)
+	}
+	return bk, nil
+}
+
+// SetBreak sets a new breakpoint at given file and line number
+func (gd *GiDap) SetBreak(fname string, line int) (*cdebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	bk, _ := cdebug.BreakByFile(gd.breaks, fname, line)
+	if bk == nil {
+		bk = gd.newBreak(fname, line)
+	}
+	gd.mu.Unlock()
+	return bk, gd.syncBreaks(fname)
+}
+
+// newBreak adds a new breakpoint. Must be called under mutex.
+func (gd *GiDap) newBreak(fpath string, line int) *cdebug.Break {
+	gd.nextBreak++
+	bk := &cdebug.Break{ID: gd.nextBreak, On: true, FPath: fpath, Line: line}
+	bk.File = fsx.RelativeFilePath(fpath, gd.rootPath)
+	gd.breaks = append(gd.breaks, bk)
+	return bk
+}
+
+// ListBreaks gets all breakpoints.
+func (gd *GiDap) ListBreaks() ([]*cdebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	bks := make([]*cdebug.Break, len(gd.breaks))
+	for i, bk := range gd.breaks {
+		cb := *bk
+		bks[i] = &cb
+	}
+	cdebug.SortBreaks(bks)
+	return bks, nil
+}
+
+// ClearBreak deletes a breakpoint by ID.
+func (gd *GiDap) ClearBreak(id int) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	bk, i := cdebug.BreakByID(gd.breaks, id)
+	if bk != nil {
+		gd.breaks = slices.Delete(gd.breaks, i, i+1)
+	}
+	gd.mu.Unlock()
+	if bk == nil {
+		return nil
+	}
+	return gd.syncBreaks(bk.FPath)
+}
+
+// AmendBreak updates the conditions, trace and log message of the
+// breakpoint with the ID of the given one.
+func (gd *GiDap) AmendBreak(bk *cdebug.Break) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	ab, _ := cdebug.BreakByID(gd.breaks, bk.ID)
+	if ab != nil {
+		ab.Cond = bk.Cond
+		ab.HitCond = bk.HitCond
+		ab.Trace = bk.Trace
+		ab.LogMsg = bk.LogMsg
+	}
+	gd.mu.Unlock()
+	if ab == nil {
+		return gd.LogErr(fmt.Errorf("breakpoint %d not found", bk.ID))
+	}
+	return gd.syncBreaks(ab.FPath)
+}
+
+// SetWatch is not supported by debug adapters.
+func (gd *GiDap) SetWatch(expr string, threadID int, frame int) (*cdebug.Break, error) {
+	return nil, NotSupportedErr
+}
+
+// UpdateBreaks updates current breakpoints based on given list of breakpoints,
+// setting the breakpoints that are On in the adapter.
+func (gd *GiDap) UpdateBreaks(brk *[]*cdebug.Break) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	files := map[string]bool{}
+	for _, bk := range gd.breaks {
+		files[bk.FPath] = true
+	}
+	old := gd.breaks
+	gd.breaks = nil
+	for _, b := range *brk {
+		if !b.On {
+			continue
+		}
+		c, _ := cdebug.BreakByFile(old, b.FPath, b.Line)
+		if c != nil {
+			gd.breaks = append(gd.breaks, c)
+		} else {
+			c = gd.newBreak(b.FPath, b.Line)
+		}
+		c.Cond = b.Cond
+		c.HitCond = b.HitCond
+		c.Trace = b.Trace
+		c.LogMsg = b.LogMsg
+		b.ID = c.ID
+		b.File = c.File
+		files[b.FPath] = true
+	}
+	gd.mu.Unlock()
+	var errs []error
+	for _, fpath := range slices.Sorted(maps.Keys(files)) {
+		errs = append(errs, gd.syncBreaks(fpath))
+	}
+	cdebug.SortBreaks(*brk)
+	return errors.Join(errs...)
+}
+
+// syncBreaks sets the breakpoints for given file in the adapter.
+// Trace breakpoints are set as logpoints, and hit conditions
+// are only set if the adapter supports them.
+func (gd *GiDap) syncBreaks(fpath string) error {
+	args := &SetBreakpointsArguments{Source: Source{Name: filepath.Base(fpath), Path: fpath}}
+	var bks []*cdebug.Break
+	gd.mu.Lock()
+	for _, bk := range gd.breaks {
+		if bk.FPath != fpath {
+			continue
+		}
+		sb := SourceBreakpoint{Line: bk.Line, Condition: bk.Cond}
+		if gd.supports("supportsHitConditionalBreakpoints") {
+			sb.HitCondition = bk.HitCond
+		}
+		if bk.LogMsg != "" {
+			sb.LogMessage = bk.LogMsg // same {expr} syntax
+		} else if bk.Trace {
+			sb.LogMessage = fmt.Sprintf("Trace: %d File: %s:%d", bk.ID, bk.File, bk.Line)
+		}
+		args.Breakpoints = append(args.Breakpoints, sb)
+		bks = append(bks, bk)
+	}
+	gd.mu.Unlock()
+	if args.Breakpoints == nil {
+		args.Breakpoints = []SourceBreakpoint{}
+	}
+	resp := &SetBreakpointsResponse{}
+	if err := gd.conn.Request("setBreakpoints", args, resp, gd.timeout); err != nil {
+		return gd.LogErr(err)
+	}
+	for i, bp := range resp.Breakpoints {
+		if i < len(bks) && !bp.Verified && bp.Message != "" {
+			gd.WriteToConsole(fmt.Sprintf("Breakpoint %s:%d: %s\n", bks[i].File, bks[i].Line, bp.Message))
+		}
+	}
+	return nil
+}
+
+// Cancels a Next or Step call that was interrupted by a manual stop or by another breakpoint
+func (gd *GiDap) CancelNext() error {
+	return nil
+}
+
+// InitAllState initializes the given AllState with relevant info for
+// current state of things.  Does Not get AllVars
+func (gd *GiDap) InitAllState(all *cdebug.AllState) error {
+	all.CurThread = all.State.Thread.ID
+	all.CurTask = all.State.Task.ID
+	all.CurFrame = 0
+	th, err := gd.ListThreads()
+	if err != nil {
+		return err
+	}
+	all.Threads = th
+	all.Tasks = nil
+	sf, err := gd.Stack(all.CurThread, 100)
+	if err != nil {
+		return err
+	}
+	all.Stack = sf
+	all.Vars = nil
+	if len(sf) > 0 {
+		vr, err := gd.ListVars(all.CurThread, 0)
+		if err != nil {
+			return err
+		}
+		all.Vars = vr
+	}
+
+	all.CurBreak = 0
+	cf := all.StackFrame(0)
+	if cf != nil {
+		bk, _ := cdebug.BreakByFile(all.Breaks, cf.FPath, cf.Line)
+		if bk != nil {
+			all.CurBreak = bk.ID
+		}
+	}
+	return nil
+}
+
+// UpdateAllState updates the state for given thread id and
+// frame number (only info different from current results is updated).
+func (gd *GiDap) UpdateAllState(all *cdebug.AllState, threadID int, frame int) error {
+	update := false
+	if threadID != all.CurThread {
+		update = true
+		all.CurThread = threadID
+		sf, err := gd.Stack(all.CurThread, 100)
+		if err != nil {
+			return err
+		}
+		all.Stack = sf
+	}
+	if update || all.CurFrame != frame {
+		all.CurFrame = frame
+		vr, err := gd.ListVars(all.CurThread, all.CurFrame)
+		if err != nil {
+			return err
+		}
+		all.Vars = vr
+	}
+	return nil
+}
+
+// FindFrames looks through the Stacks of all Threads
+// for the closest Stack Frame to given file and line number.
+// File name search uses Contains to allow for paths to be searched.
+// Results are sorted by line number proximity to given line.
+func (gd *GiDap) FindFrames(all *cdebug.AllState, fname string, line int) ([]*cdebug.Frame, error) {
+	var err error
+	var fr []*cdebug.Frame
+	for _, th := range all.Threads {
+		sf, err := gd.Stack(th.ID, 100)
+		if err != nil {
+			break
+		}
+		for _, f := range sf {
+			if !strings.Contains(f.FPath, fname) {
+				continue
+			}
+			fr = append(fr, f)
+			break
+		}
+	}
+	sort.Slice(fr, func(i, j int) bool {
+		dsti := num.Abs(fr[i].Line - line)
+		dstj := num.Abs(fr[j].Line - line)
+		return dsti < dstj
+	})
+	return fr, err
+}
+
+// CurThreadID returns the current thread id from given state.
+func (gd *GiDap) CurThreadID(all *cdebug.AllState) int {
+	return all.CurThread
+}
+
+// ListThreads lists all threads, with their current locations.
+func (gd *GiDap) ListThreads() ([]*cdebug.Thread, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	resp := &ThreadsResponse{}
+	if err := gd.conn.Request("threads", nil, resp, gd.timeout); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	ths := make([]*cdebug.Thread, len(resp.Threads))
+	for i, dt := range resp.Threads {
+		th := &cdebug.Thread{ID: dt.ID, Func: dt.Name}
+		gd.locate(th)
+		ths[i] = th
+	}
+	return ths, nil
+}
+
+// GetThread gets a thread by its ID.
+func (gd *GiDap) GetThread(id int) (*cdebug.Thread, error) {
+	ths, err := gd.ListThreads()
+	if err != nil {
+		return nil, err
+	}
+	th, _ := cdebug.ThreadByID(ths, id)
+	if th == nil {
+		return nil, fmt.Errorf("thread %d not found", id)
+	}
+	return th, nil
+}
+
+// ListTasks returns nil, as there are no tasks.
+func (gd *GiDap) ListTasks() ([]*cdebug.Task, error) {
+	return nil, nil
+}
+
+// Stack returns the stack trace for given thread, up to given depth.
+func (gd *GiDap) Stack(threadID int, depth int) ([]*cdebug.Frame, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	resp := &StackTraceResponse{}
+	err := gd.conn.Request("stackTrace", &StackTraceArguments{ThreadID: threadID, Levels: depth}, resp, gd.timeout)
+	if err != nil {
+		return nil, gd.LogErr(err)
+	}
+	frs := gd.cvtStack(resp.StackFrames, threadID)
+	gd.mu.Lock()
+	if gd.frames == nil {
+		gd.frames = make(map[frameKey]int)
+	}
+	for i := range resp.StackFrames {
+		gd.frames[frameKey{threadID, i}] = resp.StackFrames[i].ID
+	}
+	gd.mu.Unlock()
+	return frs, nil
+}
+
+// frameID returns the adapter id of given frame depth in given thread.
+func (gd *GiDap) frameID(threadID int, depth int) (int, error) {
+	gd.mu.Lock()
+	id, ok := gd.frames[frameKey{threadID, depth}]
+	gd.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+	if _, err := gd.Stack(threadID, depth+1); err != nil {
+		return 0, err
+	}
+	gd.mu.Lock()
+	defer gd.mu.Unlock()
+	id, ok = gd.frames[frameKey{threadID, depth}]
+	if !ok {
+		return 0, fmt.Errorf("frame %d not found in thread %d", depth, threadID)
+	}
+	return id, nil
+}
+
+// scopes returns the variable scopes of given frame.
+func (gd *GiDap) scopes(threadID int, frame int) ([]Scope, error) {
+	fid, err := gd.frameID(threadID, frame)
+	if err != nil {
+		return nil, err
+	}
+	resp := &ScopesResponse{}
+	if err := gd.conn.Request("scopes", map[string]any{"frameId": fid}, resp, gd.timeout); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	return resp.Scopes, nil
+}
+
+// isGlobalScope returns true if given scope has global variables.
+func isGlobalScope(sc *Scope) bool {
+	return strings.Contains(strings.ToLower(sc.Name), "global")
+}
+
+// isLocalScope returns true if given scope has local variables (and args).
+func isLocalScope(sc *Scope) bool {
+	nm := strings.ToLower(sc.Name)
+	return !sc.Expensive && !strings.Contains(nm, "global") && !strings.Contains(nm, "register")
+}
+
+// ListGlobalVars lists the global variables, in the context of the
+// current thread, whose names contain filter.
+func (gd *GiDap) ListGlobalVars(filter string) ([]*cdebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	scs, err := gd.scopes(gd.thread(), 0)
+	if err != nil {
+		return nil, err
+	}
+	var vrs []*cdebug.Variable
+	for i := range scs {
+		if !isGlobalScope(&scs[i]) {
+			continue
+		}
+		vs, err := gd.variables(scs[i].VariablesReference, &gd.params.VarList, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, vr := range vs {
+			if strings.Contains(vr.Name, filter) {
+				vrs = append(vrs, vr)
+			}
+		}
+	}
+	cdebug.SortVars(vrs)
+	return vrs, nil
+}
+
+// ListVars lists all local variables in scope, including args
+func (gd *GiDap) ListVars(threadID int, frame int) ([]*cdebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	scs, err := gd.scopes(threadID, frame)
+	if err != nil {
+		return nil, err
+	}
+	var vrs []*cdebug.Variable
+	for i := range scs {
+		if !isLocalScope(&scs[i]) {
+			continue
+		}
+		vs, err := gd.variables(scs[i].VariablesReference, &gd.params.VarList, 0)
+		if err != nil {
+			return nil, err
+		}
+		vrs = append(vrs, vs...)
+	}
+	cdebug.SortVars(vrs)
+	return vrs, nil
+}
+
+// listVariables returns the variables for given reference.
+func (gd *GiDap) listVariables(ref int) ([]Variable, error) {
+	resp := &VariablesResponse{}
+	if err := gd.conn.Request("variables", map[string]any{"variablesReference": ref}, resp, gd.timeout); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	return resp.Variables, nil
+}
+
+// variables returns the variables for given reference, recursively
+// getting their children up to the limits in given params.
+func (gd *GiDap) variables(ref int, vp *cdebug.VarParams, depth int) ([]*cdebug.Variable, error) {
+	dvs, err := gd.listVariables(ref)
+	if err != nil {
+		return nil, err
+	}
+	vrs := make([]*cdebug.Variable, 0, len(dvs))
+	nel := 0
+	for i := range dvs {
+		dv := &dvs[i]
+		if strings.HasPrefix(dv.Name, "[") {
+			if vp.MaxArrayValues >= 0 && nel >= vp.MaxArrayValues {
+				continue
+			}
+			nel++
+		}
+		vr := gd.cvtVar(dv)
+		vrs = append(vrs, vr)
+		if dv.VariablesReference == 0 || depth >= vp.MaxRecurse || (vr.Kind == syms.Ptr && !vp.FollowPointers) {
+			continue
+		}
+		kids, err := gd.variables(dv.VariablesReference, vp, depth+1)
+		if err != nil {
+			continue
+		}
+		for _, k := range kids {
+			vr.AddChild(k)
+		}
+	}
+	return vrs, nil
+}
+
+// GetVar returns a variable based on expression in the context of given
+// thread and frame.
+func (gd *GiDap) GetVar(expr string, threadID int, frame int) (*cdebug.Variable, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	args := &EvaluateArguments{Expression: expr, Context: "watch"}
+	if fid, err := gd.frameID(threadID, frame); err == nil {
+		args.FrameID = fid
+	}
+	resp := &EvaluateResponse{}
+	if err := gd.conn.Request("evaluate", args, resp, gd.timeout); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	dv := &Variable{Name: expr, Value: resp.Result, Type: resp.Type, VariablesReference: resp.VariablesReference, MemoryReference: resp.MemoryReference}
+	vr := gd.cvtVar(dv)
+	if dv.VariablesReference > 0 {
+		kids, err := gd.variables(dv.VariablesReference, &gd.params.GetVar, 1)
+		if err == nil {
+			for _, k := range kids {
+				vr.AddChild(k)
+			}
+		}
+	}
+	return vr, nil
+}
+
+// FollowPtr fills in the children of given Variable
+// with retrieved values.
+func (gd *GiDap) FollowPtr(vr *cdebug.Variable) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	gd.mu.Lock()
+	ref := gd.refs[vr]
+	gd.mu.Unlock()
+	if ref == 0 {
+		return fmt.Errorf("FollowPtr: variable %s has no children to get", vr.Name)
+	}
+	kids, err := gd.variables(ref, &gd.params.GetVar, 1)
+	if err != nil {
+		return err
+	}
+	vr.DeleteChildren()
+	for _, k := range kids {
+		vr.AddChild(k)
+	}
+	return nil
+}
+
+// SetVar sets the value of a variable in given thread and frame.
+func (gd *GiDap) SetVar(name, value string, threadID int, frame int) error {
+	if err := gd.StartedCheck(); err != nil {
+		return err
+	}
+	scs, err := gd.scopes(threadID, frame)
+	if err != nil {
+		return err
+	}
+	for _, sc := range scs {
+		dvs, err := gd.listVariables(sc.VariablesReference)
+		if err != nil {
+			continue
+		}
+		if !slices.ContainsFunc(dvs, func(dv Variable) bool { return dv.Name == name }) {
+			continue
+		}
+		args := &SetVariableArguments{VariablesReference: sc.VariablesReference, Name: name, Value: value}
+		return gd.LogErr(gd.conn.Request("setVariable", args, nil, gd.timeout))
+	}
+	return gd.LogErr(fmt.Errorf("variable %s not found", name))
+}
+
+// ListSources lists all source files in the process matching filter.
+func (gd *GiDap) ListSources(filter string) ([]string, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	if !gd.supports("supportsLoadedSourcesRequest") {
+		return nil, NotSupportedErr
+	}
+	resp := &LoadedSourcesResponse{}
+	if err := gd.conn.Request("loadedSources", nil, resp, gd.timeout); err != nil {
+		return nil, gd.LogErr(err)
+	}
+	var srcs []string
+	for _, src := range resp.Sources {
+		if strings.Contains(src.Path, filter) {
+			srcs = append(srcs, src.Path)
+		}
+	}
+	return srcs, nil
+}
+
+// ListFuncs is not supported by DAP.
+func (gd *GiDap) ListFuncs(filter string) ([]string, error) {
+	return nil, NotSupportedErr
+}
+
+// ListTypes is not supported by DAP.
+func (gd *GiDap) ListTypes(filter string) ([]string, error) {
+	return nil, NotSupportedErr
+}
+
+// freeAddr returns a free local host:port address.
+func freeAddr() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	return ln.Addr().String(), nil
+}
+
+// pipeRWC combines the stdout and stdin pipes of an adapter process.
+type pipeRWC struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (p *pipeRWC) Close() error {
+	werr := p.WriteCloser.Close()
+	rerr := p.ReadCloser.Close()
+	return errors.Join(werr, rerr)
+}
@@ -0,0 +1,292 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/text/parse/syms"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockAdapter is a minimal debug adapter for a program with one thread,
+// stopped in main.main, for testing the client.
+type mockAdapter struct {
+	conn *Conn
+
+	mu     sync.Mutex
+	line   int                           // current line
+	breaks map[string][]SourceBreakpoint // set breakpoints by file
+	sets   map[string]string             // set variables
+	launch map[string]any                // launch args
+}
+
+func newMockAdapter(rwc net.Conn) *mockAdapter {
+	ma := &mockAdapter{line: 10, breaks: map[string][]SourceBreakpoint{}, sets: map[string]string{}}
+	ma.conn = NewConn(rwc, ma.handle, nil)
+	return ma
+}
+
+func (ma *mockAdapter) handle(command string, args json.RawMessage) (any, error) {
+	ma.mu.Lock()
+	defer ma.mu.Unlock()
+	switch command {
+	case "initialize":
+		return map[string]any{"supportsConfigurationDoneRequest": true, "supportsSteppingGranularity": true, "supportsHitConditionalBreakpoints": true}, nil
+	case "launch":
+		json.Unmarshal(args, &ma.launch)
+		ma.conn.Event("initialized", nil)
+		return nil, nil
+	case "setBreakpoints":
+		ba := &SetBreakpointsArguments{}
+		json.Unmarshal(args, ba)
+		ma.breaks[ba.Source.Path] = ba.Breakpoints
+		resp := &SetBreakpointsResponse{Breakpoints: []Breakpoint{}}
+		for _, sb := range ba.Breakpoints {
+			bp := Breakpoint{Verified: sb.Line != 99, Line: sb.Line}
+			if !bp.Verified {
+				bp.Message = "no code at line"
+			}
+			resp.Breakpoints = append(resp.Breakpoints, bp)
+		}
+		return resp, nil
+	case "configurationDone":
+		ma.conn.Event("process", &ProcessEvent{Name: "main", SystemProcessID: 42})
+		ma.conn.Event("output", &OutputEvent{Category: "stdout", Output: "hello\n"})
+		ma.conn.Event("stopped", &StoppedEvent{Reason: "breakpoint", ThreadID: 1})
+		return nil, nil
+	case "next":
+		ma.line++
+		ma.conn.Event("stopped", &StoppedEvent{Reason: "step", ThreadID: 1})
+		return nil, nil
+	case "continue":
+		ma.conn.Event("exited", &ExitedEvent{ExitCode: 3})
+		ma.conn.Event("terminated", nil)
+		return nil, nil
+	case "threads":
+		return &ThreadsResponse{Threads: []Thread{{ID: 1, Name: "main"}}}, nil
+	case "stackTrace":
+		sa := &StackTraceArguments{}
+		json.Unmarshal(args, sa)
+		frs := []StackFrame{
+			{ID: 1000, Name: "main.main", Source: &Source{Path: "/proj/main.go"}, Line: ma.line, InstructionPointerReference: "0x10"},
+			{ID: 1001, Name: "runtime.main", Source: &Source{Path: "/go/proc.go"}, Line: 250},
+		}
+		if sa.Levels > 0 && sa.Levels < len(frs) {
+			frs = frs[:sa.Levels]
+		}
+		return &StackTraceResponse{StackFrames: frs}, nil
+	case "scopes":
+		return &ScopesResponse{Scopes: []Scope{{Name: "Locals", VariablesReference: 1}, {Name: "Globals", VariablesReference: 2}}}, nil
+	case "variables":
+		va := map[string]int{}
+		json.Unmarshal(args, &va)
+		switch va["variablesReference"] {
+		case 1:
+			return &VariablesResponse{Variables: []Variable{
+				{Name: "x", Value: "5", Type: "int"},
+				{Name: "s", Value: "main.S {A: 1}", Type: "main.S", VariablesReference: 3},
+				{Name: "p", Value: "*main.S {A: 1}", Type: "*main.S", VariablesReference: 4, MemoryReference: "0xc000"},
+			}}, nil
+		case 2:
+			return &VariablesResponse{Variables: []Variable{{Name: "main.g", Value: "2", Type: "int"}}}, nil
+		case 3:
+			return &VariablesResponse{Variables: []Variable{{Name: "A", Value: "1", Type: "int"}}}, nil
+		case 4:
+			return &VariablesResponse{Variables: []Variable{{Name: "", Value: "main.S {A: 1}", Type: "main.S", VariablesReference: 3}}}, nil
+		}
+		return nil, fmt.Errorf("unknown reference")
+	case "evaluate":
+		ea := &EvaluateArguments{}
+		json.Unmarshal(args, ea)
+		return &EvaluateResponse{Result: "6", Type: "int"}, nil
+	case "setVariable":
+		sa := &SetVariableArguments{}
+		json.Unmarshal(args, sa)
+		ma.sets[sa.Name] = sa.Value
+		return map[string]any{"value": sa.Value}, nil
+	case "disconnect":
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported request: %s", command)
+}
+
+func TestGiDap(t *testing.T) {
+	cc, sc := net.Pipe()
+	ma := newMockAdapter(sc)
+	defer ma.conn.Close()
+
+	stats := make(chan cdebug.Status, 4)
+	pars := cdebug.DefaultParams
+	pars.Args = []string{"-v", "--", "arg1"}
+	pars.Env = []string{"DEBUG=1"}
+	pars.Dir = "/proj/run"
+	pars.StatFunc = func(stat cdebug.Status) { stats <- stat }
+	gd := &GiDap{adapter: Adapter{Cmd: "mock", LaunchArgs: map[string]any{"mode": "debug"}}}
+	assert.False(t, gd.IsActive())
+	gd.StartConn(cc, "/proj/main", "/proj", nil, &pars)
+	select {
+	case stat := <-stats:
+		if !assert.Equal(t, cdebug.Ready, stat) {
+			return
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Ready")
+	}
+	assert.True(t, gd.IsActive())
+	assert.Equal(t, "/proj/main", ma.launch["program"])
+	assert.Equal(t, "debug", ma.launch["mode"])
+	assert.Equal(t, []any{"arg1"}, ma.launch["args"])
+	assert.Equal(t, "/proj/run", ma.launch["cwd"])
+	assert.Equal(t, map[string]any{"DEBUG": "1"}, ma.launch["env"])
+
+	// breakpoints
+	brks := []*cdebug.Break{{On: true, FPath: "/proj/main.go", Line: 10, Cond: "x > 2"}, {On: true, FPath: "/proj/main.go", Line: 99, HitCond: "> 2"},
+		{FPath: "/proj/util.go", Line: 5}, {On: true, FPath: "/proj/util.go", Line: 3, Trace: true}, {On: true, FPath: "/proj/util.go", Line: 7, LogMsg: "x is {x}"}}
+	assert.NoError(t, gd.UpdateBreaks(&brks))
+	bk, _ := cdebug.BreakByFile(brks, "/proj/main.go", 10)
+	assert.Equal(t, 1, bk.ID)
+	assert.Equal(t, "main.go", bk.File)
+	bk, _ = cdebug.BreakByFile(brks, "/proj/util.go", 5)
+	assert.Equal(t, 0, bk.ID) // not on
+	assert.Equal(t, []SourceBreakpoint{{Line: 10, Condition: "x > 2"}, {Line: 99, HitCondition: "> 2"}}, ma.breaks["/proj/main.go"])
+	if assert.Len(t, ma.breaks["/proj/util.go"], 2) {
+		assert.Contains(t, ma.breaks["/proj/util.go"][0].LogMessage, "util.go:3")
+		assert.Equal(t, "x is {x}", ma.breaks["/proj/util.go"][1].LogMessage)
+	}
+	lb, err := gd.ListBreaks()
+	assert.NoError(t, err)
+	assert.Len(t, lb, 4)
+	bk, _ = cdebug.BreakByFile(lb, "/proj/main.go", 10)
+	bk.Cond = ""
+	bk.LogMsg = "at {x}"
+	assert.NoError(t, gd.AmendBreak(bk))
+	assert.Equal(t, []SourceBreakpoint{{Line: 10, LogMessage: "at {x}"}, {Line: 99, HitCondition: "> 2"}}, ma.breaks["/proj/main.go"])
+	_, err = gd.SetWatch("x", 1, 0)
+	assert.ErrorIs(t, err, NotSupportedErr)
+	assert.NoError(t, gd.ClearBreak(2))
+	assert.Len(t, ma.breaks["/proj/main.go"], 1)
+	bk, err = gd.SetBreak("/proj/main.go", 20)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, bk.ID)
+	assert.Len(t, ma.breaks["/proj/main.go"], 2)
+
+	// run to first stop
+	var st *cdebug.State
+	for st = range gd.Continue(nil) {
+	}
+	if !assert.NotNil(t, st) {
+		return
+	}
+	assert.False(t, st.Exited)
+	assert.Equal(t, 1, st.Thread.ID)
+	assert.Equal(t, 10, st.Thread.Line)
+	assert.Equal(t, "main.go", st.Thread.File)
+	assert.Equal(t, 42, gd.ProcessPid())
+
+	all := &cdebug.AllState{}
+	all.State = *st
+	all.Breaks = brks
+	assert.NoError(t, gd.InitAllState(all))
+	assert.Equal(t, 1, gd.CurThreadID(all))
+	assert.Len(t, all.Threads, 1)
+	if assert.Len(t, all.Stack, 2) {
+		assert.Equal(t, "main.main", all.Stack[0].Func)
+		assert.Equal(t, uint64(0x10), all.Stack[0].PC)
+		assert.Equal(t, 1, all.Stack[1].Depth)
+	}
+	assert.Equal(t, 1, all.CurBreak)
+
+	// variables
+	if assert.Len(t, all.Vars, 3) {
+		p, s, x := all.Vars[0], all.Vars[1], all.Vars[2]
+		assert.Equal(t, "p", p.Name)
+		assert.Equal(t, syms.Ptr, p.Kind)
+		assert.Equal(t, uintptr(0xc000), p.Addr)
+		assert.Equal(t, 0, p.NumChildren())
+		assert.Equal(t, syms.Struct, s.Kind)
+		if assert.Equal(t, 1, s.NumChildren()) {
+			assert.Equal(t, "1", s.Child(0).(*cdebug.Variable).Value)
+		}
+		assert.Equal(t, syms.Primitive, x.Kind)
+		assert.NoError(t, gd.FollowPtr(p))
+		assert.Equal(t, 1, p.NumChildren())
+	}
+	gv, err := gd.ListGlobalVars("g")
+	assert.NoError(t, err)
+	assert.Len(t, gv, 1)
+	vr, err := gd.GetVar("x+1", 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "6", vr.Value)
+	assert.NoError(t, gd.SetVar("x", "7", 1, 0))
+	assert.Equal(t, "7", ma.sets["x"])
+	assert.Error(t, gd.SetVar("nope", "1", 1, 0))
+	_, err = gd.ListFuncs("")
+	assert.ErrorIs(t, err, NotSupportedErr)
+
+	// stepping
+	st, err = gd.StepOver()
+	assert.NoError(t, err)
+	assert.Equal(t, 11, st.Thread.Line)
+	st, err = gd.StepSingle()
+	assert.NoError(t, err)
+	assert.Equal(t, 12, st.Thread.Line)
+	assert.ErrorIs(t, gd.Restart(), NotSupportedErr)
+
+	// run to exit
+	for st = range gd.Continue(all) {
+	}
+	if !assert.NotNil(t, st) {
+		return
+	}
+	assert.True(t, st.Exited)
+	assert.Equal(t, 3, st.ExitStatus)
+
+	assert.NoError(t, gd.Detach(true))
+	assert.False(t, gd.IsActive())
+}
+
+func TestVarKind(t *testing.T) {
+	tests := []struct {
+		v    Variable
+		kind syms.Kinds
+	}{
+		{Variable{Type: "int"}, syms.Primitive},
+		{Variable{Type: "*main.S"}, syms.Ptr},
+		{Variable{Type: "char *"}, syms.Ptr},
+		{Variable{Type: "[]int"}, syms.List},
+		{Variable{Type: "[3]int"}, syms.Array},
+		{Variable{Type: "list", IndexedVariables: 3}, syms.Array},
+		{Variable{Type: "map[string]int"}, syms.Map},
+		{Variable{Type: "str"}, syms.String},
+		{Variable{Type: "Point", VariablesReference: 2}, syms.Struct},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.kind, varKind(&test.v), test.v.Type)
+	}
+}
+
+func TestLaunchArgsCore(t *testing.T) {
+	pars := cdebug.DefaultParams
+	pars.Mode = cdebug.Core
+	pars.CoreFile = "/proj/core.1234"
+	gd := &GiDap{adapter: Adapter{Cmd: "/go/bin/dlv"}, path: "/proj/main", params: pars}
+	req, args := gd.launchArgs()
+	assert.Equal(t, "launch", req)
+	assert.Equal(t, "core", args["mode"])
+	assert.Equal(t, "/proj/main", args["program"])
+	assert.Equal(t, "/proj/core.1234", args["coreFilePath"])
+
+	gd.adapter = Adapter{Cmd: "lldb-dap"}
+	_, args = gd.launchArgs()
+	assert.NotContains(t, args, "mode")
+	assert.Equal(t, "/proj/core.1234", args["coreFilePath"])
+}
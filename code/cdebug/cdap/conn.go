@@ -0,0 +1,152 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"cogentcore.org/cogent/code/baseproto"
+)
+
+// ErrClosed is returned for requests on a closed connection.
+var ErrClosed = errors.New("cdap: connection closed")
+
+// message is a DAP request, response or event.
+type message struct {
+	Seq        int             `json:"seq"`
+	Type       string          `json:"type"`
+	Command    string          `json:"command,omitempty"`
+	Arguments  json.RawMessage `json:"arguments,omitempty"`
+	RequestSeq int             `json:"request_seq,omitempty"`
+	Success    bool            `json:"success,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	Event      string          `json:"event,omitempty"`
+}
+
+// Handler handles incoming requests on a [Conn]. The returned body
+// is sent back in a successful response, or the error in a failed one.
+type Handler func(command string, args json.RawMessage) (any, error)
+
+// EventHandler handles incoming events on a [Conn]. It is called
+// from the connection read goroutine and must not block.
+type EventHandler func(event string, body json.RawMessage)
+
+// Conn is a connection using the DAP base protocol, where each message
+// has a Content-Length header. It is symmetric, and is used for both
+// the client and (in tests) the adapter side.
+type Conn struct {
+	conn    *baseproto.Conn[message]
+	handler Handler
+	events  EventHandler
+}
+
+// NewConn returns a new connection reading and writing given stream,
+// with given handlers for incoming requests and events (can be nil).
+// It starts a goroutine reading incoming messages.
+func NewConn(rwc io.ReadWriteCloser, handler Handler, events EventHandler) *Conn {
+	c := &Conn{handler: handler, events: events}
+	c.conn = baseproto.NewConn(rwc, c.dispatch)
+	return c
+}
+
+// Request sends a request and waits up to timeout for the response,
+// whose body is decoded into body (if non-nil). A zero timeout waits forever.
+// A failed response is returned as an error with the response message.
+func (c *Conn) Request(command string, args, body any, timeout time.Duration) error {
+	resp, err := c.conn.Call(func(seq int64) (*message, error) {
+		msg := &message{Seq: int(seq), Type: "request", Command: command}
+		if args != nil {
+			b, err := json.Marshal(args)
+			if err != nil {
+				return nil, err
+			}
+			msg.Arguments = b
+		}
+		return msg, nil
+	}, timeout)
+	switch {
+	case errors.Is(err, baseproto.ErrClosed):
+		return ErrClosed
+	case errors.Is(err, baseproto.ErrTimeout):
+		return fmt.Errorf("cdap: %s timed out after %v", command, timeout)
+	case err != nil:
+		return err
+	case !resp.Success:
+		return fmt.Errorf("cdap: %s failed: %s", command, resp.Message)
+	case body == nil || len(resp.Body) == 0:
+		return nil
+	}
+	return json.Unmarshal(resp.Body, body)
+}
+
+// Event sends an event, which has no response.
+func (c *Conn) Event(event string, body any) error {
+	msg := &message{Type: "event", Event: event}
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		msg.Body = b
+	}
+	msg.Seq = int(c.conn.NextID())
+	return c.conn.Write(msg)
+}
+
+// Close closes the connection and the underlying stream.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Conn) Done() <-chan struct{} {
+	return c.conn.Done()
+}
+
+// dispatch handles an incoming message, returning the request seq of a response.
+func (c *Conn) dispatch(msg *message) (int64, bool) {
+	switch msg.Type {
+	case "request":
+		go c.reply(msg)
+	case "event":
+		if c.events != nil {
+			c.events(msg.Event, msg.Body)
+		}
+	case "response":
+		return int64(msg.RequestSeq), true
+	}
+	return 0, false
+}
+
+// reply handles an incoming request and sends the response.
+func (c *Conn) reply(req *message) {
+	resp := &message{Type: "response", RequestSeq: req.Seq, Command: req.Command}
+	var res any
+	err := fmt.Errorf("unsupported request: %s", req.Command)
+	if c.handler != nil {
+		res, err = c.handler(req.Command, req.Arguments)
+	}
+	if err != nil {
+		resp.Message = err.Error()
+	} else {
+		resp.Success = true
+		if res != nil {
+			b, err := json.Marshal(res)
+			if err != nil {
+				resp.Success = false
+				resp.Message = err.Error()
+			} else {
+				resp.Body = b
+			}
+		}
+	}
+	resp.Seq = int(c.conn.NextID())
+	c.conn.Write(resp)
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdap
+
+import (
+	"strconv"
+	"strings"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/text/parse/syms"
+)
+
+// Converts DAP types into cdebug types
+
+func (gd *GiDap) cvtFrame(ds *StackFrame, threadID int) *cdebug.Frame {
+	if ds == nil {
+		return nil
+	}
+	fr := &cdebug.Frame{}
+	fr.ThreadID = threadID
+	fr.PC = parseAddr(ds.InstructionPointerReference)
+	if ds.Source != nil {
+		fr.FPath = ds.Source.Path
+		fr.File = fsx.RelativeFilePath(ds.Source.Path, gd.rootPath)
+	}
+	fr.Line = ds.Line
+	fr.Func = ds.Name
+	return fr
+}
+
+func (gd *GiDap) cvtStack(ds []StackFrame, threadID int) []*cdebug.Frame {
+	if len(ds) == 0 {
+		return nil
+	}
+	vr := make([]*cdebug.Frame, len(ds))
+	for i := range ds {
+		vr[i] = gd.cvtFrame(&ds[i], threadID)
+		vr[i].Depth = i
+	}
+	return vr
+}
+
+// cvtVar converts given variable, recording its reference for
+// getting its children later.
+func (gd *GiDap) cvtVar(ds *Variable) *cdebug.Variable {
+	vr := cdebug.NewVariable()
+	vr.SetName(ds.Name)
+	vr.FullTypeStr = ds.Type
+	vr.TypeStr = ds.Type
+	vr.Kind = varKind(ds)
+	vr.Value = ds.Value
+	vr.ElementValue = ds.Value
+	vr.Addr = uintptr(parseAddr(ds.MemoryReference))
+	vr.Len = int64(ds.IndexedVariables)
+	vr.Dbg = gd
+	if ds.VariablesReference > 0 {
+		gd.mu.Lock()
+		if gd.refs != nil {
+			gd.refs[vr] = ds.VariablesReference
+		}
+		gd.mu.Unlock()
+	}
+	return vr
+}
+
+// varKind guesses the kind of given variable from its type,
+// which is language specific.
+func varKind(ds *Variable) syms.Kinds {
+	typ := ds.Type
+	switch {
+	case strings.HasPrefix(typ, "*") || strings.HasSuffix(typ, "*"):
+		return syms.Ptr
+	case strings.HasPrefix(typ, "[]"):
+		return syms.List
+	case strings.HasPrefix(typ, "map[") || typ == "dict":
+		return syms.Map
+	case strings.HasPrefix(typ, "["), ds.IndexedVariables > 0:
+		return syms.Array
+	case typ == "string" || typ == "str":
+		return syms.String
+	case ds.VariablesReference > 0:
+		return syms.Struct
+	}
+	return syms.Primitive
+}
+
+// parseAddr parses a hex (0x) or decimal memory reference.
+func parseAddr(ref string) uint64 {
+	if ref == "" {
+		return 0
+	}
+	a, _ := strconv.ParseUint(ref, 0, 64)
+	return a
+}
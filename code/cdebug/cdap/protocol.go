@@ -0,0 +1,170 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdap
+
+// This file contains the subset of the Debug Adapter Protocol types
+// that are used by the client. See
+// https://microsoft.github.io/debug-adapter-protocol/specification
+
+// Source is a source file known to the debug adapter.
+type Source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// SourceBreakpoint is a breakpoint requested in a source file.
+type SourceBreakpoint struct {
+	Line         int    `json:"line"`
+	Condition    string `json:"condition,omitempty"`
+	HitCondition string `json:"hitCondition,omitempty"`
+	LogMessage   string `json:"logMessage,omitempty"`
+}
+
+// SetBreakpointsArguments are the arguments of the setBreakpoints request,
+// which replaces all breakpoints in the given source.
+type SetBreakpointsArguments struct {
+	Source      Source             `json:"source"`
+	Breakpoints []SourceBreakpoint `json:"breakpoints"`
+}
+
+// Breakpoint is the adapter's information about a breakpoint.
+type Breakpoint struct {
+	ID       int     `json:"id,omitempty"`
+	Verified bool    `json:"verified"`
+	Message  string  `json:"message,omitempty"`
+	Source   *Source `json:"source,omitempty"`
+	Line     int     `json:"line,omitempty"`
+}
+
+// SetBreakpointsResponse is the body of the setBreakpoints response,
+// with one entry for each requested breakpoint, in the same order.
+type SetBreakpointsResponse struct {
+	Breakpoints []Breakpoint `json:"breakpoints"`
+}
+
+// Thread is a thread in the debuggee.
+type Thread struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ThreadsResponse is the body of the threads response.
+type ThreadsResponse struct {
+	Threads []Thread `json:"threads"`
+}
+
+// ThreadArguments are the arguments for requests that act on one thread,
+// such as continue, next, stepIn, stepOut and pause.
+type ThreadArguments struct {
+	ThreadID    int    `json:"threadId"`
+	Granularity string `json:"granularity,omitempty"`
+}
+
+// StackTraceArguments are the arguments of the stackTrace request.
+type StackTraceArguments struct {
+	ThreadID   int `json:"threadId"`
+	StartFrame int `json:"startFrame"`
+	Levels     int `json:"levels"`
+}
+
+// StackFrame is one frame in a stack trace.
+type StackFrame struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Source *Source `json:"source,omitempty"`
+	Line   int     `json:"line"`
+	Column int     `json:"column"`
+
+	// InstructionPointerReference is the memory address of the frame,
+	// typically in hex.
+	InstructionPointerReference string `json:"instructionPointerReference,omitempty"`
+}
+
+// StackTraceResponse is the body of the stackTrace response.
+type StackTraceResponse struct {
+	StackFrames []StackFrame `json:"stackFrames"`
+}
+
+// Scope is a named group of variables in a stack frame, e.g., Locals.
+type Scope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+// ScopesResponse is the body of the scopes response.
+type ScopesResponse struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+// Variable is a variable, whose children (if any) are obtained
+// with a variables request for its VariablesReference.
+type Variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+	NamedVariables     int    `json:"namedVariables,omitempty"`
+	IndexedVariables   int    `json:"indexedVariables,omitempty"`
+	MemoryReference    string `json:"memoryReference,omitempty"`
+}
+
+// VariablesResponse is the body of the variables response.
+type VariablesResponse struct {
+	Variables []Variable `json:"variables"`
+}
+
+// EvaluateArguments are the arguments of the evaluate request.
+type EvaluateArguments struct {
+	Expression string `json:"expression"`
+	FrameID    int    `json:"frameId,omitempty"`
+	Context    string `json:"context,omitempty"`
+}
+
+// EvaluateResponse is the body of the evaluate response.
+type EvaluateResponse struct {
+	Result             string `json:"result"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+	MemoryReference    string `json:"memoryReference,omitempty"`
+}
+
+// SetVariableArguments are the arguments of the setVariable request.
+type SetVariableArguments struct {
+	VariablesReference int    `json:"variablesReference"`
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+}
+
+// LoadedSourcesResponse is the body of the loadedSources response.
+type LoadedSourcesResponse struct {
+	Sources []Source `json:"sources"`
+}
+
+// StoppedEvent is the body of the stopped event.
+type StoppedEvent struct {
+	Reason            string `json:"reason"`
+	Description       string `json:"description,omitempty"`
+	ThreadID          int    `json:"threadId,omitempty"`
+	AllThreadsStopped bool   `json:"allThreadsStopped,omitempty"`
+	HitBreakpointIDs  []int  `json:"hitBreakpointIds,omitempty"`
+}
+
+// ExitedEvent is the body of the exited event.
+type ExitedEvent struct {
+	ExitCode int `json:"exitCode"`
+}
+
+// OutputEvent is the body of the output event.
+type OutputEvent struct {
+	Category string `json:"category,omitempty"`
+	Output   string `json:"output"`
+}
+
+// ProcessEvent is the body of the process event.
+type ProcessEvent struct {
+	Name            string `json:"name"`
+	SystemProcessID int    `json:"systemProcessId,omitempty"`
+}
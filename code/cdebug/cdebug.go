@@ -68,7 +68,7 @@ type GiDebug interface {
 	GetState() (*State, error)
 
 	// Continue resumes process execution.  The channel will block until the
-	// process stops by any means.  Tracepoints are automatically handled by
+	// process stops by any means.  Tracepoints and logpoints are handled by
 	// the debugger, and do not appear.  Typically there is just one State
 	// in the channel, but perhaps there could be more -- use a range to iterate
 	// over all items in the channel -- it will close after data is sent.
@@ -109,9 +109,14 @@ type GiDebug interface {
 	// ClearBreak deletes a breakpoint by ID.
 	ClearBreak(id int) error
 
-	// AmmendBreak updates the Condition and Trace information
-	// for the given breakpoint
-	AmendBreak(id int, fname string, line int, cond string, trace bool) error
+	// AmendBreak updates the Cond, HitCond, Trace and LogMsg information
+	// for the breakpoint with the ID of the given one
+	AmendBreak(bk *Break) error
+
+	// SetWatch sets a new watchpoint that stops when the memory of the
+	// variable given by the expression, in the given thread and frame,
+	// is written.  Returns an error if watchpoints are not supported.
+	SetWatch(expr string, threadID int, frame int) (*Break, error)
 
 	// UpdateBreaks updates current breakpoints based on given list of breakpoints.
 	// first gets the current list, and does actions to ensure that the list is set.
@@ -193,7 +198,7 @@ type GiDebug interface {
 }
 
 // Modes are different modes of running the debugger
-type Modes int32
+type Modes int32 //enums:enum
 
 const (
 	// Exec means debug a standard executable program
@@ -204,4 +209,8 @@ const (
 
 	// Attach means attach to an already-running process
 	Attach
+
+	// Core means inspect the core dump of a crashed process, post-mortem,
+	// in which case the process cannot be run, so it is read-only
+	Core
 )
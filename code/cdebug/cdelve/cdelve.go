@@ -9,8 +9,10 @@ package cdelve
 import (
 	"fmt"
 	"log"
+	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -43,6 +45,8 @@ type GiDelve struct {
 	lastEvalScope *api.EvalScope           // last used EvalScope
 	statFunc      func(stat cdebug.Status) // status function
 	params        cdebug.Params            // local copy of initial params
+	logMsgs       map[int]string           // logpoint messages by breakpoint id
+	waitReasons   []string                 // names of goroutine wait reasons, from the runtime
 }
 
 // NewGiDelve creates a new debugger exe and client
@@ -108,18 +112,34 @@ func (gd *GiDelve) Start(path, rootPath string, outbuf *lines.Lines, pars *cdebu
 		gd.cmd = exec.Command("dlv", targs...)
 	case cdebug.Test:
 		targs := []string{"test", "--headless", "--api-version=2"}
+		dargs, pargs := gd.params.SplitArgs()
+		targs = append(targs, dargs...)
 		if pars.TestName != "" {
-			targs = append(targs, "--", "-test.run", pars.TestName)
+			pargs = append([]string{"-test.run", pars.TestName}, pargs...)
+		}
+		if len(pargs) > 0 {
+			targs = append(targs, "--")
+			targs = append(targs, pargs...)
 		}
-		targs = append(targs, gd.params.Args...)
 		gd.cmd = exec.Command("dlv", targs...)
 	case cdebug.Attach:
 		// note: --log here creates huge amounts of messages and doesn't work..
 		targs := []string{"attach", fmt.Sprintf("%d", gd.params.PID), "--headless", "--api-version=2"}
 		targs = append(targs, gd.params.Args...)
 		gd.cmd = exec.Command("dlv", targs...)
+	case cdebug.Core:
+		targs := []string{"core", path, gd.params.CoreFile, "--headless", "--api-version=2"}
+		dargs, _ := gd.params.SplitArgs()
+		targs = append(targs, dargs...)
+		gd.cmd = exec.Command("dlv", targs...)
 	}
 	gd.cmd.Dir = filepath.Dir(path)
+	if pars.Dir != "" && (pars.Mode == cdebug.Exec || pars.Mode == cdebug.Test) {
+		gd.cmd.Args = slices.Insert(gd.cmd.Args, 2, "--wd", pars.Dir)
+	}
+	if len(pars.Env) > 0 {
+		gd.cmd.Env = append(os.Environ(), pars.Env...)
+	}
 	stdout, err := gd.cmd.StdoutPipe()
 	if err == nil {
 		gd.cmd.Stderr = gd.cmd.Stdout
@@ -260,9 +280,9 @@ func (gd *GiDelve) Continue(all *cdebug.AllState) <-chan *cdebug.State {
 			ds := gd.cvtState(nv)
 			if !ds.Exited {
 				bk, _ := cdebug.BreakByFile(all.Breaks, ds.Task.FPath, ds.Task.Line)
-				if bk != nil && bk.Trace {
+				if bk != nil && bk.IsTrace() {
 					ds.CurTrace = bk.ID
-					gd.WriteToConsole(fmt.Sprintf("Trace: %d File: %s:%d\n", bk.ID, ds.Task.File, ds.Task.Line))
+					gd.WriteToConsole(bk.Message(ds.Task.File, ds.Task.Line, traceEval(cdebug.LogExprs(bk.LogMsg), nv)) + "\n")
 					continue
 				}
 			}
@@ -274,6 +294,24 @@ func (gd *GiDelve) Continue(all *cdebug.AllState) <-chan *cdebug.State {
 	return sc
 }
 
+// traceEval returns a function that returns the values of the given
+// logpoint expressions that delve evaluated for the tracepoint that
+// the given state stopped at. The values are in the same order as the
+// expressions, which were set as the Variables of the breakpoint,
+// as the variable names are not always the expressions.
+func traceEval(exprs []string, ds *api.DebuggerState) func(expr string) string {
+	var vars []api.Variable
+	if ds.CurrentThread != nil && ds.CurrentThread.BreakpointInfo != nil {
+		vars = ds.CurrentThread.BreakpointInfo.Variables
+	}
+	return func(expr string) string {
+		if i := slices.Index(exprs, expr); i >= 0 && i < len(vars) {
+			return vars[i].SinglelineString()
+		}
+		return "<" + expr + "?>"
+	}
+}
+
 // // Rewind resumes process execution backwards.
 // func (gd *GiDelve) Rewind() <-chan *cdebug.State {
 // 	if err := gd.StartedCheck(); err != nil {
@@ -425,25 +463,57 @@ func (gd *GiDelve) ClearBreakByName(name string) (*cdebug.Break, error) {
 	return gd.cvtBreak(ds), err
 }
 
-// AmmendBreak allows user to update an existing breakpoint for example
+// AmendBreak allows user to update an existing breakpoint for example
 // to change the information retrieved when the breakpoint is hit or to change,
-// add or remove the break condition
-func (gd *GiDelve) AmendBreak(id int, fname string, line int, cond string, trace bool) error {
+// add or remove the break and hit count conditions.
+// Logpoints are set as tracepoints that retrieve the variables in the message.
+func (gd *GiDelve) AmendBreak(bk *cdebug.Break) error {
 	if err := gd.StartedCheck(); err != nil {
 		return err
 	}
 	bp := &api.Breakpoint{}
-	bp.ID = id
-	bp.File = fname
-	bp.Line = line
-	bp.Cond = cond
-	bp.Tracepoint = trace
+	bp.ID = bk.ID
+	bp.File = bk.FPath
+	bp.Line = bk.Line
+	bp.Cond = bk.Cond
+	bp.HitCond = bk.HitCond
+	bp.Tracepoint = bk.IsTrace()
+	bp.Variables = cdebug.LogExprs(bk.LogMsg)
+	bp.WatchExpr = bk.Watch
+	if bk.Watch != "" {
+		bp.WatchType = api.WatchWrite
+	}
 	err := gd.dlv.AmendBreakpoint(bp)
+	if err == nil {
+		if gd.logMsgs == nil {
+			gd.logMsgs = map[int]string{}
+		}
+		if bk.LogMsg != "" {
+			gd.logMsgs[bk.ID] = bk.LogMsg
+		} else {
+			delete(gd.logMsgs, bk.ID)
+		}
+	}
 	return gd.LogErr(err)
 }
 
+// SetWatch sets a new watchpoint that stops when the memory of the
+// variable given by the expression, in the given thread and frame,
+// is written.
+func (gd *GiDelve) SetWatch(expr string, threadID int, frame int) (*cdebug.Break, error) {
+	if err := gd.StartedCheck(); err != nil {
+		return nil, err
+	}
+	ec := gd.toEvalScope(threadID, frame)
+	ds, err := gd.dlv.CreateWatchpoint(*ec, quotePkgPaths(expr), api.WatchWrite)
+	gd.LogErr(err)
+	return gd.cvtBreak(ds), err
+}
+
 // UpdateBreaks updates current breakpoints based on given list of breakpoints.
 // first gets the current list, and does actions to ensure that the list is set.
+// Watchpoints that are no longer set, e.g., from a previous run, are removed
+// from the list, as they go out of scope and cannot be set again.
 func (gd *GiDelve) UpdateBreaks(brk *[]*cdebug.Break) error {
 	if err := gd.StartedCheck(); err != nil {
 		return err
@@ -454,30 +524,37 @@ func (gd *GiDelve) UpdateBreaks(brk *[]*cdebug.Break) error {
 	}
 	for itr := 0; itr < 2; itr++ {
 		update := false
+		keep := make([]*cdebug.Break, 0, len(*brk))
 		for _, b := range *brk {
 			c, ci := cdebug.BreakByFile(cb, b.FPath, b.Line)
+			if b.Watch != "" {
+				c, ci = cdebug.BreakByID(cb, b.ID)
+				if c == nil || c.ID <= 0 {
+					continue // drop
+				}
+			}
+			keep = append(keep, b)
 			if c != nil && c.ID > 0 {
 				if !b.On {
 					cb = append(cb[:ci], cb[ci+1:]...) // remove from cb
 					gd.ClearBreak(c.ID)                // remove from list
 					continue
 				}
-				bc := b.Cond
-				bt := b.Trace
-				if bc != c.Cond || bt != c.Trace {
-					gd.AmendBreak(c.ID, c.File, c.Line, b.Cond, b.Trace)
+				bc, bh, bt, bl := b.Cond, b.HitCond, b.Trace, b.LogMsg
+				if bc != c.Cond || bh != c.HitCond || bt != c.Trace || bl != c.LogMsg {
+					nb := *c
+					nb.Cond, nb.HitCond, nb.Trace, nb.LogMsg = bc, bh, bt, bl
+					gd.AmendBreak(&nb)
 				}
 				*b = *c
-				b.Cond = bc
-				b.Trace = bt
+				b.Cond, b.HitCond, b.Trace, b.LogMsg = bc, bh, bt, bl
 				cb = append(cb[:ci], cb[ci+1:]...) // remove from cb
-			} else { // set but not found
-				if b.On {
-					update = true // need another iter
-					gd.SetBreak(b.FPath, b.Line)
-				}
+			} else if b.On { // set but not found
+				update = true // need another iter
+				gd.SetBreak(b.FPath, b.Line)
 			}
 		}
+		*brk = keep
 		for _, c := range cb { // any we didn't get
 			if c.ID <= 0 {
 				continue
@@ -634,9 +711,21 @@ func (gd *GiDelve) ListTasks() ([]*cdebug.Task, error) {
 	if err := gd.StartedCheck(); err != nil {
 		return nil, err
 	}
-	ds, _, err := gd.dlv.ListGoroutines(0, 1000)
-	gd.LogErr(err)
-	return gd.cvtTasks(ds), err
+	gd.loadWaitReasons()
+	var ds []*api.Goroutine
+	for start := 0; start >= 0; {
+		gs, next, err := gd.dlv.ListGoroutines(start, 1000)
+		if err != nil {
+			gd.LogErr(err)
+			return gd.cvtTasks(ds), err
+		}
+		ds = append(ds, gs...)
+		if next <= start { // no more
+			break
+		}
+		start = next
+	}
+	return gd.cvtTasks(ds), nil
 }
 
 // Stack returns stacktrace
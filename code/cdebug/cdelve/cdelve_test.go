@@ -0,0 +1,31 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdelve
+
+import (
+	"reflect"
+	"testing"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"github.com/go-delve/delve/service/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceEval(t *testing.T) {
+	bk := &cdebug.Break{ID: 1, LogMsg: "A={s.A} n={len(s)} first={ a[0] }"}
+	exprs := cdebug.LogExprs(bk.LogMsg)
+	ds := &api.DebuggerState{CurrentThread: &api.Thread{BreakpointInfo: &api.BreakpointInfo{
+		Variables: []api.Variable{ // names as given by delve, not the expressions
+			{Name: "A", Kind: reflect.Int, Value: "1"},
+			{Name: "", Kind: reflect.Int, Value: "3"},
+			{Name: "a[0]", Kind: reflect.Int, Value: "7"},
+		},
+	}}}
+	assert.Equal(t, "A=1 n=3 first=7", bk.Message("a.go", 3, traceEval(exprs, ds)))
+
+	bk.LogMsg = "A={s.A} b={b}"
+	assert.Equal(t, "A=1 b=<b?>", bk.Message("a.go", 3, traceEval(exprs[:1], ds)))
+	assert.Equal(t, "A=<s.A?> b=<b?>", bk.Message("a.go", 3, traceEval(exprs, &api.DebuggerState{})))
+}
@@ -16,6 +16,7 @@ import (
 	"cogentcore.org/core/base/fsx"
 	"cogentcore.org/core/text/parse/lexer"
 	"cogentcore.org/core/text/parse/syms"
+	"github.com/go-delve/delve/pkg/proc"
 	"github.com/go-delve/delve/service/api"
 )
 
@@ -83,11 +84,61 @@ func (gd *GiDelve) cvtTask(ds *api.Goroutine) *cdebug.Task {
 		gr.Func = ds.UserCurrentLoc.Function.Name_
 	}
 	gr.Thread = ds.ThreadID
+	gr.Status = gd.taskStatus(ds)
 	gr.LaunchLoc = *gd.cvtLocation(&ds.GoStatementLoc)
 	gr.StartLoc = *gd.cvtLocation(&ds.StartLoc)
 	return gr
 }
 
+// loadWaitReasons loads the names of the reasons that goroutines are
+// waiting from the runtime of the process being debugged, as they are
+// numbered differently by different versions of the runtime.
+func (gd *GiDelve) loadWaitReasons() {
+	if gd.waitReasons != nil {
+		return
+	}
+	lc := api.LoadConfig{MaxStringLen: 64, MaxArrayValues: 256}
+	ds, err := gd.dlv.EvalVariable(api.EvalScope{GoroutineID: -1}, "runtime.waitReasonStrings", lc)
+	if err != nil {
+		return
+	}
+	gd.waitReasons = make([]string, len(ds.Children))
+	for i, ch := range ds.Children {
+		gd.waitReasons[i] = ch.Value
+	}
+}
+
+// taskStatus returns the status of the given goroutine,
+// including why it is waiting, which is given by number if
+// its name is not known.
+func (gd *GiDelve) taskStatus(ds *api.Goroutine) string {
+	var st string
+	switch ds.Status {
+	case proc.Gidle:
+		st = "idle"
+	case proc.Grunnable:
+		st = "runnable"
+	case proc.Grunning:
+		st = "running"
+	case proc.Gsyscall:
+		st = "syscall"
+	case proc.Gwaiting:
+		st = "waiting"
+	case proc.Gdead:
+		st = "dead"
+	default:
+		st = fmt.Sprintf("status %d", ds.Status)
+	}
+	if (ds.Status == api.GoroutineWaiting || ds.Status == api.GoroutineSyscall) && ds.WaitReason > 0 {
+		if ds.WaitReason < int64(len(gd.waitReasons)) {
+			st += ": " + gd.waitReasons[ds.WaitReason]
+		} else {
+			st += fmt.Sprintf(": wait reason %d", ds.WaitReason)
+		}
+	}
+	return st
+}
+
 func (gd *GiDelve) cvtTasks(ds []*api.Goroutine) []*cdebug.Task {
 	if ds == nil || len(ds) == 0 {
 		return nil
@@ -128,7 +179,11 @@ func (gd *GiDelve) cvtBreak(ds *api.Breakpoint) *cdebug.Break {
 	bp.Line = ds.Line
 	bp.Func = ds.FunctionName
 	bp.Cond = ds.Cond
-	bp.Trace = ds.Tracepoint
+	bp.HitCond = ds.HitCond
+	bp.Hits = int(ds.TotalHitCount)
+	bp.LogMsg = gd.logMsgs[ds.ID]
+	bp.Trace = ds.Tracepoint && bp.LogMsg == ""
+	bp.Watch = ds.WatchExpr
 	return bp
 }
 
@@ -6,6 +6,45 @@ import (
 	"cogentcore.org/core/enums"
 )
 
+var _ModesValues = []Modes{0, 1, 2, 3}
+
+// ModesN is the highest valid value for type Modes, plus one.
+const ModesN Modes = 4
+
+var _ModesValueMap = map[string]Modes{`Exec`: 0, `Test`: 1, `Attach`: 2, `Core`: 3}
+
+var _ModesDescMap = map[Modes]string{0: `Exec means debug a standard executable program`, 1: `Test means debug a testing program`, 2: `Attach means attach to an already-running process`, 3: `Core means inspect the core dump of a crashed process, post-mortem, in which case the process cannot be run, so it is read-only`}
+
+var _ModesMap = map[Modes]string{0: `Exec`, 1: `Test`, 2: `Attach`, 3: `Core`}
+
+// String returns the string representation of this Modes value.
+func (i Modes) String() string { return enums.String(i, _ModesMap) }
+
+// SetString sets the Modes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Modes) SetString(s string) error { return enums.SetString(i, s, _ModesValueMap, "Modes") }
+
+// Int64 returns the Modes value as an int64.
+func (i Modes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Modes value from an int64.
+func (i *Modes) SetInt64(in int64) { *i = Modes(in) }
+
+// Desc returns the description of the Modes value.
+func (i Modes) Desc() string { return enums.Desc(i, _ModesDescMap) }
+
+// ModesValues returns all possible values for the type Modes.
+func ModesValues() []Modes { return _ModesValues }
+
+// Values returns all possible values for the type Modes.
+func (i Modes) Values() []enums.Enum { return enums.Values(_ModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Modes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Modes) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Modes") }
+
 var _StatusValues = []Status{0, 1, 2, 3, 4, 5, 6, 7}
 
 // StatusN is the highest valid value for type Status, plus one.
@@ -44,3 +83,46 @@ func (i Status) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
 
 // UnmarshalText implements the [encoding.TextUnmarshaler] interface.
 func (i *Status) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Status") }
+
+var _TaskGroupingsValues = []TaskGroupings{0, 1, 2}
+
+// TaskGroupingsN is the highest valid value for type TaskGroupings, plus one.
+const TaskGroupingsN TaskGroupings = 3
+
+var _TaskGroupingsValueMap = map[string]TaskGroupings{`None`: 0, `Stack`: 1, `Start`: 2}
+
+var _TaskGroupingsDescMap = map[TaskGroupings]string{0: `GroupNone lists each of the tasks.`, 1: `GroupStack groups tasks with an identical stack.`, 2: `GroupStart groups tasks started at the same location.`}
+
+var _TaskGroupingsMap = map[TaskGroupings]string{0: `None`, 1: `Stack`, 2: `Start`}
+
+// String returns the string representation of this TaskGroupings value.
+func (i TaskGroupings) String() string { return enums.String(i, _TaskGroupingsMap) }
+
+// SetString sets the TaskGroupings value from its string representation,
+// and returns an error if the string is invalid.
+func (i *TaskGroupings) SetString(s string) error {
+	return enums.SetString(i, s, _TaskGroupingsValueMap, "TaskGroupings")
+}
+
+// Int64 returns the TaskGroupings value as an int64.
+func (i TaskGroupings) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the TaskGroupings value from an int64.
+func (i *TaskGroupings) SetInt64(in int64) { *i = TaskGroupings(in) }
+
+// Desc returns the description of the TaskGroupings value.
+func (i TaskGroupings) Desc() string { return enums.Desc(i, _TaskGroupingsDescMap) }
+
+// TaskGroupingsValues returns all possible values for the type TaskGroupings.
+func TaskGroupingsValues() []TaskGroupings { return _TaskGroupingsValues }
+
+// Values returns all possible values for the type TaskGroupings.
+func (i TaskGroupings) Values() []enums.Enum { return enums.Values(_TaskGroupingsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i TaskGroupings) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *TaskGroupings) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "TaskGroupings")
+}
@@ -6,6 +6,7 @@ package cdebug
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -83,6 +84,9 @@ type Task struct {
 	// the name of the function
 	Func string `width:"80"`
 
+	// status of the task, e.g., running, or waiting and why
+	Status string
+
 	// id of the current Thread this task is running on
 	Thread int `format:"%#x"`
 
@@ -185,8 +189,81 @@ type Break struct {
 	// condition for conditional breakbpoint
 	Cond string
 
+	// hit count condition: an operator and a count that the number of hits
+	// is compared with, e.g., "> 5" to stop after five hits, "== 3" to stop
+	// only on the third hit, or "% 2" to stop on every second hit
+	HitCond string `width:"7"`
+
+	// number of times the breakpoint has been hit, if known
+	Hits int `edit:"-" width:"4"`
+
 	// if true, execution does not stop -- just a message is reported when this point is hit
 	Trace bool `width:"7"`
+
+	// logpoint message: if set, execution does not stop, and the message
+	// is reported when this point is hit, with each {expr} in it replaced
+	// by the value of that expression
+	LogMsg string `width:"40"`
+
+	// for a watchpoint, the expression whose memory is watched, stopping when it
+	// is written -- the File and Line are then those where it was set
+	Watch string `edit:"-"`
+}
+
+// IsTrace returns whether execution does not stop at the breakpoint,
+// which is the case for trace breakpoints and logpoints.
+func (br *Break) IsTrace() bool {
+	return br.Trace || br.LogMsg != ""
+}
+
+// Message returns the message to report when a trace breakpoint or
+// logpoint is hit, at the given file and line.  The {expr} expressions
+// in a logpoint message are replaced by the result of the eval function.
+func (br *Break) Message(file string, line int, eval func(expr string) string) string {
+	if br.LogMsg == "" {
+		return fmt.Sprintf("Trace: %d File: %s:%d", br.ID, file, line)
+	}
+	return InterpolateLog(br.LogMsg, eval)
+}
+
+// LogExprs returns the expressions in {} in the given logpoint message,
+// in order and without duplicates.
+func LogExprs(msg string) []string {
+	var exprs []string
+	InterpolateLog(msg, func(expr string) string {
+		if !slices.Contains(exprs, expr) {
+			exprs = append(exprs, expr)
+		}
+		return ""
+	})
+	return exprs
+}
+
+// InterpolateLog returns the given logpoint message with each {expr}
+// replaced by the result of the eval function for that expression.
+// Braces can be included literally as {{ and }}.
+func InterpolateLog(msg string, eval func(expr string) string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		if (c == '{' || c == '}') && i+1 < len(msg) && msg[i+1] == c {
+			sb.WriteByte(c)
+			i++
+			continue
+		}
+		if c != '{' {
+			sb.WriteByte(c)
+			continue
+		}
+		end := strings.IndexByte(msg[i+1:], '}')
+		if end < 0 {
+			sb.WriteString(msg[i:])
+			break
+		}
+		sb.WriteString(eval(strings.TrimSpace(msg[i+1 : i+1+end])))
+		i += end + 1
+	}
+	return sb.String()
 }
 
 // BreakByID returns the given breakpoint by ID from full list, and index.
@@ -298,6 +375,9 @@ type AllState struct {
 
 	// current find-frames result
 	FindFrames []*Frame
+
+	// watch expressions, evaluated every time the debugger stops
+	Watches []*Watch
 }
 
 // BlankState initializes state with a blank initial state with the various slices
@@ -311,6 +391,7 @@ func (as *AllState) BlankState() {
 	as.Vars = []*Variable{}
 	as.GlobalVars = []*Variable{}
 	as.FindFrames = []*Frame{}
+	as.Watches = []*Watch{}
 }
 
 // StackFrame safely returns the given stack frame -- nil if out of range
@@ -389,13 +470,39 @@ func (as *AllState) MergeBreaks() {
 			br.On = true
 			as.Breaks = append(as.Breaks, br)
 		} else {
+			lm := ab.LogMsg
 			*ab = *br
 			ab.On = true
+			if ab.LogMsg == "" { // not all debuggers keep it
+				ab.LogMsg = lm
+			}
 		}
 	}
 	SortBreaks(as.Breaks)
 }
 
+// AddWatch adds the given expression to the watches,
+// if it is not already there, and returns its watch.
+func (as *AllState) AddWatch(expr string) *Watch {
+	for _, w := range as.Watches {
+		if w.Expr == expr {
+			return w
+		}
+	}
+	w := &Watch{Expr: expr}
+	as.Watches = append(as.Watches, w)
+	return w
+}
+
+// UpdateWatches evaluates all of the watch expressions
+// using the given eval function, typically GiDebug.GetVar
+// in the current thread and frame.
+func (as *AllState) UpdateWatches(eval func(expr string) (*Variable, error)) {
+	for _, w := range as.Watches {
+		w.Update(eval)
+	}
+}
+
 // VarByName returns variable with the given name, or nil if not found
 func (as *AllState) VarByName(varNm string) *Variable {
 	for _, vr := range as.Vars {
@@ -416,6 +523,40 @@ func (as *AllState) VarByName(varNm string) *Variable {
 	return nil
 }
 
+// Watch is an expression that is evaluated every time the debugger stops.
+type Watch struct {
+
+	// expression to evaluate in the current thread and frame
+	Expr string `width:"40"`
+
+	// value of the expression, or the error from evaluating it
+	Value string `edit:"-" width:"60"`
+
+	// type of the value
+	TypeStr string `edit:"-"`
+
+	// if true, the value changed at the last update
+	Changed bool `edit:"-" width:"4"`
+}
+
+// Update evaluates the expression with the given eval function,
+// and sets the value and whether it changed.
+func (w *Watch) Update(eval func(expr string) (*Variable, error)) {
+	if strings.TrimSpace(w.Expr) == "" {
+		w.Value, w.TypeStr, w.Changed = "", "", false
+		return
+	}
+	val, typ := "", ""
+	vr, err := eval(w.Expr)
+	if err != nil {
+		val = "error: " + err.Error()
+	} else if vr != nil {
+		val, typ = vr.Value, vr.TypeStr
+	}
+	w.Changed = w.Value != "" && val != w.Value
+	w.Value, w.TypeStr = val, typ
+}
+
 // Status of the debugger
 type Status int32 //enums:enum
 
@@ -0,0 +1,68 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdebug
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterpolateLog(t *testing.T) {
+	eval := func(expr string) string { return "<" + expr + ">" }
+	assert.Equal(t, "x = <x>, s.A = <s.A>", InterpolateLog("x = {x}, s.A = { s.A }", eval))
+	assert.Equal(t, "{x} = <x>", InterpolateLog("{{x}} = {x}", eval))
+	assert.Equal(t, "no exprs", InterpolateLog("no exprs", eval))
+	assert.Equal(t, "open {x", InterpolateLog("open {x", eval))
+	assert.Equal(t, []string{"x", "len(s)"}, LogExprs("{x} {len(s)} {x}"))
+	assert.Nil(t, LogExprs("{{x}}"))
+
+	bk := &Break{ID: 2, Trace: true}
+	assert.True(t, bk.IsTrace())
+	assert.Equal(t, "Trace: 2 File: a.go:3", bk.Message("a.go", 3, eval))
+	bk = &Break{ID: 2, LogMsg: "x={x}"}
+	assert.True(t, bk.IsTrace())
+	assert.Equal(t, "x=<x>", bk.Message("a.go", 3, eval))
+	assert.False(t, (&Break{}).IsTrace())
+}
+
+func TestWatches(t *testing.T) {
+	vals := map[string]string{"x": "1", "y": "2"}
+	eval := func(expr string) (*Variable, error) {
+		v, ok := vals[expr]
+		if !ok {
+			return nil, errors.New("undefined: " + expr)
+		}
+		return &Variable{Value: v, TypeStr: "int"}, nil
+	}
+	as := &AllState{}
+	x := as.AddWatch("x")
+	assert.Equal(t, x, as.AddWatch("x"))
+	z := as.AddWatch("z")
+	as.UpdateWatches(eval)
+	assert.Equal(t, "1", x.Value)
+	assert.Equal(t, "int", x.TypeStr)
+	assert.False(t, x.Changed)
+	assert.Equal(t, "error: undefined: z", z.Value)
+
+	vals["x"] = "3"
+	as.UpdateWatches(eval)
+	assert.Equal(t, "3", x.Value)
+	assert.True(t, x.Changed)
+	as.UpdateWatches(eval)
+	assert.False(t, x.Changed)
+}
+
+func TestSplitArgs(t *testing.T) {
+	pars := &Params{Args: []string{"-v", "--", "a", "b"}}
+	dbg, prog := pars.SplitArgs()
+	assert.Equal(t, []string{"-v"}, dbg)
+	assert.Equal(t, []string{"a", "b"}, prog)
+	pars.Args = []string{"-v"}
+	dbg, prog = pars.SplitArgs()
+	assert.Equal(t, []string{"-v"}, dbg)
+	assert.Nil(t, prog)
+}
@@ -107,10 +107,14 @@ func (st *Stub) ClearBreak(id int) error {
 	return nil
 }
 
-func (st *Stub) AmendBreak(id int, fname string, line int, cond string, trace bool) error {
+func (st *Stub) AmendBreak(bk *cdebug.Break) error {
 	return nil
 }
 
+func (st *Stub) SetWatch(expr string, threadID int, frame int) (*cdebug.Break, error) {
+	return nil, nil
+}
+
 func (st *Stub) UpdateBreaks(brk *[]*cdebug.Break) error {
 	return nil
 }
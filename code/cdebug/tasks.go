@@ -0,0 +1,187 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdebug
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TaskGroupings are the ways of grouping tasks, e.g., goroutines,
+// which there can be thousands of, so that they can be understood.
+type TaskGroupings int32 //enums:enum -trim-prefix Group
+
+const (
+	// GroupNone lists each of the tasks.
+	GroupNone TaskGroupings = iota
+
+	// GroupStack groups tasks with an identical stack.
+	GroupStack
+
+	// GroupStart groups tasks started at the same location.
+	GroupStart
+)
+
+// TaskGroup is a group of tasks with an identical stack,
+// or started at the same location.
+type TaskGroup struct {
+
+	// number of tasks in the group
+	Count int
+
+	// file name (trimmed up to point of project base path), of the
+	// current location for a stack group, or the start location
+	File string
+
+	// line within file
+	Line int
+
+	// full path to file
+	FPath string `table:"-"`
+
+	// the name of the function, at the top of the stack for
+	// a stack group, or the starting function
+	Func string `width:"60"`
+
+	// status of the tasks, if they all have the same one
+	Status string
+
+	// the tasks in the group
+	Tasks []*Task `table:"-"`
+
+	// the stack of the tasks, for a stack group
+	Stack []*Frame `table:"-"`
+}
+
+// TaskFilter selects the tasks to show.
+type TaskFilter struct {
+
+	// only show tasks with this function on their stack, which matches
+	// the end of the full function name, ignoring pointer receiver
+	// parens, e.g., Serve, Server.Serve or http.Server.Serve
+	Func string
+
+	// only show tasks whose status contains this, e.g., chan or select
+	Status string
+}
+
+// IsEmpty returns true if the filter does not filter any tasks.
+func (tf *TaskFilter) IsEmpty() bool {
+	return tf.Func == "" && tf.Status == ""
+}
+
+// Matches returns true if the task, with the given stack, matches
+// the filter. If the stack is nil, only the current function is checked.
+func (tf *TaskFilter) Matches(tk *Task, stack []*Frame) bool {
+	if tf.Status != "" && !strings.Contains(tk.Status, tf.Status) {
+		return false
+	}
+	if tf.Func == "" {
+		return true
+	}
+	if stack == nil {
+		return FuncMatches(tk.Func, tf.Func)
+	}
+	return slices.ContainsFunc(stack, func(fr *Frame) bool {
+		return FuncMatches(fr.Func, tf.Func)
+	})
+}
+
+// FuncMatches returns true if the given full function name, e.g.,
+// net/http.(*Server).Serve, matches the given name, which matches
+// the end of it, ignoring pointer receiver parens, at a package
+// or name boundary, e.g., Serve, Server.Serve or http.Server.Serve.
+func FuncMatches(full, name string) bool {
+	if full == "" || name == "" {
+		return false
+	}
+	full = strings.NewReplacer("(*", "", ")", "").Replace(full)
+	name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+	return full == name || strings.HasSuffix(full, "."+name) || strings.HasSuffix(full, "/"+name)
+}
+
+// FilterTasks returns the tasks that match the filter,
+// using the given stacks by task ID, which can be nil.
+func FilterTasks(tasks []*Task, tf *TaskFilter, stacks map[int][]*Frame) []*Task {
+	if tf.IsEmpty() {
+		return tasks
+	}
+	var ft []*Task
+	for _, tk := range tasks {
+		if tf.Matches(tk, stacks[tk.ID]) {
+			ft = append(ft, tk)
+		}
+	}
+	return ft
+}
+
+// StackLines returns a line for each frame of the given stack,
+// with the function and its location, e.g., for comparing stacks.
+func StackLines(stack []*Frame) []string {
+	lns := make([]string, len(stack))
+	for i, fr := range stack {
+		lns[i] = fmt.Sprintf("%s  %s:%d", fr.Func, fr.File, fr.Line)
+	}
+	return lns
+}
+
+// GroupTasks returns the given tasks grouped in the given way, using the
+// given stacks by task ID for grouping by stack, with the largest groups
+// first. It returns nil for GroupNone.
+func GroupTasks(tasks []*Task, by TaskGroupings, stacks map[int][]*Frame) []*TaskGroup {
+	if by == GroupNone {
+		return nil
+	}
+	var groups []*TaskGroup
+	byKey := map[string]*TaskGroup{}
+	for _, tk := range tasks {
+		var key string
+		switch by {
+		case GroupStack:
+			key = strings.Join(StackLines(stacks[tk.ID]), "\n")
+		case GroupStart:
+			key = fmt.Sprintf("%s %s:%d", tk.StartLoc.Func, tk.StartLoc.FPath, tk.StartLoc.Line)
+		}
+		tg := byKey[key]
+		if tg == nil {
+			tg = &TaskGroup{Status: tk.Status}
+			switch by {
+			case GroupStack:
+				tg.File, tg.Line, tg.FPath, tg.Func = tk.File, tk.Line, tk.FPath, tk.Func
+				tg.Stack = stacks[tk.ID]
+			case GroupStart:
+				sl := &tk.StartLoc
+				tg.File, tg.Line, tg.FPath, tg.Func = sl.File, sl.Line, sl.FPath, sl.Func
+			}
+			byKey[key] = tg
+			groups = append(groups, tg)
+		}
+		tg.Count++
+		tg.Tasks = append(tg.Tasks, tk)
+		if tg.Status != tk.Status {
+			tg.Status = ""
+		}
+	}
+	slices.SortStableFunc(groups, func(a, b *TaskGroup) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	return groups
+}
+
+// TaskStacks returns the stacks of the given tasks, up to the given
+// depth, by task ID, from the given debugger. Tasks whose stack
+// cannot be found are skipped.
+func TaskStacks(dbg GiDebug, tasks []*Task, depth int) map[int][]*Frame {
+	stacks := make(map[int][]*Frame, len(tasks))
+	for _, tk := range tasks {
+		st, err := dbg.Stack(tk.ID, depth)
+		if err == nil {
+			stacks[tk.ID] = st
+		}
+	}
+	return stacks
+}
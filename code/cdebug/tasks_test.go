@@ -0,0 +1,79 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cdebug
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuncMatches(t *testing.T) {
+	full := "net/http.(*Server).Serve"
+	assert.True(t, FuncMatches(full, "Serve"))
+	assert.True(t, FuncMatches(full, "Server.Serve"))
+	assert.True(t, FuncMatches(full, "(*Server).Serve"))
+	assert.True(t, FuncMatches(full, "http.Server.Serve"))
+	assert.True(t, FuncMatches(full, "net/http.(*Server).Serve"))
+	assert.False(t, FuncMatches(full, "erve"))
+	assert.False(t, FuncMatches(full, "ListenAndServe"))
+	assert.False(t, FuncMatches(full, ""))
+	assert.True(t, FuncMatches("main.worker", "worker"))
+}
+
+func TestGroupTasks(t *testing.T) {
+	loop := &Location{Func: "main.loop", File: "main.go", FPath: "/p/main.go", Line: 5}
+	serve := &Location{Func: "main.serve", File: "main.go", FPath: "/p/main.go", Line: 20}
+	task := func(id int, fun, status string, start *Location) *Task {
+		return &Task{ID: id, Func: fun, File: "main.go", Line: id, Status: status, StartLoc: *start}
+	}
+	tasks := []*Task{
+		task(1, "main.main", "running", serve),
+		task(2, "main.worker", "waiting: chan receive", loop),
+		task(3, "main.worker", "waiting: chan receive", loop),
+		task(4, "main.worker", "waiting: select", loop),
+	}
+	frame := func(fun string, line int) *Frame {
+		return &Frame{Func: fun, File: "main.go", Line: line}
+	}
+	wait := []*Frame{frame("runtime.gopark", 1), frame("main.worker", 10), frame("main.loop", 5)}
+	stacks := map[int][]*Frame{
+		1: {frame("main.main", 1)},
+		2: wait,
+		3: wait,
+		4: {frame("runtime.selectgo", 2), frame("main.worker", 12), frame("main.loop", 5)},
+	}
+
+	assert.Nil(t, GroupTasks(tasks, GroupNone, stacks))
+	gs := GroupTasks(tasks, GroupStack, stacks)
+	if assert.Len(t, gs, 3) {
+		assert.Equal(t, 2, gs[0].Count)
+		assert.Equal(t, []*Task{tasks[1], tasks[2]}, gs[0].Tasks)
+		assert.Equal(t, "waiting: chan receive", gs[0].Status)
+		assert.Equal(t, wait, gs[0].Stack)
+		assert.Equal(t, 1, gs[1].Count)
+	}
+	gs = GroupTasks(tasks, GroupStart, stacks)
+	if assert.Len(t, gs, 2) {
+		assert.Equal(t, 3, gs[0].Count)
+		assert.Equal(t, "main.loop", gs[0].Func)
+		assert.Equal(t, 5, gs[0].Line)
+		assert.Equal(t, "", gs[0].Status) // mixed
+		assert.Equal(t, "main.serve", gs[1].Func)
+	}
+
+	tf := &TaskFilter{}
+	assert.Equal(t, tasks, FilterTasks(tasks, tf, stacks))
+	tf.Func = "loop"
+	assert.Equal(t, tasks[1:], FilterTasks(tasks, tf, stacks))
+	assert.Nil(t, FilterTasks(tasks, tf, nil)) // only current func
+	tf.Status = "select"
+	assert.Equal(t, tasks[3:], FilterTasks(tasks, tf, stacks))
+	tf.Func = ""
+	tf.Status = "waiting"
+	assert.Equal(t, tasks[1:], FilterTasks(tasks, tf, nil))
+
+	assert.Equal(t, []string{"runtime.gopark  main.go:1", "main.worker  main.go:10", "main.loop  main.go:5"}, StackLines(wait))
+}
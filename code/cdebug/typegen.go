@@ -71,4 +71,4 @@ func (t *Variable) SetMapVar(v map[string]*Variable) *Variable { t.MapVar = v; r
 // our debugger -- for getting further variable data
 func (t *Variable) SetDbg(v GiDebug) *Variable { t.Dbg = v; return t }
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code/cdebug.Params", IDName: "params", Doc: "Params are overall debugger parameters", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Mode", Doc: "mode for running the debugger"}, {Name: "PID", Doc: "process id number to attach to, for Attach mode"}, {Name: "TestName", Doc: "name of test to run for DebugTest function.\nif non-empty, only tests matching this name will be run."}, {Name: "Args", Doc: "optional extra args to pass to the debugger.\nUse -- double-dash and then add args to pass args to the executable\n(double-dash is by itself as a separate arg first).\nTest args are passed automatically if TestName is set."}, {Name: "StatFunc", Doc: "status function for debugger updating status"}, {Name: "VarList", Doc: "parameters for level of detail on overall list of variables"}, {Name: "GetVar", Doc: "parameters for level of detail retrieving a specific variable"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code/cdebug.Params", IDName: "params", Doc: "Params are overall debugger parameters", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "Mode", Doc: "mode for running the debugger"}, {Name: "PID", Doc: "process id number to attach to, for Attach mode"}, {Name: "CoreFile", Doc: "core dump file to open, for Core mode"}, {Name: "TestName", Doc: "name of test to run for DebugTest function.\nif non-empty, only tests matching this name will be run."}, {Name: "Args", Doc: "optional extra args to pass to the debugger.\nUse -- double-dash and then add args to pass args to the executable\n(double-dash is by itself as a separate arg first).\nTest args are passed automatically if TestName is set."}, {Name: "Env", Doc: "environment variables for the program, as name=value,\nin addition to those of the current process"}, {Name: "Dir", Doc: "working directory for the program; if empty,\nit is the directory of the executable"}, {Name: "StatFunc", Doc: "status function for debugger updating status"}, {Name: "VarList", Doc: "parameters for level of detail on overall list of variables"}, {Name: "GetVar", Doc: "parameters for level of detail retrieving a specific variable"}}})
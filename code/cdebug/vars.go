@@ -219,6 +219,9 @@ type Params struct { //types:add
 	// process id number to attach to, for Attach mode
 	PID uint64 `xml:"-" toml:"-" json:"-" display:"-"`
 
+	// core dump file to open, for Core mode
+	CoreFile string `xml:"-" toml:"-" json:"-" display:"-"`
+
 	// name of test to run for DebugTest function.
 	// if non-empty, only tests matching this name will be run.
 	TestName string
@@ -229,6 +232,14 @@ type Params struct { //types:add
 	// Test args are passed automatically if TestName is set.
 	Args []string
 
+	// environment variables for the program, as name=value,
+	// in addition to those of the current process
+	Env []string `xml:"-" toml:"-" json:"-" display:"-"`
+
+	// working directory for the program; if empty,
+	// it is the directory of the executable
+	Dir string `xml:"-" toml:"-" json:"-" display:"-"`
+
 	// status function for debugger updating status
 	StatFunc func(stat Status) `xml:"-" toml:"-" json:"-" display:"-"`
 
@@ -239,6 +250,17 @@ type Params struct { //types:add
 	GetVar VarParams
 }
 
+// SplitArgs returns the Args for the debugger itself, and those
+// for the program, which are after a -- double-dash arg.
+func (pars *Params) SplitArgs() (dbg, prog []string) {
+	for i, arg := range pars.Args {
+		if arg == "--" {
+			return pars.Args[:i], pars.Args[i+1:]
+		}
+	}
+	return pars.Args, nil
+}
+
 // DefaultParams are default parameter values
 var DefaultParams = Params{
 	VarList: VarParams{
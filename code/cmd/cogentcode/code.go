@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"cogentcore.org/cogent/code"
+	"cogentcore.org/cogent/code/remote"
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/core"
 )
@@ -23,6 +24,15 @@ func main() {
 	// we must load the settings before initializing the console
 	errors.Log(core.LoadAllSettings())
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "run":
+			os.Exit(runCommand(os.Args[2:]))
+		case "list-commands":
+			os.Exit(listCommands(os.Args[2:]))
+		}
+	}
+
 	// note: comment this out when printing out debug messages involving components of code itself!
 	InitConsole(lfnm)
 
@@ -53,7 +63,7 @@ func main() {
 		proj, _ = filepath.Abs(proj)
 		code.OpenCodeProject(proj)
 	} else {
-		if path != "" {
+		if path != "" && !remote.IsURL(path) {
 			path, _ = filepath.Abs(path)
 		}
 		code.NewCodeProjectPath(path)
@@ -0,0 +1,133 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/cogent/code"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/base/vcs"
+	"cogentcore.org/core/text/textpos"
+)
+
+// promptFlags are the values for command prompts given on the command line,
+// as Name=value, where Name is the prompt variable without the braces.
+type promptFlags map[string]string
+
+func (pf promptFlags) String() string {
+	return fmt.Sprint(map[string]string(pf))
+}
+
+func (pf promptFlags) Set(s string) error {
+	nm, val, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("prompt value must be Name=value: %q", s)
+	}
+	pf["{"+strings.Trim(nm, "{}")+"}"] = val
+	return nil
+}
+
+// runCommand runs the run subcommand with given args, which runs
+// the named command in a project without a GUI, returning its exit status.
+func runCommand(args []string) int {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	proj := fs.String("proj", ".", "project to run the command in: a .code project file or the project root directory")
+	file := fs.String("file", "", "current file, for {FilePath} and other file variables")
+	line := fs.Int("line", 0, "cursor line, for {CurLine}")
+	col := fs.Int("col", 0, "cursor column, for {CurCol}")
+	sel := fs.String("sel", "", "selected region as startLine:startCol-endLine:endCol, for {SelStartLine} etc")
+	prompts := promptFlags{}
+	fs.Var(prompts, "prompt", "value for a prompt as Name=value, e.g., PromptString1=./...; can be repeated. PromptFiles takes a comma-separated list")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: cogentcode run [flags] \"Cat: Name\"")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *file != "" { // relative to the current directory, not the project
+		abs, err := filepath.Abs(*file)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		*file = abs
+	}
+	cv, err := code.OpenHeadless(*proj, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	cv.ArgVals.Set(*file, &cv.Settings, nil)
+	if *line > 0 || *col > 0 || *sel != "" {
+		var rg textpos.Region
+		if *sel != "" {
+			if _, err := fmt.Sscanf(*sel, "%d:%d-%d:%d", &rg.Start.Line, &rg.Start.Char, &rg.End.Line, &rg.End.Char); err != nil {
+				fmt.Fprintf(os.Stderr, "invalid -sel %q: %v\n", *sel, err)
+				return 2
+			}
+		}
+		cv.ArgVals.SetCursor(textpos.Pos{Line: *line, Char: *col}, rg)
+	}
+	pr := &code.PresetPrompter{Values: prompts, Files: map[string][]string{}}
+	if fl, ok := prompts["{PromptFiles}"]; ok {
+		pr.Files["{PromptFiles}"] = strings.Split(fl, ",")
+	}
+	cv.Prompter = pr
+
+	err = cv.RunHeadless(code.CmdName(fs.Arg(0)))
+	if err == nil {
+		return 0
+	}
+	if st, ok := code.ExitCode(err); ok {
+		return st
+	}
+	fmt.Fprintln(os.Stderr, err)
+	return 1
+}
+
+// listCommands runs the list-commands subcommand with given args, which
+// prints the commands available for a language and version control system.
+func listCommands(args []string) int {
+	fs := flag.NewFlagSet("list-commands", flag.ExitOnError)
+	proj := fs.String("proj", "", "project to use the main language and version control system of, if not specified by -lang and -vcs")
+	lang := fs.String("lang", "", "language to list the commands for, e.g., Go; Any lists only the commands for any language")
+	vc := fs.String("vcs", "", "version control system to list the commands for, e.g., Git")
+	fs.Parse(args)
+
+	kn, vt := fileinfo.Any, vcs.NoVCS
+	if *proj != "" {
+		cv, err := code.OpenHeadless(*proj, os.Stdout)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if cv.Settings.MainLang != fileinfo.Unknown {
+			kn = cv.Settings.MainLang
+		}
+		vt = cv.Settings.VersionControl
+	}
+	if *lang != "" {
+		if err := kn.SetString(*lang); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	if *vc != "" {
+		if err := vt.SetString(*vc); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+	}
+	code.ListCommands(os.Stdout, kn, vt)
+	return 0
+}
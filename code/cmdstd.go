@@ -93,7 +93,8 @@ var StandardCommands = Commands{
 		Cmds: []CmdAndArgs{{Cmd: "go",
 			Args: []string{"build", "-v"}}},
 		Dir:  "{FileDirPath}",
-		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm},
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoProblemRegexp},
 
 	{Cat: "Go", Name: "Build Proj",
 		Desc: "run go build for project BuildDir",
@@ -101,7 +102,8 @@ var StandardCommands = Commands{
 		Cmds: []CmdAndArgs{{Cmd: "go",
 			Args: []string{"build", "-v"}}},
 		Dir:  "{BuildDir}",
-		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm},
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoProblemRegexp},
 
 	{Cat: "Go", Name: "Install Dir",
 		Desc: "run go install in current dir",
@@ -125,7 +127,35 @@ var StandardCommands = Commands{
 		Cmds: []CmdAndArgs{{Cmd: "go",
 			Args: []string{"test", "-v", "{PromptString1}"}}},
 		Dir:  "{FileDirPath}",
-		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm},
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoTestProblemRegexp},
+
+	{Cat: "Go", Name: "Test Coverage",
+		Desc: "run go test with a coverage profile in current dir, and show the coverage in the editors and file tree",
+		Lang: fileinfo.Go,
+		Cmds: []CmdAndArgs{{Cmd: "go",
+			Args: []string{"test", "-coverprofile={TempDir}/coverage.out", "{PromptString1}"}}},
+		Dir:  "{FileDirPath}",
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoTestProblemRegexp, CoverProfile: "{TempDir}/coverage.out"},
+
+	{Cat: "Go", Name: "Test CPU Profile",
+		Desc: "run go test with a CPU profile in current dir, and show the profile in the Profile panel and the editors",
+		Lang: fileinfo.Go,
+		Cmds: []CmdAndArgs{{Cmd: "go",
+			Args: []string{"test", "-o={TempDir}/", "-cpuprofile={TempDir}/cpu.pprof", "{PromptString1}"}}},
+		Dir:  "{FileDirPath}",
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoTestProblemRegexp, Pprof: "{TempDir}/cpu.pprof"},
+
+	{Cat: "Go", Name: "Test Memory Profile",
+		Desc: "run go test with a memory profile in current dir, and show the profile in the Profile panel and the editors",
+		Lang: fileinfo.Go,
+		Cmds: []CmdAndArgs{{Cmd: "go",
+			Args: []string{"test", "-o={TempDir}/", "-memprofile={TempDir}/mem.pprof", "{PromptString1}"}}},
+		Dir:  "{FileDirPath}",
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoTestProblemRegexp, Pprof: "{TempDir}/mem.pprof"},
 
 	{Cat: "Go", Name: "Vet",
 		Desc: "run go vet in current dir",
@@ -133,7 +163,8 @@ var StandardCommands = Commands{
 		Cmds: []CmdAndArgs{{Cmd: "go",
 			Args: []string{"vet"}}},
 		Dir:  "{FileDirPath}",
-		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm},
+		Wait: CmdNoWait, Focus: CmdNoFocus, Confirm: CmdNoConfirm,
+		ProblemRegexp: GoProblemRegexp},
 
 	{Cat: "Go", Name: "Mod Tidy",
 		Desc: "run go mod tidy in current dir",
@@ -9,6 +9,7 @@ package code
 
 import (
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"log/slog"
@@ -18,6 +19,9 @@ import (
 	"sync"
 	"time"
 
+	"cogentcore.org/cogent/code/index"
+	"cogentcore.org/cogent/code/remote"
+	"cogentcore.org/cogent/code/snippets"
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/base/fileinfo/mimedata"
@@ -97,9 +101,49 @@ type Code struct {
 	// current debug view
 	CurDbg *DebugPanel `set:"-"`
 
+	// language servers for this project
+	LangServers LangServers `set:"-" json:"-" xml:"-"`
+
+	// problems reported by commands, shown in the Problems panel
+	Problems Problems `set:"-" json:"-" xml:"-"`
+
+	// code coverage loaded from a coverage profile, shown in the editors and file tree
+	Coverage *Coverage `set:"-" json:"-" xml:"-"`
+
+	// pprof profile shown in the Profile panel and the editors
+	Profile *Profile `set:"-" json:"-" xml:"-"`
+
+	// connection to the remote host for a project opened at an ssh:// url, nil if local
+	Remote *remote.FS `set:"-" json:"-" xml:"-"`
+
+	// trigram index of the files under the ProjectRoot, for fast project-wide search,
+	// which is built in the background; nil for a remote project
+	Index *index.Index `set:"-" json:"-" xml:"-"`
+
+	// provides the values for prompted argument variables of commands,
+	// which are prompted for in dialogs if nil
+	Prompter Prompter `set:"-" json:"-" xml:"-"`
+
+	// if set, the output and status of commands are written here as plain
+	// text instead of being shown in tabs, for running them without a GUI
+	Output io.Writer `set:"-" json:"-" xml:"-"`
+
+	// error from the last failed command run with Output
+	outputErr error
+
 	// first key in sequence if needs2 key pressed
 	KeySeq1 key.Chord `set:"-"`
 
+	// keyboard macro being recorded, nil if not recording
+	macroRecord *Macro
+
+	// last recorded keyboard macro, which is replayed by default
+	lastMacro Macro
+
+	// whether a keyboard macro is being replayed, so that its
+	// keys are not recorded again
+	macroReplaying bool
+
 	// mutex for protecting overall updates to Code
 	UpdateMu sync.Mutex `set:"-"`
 }
@@ -118,7 +162,13 @@ func (cv *Code) Init() {
 		s.Grow.Set(1, 1)
 	})
 
+	cv.LangServers.OnDiagnostics = cv.langServerDiagnostics
 	cv.AddCloseDialog()
+	cv.OnClose(func(e events.Event) {
+		cv.LangServers.Shutdown()
+		errors.Log(cv.Index.Close())
+		cv.closeRemote()
+	})
 	cv.OnFirst(events.KeyChord, cv.codeKeys)
 	cv.OnShow(func(e events.Event) {
 		cv.OpenConsoleTab()
@@ -151,23 +201,13 @@ func (cv *Code) Init() {
 				s.Overflow.Set(styles.OverflowAuto)
 			})
 			tree.AddChildAt(w, "filetree", func(w *filetree.Tree) {
-				w.OpenDepth = 4
 				cv.Files = w
-				w.FilterFunc = func(path string, info fs.FileInfo) bool {
-					if info.Name() == ".DS_Store" {
-						return false
-					}
-					return true
+				cv.configFileTree(w)
+			})
+			w.Maker(func(p *tree.Plan) {
+				for i := range cv.Settings.Roots {
+					tree.AddAt(p, fmt.Sprintf("root-%d", i), cv.configFileTree)
 				}
-				w.FileNodeType = types.For[FileNode]()
-
-				w.OnSelect(func(e events.Event) {
-					e.SetHandled()
-					sn := cv.SelectedFileNode()
-					if sn != nil {
-						cv.FileNodeSelected(sn)
-					}
-				})
 			})
 		})
 		w.Maker(func(p *tree.Plan) {
@@ -232,6 +272,8 @@ func (cv *Code) makeTextEditor(p *tree.Plan, i int) {
 			// todo: update
 			// ge.UpdateTextButtons()
 		})
+		tree.AddChildAt(w, "breadcrumbs-"+txnm, func(w *Breadcrumbs) {})
+		tree.AddChildAt(w, "sticky-"+txnm, func(w *StickyHeaders) {})
 		tree.AddChildAt(w, "texteditor-"+txnm, func(w *TextEditor) {
 			w.Code = cv
 			w.Styler(func(s *styles.Style) {
@@ -246,9 +288,7 @@ func (cv *Code) makeTextEditor(p *tree.Plan, i int) {
 			w.OnFocus(func(e events.Event) {
 				cv.ActiveEditorIndex = i
 				cv.updatePreviewPanel()
-				if w.Complete != nil {
-					w.Complete.LookupFunc = cv.LookupFun
-				}
+				cv.setCompleter(w)
 			})
 			// get updates on cursor movement and qreplace
 			w.OnInput(func(e events.Event) {
@@ -301,7 +341,7 @@ func (cv *Code) EditorButtonByIndex(idx int) *core.Button {
 
 // EditorByIndex returns the TextEditor by index (0 or 1), nil if not found
 func (cv *Code) EditorByIndex(idx int) *TextEditor {
-	return cv.Splits().Child(TextEditor1Index + idx).AsTree().Child(1).(*TextEditor)
+	return cv.Splits().Child(TextEditor1Index + idx).AsTree().ChildByName(fmt.Sprintf("texteditor-%d", idx), 3).(*TextEditor)
 }
 
 // Tabs returns the main TabView
@@ -322,20 +362,52 @@ func (cv *Code) StatusText() *core.Text {
 	return cv.StatusBar().Child(0).(*core.Text)
 }
 
+// configFileTree configures the given file tree for one of the project roots.
+func (cv *Code) configFileTree(w *filetree.Tree) {
+	w.OpenDepth = 4
+	w.DirsOnTop = cv.Settings.Files.DirsOnTop
+	w.FilterFunc = func(path string, info fs.FileInfo) bool {
+		if info.Name() == ".DS_Store" {
+			return false
+		}
+		return true
+	}
+	w.FileNodeType = types.For[FileNode]()
+
+	w.OnSelect(func(e events.Event) {
+		e.SetHandled()
+		sn := selectedFileNode(w)
+		if sn != nil {
+			cv.FileNodeSelected(sn)
+		}
+	})
+}
+
 // SelectedFileNode returns currently selected file tree node as a *filetree.Node
 // could be nil.
 func (cv *Code) SelectedFileNode() *filetree.Node {
-	n := len(cv.Files.SelectedNodes)
+	for _, ft := range cv.FileTrees() {
+		if sn := selectedFileNode(ft); sn != nil {
+			return sn
+		}
+	}
+	return nil
+}
+
+// selectedFileNode returns the last selected node in the given file tree, or nil.
+func selectedFileNode(ft *filetree.Tree) *filetree.Node {
+	n := len(ft.SelectedNodes)
 	if n == 0 {
 		return nil
 	}
-	return filetree.AsNode(cv.Files.SelectedNodes[n-1])
+	return filetree.AsNode(ft.SelectedNodes[n-1])
 }
 
 // VersionControl returns the version control system in effect,
-// using the file tree detected version or whatever is set in project settings.
+// using the file tree detected version or whatever is set in project settings,
+// for the project root containing the active file.
 func (cv *Code) VersionControl() vcs.Types {
-	return cv.Settings.VersionControl
+	return cv.Settings.RootFor(string(cv.ActiveFilename)).VersionControl
 }
 
 func (cv *Code) FocusOnTabs() bool {
@@ -346,10 +418,31 @@ func (cv *Code) FocusOnTabs() bool {
 
 // UpdateFiles updates the list of files saved in project
 func (cv *Code) UpdateFiles() { //types:add
-	if cv.Files != nil && cv.ProjectRoot != "" {
-		cv.Files.OpenPath(string(cv.ProjectRoot))
-		cv.Files.Open()
+	if cv.Files == nil || cv.ProjectRoot == "" {
+		return
+	}
+	roots := cv.Settings.AllRoots()
+	if len(cv.FileTrees()) != len(roots) {
+		cv.Files.Parent.(core.Widget).AsWidget().Update() // makes trees for Roots
+	}
+	roots[0].Path = cv.ProjectRoot
+	for i, ft := range cv.FileTrees() {
+		if i >= len(roots) {
+			break
+		}
+		if cv.IsRemote() {
+			ft.OpenPathFS(cv.Remote, string(roots[i].Path))
+		} else {
+			ft.OpenPath(string(roots[i].Path))
+		}
+		ft.Open()
+		if i > 0 && roots[i].VersionControl == vcs.NoVCS {
+			if repo, _ := ft.FirstVCS(); repo != nil {
+				cv.Settings.Roots[i-1].VersionControl = repo.Type()
+			}
+		}
 	}
+	cv.startIndex()
 }
 
 func (cv *Code) IsEmpty() bool {
@@ -404,12 +497,17 @@ func (cv *Code) OpenFile(fnm string) { //types:add
 // SetWindowNameTitle sets the window name and title based on current project name
 func (cv *Code) SetWindowNameTitle() {
 	title := "Cogent Code • " + cv.Name
+	if cv.IsRemote() {
+		title += " • " + cv.Remote.URL.Host
+	}
 	cv.Scene.Body.SetTitle(title)
 }
 
 // OpenPath creates a new project by opening given path, which can either be a
 // specific file or a folder containing multiple files of interest -- opens in
 // current Code object if it is empty, or otherwise opens a new window.
+// The path can also be an ssh://[user@]host[:port]/path url for a project
+// on a remote host, which is then edited and built over the SSH connection.
 func (cv *Code) OpenPath(path core.Filename) *Code { //types:add
 	empty := cv.IsEmpty()
 	ncv := cv.openPath(path)
@@ -423,6 +521,9 @@ func (cv *Code) OpenPath(path core.Filename) *Code { //types:add
 // specific file or a folder containing multiple files of interest -- opens in
 // current Code object if it is empty, or otherwise opens a new window.
 func (cv *Code) openPath(path core.Filename) *Code { //types:add
+	if remote.IsURL(string(path)) {
+		return cv.openRemote(string(path))
+	}
 	if gproj, has := CheckForProjectAtPath(string(path)); has {
 		return cv.openProject(core.Filename(gproj))
 	}
@@ -513,21 +614,38 @@ func (cv *Code) NewProject(path core.Filename, folder string, mainLang fileinfo.
 	return nge
 }
 
-// NewFile creates a new file in the project
-func (cv *Code) NewFile(filename string, addToVcs bool) { //types:add
+// NewFile creates a new file in the project, optionally from the given
+// template, which is inserted into the new file as a snippet, with its
+// tab stops to fill in.
+func (cv *Code) NewFile(filename string, template TemplateName, addToVcs bool) { //types:add
+	var sn *snippets.Snippet
+	if template != "" {
+		var ok bool
+		if _, sn, ok = template.Template(); !ok {
+			core.MessageSnackbar(cv, fmt.Sprintf("Template not found: %v", template))
+			return
+		}
+	}
 	np := filepath.Join(string(cv.ProjectRoot), filename)
-	_, err := os.Create(np)
+	f, err := os.Create(np)
 	if err != nil {
 		core.MessageDialog(cv, fmt.Sprintf("Could not make new file at: %v, err: %v", np, err), "Could not Make File")
 		return
 	}
-	cv.Files.UpdatePath(np)
+	f.Close()
+	cv.FileTreeFor(np).UpdatePath(np)
 	if addToVcs {
-		nfn, ok := cv.Files.FindFile(np)
+		nfn, ok := cv.FindFile(np)
 		if ok {
 			nfn.AddToVCS()
 		}
 	}
+	if sn != nil {
+		if tv, _, ok := cv.NextViewFile(np); ok {
+			tv.SetFocus()
+			tv.InsertSnippet(sn)
+		}
+	}
 }
 
 // SaveProject saves project file containing custom project settings, in a
@@ -570,7 +688,7 @@ func (cv *Code) SaveProjectAs(filename core.Filename) bool { //types:add
 	cv.ProjectFilename = cv.Settings.ProjectFilename
 	cv.GrabSettings()
 	cv.Settings.Save(filename)
-	cv.Files.UpdatePath(string(filename))
+	cv.FileTreeFor(string(filename)).UpdatePath(string(filename))
 	cv.Changed = false
 	return false
 }
@@ -692,6 +810,10 @@ func (cv *Code) AddCloseDialog() {
 // NewCodeProjectPath creates a new Code window with a new Code project for given
 // path, returning the window and the path
 func NewCodeProjectPath(path string) *Code {
+	if remote.IsURL(path) {
+		root, projnm := remoteProjectRoot(path)
+		return NewCodeWindow(path, projnm, root, true)
+	}
 	root, projnm, _, _ := ProjectPathParse(path)
 	return NewCodeWindow(path, projnm, root, true)
 }
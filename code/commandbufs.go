@@ -70,18 +70,31 @@ func (cv *Code) RecycleCmdTab(cmdName string) (*lines.Lines, *textcore.Editor, b
 // TabDeleted is called when a main tab is deleted -- we cancel any running commands
 func (cv *Code) TabDeleted(tabnm string) {
 	cv.RunningCmds.KillByName(tabnm)
+	if cm, _, ok := AvailableCommands.CmdByName(CmdName(tabnm), false); ok && cm.Parallel {
+		for i := range cm.Cmds {
+			cv.RunningCmds.KillByName(cm.stepName(i))
+		}
+	}
 }
 
 // ExecCmdName executes command of given name; this is the final common
 // pathway for all command invocation except on a node.
 func (cv *Code) ExecCmdName(cmdName CmdName) {
+	cv.execCmdName(cmdName, nil)
+}
+
+// execCmdName executes the command of the given name as in
+// [Code.ExecCmdName], calling done after it is complete as in
+// [Command.run]. It returns false if there is no such command.
+func (cv *Code) execCmdName(cmdName CmdName, done func(ok bool)) bool {
 	cmd, _, ok := AvailableCommands.CmdByName(cmdName, true)
 	if !ok {
-		return
+		return false
 	}
 	cv.SetArgVarVals()
 	cbuf, _, _ := cv.RecycleCmdTab(cmd.Name)
-	cmd.Run(cv, cbuf)
+	cmd.run(cv, cbuf, done)
+	return true
 }
 
 // ExecCmdNameFile executes command of given name on given file name
@@ -118,6 +131,7 @@ func (cv *Code) ExecCmdNameActive(cmdName string) { //types:add
 	if tv == nil {
 		return
 	}
+	cv.recordMacroCommand(CmdName(cmdName))
 	cv.SaveAllCheck(true, func() { // true = cancel option
 		cv.ExecCmdName(CmdName(cmdName))
 	})
@@ -5,15 +5,21 @@
 package code
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"cogentcore.org/cogent/code/remote"
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/base/iox/jsonx"
@@ -22,7 +28,6 @@ import (
 	"cogentcore.org/core/events"
 	"cogentcore.org/core/filetree"
 	"cogentcore.org/core/icons"
-	"cogentcore.org/core/styles"
 	"cogentcore.org/core/text/highlighting"
 	"cogentcore.org/core/text/lines"
 	"cogentcore.org/core/text/rich"
@@ -30,6 +35,7 @@ import (
 	"cogentcore.org/core/text/textcore"
 	"github.com/alecthomas/chroma/v2/lexers"
 	"github.com/mattn/go-shellwords"
+	"golang.org/x/crypto/ssh"
 )
 
 // CmdAndArgs contains the name of an external program to execute and args to
@@ -51,6 +57,10 @@ type CmdAndArgs struct {
 
 	// if true, then do not split any prompted string into separate space-separated fields -- otherwise do so, except for values within quotes
 	PromptIsString bool
+
+	// if specified, the directory to run this command in, overriding the Dir
+	// of the overall Command; e.g., use {RootPath}/cmd for a subdirectory.
+	Dir string `width:"20"`
 }
 
 // Label satisfies the Labeler interface
@@ -102,6 +112,8 @@ func (cm *CmdAndArgs) BindArgs(avp *ArgVarVals) []string {
 		case !cm.PromptIsString && argNm == "{PromptString1}":
 			fallthrough
 		case !cm.PromptIsString && argNm == "{PromptString2}":
+			fallthrough
+		case argNm == "{PromptFiles}":
 			flds, err := shellwords.Parse(av)
 			if err != nil {
 				fmt.Println(err)
@@ -168,20 +180,34 @@ type CmdRun struct {
 
 	// exec.Cmd for the command
 	Exec *exec.Cmd
+
+	// command run on the remote host instead, for a remote project
+	Remote *remote.Cmd
 }
 
 // Kill kills the process
 func (cm *CmdRun) Kill() {
+	if cm.Remote != nil {
+		cm.Remote.Kill()
+		return
+	}
 	if cm.Exec.Process != nil {
 		cm.Exec.Process.Kill()
 	}
 }
 
-// CmdRuns is a slice list of running commands
+// CmdRuns is a slice list of running commands.
+// Its methods are safe to call from multiple goroutines.
 type CmdRuns []*CmdRun
 
+// cmdRunsMu protects all CmdRuns, which are updated by
+// commands running in parallel.
+var cmdRunsMu sync.Mutex
+
 // Add adds a new running command
 func (rc *CmdRuns) Add(cm *CmdRun) {
+	cmdRunsMu.Lock()
+	defer cmdRunsMu.Unlock()
 	if *rc == nil {
 		*rc = make(CmdRuns, 0, 100)
 	}
@@ -190,17 +216,44 @@ func (rc *CmdRuns) Add(cm *CmdRun) {
 
 // AddCmd adds a new running command, creating CmdRun via args
 func (rc *CmdRuns) AddCmd(name, cmdstr string, cmdargs *CmdAndArgs, ex *exec.Cmd) {
-	cm := &CmdRun{name, cmdstr, cmdargs, ex}
+	cm := &CmdRun{Name: name, CmdStr: cmdstr, CmdArgs: cmdargs, Exec: ex}
 	rc.Add(cm)
 }
 
+// combinedOutput runs given command as in [exec.Cmd.CombinedOutput],
+// adding it to the running commands with given name once it has
+// started, so that it can be killed from any goroutine.
+func (rc *CmdRuns) combinedOutput(name, cmdstr string, cmdargs *CmdAndArgs, ex *exec.Cmd) ([]byte, error) {
+	var b bytes.Buffer
+	ex.Stdout = &b
+	ex.Stderr = &b
+	if err := ex.Start(); err != nil {
+		return nil, err
+	}
+	rc.AddCmd(name, cmdstr, cmdargs, ex)
+	err := ex.Wait()
+	return b.Bytes(), err
+}
+
 // DeleteIndex delete command at given index
 func (rc *CmdRuns) DeleteIndex(idx int) {
+	cmdRunsMu.Lock()
+	defer cmdRunsMu.Unlock()
+	rc.deleteIndex(idx)
+}
+
+func (rc *CmdRuns) deleteIndex(idx int) {
 	*rc = append((*rc)[:idx], (*rc)[idx+1:]...)
 }
 
 // ByName returns command with given name
 func (rc *CmdRuns) ByName(name string) (*CmdRun, int) {
+	cmdRunsMu.Lock()
+	defer cmdRunsMu.Unlock()
+	return rc.byName(name)
+}
+
+func (rc *CmdRuns) byName(name string) (*CmdRun, int) {
 	for i, cm := range *rc {
 		if cm.Name == name {
 			return cm, i
@@ -211,9 +264,11 @@ func (rc *CmdRuns) ByName(name string) (*CmdRun, int) {
 
 // DeleteByName deletes command by name
 func (rc *CmdRuns) DeleteByName(name string) bool {
-	_, idx := rc.ByName(name)
+	cmdRunsMu.Lock()
+	defer cmdRunsMu.Unlock()
+	_, idx := rc.byName(name)
 	if idx >= 0 {
-		rc.DeleteIndex(idx)
+		rc.deleteIndex(idx)
 		return true
 	}
 	return false
@@ -222,10 +277,12 @@ func (rc *CmdRuns) DeleteByName(name string) bool {
 // KillByName kills a running process by name, and removes it from the list of
 // running commands
 func (rc *CmdRuns) KillByName(name string) bool {
-	cm, idx := rc.ByName(name)
+	cmdRunsMu.Lock()
+	defer cmdRunsMu.Unlock()
+	cm, idx := rc.byName(name)
 	if idx >= 0 {
 		cm.Kill()
-		rc.DeleteIndex(idx)
+		rc.deleteIndex(idx)
 		return true
 	}
 	return false
@@ -258,17 +315,53 @@ type Command struct {
 	// values here; if not specified, directory will be project root directory.
 	Dir string `width:"20"`
 
+	// environment variables to set for the commands, in the form NAME=value,
+	// in addition to the current environment. Argument variables such as
+	// {ProjectPath} can be used in the values.
+	Env []string
+
+	// other commands that must be run successfully before this one, which
+	// are run in order, along with their own dependencies, each only once.
+	DependsOn CmdNames
+
 	// if true, we wait for the command to run before displaying output -- mainly for post-save commands and those with subsequent steps: if multiple commands are present, then it uses Wait mode regardless.
 	Wait bool
 
+	// if true and there are multiple Cmds, they are all run at the same time,
+	// each in its own process, instead of one after the other, and the command
+	// succeeds if all of them do.
+	Parallel bool
+
 	// if true, keyboard focus is directed to the command output tab panel after the command runs.
 	Focus bool
 
 	// if true, command requires Ok / Cancel confirmation dialog -- only needed for non-prompt commands
 	Confirm bool
 
+	// items to choose from for the {PromptChoice} argument variable
+	Choices []string
+
 	// what type of file to use for syntax highlighting.  Bash is the default.
 	Hilight fileinfo.Known
+
+	// if specified, a regular expression used to find problems (errors, warnings)
+	// in the command output, which are shown in the Problems panel and marked in
+	// the editors. It must have named groups file and line, and can have col,
+	// severity and msg, e.g., (?P<file>[^:]+):(?P<line>\d+): (?P<msg>.+)
+	ProblemRegexp string `width:"40"`
+
+	// if specified, a Go coverage profile written by the command, e.g., by
+	// go test -coverprofile, which is loaded after the command runs to show
+	// the coverage in the editors and file tree. A relative path is relative
+	// to the command directory, and argument variables can be used, e.g.,
+	// {TempDir} to keep it out of the source directories.
+	CoverProfile string `width:"20"`
+
+	// if specified, a pprof CPU or memory profile written by the command,
+	// e.g., by go test -cpuprofile, which is opened after the command runs
+	// to show it in the Profile panel and the editors. A relative path is
+	// relative to the command directory, and argument variables can be used.
+	Pprof string `width:"20"`
 }
 
 // CommandName returns a qualified command name as cat: cmd
@@ -351,67 +444,9 @@ func RepoCurBranches(repo vcs.Repo) (string, []string, error) {
 // PromptUser prompts for values that need prompting for, and then runs
 // RunAfterPrompts if not otherwise cancelled by user
 func (cm *Command) PromptUser(cv *Code, buf *lines.Lines, pvals map[string]struct{}) {
-	sz := len(pvals)
-	cnt := 0
-	tv := cv.ActiveEditor()
-	var cmvals map[string]string
-	for pv := range pvals {
-		switch pv {
-		case "{PromptString1}":
-			cmvals = CmdPrompt1Vals
-			fallthrough
-		case "{PromptString2}":
-			if cmvals == nil {
-				cmvals = CmdPrompt2Vals
-			}
-			curval, _ := cmvals[cm.Label()] // (*avp)[pv]
-			if curval == "" && cm.Cmds[0].Default != "" {
-				curval = cm.Cmds[0].Default
-			}
-			d := core.NewBody("Code Command Prompt")
-			core.NewText(d).SetType(core.TextSupporting).SetText(fmt.Sprintf("Command: %v: %v", cm.Name, cm.Desc))
-			tf := core.NewTextField(d).SetText(curval)
-			tf.Styler(func(s *styles.Style) {
-				s.Min.X.Ch(100)
-			})
-			d.AddBottomBar(func(bar *core.Frame) {
-				d.AddCancel(bar)
-				d.AddOK(bar).OnClick(func(e events.Event) {
-					val := tf.Text()
-					cmvals[cm.Label()] = val
-					cv.ArgVals[pv] = val
-					cnt++
-					if cnt == sz {
-						cm.RunAfterPrompts(cv, buf)
-					}
-				})
-			})
-			d.RunDialog(tv) // SetModal(false).
-
-		// todo: looks like all the file prompts are not supported?
-		case "{PromptBranch}":
-			ln := cv.ActiveLines()
-			if ln != nil {
-				repo := GetVCSRepo(ln)
-				if repo != nil {
-					cur, br, err := RepoCurBranches(repo)
-					if err == nil {
-						m := core.NewMenuFromStrings(br, cur, func(idx int) {
-							cv.ArgVals[pv] = br[idx]
-							cnt++
-							if cnt == sz {
-								cm.RunAfterPrompts(cv, buf)
-							}
-						})
-						m.Name = "prompt-branch"
-						core.NewMenuStage(m, tv, tv.ContextMenuPos(nil)).Run()
-					} else {
-						fmt.Println(err)
-					}
-				}
-			}
-		}
-	}
+	cm.Prompt(cv, cv.prompter(), pvals, func() {
+		cm.RunAfterPrompts(cv, buf)
+	})
 }
 
 // Run runs the command and saves the output in the Buf if it is non-nil,
@@ -419,13 +454,20 @@ func (cm *Command) PromptUser(cv *Code, buf *lines.Lines, pvals map[string]struc
 // occurs. Status is updated with status of command exec.  User is prompted
 // for any values that might be needed for command.
 func (cm *Command) Run(cv *Code, buf *lines.Lines) {
+	cm.run(cv, buf, nil)
+}
+
+// run runs the command as in [Command.Run], calling done with its success
+// after it is complete, if it is non-nil, with the Code locked.
+// done is not called if the user cancels the command.
+func (cm *Command) run(cv *Code, buf *lines.Lines, done func(ok bool)) {
 	if cm.Confirm {
 		d := core.NewBody("Confirm command")
 		core.NewText(d).SetType(core.TextSupporting).SetText(fmt.Sprintf("Command: %v: %v", cm.Label(), cm.Desc))
 		d.AddBottomBar(func(bar *core.Frame) {
 			d.AddCancel(bar)
 			d.AddOK(bar).SetText("Run").OnClick(func(e events.Event) {
-				cm.RunAfterPrompts(cv, buf)
+				cm.runAfterPrompts(cv, buf, done)
 			})
 		})
 		d.RunDialog(cv.AsWidget().Scene)
@@ -433,100 +475,361 @@ func (cm *Command) Run(cv *Code, buf *lines.Lines) {
 	}
 	pvals, hasp := cm.HasPrompts()
 	if !hasp || CmdNoUserPrompt {
-		cm.RunAfterPrompts(cv, buf)
+		cm.runAfterPrompts(cv, buf, done)
 		return
 	}
-	cm.PromptUser(cv, buf, pvals)
+	cm.Prompt(cv, cv.prompter(), pvals, func() {
+		cm.runAfterPrompts(cv, buf, done)
+	})
 }
 
-// RunAfterPrompts runs after any prompts have been set, if needed
+// RunAfterPrompts runs after any prompts have been set, if needed.
+// Any commands that this one DependsOn are run first, in their own
+// tabs, and it is only run if they all succeed. The user is prompted
+// for any values needed by the dependencies before any of them are run.
 func (cm *Command) RunAfterPrompts(cv *Code, buf *lines.Lines) {
+	cm.runAfterPrompts(cv, buf, nil)
+}
+
+// runAfterPrompts runs the command as in [Command.RunAfterPrompts],
+// calling done as in [Command.run].
+func (cm *Command) runAfterPrompts(cv *Code, buf *lines.Lines, done func(ok bool)) {
 	// ge.RunningCmds.KillByName(cm.Label()) // make sure nothing still running for us..
 	CmdNoUserPrompt = false
+	finish := func(ok bool) {
+		if done == nil {
+			return
+		}
+		if cv.Output == nil {
+			cv.AsyncLock()
+			defer cv.AsyncUnlock()
+		}
+		done(ok)
+	}
+	vals := maps.Clone(cv.ArgVals)
+	if len(cm.DependsOn) == 0 {
+		if done == nil {
+			cm.runCmds(cv, vals, buf, false)
+			return
+		}
+		go func() {
+			finish(cm.runCmds(cv, vals, buf, true))
+		}()
+		return
+	}
+	deps, err := cm.Dependencies()
+	if err != nil {
+		cm.AppendCmdOut(cv, buf, []rune(err.Error()))
+		core.ErrorSnackbar(cv, err)
+		return
+	}
+	promptDeps(cv, deps, func(dvals []ArgVarVals) {
+		dbufs := make([]*lines.Lines, len(deps))
+		if buf != nil {
+			for i, dc := range deps {
+				dbufs[i], _, _ = cv.RecycleCmdTab(dc.Label())
+			}
+			cv.SelectTabByName(cm.Label())
+		}
+		go func() {
+			for i, dc := range deps {
+				if !dc.runCmds(cv, dvals[i], dbufs[i], true) {
+					cm.AppendCmdOut(cv, buf, []rune(fmt.Sprintf("Not run: dependency %v failed", dc.Label())))
+					finish(false)
+					return
+				}
+			}
+			finish(cm.runCmds(cv, vals, buf, true))
+		}()
+	})
+}
+
+// promptDeps checks that each of the given dependencies applies to the
+// project, and prompts for any values that it needs, one after the other.
+// It then calls done with the argument values for each of them.
+// It does not call done if any of them do not apply or a prompt is cancelled.
+func promptDeps(cv *Code, deps []*Command, done func(vals []ArgVarVals)) {
+	vals := make([]ArgVarVals, len(deps))
+	var next func(i int)
+	next = func(i int) {
+		if i == len(deps) {
+			done(vals)
+			return
+		}
+		dc := deps[i]
+		if err := dc.applies(cv); err != nil {
+			core.ErrorSnackbar(cv, err)
+			return
+		}
+		prompted := func() {
+			vals[i] = maps.Clone(cv.ArgVals)
+			next(i + 1)
+		}
+		pvals, hasp := dc.HasPrompts()
+		if !hasp {
+			prompted()
+			return
+		}
+		dc.Prompt(cv, cv.prompter(), pvals, prompted)
+	}
+	next(0)
+}
+
+// applies returns an error if the command does not apply to the language
+// or version control system of the project, as in [Commands.FilterCmdNames].
+func (cm *Command) applies(cv *Code) error {
+	lang := cv.ActiveLang
+	if lang == fileinfo.Unknown {
+		lang = cv.Settings.MainLang
+	}
+	if !cm.LangMatch(lang) && !cm.LangMatch(cv.Settings.MainLang) {
+		return fmt.Errorf("command %v does not apply to language %v", cm.Label(), lang)
+	}
+	var vct vcs.Types
+	if vct.SetString(cm.Cat) == nil && vct != cv.VersionControl() {
+		return fmt.Errorf("command %v does not apply to version control system %v", cm.Label(), cv.VersionControl())
+	}
+	return nil
+}
+
+// runCmds runs the Cmds in the command directory, with given argument
+// values, waiting for them to complete if wait is true or they otherwise
+// need to be waited on, returning overall command success.
+// The argument values must not be changed while the command is running.
+func (cm *Command) runCmds(cv *Code, av ArgVarVals, buf *lines.Lines, wait bool) bool {
 	cdir := "{ProjectPath}"
 	if cm.Dir != "" {
 		cdir = cm.Dir
 	}
-	cds := cv.ArgVals.Bind(cdir)
-	err := os.Chdir(cds)
-	_ = err
+	cds := av.Bind(cdir)
 	cm.AppendCmdOut(cv, buf, []rune(fmt.Sprintf("cd %v (from: %v)", cds, cdir)))
-	if err != nil {
-		cm.AppendCmdOut(cv, buf, []rune(fmt.Sprintf("Could not change to directory %v -- error: %v", cds, err)))
-	}
 
-	if CmdWaitOverride || cm.Wait || len(cm.Cmds) > 1 {
+	switch {
+	case len(cm.Cmds) == 0:
+		return true
+	case cm.Parallel && len(cm.Cmds) > 1:
+		if !wait {
+			go cm.runParallel(cv, av, buf)
+			return true
+		}
+		return cm.runParallel(cv, av, buf)
+	case wait || CmdWaitOverride || cm.Wait || len(cm.Cmds) > 1:
 		for i := range cm.Cmds {
-			cma := &cm.Cmds[i]
-			if buf == nil {
-				if !cm.RunNoBuf(cv, cma) {
-					break
-				}
-			} else {
-				if !cm.RunBufWait(cv, buf, cma) {
-					break
-				}
+			if !cm.runStep(cv, av, buf, &cm.Cmds[i], true) {
+				return false
 			}
 		}
-	} else if len(cm.Cmds) > 0 {
-		cma := &cm.Cmds[0]
-		if buf == nil {
-			go cm.RunNoBuf(cv, cma)
-		} else {
-			go cm.RunBuf(cv, buf, cma)
+		return true
+	default:
+		go cm.runStep(cv, av, buf, &cm.Cmds[0], false)
+		return true
+	}
+}
+
+// runParallel runs all of the Cmds at the same time, waiting for all of
+// them to complete, and returns true if they were all successful.
+// The results of the Cmds are loaded once, after all of them are done.
+func (cm *Command) runParallel(cv *Code, av ArgVarVals, buf *lines.Lines) bool {
+	oks := make([]bool, len(cm.Cmds))
+	outs := make([][]byte, len(cm.Cmds))
+	var wg sync.WaitGroup
+	for i := range cm.Cmds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			oks[i], outs[i] = cm.runParallelStep(cv, av, buf, i)
+		}()
+	}
+	wg.Wait()
+	ok := !slices.Contains(oks, false)
+	if cv.Output == nil {
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+	}
+	status := "successful"
+	if !ok {
+		status = "failed"
+	}
+	cm.finishStatus(cv, av, buf, ok, bytes.Join(outs, nil), cm.Label(), status)
+	return ok
+}
+
+// runParallelStep runs the given index of the Cmds in [Command.runParallel],
+// reporting its status without loading its results, and returns its success
+// and output, which is only returned if there is no buffer.
+func (cm *Command) runParallelStep(cv *Code, av ArgVarVals, buf *lines.Lines, i int) (bool, []byte) {
+	if cv.IsRemote() {
+		return cm.runRemoteStep(cv, av, buf, i)
+	}
+	cma := &cm.Cmds[i]
+	cmd, cmdstr := cm.prepCmd(av, cma)
+	if cmd == nil {
+		return false, nil
+	}
+	out, err := cv.RunningCmds.combinedOutput(cm.stepName(i), cmdstr, cma, cmd)
+	cv.RunningCmds.DeleteByName(cm.stepName(i))
+	cm.AppendCmdOut(cv, buf, []rune(string(out)))
+	ok, _ := cm.stepStatus(cv, buf, cmdstr, err, out)
+	if buf != nil {
+		out = nil
+	}
+	return ok, out
+}
+
+// runStep runs one of the Cmds, with output to the buffer if non-nil,
+// incrementally if !wait, returning command success.
+func (cm *Command) runStep(cv *Code, av ArgVarVals, buf *lines.Lines, cma *CmdAndArgs, wait bool) bool {
+	switch {
+	case buf == nil && cv.Output == nil:
+		return cm.RunNoBuf(cv, av, cma)
+	case wait:
+		return cm.RunBufWait(cv, av, buf, cma)
+	default:
+		return cm.RunBuf(cv, av, buf, cma)
+	}
+}
+
+// stepName returns the name of the given index of the Cmds in the
+// running commands when they are run in parallel, so that each
+// can be stopped.
+func (cm *Command) stepName(i int) string {
+	return fmt.Sprintf("%v [%d]", cm.Label(), i+1)
+}
+
+// Dependencies returns the commands that this command DependsOn, directly or
+// indirectly, in the order in which they need to be run, with each only once.
+// It returns an error for an unknown command or a cycle of dependencies.
+func (cm *Command) Dependencies() ([]*Command, error) {
+	var deps []*Command
+	visiting := map[string]bool{}
+	done := map[string]bool{}
+	var visit func(c *Command) error
+	visit = func(c *Command) error {
+		visiting[c.Label()] = true
+		for _, dn := range c.DependsOn {
+			dc, _, ok := AvailableCommands.CmdByName(dn, false)
+			switch {
+			case !ok:
+				return fmt.Errorf("command %v depends on unknown command %v", c.Label(), dn)
+			case visiting[dc.Label()]:
+				return fmt.Errorf("command %v has a dependency cycle through %v", cm.Label(), dc.Label())
+			case done[dc.Label()]:
+				continue
+			}
+			if err := visit(dc); err != nil {
+				return err
+			}
+			deps = append(deps, dc)
 		}
+		visiting[c.Label()] = false
+		done[c.Label()] = true
+		return nil
 	}
+	return deps, visit(cm)
 }
 
-// RunBufWait runs a command with output to the buffer, using CombinedOutput
-// so it waits for completion -- returns overall command success, and logs one
-// line of the command output to code statusbar
-func (cm *Command) RunBufWait(cv *Code, buf *lines.Lines, cma *CmdAndArgs) bool {
-	cmd, cmdstr := cma.PrepCmd(&cv.ArgVals)
+// prepCmd prepares to run the given one of the Cmds locally with given
+// argument values, as in [CmdAndArgs.PrepCmd], setting its directory
+// and environment.
+func (cm *Command) prepCmd(av ArgVarVals, cma *CmdAndArgs) (*exec.Cmd, string) {
+	cmd, cmdstr := cma.PrepCmd(&av)
+	if cmd == nil {
+		return nil, cmdstr
+	}
+	cmd.Dir = cm.stepDir(av, cma)
+	if env := cm.bindEnv(av); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	return cmd, cmdstr
+}
+
+// stepDir returns the directory to run the given one of the Cmds in.
+func (cm *Command) stepDir(av ArgVarVals, cma *CmdAndArgs) string {
+	switch {
+	case cma.Dir != "":
+		return av.Bind(cma.Dir)
+	case cm.Dir != "":
+		return av.Bind(cm.Dir)
+	}
+	return av.Bind("{ProjectPath}")
+}
+
+// bindEnv returns the Env with any argument variables replaced by their values.
+func (cm *Command) bindEnv(av ArgVarVals) []string {
+	if len(cm.Env) == 0 {
+		return nil
+	}
+	env := make([]string, len(cm.Env))
+	for i, ev := range cm.Env {
+		env[i] = av.Bind(ev)
+	}
+	return env
+}
+
+// RunBufWait runs a command with given argument values and output to the
+// buffer, using CombinedOutput so it waits for completion -- returns overall
+// command success, and logs one line of the command output to code statusbar
+func (cm *Command) RunBufWait(cv *Code, av ArgVarVals, buf *lines.Lines, cma *CmdAndArgs) bool {
+	if cv.IsRemote() {
+		return cm.runRemote(cv, av, buf, cma, true)
+	}
+	cmd, cmdstr := cm.prepCmd(av, cma)
 	if cmd == nil {
 		return false
 	}
-	cv.RunningCmds.AddCmd(cm.Label(), cmdstr, cma, cmd)
-	out, err := cmd.CombinedOutput()
+	out, err := cv.RunningCmds.combinedOutput(cm.Label(), cmdstr, cma, cmd)
 	cm.AppendCmdOut(cv, buf, []rune(string(out)))
-	return cm.RunStatus(cv, buf, cmdstr, err, out)
+	return cm.RunStatus(cv, av, buf, cmdstr, err, out)
 }
 
-// RunBuf runs a command with output to the buffer, incrementally updating the
-// buffer with new results line-by-line as they come in
-func (cm *Command) RunBuf(cv *Code, buf *lines.Lines, cma *CmdAndArgs) bool {
-	cmd, cmdstr := cma.PrepCmd(&cv.ArgVals)
+// RunBuf runs a command with given argument values and output to the buffer,
+// incrementally updating the buffer with new results line-by-line as they come in
+func (cm *Command) RunBuf(cv *Code, av ArgVarVals, buf *lines.Lines, cma *CmdAndArgs) bool {
+	if cv.IsRemote() {
+		return cm.runRemote(cv, av, buf, cma, false)
+	}
+	cmd, cmdstr := cm.prepCmd(av, cma)
 	if cmd == nil {
 		return false
 	}
-	cv.RunningCmds.AddCmd(cm.Label(), cmdstr, cma, cmd)
 	stdout, err := cmd.StdoutPipe()
 	if err == nil {
 		cmd.Stderr = cmd.Stdout
 		err = cmd.Start()
 		if err == nil {
+			cv.RunningCmds.AddCmd(cm.Label(), cmdstr, cma, cmd)
 			obuf := textcore.OutputBuffer{}
 			obuf.SetOutput(stdout).SetLines(buf).SetMarkupFunc(cm.MarkupCmdOutput)
 			obuf.MonitorOutput()
 		}
 		err = cmd.Wait()
 	}
-	return cm.RunStatus(cv, buf, cmdstr, err, nil)
+	return cm.RunStatus(cv, av, buf, cmdstr, err, nil)
 }
 
-// RunNoBuf runs a command without any output to the buffer -- can call using
-// go as a goroutine for no-wait case -- returns overall command success, and
-// logs one line of the command output to code statusbar
-func (cm *Command) RunNoBuf(cv *Code, cma *CmdAndArgs) bool {
-	cmd, cmdstr := cma.PrepCmd(&cv.ArgVals)
-	cv.RunningCmds.AddCmd(cm.Label(), cmdstr, cma, cmd)
-	out, err := cmd.CombinedOutput()
-	return cm.RunStatus(cv, nil, cmdstr, err, out)
+// RunNoBuf runs a command with given argument values without any output to
+// the buffer -- can call using go as a goroutine for no-wait case -- returns
+// overall command success, and logs one line of the command output to code statusbar
+func (cm *Command) RunNoBuf(cv *Code, av ArgVarVals, cma *CmdAndArgs) bool {
+	if cv.IsRemote() {
+		return cm.runRemote(cv, av, nil, cma, true)
+	}
+	cmd, cmdstr := cm.prepCmd(av, cma)
+	if cmd == nil {
+		return false
+	}
+	out, err := cv.RunningCmds.combinedOutput(cm.Label(), cmdstr, cma, cmd)
+	return cm.RunStatus(cv, av, nil, cmdstr, err, out)
 }
 
 // AppendCmdOut appends command output to buffer, applying markup for links
 func (cm *Command) AppendCmdOut(cv *Code, buf *lines.Lines, out []rune) {
 	if buf == nil {
+		if cv.Output != nil && len(out) > 0 {
+			cv.UpdateMu.Lock() // steps can run in parallel
+			cv.Output.Write([]byte(strings.TrimSuffix(string(out), "\n") + "\n"))
+			cv.UpdateMu.Unlock()
+		}
 		return
 	}
 
@@ -547,8 +850,18 @@ var CmdOutStatusLen = 80
 // RunStatus reports the status of the command run (given in cmdstr) to
 // ge.StatusBar, and appends to the buffer.
 // Returns true if there are no errors, and false if there were errors.
-func (cm *Command) RunStatus(cv *Code, buf *lines.Lines, cmdstr string, err error, out []byte) bool {
+func (cm *Command) RunStatus(cv *Code, av ArgVarVals, buf *lines.Lines, cmdstr string, err error, out []byte) bool {
 	cv.RunningCmds.DeleteByName(cm.Label())
+	rval, outstr := cm.stepStatus(cv, buf, cmdstr, err, out)
+	cm.finishStatus(cv, av, buf, rval, out, cmdstr, outstr)
+	return rval
+}
+
+// stepStatus reports the status of one command run (given in cmdstr)
+// to the output and appends it to the buffer, returning true if there
+// are no errors, and the first line of the output for the status bar.
+// It is safe to call for commands running in parallel.
+func (cm *Command) stepStatus(cv *Code, buf *lines.Lines, cmdstr string, err error, out []byte) (bool, string) {
 	var rval bool
 	var sty *rich.Style
 	if buf != nil {
@@ -560,7 +873,7 @@ func (cm *Command) RunStatus(cv *Code, buf *lines.Lines, cmdstr string, err erro
 	bold.SetWeight(rich.Bold)
 	outstr := ""
 	if out != nil {
-		outstr = string(out[:CmdOutStatusLen])
+		outstr = string(out[:min(len(out), CmdOutStatusLen)])
 		outlns := strings.Split(outstr, "\n")
 		outstr = outlns[0]
 	}
@@ -569,20 +882,25 @@ func (cm *Command) RunStatus(cv *Code, buf *lines.Lines, cmdstr string, err erro
 	if err == nil {
 		finstat.AddSpan(&bold, []rune(" successful")).AddSpan(sty, []rune(" at: "+tstr))
 		rval = true
-	} else if ee, ok := err.(*exec.ExitError); ok {
-		finstat.AddSpan(&bold, []rune("failed")).AddSpan(sty, []rune(" at: "+tstr)).
-			AddSpan(sty, []rune(" with error: "+ee.Error()))
+	} else if isExitError(err) {
+		finstat.AddSpan(&bold, []rune(" failed")).AddSpan(sty, []rune(" at: "+tstr)).
+			AddSpan(sty, []rune(" with error: "+err.Error()))
 		rval = false
 	} else {
-		finstat.AddSpan(&bold, []rune("exec error")).AddSpan(sty, []rune(" at: "+tstr)).
+		finstat.AddSpan(&bold, []rune(" exec error")).AddSpan(sty, []rune(" at: "+tstr)).
 			AddSpan(sty, []rune(" error: "+err.Error()))
 		rval = false
 	}
-	if buf != nil {
-		buf.SetReadOnly(true)
+	if cv.Output != nil {
+		cv.UpdateMu.Lock()
+		fmt.Fprintln(cv.Output, string(finstat.Join()))
 		if err != nil {
-			cv.SelectTabByName(cm.Label()) // sometimes it isn't
+			cv.outputErr = err
 		}
+		cv.UpdateMu.Unlock()
+	}
+	if buf != nil {
+		buf.SetReadOnly(true)
 		lns := [][]rune{[]rune{}, finstat.Join()}
 		mu := []rich.Text{rich.NewText(sty, nil), finstat}
 		if len(outstr) > 0 {
@@ -591,12 +909,127 @@ func (cm *Command) RunStatus(cv *Code, buf *lines.Lines, cmdstr string, err erro
 			mu = append(mu, cm.MarkupCmdOutput(buf, rout))
 		}
 		buf.AppendTextMarkup(lns, mu)
+	}
+	return rval, outstr
+}
+
+// finishStatus finishes a command run once all of its Cmds are done,
+// showing its tab, loading its problems, coverage and profile,
+// and updating the status bar with given command and output strings.
+func (cm *Command) finishStatus(cv *Code, av ArgVarVals, buf *lines.Lines, ok bool, out []byte, cmdstr, outstr string) {
+	if buf != nil {
+		if !ok {
+			cv.SelectTabByName(cm.Label()) // sometimes it isn't
+		}
 		if cm.Focus {
 			cv.FocusOnTabs()
 		}
 	}
+	cm.parseProblems(cv, av, buf, out)
+	cm.loadCoverage(cv, av, buf)
+	cm.loadProfile(cv, av, buf)
+	if cm.Cat == "Git" {
+		cv.gitCommandDone(cm.runDir(av, buf), ok)
+	}
 	cv.SetStatus(cmdstr + " " + outstr)
-	return rval
+}
+
+// isExitError returns true if the given error is from a command
+// that exited with a non-zero status, locally or on the remote host.
+func isExitError(err error) bool {
+	_, ok := ExitCode(err)
+	return ok
+}
+
+// ExitCode returns the exit status of the command that returned the given
+// error, run locally or on the remote host, and whether it is from a
+// command that exited with a non-zero status.
+func ExitCode(err error) (int, bool) {
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return ee.ExitCode(), true
+	}
+	var se *ssh.ExitError
+	if errors.As(err, &se) {
+		return se.ExitStatus(), true
+	}
+	return 0, false
+}
+
+// parseProblems parses the problems in the command output if the
+// command has a ProblemRegexp, replacing its previous problems.
+func (cm *Command) parseProblems(cv *Code, av ArgVarVals, buf *lines.Lines, out []byte) {
+	if cm.ProblemRegexp == "" {
+		return
+	}
+	re, err := regexp.Compile(cm.ProblemRegexp)
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Invalid ProblemRegexp for command "+cm.Label())
+		return
+	}
+	if buf != nil {
+		out = buf.Text()
+	}
+	cv.SetProblems(cm.Label(), ParseProblems(re, string(out), cm.runDir(av, buf), cm.Label()))
+}
+
+// loadCoverage loads the CoverProfile if the command has one,
+// replacing any previous coverage.
+func (cm *Command) loadCoverage(cv *Code, av ArgVarVals, buf *lines.Lines) {
+	if cm.CoverProfile == "" {
+		return
+	}
+	if cv.remoteUnsupported("Coverage") {
+		return
+	}
+	dir := cm.runDir(av, buf)
+	fname := av.Bind(cm.CoverProfile)
+	if !filepath.IsAbs(fname) {
+		fname = filepath.Join(dir, fname)
+	}
+	cov, err := OpenCoverage(fname, dir)
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Could not load coverage for command "+cm.Label())
+		return
+	}
+	cv.SetCoverage(cov)
+}
+
+// loadProfile opens the Pprof profile if the command has one,
+// replacing any previous profile.
+func (cm *Command) loadProfile(cv *Code, av ArgVarVals, buf *lines.Lines) {
+	if cm.Pprof == "" {
+		return
+	}
+	if cv.remoteUnsupported("Profiling") {
+		return
+	}
+	dir := cm.runDir(av, buf)
+	fname := av.Bind(cm.Pprof)
+	if !filepath.IsAbs(fname) {
+		fname = filepath.Join(dir, fname)
+	}
+	pr, err := OpenProfile(fname, dir)
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Could not open profile for command "+cm.Label())
+		return
+	}
+	cv.SetProfile(pr)
+}
+
+// runDir returns the directory the command ran in, from the
+// output buffer if available, and otherwise from the Dir.
+func (cm *Command) runDir(av ArgVarVals, buf *lines.Lines) string {
+	if buf != nil {
+		if dir := cmdOutputDir(buf); dir != "" {
+			return dir
+		}
+	}
+	cdir := "{ProjectPath}"
+	if cm.Dir != "" {
+		cdir = cm.Dir
+	}
+	return av.Bind(cdir)
 }
 
 // LangMatch returns true if the given language matches the command Lang constraints
@@ -0,0 +1,37 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencies(t *testing.T) {
+	avail := AvailableCommands
+	defer func() { AvailableCommands = avail }()
+	gen := &Command{Cat: "Task", Name: "Generate"}
+	build := &Command{Cat: "Task", Name: "Build", DependsOn: CmdNames{"Task: Generate"}}
+	lint := &Command{Cat: "Task", Name: "Lint", DependsOn: CmdNames{"Task: Generate"}}
+	test := &Command{Cat: "Task", Name: "Test", DependsOn: CmdNames{"Task: Build", "Task: Lint"}}
+	AvailableCommands = Commands{gen, build, lint, test}
+
+	deps, err := test.Dependencies()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Command{gen, build, lint}, deps)
+
+	deps, err = gen.Dependencies()
+	assert.NoError(t, err)
+	assert.Empty(t, deps)
+
+	gen.DependsOn = CmdNames{"Task: Test"}
+	_, err = test.Dependencies()
+	assert.ErrorContains(t, err, "dependency cycle")
+
+	gen.DependsOn = CmdNames{"Task: Missing"}
+	_, err = build.Dependencies()
+	assert.ErrorContains(t, err, "unknown command Task: Missing")
+}
@@ -0,0 +1,351 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/token"
+)
+
+// CoverBlock is a block of statements in a Go coverage profile,
+// with 1-based lines and columns.
+type CoverBlock struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+
+	// number of statements in the block
+	NumStmt int
+
+	// number of times the block was executed (0 or 1 in set mode)
+	Count int
+}
+
+// Coverage is the code coverage from a Go coverage profile,
+// as written by go test -coverprofile.
+type Coverage struct {
+
+	// Mode is the coverage mode: set, count or atomic.
+	Mode string
+
+	// Files are the coverage blocks for each file, by absolute path,
+	// sorted by position.
+	Files map[string][]CoverBlock
+
+	// dirs are the numbers of covered and total statements in each
+	// directory containing any of the Files, computed by [Coverage.sumDirs].
+	dirs map[string][2]int
+}
+
+// OpenCoverage opens the Go coverage profile in given file, finding
+// the files in the module containing given directory.
+func OpenCoverage(fname, dir string) (*Coverage, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	root, mod := findModule(dir)
+	return ParseCoverage(f, func(file string) string {
+		switch {
+		case filepath.IsAbs(file):
+			return file
+		case mod != "" && strings.HasPrefix(file, mod+"/"):
+			return filepath.Join(root, filepath.FromSlash(file[len(mod)+1:]))
+		}
+		return "" // not in this module
+	})
+}
+
+// ParseCoverage parses a Go coverage profile, using given function to get the
+// absolute path for each file in the profile, which are import path based.
+// Files for which it returns "" are skipped. Blocks at the same position,
+// from different test binaries, are merged.
+func ParseCoverage(r io.Reader, path func(file string) string) (*Coverage, error) {
+	c := &Coverage{Files: map[string][]CoverBlock{}}
+	blocks := map[string]map[CoverBlock]int{}
+	sc := bufio.NewScanner(r)
+	ln := 0
+	for sc.Scan() {
+		ln++
+		txt := strings.TrimSpace(sc.Text())
+		if txt == "" {
+			continue
+		}
+		if mode, ok := strings.CutPrefix(txt, "mode: "); ok {
+			c.Mode = mode
+			continue
+		}
+		file, blk, err := parseCoverLine(txt)
+		if err != nil {
+			return nil, fmt.Errorf("coverage profile line %d: %w", ln, err)
+		}
+		fpath := path(file)
+		if fpath == "" {
+			continue
+		}
+		fb := blocks[fpath]
+		if fb == nil {
+			fb = map[CoverBlock]int{}
+			blocks[fpath] = fb
+		}
+		cnt := blk.Count
+		blk.Count = 0
+		if c.Mode == "set" {
+			fb[blk] = max(fb[blk], cnt)
+		} else {
+			fb[blk] += cnt
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for fpath, fb := range blocks {
+		bs := make([]CoverBlock, 0, len(fb))
+		for blk, cnt := range fb {
+			blk.Count = cnt
+			bs = append(bs, blk)
+		}
+		sort.Slice(bs, func(i, j int) bool {
+			if bs[i].StartLine != bs[j].StartLine {
+				return bs[i].StartLine < bs[j].StartLine
+			}
+			return bs[i].StartCol < bs[j].StartCol
+		})
+		c.Files[fpath] = bs
+	}
+	return c, nil
+}
+
+// parseCoverLine parses a profile line of the form
+// file:startLine.startCol,endLine.endCol numStmt count
+func parseCoverLine(txt string) (string, CoverBlock, error) {
+	blk := CoverBlock{}
+	ci := strings.LastIndex(txt, ":")
+	if ci < 0 {
+		return "", blk, fmt.Errorf("no file name in %q", txt)
+	}
+	fs := strings.Fields(txt[ci+1:])
+	if len(fs) != 3 {
+		return "", blk, fmt.Errorf("invalid block %q", txt)
+	}
+	se, ee, _ := strings.Cut(fs[0], ",")
+	vals := []*int{&blk.NumStmt, &blk.Count}
+	for i, s := range []string{fs[1], fs[2]} {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return "", blk, err
+		}
+		*vals[i] = v
+	}
+	var err error
+	if blk.StartLine, blk.StartCol, err = parseCoverPos(se); err != nil {
+		return "", blk, err
+	}
+	if blk.EndLine, blk.EndCol, err = parseCoverPos(ee); err != nil {
+		return "", blk, err
+	}
+	return txt[:ci], blk, nil
+}
+
+// parseCoverPos parses a line.col position.
+func parseCoverPos(s string) (int, int, error) {
+	ls, cs, _ := strings.Cut(s, ".")
+	ln, err := strconv.Atoi(ls)
+	if err != nil {
+		return 0, 0, err
+	}
+	col, err := strconv.Atoi(cs)
+	return ln, col, err
+}
+
+// Statements returns the number of covered and total statements for given
+// file, or for all of the files within it if it is a directory.
+func (c *Coverage) Statements(path string, isDir bool) (covered, total int) {
+	add := func(bs []CoverBlock) {
+		for _, b := range bs {
+			total += b.NumStmt
+			if b.Count > 0 {
+				covered += b.NumStmt
+			}
+		}
+	}
+	if !isDir {
+		add(c.Files[path])
+		return
+	}
+	if c.dirs != nil {
+		d := c.dirs[path]
+		return d[0], d[1]
+	}
+	pfx := path + string(filepath.Separator)
+	for fpath, bs := range c.Files {
+		if strings.HasPrefix(fpath, pfx) {
+			add(bs)
+		}
+	}
+	return
+}
+
+// sumDirs computes the numbers of statements in each directory
+// containing any of the Files, so that [Coverage.Statements] does
+// not need to scan all of them for each directory.
+func (c *Coverage) sumDirs() {
+	c.dirs = map[string][2]int{}
+	for fpath := range c.Files {
+		cov, tot := c.Statements(fpath, false)
+		for dir := filepath.Dir(fpath); ; dir = filepath.Dir(dir) {
+			d := c.dirs[dir]
+			c.dirs[dir] = [2]int{d[0] + cov, d[1] + tot}
+			if pdir := filepath.Dir(dir); pdir == dir {
+				break
+			}
+		}
+	}
+}
+
+// Percent returns the percent of statements covered in given file
+// or directory, and false if there are no statements in it.
+func (c *Coverage) Percent(path string, isDir bool) (float32, bool) {
+	cov, tot := c.Statements(path, isDir)
+	if tot == 0 {
+		return 0, false
+	}
+	return 100 * float32(cov) / float32(tot), true
+}
+
+// LineCoverage returns the coverage of each of the first n lines of
+// given file: 1 if covered, -1 if not covered, and 0 if there are no
+// statements. Lines that are only partly covered are not covered.
+func (c *Coverage) LineCoverage(path string, n int) []int {
+	lc := make([]int, n)
+	for _, b := range c.Files[path] {
+		for ln := max(b.StartLine, 1); ln <= min(b.EndLine, n); ln++ {
+			switch {
+			case b.Count == 0:
+				lc[ln-1] = -1
+			case lc[ln-1] == 0:
+				lc[ln-1] = 1
+			}
+		}
+	}
+	return lc
+}
+
+////////  Markers
+
+// coverageColor returns the line number gutter color for covered or not
+// covered lines, which is distinct from the [problemColor]s and [DebugBreakColors].
+func coverageColor(covered bool) image.Image {
+	if covered {
+		return colors.Scheme.Success.Container
+	}
+	return colors.Scheme.Tertiary.Container
+}
+
+// coverageToken returns the tag token used to mark covered
+// or not covered blocks of statements in the text.
+func coverageToken(covered bool) token.Tokens {
+	if covered {
+		return token.TextStyleInserted
+	}
+	return token.TextStyleDeleted
+}
+
+// SetCoverageMarkers marks the covered and not covered blocks of statements
+// of given lines in the text, and their lines in the line number gutter
+// on lines that do not already have a marker, so that breakpoints and problems
+// take precedence over coverage. It replaces any existing coverage markers.
+func SetCoverageMarkers(ln *lines.Lines, c *Coverage) {
+	ClearCoverageMarkers(ln)
+	nln := ln.NumLines()
+	for li, lc := range c.LineCoverage(ln.Filename(), nln) {
+		if lc == 0 {
+			continue
+		}
+		if _, has := ln.LineColor(li); has {
+			continue
+		}
+		setGutterMarker(ln, li, coverageMarker, coverageColor(lc > 0))
+	}
+	for _, b := range c.Files[ln.Filename()] {
+		for li := max(b.StartLine-1, 0); li < min(b.EndLine, nln); li++ {
+			st, ed := 0, len(ln.Line(li))
+			if li == b.StartLine-1 {
+				st = min(max(b.StartCol-1, 0), ed)
+			}
+			if li == b.EndLine-1 {
+				ed = min(max(b.EndCol-1, st), ed)
+			}
+			if ed > st {
+				addMarkerTag(ln, li, st, ed, coverageMarker, coverageToken(b.Count > 0))
+			}
+		}
+	}
+}
+
+// ClearCoverageMarkers removes all coverage markers from given lines.
+func ClearCoverageMarkers(ln *lines.Lines) {
+	clearGutterMarkers(ln, coverageMarker)
+	clearMarkerTags(ln, coverageMarker)
+}
+
+////////  Code
+
+// SetCoverage sets the code coverage, and updates the markers in open
+// files and the file tree. It can be called from any goroutine.
+func (cv *Code) SetCoverage(c *Coverage) {
+	if c != nil {
+		c.sumDirs()
+	}
+	if cv.Output != nil { // no GUI
+		cv.Coverage = c
+		return
+	}
+	go func() { // avoid deadlock when called from the event loop
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+		cv.Coverage = c
+		cv.updateCoverage()
+	}()
+}
+
+// ClearCoverage removes the code coverage and its markers.
+func (cv *Code) ClearCoverage() { //types:add
+	cv.Coverage = nil
+	cv.updateCoverage()
+}
+
+// updateCoverage updates the coverage markers in all open files,
+// and the coverage shown in the file tree.
+func (cv *Code) updateCoverage() {
+	for _, ln := range cv.OpenFiles.Values {
+		ClearCoverageMarkers(ln)
+		cv.coverageOpened(ln)
+		if ed, _, ok := cv.EditorForLines(ln); ok {
+			ed.NeedsRender()
+		}
+	}
+	for _, ft := range cv.FileTrees() {
+		ft.Update()
+	}
+}
+
+// coverageOpened applies any coverage markers to newly opened lines.
+func (cv *Code) coverageOpened(ln *lines.Lines) {
+	if cv.Coverage != nil {
+		SetCoverageMarkers(ln, cv.Coverage)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/token"
+	"github.com/stretchr/testify/assert"
+)
+
+const testCoverProfile = `mode: set
+example.com/m/pkg/add.go:3.24,4.15 1 1
+example.com/m/pkg/add.go:4.15,6.3 1 0
+example.com/m/pkg/add.go:7.2,7.14 1 1
+example.com/m/pkg/add.go:3.24,4.15 1 0
+example.com/m/pkg/sub/sub.go:3.20,5.2 2 0
+golang.org/x/other/other.go:1.1,2.2 1 1
+`
+
+func TestCoverage(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0666))
+	fname := filepath.Join(dir, "coverage.out")
+	assert.NoError(t, os.WriteFile(fname, []byte(testCoverProfile), 0666))
+
+	c, err := OpenCoverage(fname, filepath.Join(dir, "pkg"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "set", c.Mode)
+	add := filepath.Join(dir, "pkg", "add.go")
+	assert.Len(t, c.Files, 2)
+	if assert.Len(t, c.Files[add], 3) {
+		assert.Equal(t, CoverBlock{StartLine: 3, StartCol: 24, EndLine: 4, EndCol: 15, NumStmt: 1, Count: 1}, c.Files[add][0])
+	}
+
+	cov, tot := c.Statements(add, false)
+	assert.Equal(t, 2, cov)
+	assert.Equal(t, 3, tot)
+	pct, ok := c.Percent(filepath.Join(dir, "pkg"), true)
+	assert.True(t, ok)
+	assert.Equal(t, float32(40), pct)
+	pct, ok = c.Percent(filepath.Join(dir, "pkg", "sub"), true)
+	assert.True(t, ok)
+	assert.Equal(t, float32(0), pct)
+	_, ok = c.Percent(filepath.Join(dir, "other"), true)
+	assert.False(t, ok)
+
+	c.sumDirs()
+	cov, tot = c.Statements(filepath.Join(dir, "pkg"), true)
+	assert.Equal(t, 2, cov)
+	assert.Equal(t, 5, tot)
+	cov, tot = c.Statements(dir, true)
+	assert.Equal(t, 2, cov)
+	assert.Equal(t, 5, tot)
+	_, ok = c.Percent(filepath.Join(dir, "other"), true)
+	assert.False(t, ok)
+
+	assert.Equal(t, []int{0, 0, 1, -1, -1, -1, 1, 0}, c.LineCoverage(add, 8))
+
+	_, err = ParseCoverage(strings.NewReader("mode: set\nbad line\n"), func(f string) string { return f })
+	assert.Error(t, err)
+}
+
+func TestCoverageMarkers(t *testing.T) {
+	ln := lines.NewLines()
+	ln.SetText([]byte("package m\n\nfunc add(a, b int) int {\n\treturn a + b\n}\n"))
+	c := &Coverage{Files: map[string][]CoverBlock{"": { // no filename
+		{StartLine: 3, StartCol: 24, EndLine: 4, EndCol: 14, NumStmt: 1, Count: 0},
+	}}}
+	SetProblemMarkers(ln, []*Problem{{Line: 4, Col: 2}})
+	SetCoverageMarkers(ln, c)
+
+	clr, _ := ln.LineColor(2)
+	assert.Equal(t, coverageColor(false), clr)
+	assert.NotEqual(t, problemColor(ProblemError), clr)
+	clr, _ = ln.LineColor(3)
+	assert.Equal(t, problemColor(ProblemError), clr) // problem kept
+	tags := ln.AdjustedTags(2)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, token.TextStyleDeleted, tags[0].Token.Token)
+		assert.Equal(t, 23, tags[0].Start)
+		assert.Equal(t, 24, tags[0].End)
+	}
+	assert.Len(t, ln.AdjustedTags(3), 2)
+
+	ClearCoverageMarkers(ln)
+	_, has := ln.LineColor(2)
+	assert.False(t, has)
+	clr, _ = ln.LineColor(3)
+	assert.Equal(t, problemColor(ProblemError), clr)
+	assert.Empty(t, ln.AdjustedTags(2))
+	tags = ln.AdjustedTags(3)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, token.TextStyleError, tags[0].Token.Token)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/cogent/code/cdebug/cdap"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/colors"
 	"cogentcore.org/core/colors/cam/hct"
@@ -50,6 +51,7 @@ const (
 	DebugTabTasks   = "Tasks"
 	DebugTabThreads = "Threads"
 	DebugTabVars    = "Vars"
+	DebugTabWatches = "Watches"
 	DebugTabFrames  = "Find Frames"
 	DebugTabGlobals = "Global Vars"
 )
@@ -66,9 +68,16 @@ var (
 
 // NewDebugger returns a new debugger for given supported file type
 func NewDebugger(sup fileinfo.Known, path, rootPath string, outbuf *lines.Lines, pars *cdebug.Params) (cdebug.GiDebug, error) {
+	if lo, has := AvailableLanguages[sup]; has && lo.Debugger.IsValid() {
+		dbg, err := cdap.NewGiDap(&lo.Debugger, path, rootPath, outbuf, pars)
+		if err != nil {
+			log.Println(err)
+		}
+		return dbg, err
+	}
 	df, ok := cdebug.Debuggers[sup]
 	if !ok {
-		err := fmt.Errorf("Code Debug: File type %v not supported -- change the MainLang in File/Project Settings.. to a supported language (Go only option so far)", sup)
+		err := fmt.Errorf("Code Debug: File type %v not supported -- change the MainLang in File/Project Settings.. to a supported language, or set a Debugger adapter for it in the Languages settings", sup)
 		log.Println(err)
 		return nil, err
 	}
@@ -89,6 +98,10 @@ type DebugPanel struct {
 	// path to executable / dir to debug
 	ExePath string
 
+	// launch configuration used to start the debugger, if any,
+	// which sets the mode, args and environment
+	Launch *LaunchConfig `set:"-" json:"-" xml:"-"`
+
 	// time when dbg was last restarted
 	DbgTime time.Time
 
@@ -109,6 +122,30 @@ type DebugPanel struct {
 
 	// parent code project
 	Code *Code `set:"-" json:"-" xml:"-"`
+
+	// how the tasks are grouped in the Tasks tab
+	TaskGrouping cdebug.TaskGroupings
+
+	// filter for the tasks shown in the Tasks tab
+	TaskFilter cdebug.TaskFilter
+
+	// stacks of the tasks by ID, for grouping and filtering them,
+	// which are got when needed, and cleared when execution continues
+	taskStacks map[int][]*cdebug.Frame
+
+	// loadingStacks is whether the taskStacks are being got
+	// in the background, for the stop given by taskStacksStop
+	loadingStacks bool
+
+	// taskStacksStop counts the times execution stopped, so that task
+	// stacks got in the background for an earlier stop are discarded
+	taskStacksStop int
+
+	// tasks shown in the Tasks tab, after filtering
+	shownTasks []*cdebug.Task
+
+	// groups of the shown tasks, if they are grouped
+	taskGroups []*cdebug.TaskGroup
 }
 
 // Config sets parameters that must be set for a new view
@@ -185,20 +222,15 @@ func (dv *DebugPanel) InitTabs() {
 
 	if dv.Known == fileinfo.Go { // dv.Dbg.HasTasks() { // todo: not avail here yet
 		tv, _ := w.NewTab(DebugTabTasks)
-		tree.AddChild(tv, func(w *core.Table) {
-			w.SetReadOnly(true)
-			w.SetSlice(&dv.State.Tasks)
-			w.OnDoubleClick(func(e events.Event) {
-				if dv.Dbg != nil && dv.Dbg.HasTasks() {
-					dv.SetThreadIndex(w.SelectedIndex)
-				}
-			})
-			w.Updater(func() {
-				_, idx := cdebug.TaskByID(dv.State.Tasks, dv.State.CurTask)
-				if idx >= 0 {
-					w.SelectedIndex = idx
-				}
+		tree.AddChild(tv, func(w *core.Toolbar) {
+			w.Maker(dv.makeTasksToolbar)
+		})
+		tree.AddChild(tv, func(w *core.Frame) {
+			w.Styler(func(s *styles.Style) {
+				s.Direction = styles.Column
+				s.Grow.Set(1, 1)
 			})
+			w.Maker(dv.makeTasks)
 		})
 	}
 
@@ -229,6 +261,20 @@ func (dv *DebugPanel) InitTabs() {
 		})
 	})
 
+	wv, _ := w.NewTab(DebugTabWatches)
+	tree.AddChild(wv, func(w *core.Table) {
+		w.SetSlice(&dv.State.Watches)
+		w.OnDoubleClick(func(e events.Event) {
+			idx := w.SelectedIndex
+			if idx >= 0 && idx < len(dv.State.Watches) {
+				dv.ShowVar(dv.State.Watches[idx].Expr)
+			}
+		})
+		w.OnChange(func(e events.Event) {
+			dv.UpdateWatches()
+		})
+	})
+
 	ff, _ := w.NewTab(DebugTabFrames)
 	tree.AddChild(ff, func(w *core.Table) {
 		w.SetReadOnly(true)
@@ -276,10 +322,16 @@ func (dv *DebugPanel) DbgIsAvail() bool {
 	return true
 }
 
+// DbgCanRun means the debugger is available AND the process can be run,
+// which it cannot be for a core dump, where the state is read-only.
+func (dv *DebugPanel) DbgCanRun() bool {
+	return dv.DbgIsAvail() && dv.State.Mode != cdebug.Core
+}
+
 // DbgCanStep means the debugger is started AND process is not currently running,
 // AND it is not already waiting for a next step
 func (dv *DebugPanel) DbgCanStep() bool {
-	if !dv.DbgIsAvail() {
+	if !dv.DbgCanRun() {
 		return false
 	}
 	if dv.State.State.NextUp {
@@ -299,7 +351,7 @@ func (dv *DebugPanel) Destroy() {
 // Detach from debugger
 func (dv *DebugPanel) Detach() {
 	killProc := true
-	if dv.State.Mode == cdebug.Attach {
+	if dv.State.Mode == cdebug.Attach || dv.State.Mode == cdebug.Core {
 		killProc = false
 	}
 	if dv.DbgIsAvail() {
@@ -319,7 +371,9 @@ func (dv *DebugPanel) Start() {
 	console := dv.ConsoleText()
 	console.Clear()
 	rebuild := false
-	if dv.Dbg != nil && dv.State.Mode != cdebug.Attach {
+	if dv.Dbg != nil && dv.State.Mode == cdebug.Core {
+		rebuild = true // there is nothing to restart, so reopen it
+	} else if dv.Dbg != nil && dv.State.Mode != cdebug.Attach {
 		lmod := dv.Code.Files.LatestFileMod(fileinfo.Code)
 		rebuild = lmod.After(dv.DbgTime) || dv.Code.LastSaveTStamp.After(dv.DbgTime)
 	}
@@ -330,11 +384,15 @@ func (dv *DebugPanel) Start() {
 		}
 		rootPath := string(dv.Code.Settings.ProjectRoot)
 		pars := &dv.Code.Settings.Debug
+		if dv.Launch != nil {
+			lpars := dv.Launch.Params(pars)
+			pars = &lpars
+		}
 		dv.State.Mode = pars.Mode
 		pars.StatFunc = func(stat cdebug.Status) {
 			dv.AsyncLock()
 
-			if stat == cdebug.Ready && dv.State.Mode == cdebug.Attach {
+			if stat == cdebug.Ready && (dv.State.Mode == cdebug.Attach || dv.State.Mode == cdebug.Core) {
 				dv.UpdateFromState()
 			}
 			dv.SetStatus(stat)
@@ -369,7 +427,7 @@ func (dv *DebugPanel) UpdateView() {
 // Continue continues running from current point -- this MUST be called
 // in a separate goroutine!
 func (dv *DebugPanel) Continue() {
-	if !dv.DbgIsAvail() {
+	if !dv.DbgCanRun() {
 		return
 	}
 	dv.SetBreaks()
@@ -450,7 +508,7 @@ func (dv *DebugPanel) Stop() { //types:add
 	// if !dv.DbgIsActive() || dv.DbgIsAvail() {
 	// 	return
 	// }
-	if dv.Dbg == nil {
+	if dv.Dbg == nil || dv.State.Mode == cdebug.Core {
 		return
 	}
 	_, err := dv.Dbg.Stop()
@@ -608,6 +666,9 @@ func (dv *DebugPanel) BackupBreaks() {
 // Call this when debugger returns from any action update
 func (dv *DebugPanel) InitState(ds *cdebug.State) {
 	dv.State.State = *ds
+	dv.taskStacks = nil
+	dv.loadingStacks = false
+	dv.taskStacksStop++
 	if ds.Running {
 		return
 	}
@@ -643,9 +704,59 @@ func (dv *DebugPanel) UpdateFromState() {
 		}
 	}
 	dv.UpdateAllBreaks()
+	dv.UpdateWatches()
+	dv.UpdateTasks()
 	dv.Update()
 }
 
+// UpdateWatches evaluates the watch expressions in the current thread
+// and frame, if the debugger is available.
+func (dv *DebugPanel) UpdateWatches() {
+	if !dv.DbgIsAvail() || len(dv.State.Watches) == 0 {
+		return
+	}
+	thid := dv.Dbg.CurThreadID(&dv.State)
+	dv.State.UpdateWatches(func(expr string) (*cdebug.Variable, error) {
+		return dv.Dbg.GetVar(expr, thid, dv.State.CurFrame)
+	})
+	dv.UpdateTab(DebugTabWatches)
+}
+
+// AddWatch adds the given expression to the watches,
+// which are evaluated every time the debugger stops.
+func (dv *DebugPanel) AddWatch(expr string) { //types:add
+	if strings.TrimSpace(expr) == "" {
+		return
+	}
+	dv.State.AddWatch(expr)
+	dv.UpdateWatches()
+	dv.ShowTab(DebugTabWatches)
+	dv.UpdateTab(DebugTabWatches)
+}
+
+// SetWatchpoint sets a watchpoint on the variable given by the
+// expression in the current frame, which stops when its memory is written.
+// It is only supported by some debuggers, and is removed when the
+// variable goes out of scope.
+func (dv *DebugPanel) SetWatchpoint(expr string) { //types:add
+	if !dv.DbgCanRun() {
+		return
+	}
+	bk, err := dv.Dbg.SetWatch(expr, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
+	if err != nil {
+		core.ErrorSnackbar(dv, err, "Could not set watchpoint on "+expr)
+		return
+	}
+	if bk == nil {
+		return
+	}
+	dv.State.Breaks = append(dv.State.Breaks, bk)
+	cdebug.SortBreaks(dv.State.Breaks)
+	dv.BackupBreaks()
+	dv.ShowTab(DebugTabBreaks)
+	dv.UpdateTab(DebugTabBreaks)
+}
+
 // SetFrame sets the given frame depth level as active
 func (dv *DebugPanel) SetFrame(depth int) {
 	if !dv.DbgIsAvail() {
@@ -653,7 +764,7 @@ func (dv *DebugPanel) SetFrame(depth int) {
 	}
 	cf := dv.State.StackFrame(depth)
 	if cf != nil {
-		dv.Dbg.UpdateAllState(&dv.State, dv.State.CurTask, depth) // todo: CurTask is not general!
+		dv.Dbg.UpdateAllState(&dv.State, dv.Dbg.CurThreadID(&dv.State), depth)
 	}
 	dv.UpdateFromState()
 }
@@ -782,14 +893,14 @@ func (dv *DebugPanel) ShowVar(name string) error {
 	if !dv.DbgIsAvail() {
 		return nil
 	}
-	vv, err := dv.Dbg.GetVar(name, dv.State.CurTask, dv.State.CurFrame)
+	vv, err := dv.Dbg.GetVar(name, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
 	if err != nil {
 		return err
 	}
 	frinfo := ""
 	cf := dv.State.StackFrame(dv.State.CurFrame)
 	if cf != nil {
-		frinfo = "at: " + cf.FPath + fmt.Sprintf(":%d  Thread: %d  Depth: %d", cf.Line, dv.State.CurTask, dv.State.CurFrame)
+		frinfo = "at: " + cf.FPath + fmt.Sprintf(":%d  Thread: %d  Depth: %d", cf.Line, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
 	}
 	VarViewDialog(vv, frinfo, dv)
 	return nil
@@ -802,7 +913,7 @@ func (dv *DebugPanel) VarValue(varNm string) string {
 		return ""
 	}
 	if strings.Contains(varNm, ".") {
-		vv, err := dv.Dbg.GetVar(varNm, dv.State.CurTask, dv.State.CurFrame)
+		vv, err := dv.Dbg.GetVar(varNm, dv.Dbg.CurThreadID(&dv.State), dv.State.CurFrame)
 		if err == nil {
 			return vv.Value
 		}
@@ -895,7 +1006,7 @@ func (dv *DebugPanel) MakeToolbar(p *tree.Plan) {
 	tree.Add(p, func(w *core.Button) {
 		w.SetText("Continue").SetIcon(icons.PlayArrow).SetShortcut("Control+Alt+R")
 		w.SetTooltip("continue execution from current point")
-		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgIsAvail()) })
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgCanRun()) })
 		w.OnClick(func(e events.Event) {
 			go dv.Continue()
 		})
@@ -907,27 +1018,27 @@ func (dv *DebugPanel) MakeToolbar(p *tree.Plan) {
 
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(dv.StepOver).SetText("Over").SetShortcut("F6")
-		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgIsAvail()) })
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgCanRun()) })
 	})
 
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(dv.StepInto).SetText("Into").SetShortcut("F7")
-		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgIsAvail()) })
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgCanRun()) })
 	})
 
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(dv.StepOut).SetText("Out").SetShortcut("F8")
-		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgIsAvail()) })
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgCanRun()) })
 	})
 
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(dv.SingleStep).SetText("Single").SetIcon(icons.Step)
-		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgIsAvail()) })
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgCanRun()) })
 	})
 
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(dv.Stop)
-		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!dv.DbgIsAvail()) })
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!dv.DbgIsAvail() && dv.State.Mode != cdebug.Core) })
 	})
 
 	tree.Add(p, func(w *core.Separator) {})
@@ -942,6 +1053,15 @@ func (dv *DebugPanel) MakeToolbar(p *tree.Plan) {
 		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgIsAvail()) })
 	})
 
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(dv.AddWatch).SetText("Watch").SetIcon(icons.Visibility)
+	})
+
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(dv.SetWatchpoint).SetText("Watchpoint").SetIcon(icons.Visibility)
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(dv.DbgCanRun()) })
+	})
+
 	tree.Add(p, func(w *core.Button) {
 		w.SetText("Params").SetIcon(icons.Edit).SetTooltip("edit the debugger parameters (e.g., for passing args: use -- (double dash) to separate args passed to program vs. those passed to the debugger itself)")
 		w.OnClick(func(e events.Event) {
@@ -0,0 +1,259 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"slices"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/text/token"
+	"cogentcore.org/core/tree"
+)
+
+// taskStackDepth is the depth of the task stacks used
+// for grouping and filtering tasks.
+const taskStackDepth = 50
+
+// UpdateTasks updates the tasks shown in the Tasks tab, filtering and
+// grouping them, and getting their stacks from the debugger in the
+// background as needed, updating them again once they have been got.
+func (dv *DebugPanel) UpdateTasks() {
+	needStacks := dv.TaskFilter.Func != "" || dv.TaskGrouping == cdebug.GroupStack
+	if needStacks && dv.taskStacks == nil && !dv.loadingStacks && dv.DbgIsAvail() && dv.Dbg.HasTasks() {
+		dv.loadTaskStacks()
+	}
+	dv.shownTasks = cdebug.FilterTasks(dv.State.Tasks, &dv.TaskFilter, dv.taskStacks)
+	if dv.shownTasks == nil {
+		dv.shownTasks = []*cdebug.Task{}
+	}
+	dv.taskGroups = nil
+	if !(dv.loadingStacks && dv.TaskGrouping == cdebug.GroupStack) {
+		dv.taskGroups = cdebug.GroupTasks(dv.shownTasks, dv.TaskGrouping, dv.taskStacks)
+	}
+	if dv.taskGroups == nil {
+		dv.taskGroups = []*cdebug.TaskGroup{}
+	}
+	if tv := dv.Tabs(); tv != nil && tv.TabByName(DebugTabTasks) != nil {
+		dv.UpdateTab(DebugTabTasks)
+	}
+}
+
+// loadTaskStacks gets the stacks of all of the tasks from the debugger
+// in the background, as there can be thousands of them.
+func (dv *DebugPanel) loadTaskStacks() {
+	dv.loadingStacks = true
+	dbg, tasks, stop := dv.Dbg, dv.State.Tasks, dv.taskStacksStop
+	go func() {
+		stacks := cdebug.TaskStacks(dbg, tasks, taskStackDepth)
+		dv.AsyncLock()
+		defer dv.AsyncUnlock()
+		if dv.taskStacksStop != stop || dv.Dbg != dbg {
+			return
+		}
+		dv.loadingStacks = false
+		dv.taskStacks = stacks
+		dv.UpdateTasks()
+	}()
+}
+
+// FilterTasksInFunc shows only the tasks with the given function on their
+// stack in the Tasks tab, e.g., the goroutines blocked in it, where the
+// function name matches the end of the full name, e.g., Server.Serve.
+// An empty name shows all of the tasks.
+func (dv *DebugPanel) FilterTasksInFunc(fun string) { //types:add
+	dv.TaskFilter.Func = fun
+	dv.UpdateTasks()
+	dv.ShowTab(DebugTabTasks)
+}
+
+// DiffTaskStacks shows the differences between the stacks
+// of the two tasks with the given IDs.
+func (dv *DebugPanel) DiffTaskStacks(taskA, taskB int) { //types:add
+	if !dv.DbgIsAvail() {
+		return
+	}
+	stack := func(id int) ([]*cdebug.Frame, error) {
+		if st, ok := dv.taskStacks[id]; ok {
+			return st, nil
+		}
+		return dv.Dbg.Stack(id, taskStackDepth)
+	}
+	sa, err := stack(taskA)
+	if err != nil {
+		core.ErrorSnackbar(dv, err, fmt.Sprintf("Stack of task %d", taskA))
+		return
+	}
+	sb, err := stack(taskB)
+	if err != nil {
+		core.ErrorSnackbar(dv, err, fmt.Sprintf("Stack of task %d", taskB))
+		return
+	}
+	na, nb := fmt.Sprintf("task %d", taskA), fmt.Sprintf("task %d", taskB)
+	textcore.DiffEditorDialog(dv, "Stack diff: "+na+" vs. "+nb, cdebug.StackLines(sa), cdebug.StackLines(sb), na, nb, "", "")
+}
+
+// diffSelectedTasks shows the differences between the stacks of the
+// two tasks selected in the given table, or of the selected task
+// and the current one if only one is selected.
+func (dv *DebugPanel) diffSelectedTasks(tb *core.Table) {
+	var ids []int
+	for idx := range tb.SelectedIndexes {
+		if idx >= 0 && idx < len(dv.shownTasks) {
+			ids = append(ids, dv.shownTasks[idx].ID)
+		}
+	}
+	slices.Sort(ids)
+	if len(ids) == 1 && ids[0] != dv.State.CurTask {
+		ids = append(ids, dv.State.CurTask)
+	}
+	if len(ids) != 2 {
+		core.MessageSnackbar(dv, "Select two tasks, or a task other than the current one, to compare their stacks")
+		return
+	}
+	dv.DiffTaskStacks(ids[0], ids[1])
+}
+
+// tasksTable returns the table of tasks in the Tasks tab,
+// or nil if the tasks are grouped.
+func (dv *DebugPanel) tasksTable() *core.Table {
+	tv := dv.Tabs().TabByName(DebugTabTasks)
+	if tv == nil {
+		return nil
+	}
+	tb, _ := tv.AsTree().Child(1).AsTree().ChildByName("tasks").(*core.Table)
+	return tb
+}
+
+func (dv *DebugPanel) makeTasksToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.Text) {
+		w.SetText("Group:")
+	})
+	tree.Add(p, func(w *core.Chooser) {
+		w.SetEnum(dv.TaskGrouping)
+		w.SetTooltip("group the tasks with an identical stack, or by where they were started")
+		w.OnChange(func(e events.Event) {
+			dv.TaskGrouping = w.CurrentItem.Value.(cdebug.TaskGroupings)
+			dv.UpdateTasks()
+		})
+		w.Updater(func() {
+			w.SetCurrentValue(dv.TaskGrouping)
+		})
+	})
+	tree.Add(p, func(w *core.TextField) {
+		w.SetPlaceholder("Function")
+		w.SetTooltip("only show the tasks with this function on their stack, e.g., Serve or Server.Serve")
+		w.OnChange(func(e events.Event) {
+			dv.TaskFilter.Func = w.Text()
+			dv.UpdateTasks()
+		})
+		w.Updater(func() {
+			w.SetText(dv.TaskFilter.Func)
+		})
+	})
+	tree.Add(p, func(w *core.TextField) {
+		w.SetPlaceholder("Status")
+		w.SetTooltip("only show the tasks whose status contains this, e.g., waiting, chan or select")
+		w.OnChange(func(e events.Event) {
+			dv.TaskFilter.Status = w.Text()
+			dv.UpdateTasks()
+		})
+		w.Updater(func() {
+			w.SetText(dv.TaskFilter.Status)
+		})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Diff stacks").SetIcon(icons.Difference).
+			SetTooltip("compare the stacks of the two selected tasks, or of the selected task and the current one").
+			OnClick(func(e events.Event) {
+				if tb := dv.tasksTable(); tb != nil {
+					dv.diffSelectedTasks(tb)
+				}
+			})
+	})
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			if dv.TaskGrouping == cdebug.GroupNone {
+				w.SetText(fmt.Sprintf("%d of %d", len(dv.shownTasks), len(dv.State.Tasks)))
+			} else {
+				w.SetText(fmt.Sprintf("%d groups of %d of %d", len(dv.taskGroups), len(dv.shownTasks), len(dv.State.Tasks)))
+			}
+			if dv.loadingStacks {
+				w.SetText(w.Text + " (getting stacks)")
+			}
+		})
+	})
+}
+
+// makeTasks makes the table of the tasks,
+// or of their groups if they are grouped.
+func (dv *DebugPanel) makeTasks(p *tree.Plan) {
+	if dv.TaskGrouping == cdebug.GroupNone {
+		tree.AddAt(p, "tasks", func(w *core.Table) {
+			w.SetReadOnly(true)
+			w.SetSlice(&dv.shownTasks)
+			w.OnDoubleClick(func(e events.Event) {
+				idx := w.SelectedIndex
+				if dv.Dbg != nil && dv.Dbg.HasTasks() && idx >= 0 && idx < len(dv.shownTasks) {
+					dv.SetThread(dv.shownTasks[idx].ID)
+				}
+			})
+			w.Updater(func() {
+				_, idx := cdebug.TaskByID(dv.shownTasks, dv.State.CurTask)
+				if idx >= 0 {
+					w.SelectedIndex = idx
+				}
+			})
+		})
+		return
+	}
+	tree.AddAt(p, "task-groups", func(w *core.Table) {
+		w.SetReadOnly(true)
+		w.SetSlice(&dv.taskGroups)
+		w.SetTooltip("double-click on a group to switch to its first task")
+		w.OnDoubleClick(func(e events.Event) {
+			idx := w.SelectedIndex
+			if dv.Dbg != nil && dv.Dbg.HasTasks() && idx >= 0 && idx < len(dv.taskGroups) {
+				dv.SetThread(dv.taskGroups[idx].Tasks[0].ID)
+			}
+		})
+	})
+}
+
+// funcAtCursor returns the name of the function at the cursor, as
+// Type.Method for a method, or "" if the cursor is not in a function.
+func (ed *TextEditor) funcAtCursor() string {
+	pkg, ok := packageSymbols(ed.Lines)
+	if !ok {
+		return ""
+	}
+	path := symbolPath(ed.symbolPaths(pkg), ed.CursorPos.Line)
+	switch {
+	case len(path) == 1 && path[0].Kind == token.NameFunction:
+		return path[0].Name
+	case len(path) == 2 && path[1].Kind == token.NameMethod:
+		return path[0].Name + "." + path[1].Name
+	}
+	return ""
+}
+
+// ShowTasksInFunc shows only the tasks in the debugger, e.g., goroutines,
+// with the function at the cursor on their stack, such as those blocked in it.
+func (ed *TextEditor) ShowTasksInFunc() {
+	dbg, has := ed.CurDebug()
+	if !has {
+		return
+	}
+	fun := ed.funcAtCursor()
+	if fun == "" {
+		core.MessageSnackbar(ed, "The cursor is not in a function")
+		return
+	}
+	dbg.FilterTasksInFunc(fun)
+}
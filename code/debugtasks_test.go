@@ -0,0 +1,43 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugTasks(t *testing.T) {
+	b := core.NewBody()
+	dv := NewDebugPanel(b)
+	dv.Known = fileinfo.Go
+	start := cdebug.Location{Func: "main.loop", File: "main.go", Line: 5}
+	dv.State.Tasks = []*cdebug.Task{
+		{ID: 1, Func: "main.main", Status: "running"},
+		{ID: 2, Func: "main.worker", Status: "waiting: chan receive", StartLoc: start},
+		{ID: 3, Func: "main.worker", Status: "waiting: select", StartLoc: start},
+	}
+	dv.UpdateTasks()
+	assert.Len(t, dv.shownTasks, 3)
+
+	dv.TaskFilter.Status = "waiting"
+	dv.TaskGrouping = cdebug.GroupStart
+	dv.UpdateTasks()
+	assert.Len(t, dv.shownTasks, 2)
+	require.Len(t, dv.taskGroups, 1)
+	assert.Equal(t, 2, dv.taskGroups[0].Count)
+
+	dv.TaskFilter.Func = "worker" // no stacks without a debugger, so current func
+	dv.UpdateTasks()
+	assert.Len(t, dv.shownTasks, 2)
+	dv.TaskFilter.Func = "main"
+	dv.UpdateTasks()
+	assert.Empty(t, dv.shownTasks)
+}
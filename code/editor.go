@@ -48,20 +48,25 @@ func (cv *Code) LookupFun(data any, txt string, posLine, posChar int) (ld comple
 		log.Printf("LookupFun: data is nil not FileStates or is nil - can't lookup\n")
 		return ld
 	}
-	lp, err := parse.LanguageSupport.Properties(sfs.Known)
-	if err != nil {
-		log.Printf("LookupFun: %v\n", err)
-		return ld
-	}
-	if lp.Lang == nil {
-		return ld
-	}
+	ln := cv.GetOpenFile(sfs.Filename)
+	if cl := cv.langServerSync(ln); cl != nil {
+		ld = cv.lookupLangServer(cl, ln, textpos.Pos{Line: posLine, Char: posChar})
+	} else {
+		lp, err := parse.LanguageSupport.Properties(sfs.Known)
+		if err != nil {
+			log.Printf("LookupFun: %v\n", err)
+			return ld
+		}
+		if lp.Lang == nil {
+			return ld
+		}
 
-	// note: must have this set to true to allow viewing of AST
-	// must set it in pi/parse directly -- so it is changed in the fileparse too
-	parser.GUIActive = true // note: this is key for debugging -- runs slower but makes the tree unique
+		// note: must have this set to true to allow viewing of AST
+		// must set it in pi/parse directly -- so it is changed in the fileparse too
+		parser.GUIActive = true // note: this is key for debugging -- runs slower but makes the tree unique
 
-	ld = lp.Lang.Lookup(sfs, txt, textpos.Pos{posLine, posChar})
+		ld = lp.Lang.Lookup(sfs, txt, textpos.Pos{posLine, posChar})
+	}
 	if len(ld.Text) > 0 {
 		textcore.TextDialog(nil, "Lookup: "+txt, string(ld.Text))
 		return ld
@@ -345,6 +350,20 @@ func (cv *Code) CountWordsRegion() string { //types:add
 
 ////////   Links
 
+// cmdOutputDir returns the directory a command was run in, from the
+// initial cd line of the given command output, or "" if there is none.
+func cmdOutputDir(ln *lines.Lines) string {
+	cdln := ln.String()
+	if !strings.HasPrefix(cdln, "cd ") {
+		return ""
+	}
+	fmidx := strings.Index(cdln, " (from: ")
+	if fmidx <= 0 {
+		return ""
+	}
+	return cdln[3:fmidx]
+}
+
 // OpenFileURL is the link handler for command editors: opens given file:/// url
 func (cv *Code) OpenFileURL(ur string, ftv *textcore.Editor) bool {
 	up, err := url.Parse(ur)
@@ -356,17 +375,11 @@ func (cv *Code) OpenFileURL(ur string, ftv *textcore.Editor) bool {
 	if strings.HasPrefix(fpath, "a/") || strings.HasPrefix(fpath, "b/") { // diff output, skip
 		fpath = fpath[2:]
 	}
-	cdpath := ""
 	if ftv != nil && ftv.Lines != nil {
-		cdln := ftv.Lines.String()
-		if strings.HasPrefix(cdln, "cd ") { // get cd path for non-pathed fnames
-			fmidx := strings.Index(cdln, " (from: ")
-			if fmidx > 0 {
-				cdpath = cdln[3:fmidx]
-				dr, _ := filepath.Split(fpath)
-				if dr == "" || !filepath.IsAbs(dr) {
-					fpath = filepath.Join(cdpath, fpath)
-				}
+		if cdpath := cmdOutputDir(ftv.Lines); cdpath != "" { // for non-pathed fnames
+			dr, _ := filepath.Split(fpath)
+			if dr == "" || !filepath.IsAbs(dr) {
+				fpath = filepath.Join(cdpath, fpath)
 			}
 		}
 	}
@@ -92,16 +92,16 @@ func (i *DebugBreakStatus) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "DebugBreakStatus")
 }
 
-var _LocationsValues = []Locations{0, 1, 2, 3}
+var _LocationsValues = []Locations{0, 1, 2, 3, 4}
 
 // LocationsN is the highest valid value for type Locations, plus one.
-const LocationsN Locations = 4
+const LocationsN Locations = 5
 
-var _LocationsValueMap = map[string]Locations{`Open`: 0, `All`: 1, `Dir`: 2, `File`: 3}
+var _LocationsValueMap = map[string]Locations{`Open`: 0, `All`: 1, `Dir`: 2, `File`: 3, `Root`: 4}
 
-var _LocationsDescMap = map[Locations]string{0: `Open searches in all open directories in a filetree.`, 1: `All searches in all directories under the root path.`, 2: `Dir searches in the current active directory.`, 3: `File searches in the current active file.`}
+var _LocationsDescMap = map[Locations]string{0: `Open searches in all open directories in a filetree.`, 1: `All searches in all directories under the root paths.`, 2: `Dir searches in the current active directory.`, 3: `File searches in the current active file.`, 4: `Root searches in all directories under the project root containing the current active file, in a multi-root project.`}
 
-var _LocationsMap = map[Locations]string{0: `Open`, 1: `All`, 2: `Dir`, 3: `File`}
+var _LocationsMap = map[Locations]string{0: `Open`, 1: `All`, 2: `Dir`, 3: `File`, 4: `Root`}
 
 // String returns the string representation of this Locations value.
 func (i Locations) String() string { return enums.String(i, _LocationsMap) }
@@ -135,6 +135,221 @@ func (i *Locations) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "Locations")
 }
 
+var _GoTestKindsValues = []GoTestKinds{0, 1, 2, 3, 4}
+
+// GoTestKindsN is the highest valid value for type GoTestKinds, plus one.
+const GoTestKindsN GoTestKinds = 5
+
+var _GoTestKindsValueMap = map[string]GoTestKinds{`Package`: 0, `Test`: 1, `Benchmark`: 2, `Fuzz`: 3, `Subtest`: 4}
+
+var _GoTestKindsDescMap = map[GoTestKinds]string{0: `GoPackage is a package directory containing tests.`, 1: `GoTest is a TestXxx function.`, 2: `GoBenchmark is a BenchmarkXxx function.`, 3: `GoFuzz is a FuzzXxx function.`, 4: `GoSubtest is a subtest started with t.Run.`}
+
+var _GoTestKindsMap = map[GoTestKinds]string{0: `Package`, 1: `Test`, 2: `Benchmark`, 3: `Fuzz`, 4: `Subtest`}
+
+// String returns the string representation of this GoTestKinds value.
+func (i GoTestKinds) String() string { return enums.String(i, _GoTestKindsMap) }
+
+// SetString sets the GoTestKinds value from its string representation,
+// and returns an error if the string is invalid.
+func (i *GoTestKinds) SetString(s string) error {
+	return enums.SetString(i, s, _GoTestKindsValueMap, "GoTestKinds")
+}
+
+// Int64 returns the GoTestKinds value as an int64.
+func (i GoTestKinds) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the GoTestKinds value from an int64.
+func (i *GoTestKinds) SetInt64(in int64) { *i = GoTestKinds(in) }
+
+// Desc returns the description of the GoTestKinds value.
+func (i GoTestKinds) Desc() string { return enums.Desc(i, _GoTestKindsDescMap) }
+
+// GoTestKindsValues returns all possible values for the type GoTestKinds.
+func GoTestKindsValues() []GoTestKinds { return _GoTestKindsValues }
+
+// Values returns all possible values for the type GoTestKinds.
+func (i GoTestKinds) Values() []enums.Enum { return enums.Values(_GoTestKindsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i GoTestKinds) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *GoTestKinds) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "GoTestKinds")
+}
+
+var _TestStatusValues = []TestStatus{0, 1, 2, 3, 4}
+
+// TestStatusN is the highest valid value for type TestStatus, plus one.
+const TestStatusN TestStatus = 5
+
+var _TestStatusValueMap = map[string]TestStatus{`NotRun`: 0, `Running`: 1, `Passed`: 2, `Failed`: 3, `Skipped`: 4}
+
+var _TestStatusDescMap = map[TestStatus]string{0: `TestNotRun means the test has not been run.`, 1: `TestRunning means the test is currently running.`, 2: `TestPassed means the test passed.`, 3: `TestFailed means the test failed.`, 4: `TestSkipped means the test was skipped.`}
+
+var _TestStatusMap = map[TestStatus]string{0: `NotRun`, 1: `Running`, 2: `Passed`, 3: `Failed`, 4: `Skipped`}
+
+// String returns the string representation of this TestStatus value.
+func (i TestStatus) String() string { return enums.String(i, _TestStatusMap) }
+
+// SetString sets the TestStatus value from its string representation,
+// and returns an error if the string is invalid.
+func (i *TestStatus) SetString(s string) error {
+	return enums.SetString(i, s, _TestStatusValueMap, "TestStatus")
+}
+
+// Int64 returns the TestStatus value as an int64.
+func (i TestStatus) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the TestStatus value from an int64.
+func (i *TestStatus) SetInt64(in int64) { *i = TestStatus(in) }
+
+// Desc returns the description of the TestStatus value.
+func (i TestStatus) Desc() string { return enums.Desc(i, _TestStatusDescMap) }
+
+// TestStatusValues returns all possible values for the type TestStatus.
+func TestStatusValues() []TestStatus { return _TestStatusValues }
+
+// Values returns all possible values for the type TestStatus.
+func (i TestStatus) Values() []enums.Enum { return enums.Values(_TestStatusValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i TestStatus) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *TestStatus) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "TestStatus")
+}
+
+var _HierarchyKindsValues = []HierarchyKinds{0, 1, 2, 3}
+
+// HierarchyKindsN is the highest valid value for type HierarchyKinds, plus one.
+const HierarchyKindsN HierarchyKinds = 4
+
+var _HierarchyKindsValueMap = map[string]HierarchyKinds{`IncomingCalls`: 0, `OutgoingCalls`: 1, `Implementers`: 2, `Implements`: 3}
+
+var _HierarchyKindsDescMap = map[HierarchyKinds]string{0: `HierarchyIncomingCalls shows the functions that call the function, and the functions that call them, and so on.`, 1: `HierarchyOutgoingCalls shows the functions that the function calls, and the functions that they call, and so on.`, 2: `HierarchyImplementers shows the types that implement the interface.`, 3: `HierarchyImplements shows the interfaces that the type implements.`}
+
+var _HierarchyKindsMap = map[HierarchyKinds]string{0: `IncomingCalls`, 1: `OutgoingCalls`, 2: `Implementers`, 3: `Implements`}
+
+// String returns the string representation of this HierarchyKinds value.
+func (i HierarchyKinds) String() string { return enums.String(i, _HierarchyKindsMap) }
+
+// SetString sets the HierarchyKinds value from its string representation,
+// and returns an error if the string is invalid.
+func (i *HierarchyKinds) SetString(s string) error {
+	return enums.SetString(i, s, _HierarchyKindsValueMap, "HierarchyKinds")
+}
+
+// Int64 returns the HierarchyKinds value as an int64.
+func (i HierarchyKinds) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the HierarchyKinds value from an int64.
+func (i *HierarchyKinds) SetInt64(in int64) { *i = HierarchyKinds(in) }
+
+// Desc returns the description of the HierarchyKinds value.
+func (i HierarchyKinds) Desc() string { return enums.Desc(i, _HierarchyKindsDescMap) }
+
+// HierarchyKindsValues returns all possible values for the type HierarchyKinds.
+func HierarchyKindsValues() []HierarchyKinds { return _HierarchyKindsValues }
+
+// Values returns all possible values for the type HierarchyKinds.
+func (i HierarchyKinds) Values() []enums.Enum { return enums.Values(_HierarchyKindsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i HierarchyKinds) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *HierarchyKinds) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "HierarchyKinds")
+}
+
+var _MergeChoicesValues = []MergeChoices{0, 1, 2}
+
+// MergeChoicesN is the highest valid value for type MergeChoices, plus one.
+const MergeChoicesN MergeChoices = 3
+
+var _MergeChoicesValueMap = map[string]MergeChoices{`Ours`: 0, `Theirs`: 1, `Both`: 2}
+
+var _MergeChoicesDescMap = map[MergeChoices]string{0: `MergeOurs accepts our lines.`, 1: `MergeTheirs accepts their lines.`, 2: `MergeBoth accepts our lines followed by their lines.`}
+
+var _MergeChoicesMap = map[MergeChoices]string{0: `Ours`, 1: `Theirs`, 2: `Both`}
+
+// String returns the string representation of this MergeChoices value.
+func (i MergeChoices) String() string { return enums.String(i, _MergeChoicesMap) }
+
+// SetString sets the MergeChoices value from its string representation,
+// and returns an error if the string is invalid.
+func (i *MergeChoices) SetString(s string) error {
+	return enums.SetString(i, s, _MergeChoicesValueMap, "MergeChoices")
+}
+
+// Int64 returns the MergeChoices value as an int64.
+func (i MergeChoices) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the MergeChoices value from an int64.
+func (i *MergeChoices) SetInt64(in int64) { *i = MergeChoices(in) }
+
+// Desc returns the description of the MergeChoices value.
+func (i MergeChoices) Desc() string { return enums.Desc(i, _MergeChoicesDescMap) }
+
+// MergeChoicesValues returns all possible values for the type MergeChoices.
+func MergeChoicesValues() []MergeChoices { return _MergeChoicesValues }
+
+// Values returns all possible values for the type MergeChoices.
+func (i MergeChoices) Values() []enums.Enum { return enums.Values(_MergeChoicesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i MergeChoices) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *MergeChoices) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "MergeChoices")
+}
+
+var _ProblemSeveritiesValues = []ProblemSeverities{0, 1, 2}
+
+// ProblemSeveritiesN is the highest valid value for type ProblemSeverities, plus one.
+const ProblemSeveritiesN ProblemSeverities = 3
+
+var _ProblemSeveritiesValueMap = map[string]ProblemSeverities{`Error`: 0, `Warning`: 1, `Info`: 2}
+
+var _ProblemSeveritiesDescMap = map[ProblemSeverities]string{0: `ProblemError is an error that prevents building or passing.`, 1: `ProblemWarning is a warning, e.g., from a linter.`, 2: `ProblemInfo is an informational note or hint.`}
+
+var _ProblemSeveritiesMap = map[ProblemSeverities]string{0: `Error`, 1: `Warning`, 2: `Info`}
+
+// String returns the string representation of this ProblemSeverities value.
+func (i ProblemSeverities) String() string { return enums.String(i, _ProblemSeveritiesMap) }
+
+// SetString sets the ProblemSeverities value from its string representation,
+// and returns an error if the string is invalid.
+func (i *ProblemSeverities) SetString(s string) error {
+	return enums.SetString(i, s, _ProblemSeveritiesValueMap, "ProblemSeverities")
+}
+
+// Int64 returns the ProblemSeverities value as an int64.
+func (i ProblemSeverities) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the ProblemSeverities value from an int64.
+func (i *ProblemSeverities) SetInt64(in int64) { *i = ProblemSeverities(in) }
+
+// Desc returns the description of the ProblemSeverities value.
+func (i ProblemSeverities) Desc() string { return enums.Desc(i, _ProblemSeveritiesDescMap) }
+
+// ProblemSeveritiesValues returns all possible values for the type ProblemSeverities.
+func ProblemSeveritiesValues() []ProblemSeverities { return _ProblemSeveritiesValues }
+
+// Values returns all possible values for the type ProblemSeverities.
+func (i ProblemSeverities) Values() []enums.Enum { return enums.Values(_ProblemSeveritiesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i ProblemSeverities) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *ProblemSeverities) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "ProblemSeverities")
+}
+
 var _SymScopesValues = []SymScopes{0, 1}
 
 // SymScopesN is the highest valid value for type SymScopes, plus one.
@@ -18,11 +18,19 @@ func (cv *Code) codeKeys(e events.Event) {
 	SetGoMod(cv.Settings.GoMod)
 	kc := e.KeyChord()
 	kf := keymap.Of(kc)
+	cv.recordMacroKey(kc)
+	seq := cv.KeySeq1 != ""
 	if core.DebugSettings.KeyEventTrace {
 		slog.Info("Code KeyInput", "widget", cv, "keyFunction", kf, "keyChord", kc)
 	}
 	if cv.KeySeq1 != "" {
 		kc2 := string(cv.KeySeq1) + " " + string(kc)
+		if cv.macroKeyChord(key.Chord(kc2)) {
+			e.SetHandled()
+			cv.SetStatus(kc2)
+			cv.KeySeq1 = ""
+			return
+		}
 		kf2 := keymap.Of(key.Chord(kc2))
 		if kf2 == keymap.None || kf == keymap.CancelSelect || kc == "Escape" {
 			if core.DebugSettings.KeyEventTrace {
@@ -37,6 +45,10 @@ func (cv *Code) codeKeys(e events.Event) {
 		cv.KeySeq1 = ""
 		kf = kf2
 	} else {
+		if cv.macroKeyChord(kc) {
+			e.SetHandled()
+			return
+		}
 		if kf == keymap.MultiA || kf == keymap.MultiB {
 			e.SetHandled()
 			tv := cv.ActiveEditor()
@@ -57,6 +69,10 @@ func (cv *Code) codeKeys(e events.Event) {
 	}
 
 	atv := cv.ActiveEditor()
+	if cv.macroKeys(kf, seq) {
+		e.SetHandled()
+		return
+	}
 	switch kf {
 	case keymap.Find:
 		e.SetHandled()
@@ -132,6 +148,9 @@ func (cv *Code) codeKeys(e events.Event) {
 	case KeyRunProject:
 		e.SetHandled()
 		cv.Run()
+	case KeyInsertSnippet:
+		e.SetHandled()
+		cv.InsertSnippet(atv)
 	}
 }
 
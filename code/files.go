@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"cogentcore.org/cogent/code/structural"
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/base/fsx"
@@ -137,6 +138,27 @@ func (of *OpenFiles) SearchRegexpInPaths(paths []string, re *regexp.Regexp, lang
 	return res
 }
 
+// SearchStructuralInPaths returns search results for given structural
+// search pattern in all open Go files contained directly in given paths.
+// If paths is nil then all open files are searched.
+func (of *OpenFiles) SearchStructuralInPaths(paths []string, pat *structural.Pattern) []search.Results {
+	var res []search.Results
+	for _, ln := range of.Values {
+		path := filepath.Dir(ln.Filename())
+		if !(paths == nil || slices.Contains(paths, path)) {
+			continue
+		}
+		if ln.FileInfo().Known != fileinfo.Go {
+			continue
+		}
+		cnt, matches, err := pat.Search(ln.Text())
+		if cnt > 0 && err == nil {
+			res = append(res, search.Results{Filepath: ln.Filename(), Count: cnt, Matches: matches})
+		}
+	}
+	return res
+}
+
 // ReMarkup all open files: when mode color changes etc, during rebuild
 func (of *OpenFiles) ReMarkup() {
 	for _, ln := range of.Values {
@@ -167,18 +189,26 @@ func GetVCSRepo(obj any) vcs.Repo {
 // external files in the file browser.
 func (cv *Code) OpenLines(fpath string) *lines.Lines {
 	ln := lines.NewLines()
-	err := ln.Open(fpath)
+	var err error
+	if cv.IsRemote() {
+		err = cv.openRemoteLines(ln, fpath)
+	} else {
+		err = ln.Open(fpath)
+	}
 	if errors.Log(err) != nil {
 		return nil
 	}
 	cv.ConfigLines(ln)
 	cv.OpenFiles.Add(ln)
+	cv.langServerOpen(ln)
+	cv.problemsOpened(ln)
+	cv.coverageOpened(ln)
 	if !cv.InRootPath(fpath) {
 		cv.Files.AddExternalFile(fpath)
 	}
 	fn := cv.FileNodeForFile(fpath)
 	if fn != nil {
-		if repo, _ := fn.Repo(); repo != nil {
+		if repo, _ := fn.Repo(); repo != nil && !cv.IsRemote() { // repo is local
 			SetVCSRepo(ln, repo)
 		}
 		fn.FileIsOpen = true
@@ -187,9 +217,14 @@ func (cv *Code) OpenLines(fpath string) *lines.Lines {
 }
 
 // InRootPath returns true if the given path, which must be an absolute path,
-// is under the current file browser root path.
+// is under one of the current file browser root paths.
 func (cv *Code) InRootPath(fpath string) bool {
-	return strings.HasPrefix(fpath, string(cv.Files.Filepath))
+	for _, ft := range cv.FileTrees() {
+		if strings.HasPrefix(fpath, string(ft.Filepath)) {
+			return true
+		}
+	}
+	return false
 }
 
 // RecycleFile either opens given file or returns already open one.
@@ -218,11 +253,21 @@ func (cv *Code) SaveActiveView() { //types:add
 	}
 	cv.LastSaveTStamp = time.Now()
 	if tv.Lines.Filename() != "" {
-		tv.Save()
+		if cv.IsRemote() {
+			cv.saveLines(tv.Lines)
+		} else {
+			tv.Save()
+		}
 		fname := tv.Lines.Filename()
 		cv.SetStatus("File Saved: " + fname)
+		cv.Index.Changed(fname)
+		if filepath.Dir(fname) == SnippetsDir() {
+			errors.Log(OpenSnippetSettings())
+		}
 		fpath, _ := filepath.Split(fname)
-		cv.Files.UpdatePath(fpath) // update everything in dir -- will have removed autosave
+		cv.FileTreeFor(fpath).UpdatePath(fpath) // update everything in dir -- will have removed autosave
+		cv.langServerSaved(tv.Lines)
+		tv.updateVCS()
 		cv.RunPostCmds(tv.Lines)
 		cv.updatePreviewPanel()
 	} else {
@@ -251,18 +296,24 @@ func (cv *Code) SaveActiveViewAs(filename core.Filename) { //types:add
 	}
 	cv.LastSaveTStamp = time.Now()
 	ofn := tv.Lines.Filename()
-	textcore.SaveAs(tv.Scene, tv.Lines, string(filename), func(canceled bool) {
+	saved := func(canceled bool) {
 		if canceled {
 			cv.SetStatus(fmt.Sprintf("File %q NOT Saved As: %q", ofn, filename))
 			return
 		}
 		cv.SetStatus(fmt.Sprintf("File %q Saved As: %q", ofn, filename))
-		cv.Files.UpdatePath(string(filename)) // update everything in dir -- will have removed autosave
+		cv.FileTreeFor(string(filename)).UpdatePath(string(filename)) // update everything in dir -- will have removed autosave
 		if ofn != string(filename) {
 			cv.OpenFiles.DeleteByKey(ofn)
 			cv.OpenFiles.Add(tv.Lines)
 		}
-	})
+	}
+	if cv.IsRemote() {
+		tv.Lines.SetFilename(string(filename))
+		saved(cv.saveLines(tv.Lines) != nil)
+	} else {
+		textcore.SaveAs(tv.Scene, tv.Lines, string(filename), saved)
+	}
 	cv.SaveProjectIfExists(false) // no saveall
 }
 
@@ -273,10 +324,10 @@ func (cv *Code) RevertActiveView() { //types:add
 		return
 	}
 	// cv.ConfigLines(tv.Lines) // why here?
-	tv.Lines.Revert()
+	cv.revertLines(tv.Lines)
 	tv.Lines.UndoReset() // key implication of revert
 	fpath, _ := filepath.Split(tv.Lines.Filename())
-	cv.Files.UpdatePath(fpath) // update everything in dir -- will have removed autosave
+	cv.FileTreeFor(fpath).UpdatePath(fpath) // update everything in dir -- will have removed autosave
 }
 
 // CloseActiveView closes the buffer associated with active view.
@@ -313,7 +364,7 @@ func (cv *Code) RunPostCmds(ln *lines.Lines) bool {
 	if ptab >= 0 {
 		cv.Tabs().SelectTabIndex(ptab) // we stay at the previous tab
 	}
-	ln.Revert()
+	cv.revertLines(ln)
 	return true
 }
 
@@ -322,9 +373,9 @@ func (cv *Code) RunPostCmds(ln *lines.Lines) bool {
 // unchanged (means just opened).
 func (cv *Code) AutosaveCheck(tv *TextEditor, vidx int, ln *lines.Lines) bool {
 	fname := ln.Filename()
-	if strings.HasPrefix(fname, "#") && strings.HasSuffix(fname, "#") {
+	if cv.IsRemote() || strings.HasPrefix(fname, "#") && strings.HasSuffix(fname, "#") {
 		ln.Autosave = false
-		return false // we are the autosave file
+		return false // remote, or we are the autosave file
 	}
 	ln.Autosave = true
 	if tv.IsNotSaved() || !ln.AutosaveCheck() {
@@ -337,7 +388,7 @@ func (cv *Code) AutosaveCheck(tv *TextEditor, vidx int, ln *lines.Lines) bool {
 		core.NewButton(bar).SetText("Ignore and overwrite autosave file").OnClick(func(e events.Event) {
 			d.Close()
 			ln.AutosaveDelete()
-			cv.Files.UpdatePath(ln.AutosaveFilename()) // will update dir
+			cv.FileTreeFor(ln.AutosaveFilename()).UpdatePath(ln.AutosaveFilename()) // will update dir
 		})
 		core.NewButton(bar).SetText("Open autosave file").OnClick(func(e events.Event) {
 			d.Close()
@@ -390,7 +441,7 @@ func (cv *Code) ViewLines(tv *TextEditor, vidx int, ln *lines.Lines) {
 func (cv *Code) NextViewFile(fnm string) (*TextEditor, int, bool) { //types:add
 	ln, nw := cv.RecycleFile(fnm)
 	if ln == nil {
-		fn, ok := cv.Files.FindFile(fnm)
+		fn, ok := cv.FindFile(fnm)
 		if ok {
 			fnm = string(fn.Filepath)
 			ln, nw = cv.RecycleFile(fnm)
@@ -547,7 +598,7 @@ func (cv *Code) CloneActiveView() (*TextEditor, int) { //types:add
 func (cv *Code) SaveAllOpenFiles() {
 	for _, ln := range cv.OpenFiles.Values {
 		if ln.IsNotSaved() {
-			textcore.Save(cv.Scene, ln)
+			cv.saveLines(ln)
 			cv.RunPostCmds(ln)
 		}
 	}
@@ -597,7 +648,7 @@ func (cv *Code) CloseOpenFile(fname string) {
 // FileNodeForFile returns file node for given file path.
 // nil if not found or is a directory.
 func (cv *Code) FileNodeForFile(fpath string) *filetree.Node {
-	fn, ok := cv.Files.FindFile(fpath)
+	fn, ok := cv.FindFile(fpath)
 	if !ok {
 		return nil
 	}
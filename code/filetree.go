@@ -5,15 +5,19 @@
 package code
 
 import (
+	"fmt"
+	"image"
 	"log"
 	"path/filepath"
 
+	"cogentcore.org/core/colors"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/events"
 	"cogentcore.org/core/filetree"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/styles"
 	"cogentcore.org/core/styles/states"
+	"cogentcore.org/core/tree"
 )
 
 // FileNode is Code version of FileNode for FileTree
@@ -46,6 +50,38 @@ func (fn *FileNode) Init() {
 			}
 		})
 	})
+	fn.Parts.Maker(func(p *tree.Plan) {
+		cv, ok := ParentCode(fn.This)
+		if !ok || cv.Coverage == nil {
+			return
+		}
+		pct, ok := cv.Coverage.Percent(string(fn.Filepath), fn.IsDir())
+		if !ok {
+			return
+		}
+		tree.AddAt(p, "coverage", func(w *core.Text) {
+			w.SetType(core.TextLabelSmall)
+			w.Styler(func(s *styles.Style) {
+				s.SetNonSelectable()
+				s.Color = coverageTextColor(pct)
+			})
+			w.Updater(func() {
+				pct, _ = cv.Coverage.Percent(string(fn.Filepath), fn.IsDir())
+				w.SetText(fmt.Sprintf("%.0f%%", pct))
+			})
+		})
+	})
+}
+
+// coverageTextColor returns the color for the given percent coverage.
+func coverageTextColor(pct float32) image.Image {
+	switch {
+	case pct >= 80:
+		return colors.Scheme.Success.Base
+	case pct >= 50:
+		return colors.Scheme.Warn.Base
+	}
+	return colors.Scheme.Error.Base
 }
 
 // EditFile pulls up this file in Code
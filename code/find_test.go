@@ -7,6 +7,7 @@ package code
 import (
 	"testing"
 
+	"cogentcore.org/core/text/lines"
 	"cogentcore.org/core/text/textpos"
 	"github.com/stretchr/testify/assert"
 )
@@ -22,3 +23,47 @@ func TestFindUrl(t *testing.T) {
 	assert.Equal(t, textpos.Pos{10, 13}, reg.Start)
 	assert.Equal(t, textpos.Pos{10, 24}, reg.End)
 }
+
+func TestReplacement(t *testing.T) {
+	fv := &FindPanel{Code: &Code{}}
+	fp := fv.Params()
+	fp.Find = "hello"
+	fp.Replace = "goodbye"
+	repl, err := fv.replacement("hello")
+	assert.NoError(t, err)
+	assert.Equal(t, "goodbye", repl)
+	fp.IgnoreCase = true
+	repl, _ = fv.replacement("Hello")
+	assert.Equal(t, "Goodbye", repl)
+
+	fp.Regexp = true
+	fp.Find = `(\w+)\.Print\((\w+)\)`
+	fp.Replace = "${1}.Println(${2})"
+	assert.True(t, fv.CompileRegexp())
+	repl, _ = fv.replacement("fmt.Print(x)")
+	assert.Equal(t, "fmt.Println(x)", repl)
+}
+
+func TestFileReplacements(t *testing.T) {
+	ln := lines.NewLines().SetText([]byte("a := hello\nb := hello + hello\n"))
+	fr := &FileReplacements{Filepath: "a.go", text: ln.Text()}
+	for _, reg := range []textpos.Region{textpos.NewRegion(0, 5, 0, 10), textpos.NewRegion(1, 5, 1, 10), textpos.NewRegion(1, 13, 1, 18)} {
+		fr.Replacements = append(fr.Replacements, &Replacement{Region: reg, Old: "hello", New: "bye", Accept: true})
+	}
+	fr.Replacements[1].Accept = false
+	fr.Replacements[2].New = "world"
+	assert.Equal(t, 2, fr.Accepted())
+	diff := string(fr.Diff())
+	assert.Contains(t, diff, "-a := hello\n-b := hello + hello\n+a := bye\n+b := hello + world\n")
+	assert.Same(t, &fr.diff[0], &fr.Diff()[0]) // not changed, so not computed again
+	fr.Replacements[2].New = "there"
+	assert.Contains(t, string(fr.Diff()), "+b := hello + there\n")
+	fr.Replacements[2].New = "world"
+	orig := ln.Strings(false)
+
+	assert.Equal(t, 2, fr.ApplyTo(ln))
+	assert.Equal(t, []string{"a := bye", "b := hello + world"}, ln.Strings(false))
+	assert.Equal(t, 0, fr.ApplyTo(ln)) // already replaced
+	ln.Undo()
+	assert.Equal(t, orig, ln.Strings(false))
+}
@@ -7,12 +7,16 @@ package code
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
+	"cogentcore.org/cogent/code/remote"
+	"cogentcore.org/cogent/code/structural"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/base/fsx"
 	"cogentcore.org/core/base/metadata"
@@ -23,6 +27,8 @@ import (
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/styles"
 	"cogentcore.org/core/styles/states"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/lexer"
 	"cogentcore.org/core/text/rich"
 	"cogentcore.org/core/text/runes"
 	"cogentcore.org/core/text/search"
@@ -38,7 +44,7 @@ const (
 	// Open searches in all open directories in a filetree.
 	Open Locations = iota
 
-	// All searches in all directories under the root path.
+	// All searches in all directories under the root paths.
 	All
 
 	// Dir searches in the current active directory.
@@ -46,6 +52,10 @@ const (
 
 	// File searches in the current active file.
 	File
+
+	// Root searches in all directories under the project root
+	// containing the current active file, in a multi-root project.
+	Root
 )
 
 // FindParams are parameters for find / replace
@@ -63,6 +73,11 @@ type FindParams struct {
 	// use regexp regular expression search and replace
 	Regexp bool
 
+	// use structural search and replace in Go files, matching Go code
+	// with $name metavariables that match any expression, e.g., fmt.Errorf($msg),
+	// which can be used in the replace string, e.g., errors.New($msg)
+	Structural bool
+
 	// locations to search in
 	Loc Locations
 
@@ -89,6 +104,9 @@ type FindPanel struct {
 
 	// compiled regexp
 	Re *regexp.Regexp
+
+	// compiled structural search pattern
+	Pattern *structural.Pattern
 }
 
 func (fv *FindPanel) Init() {
@@ -128,7 +146,13 @@ func (fv *FindPanel) Params() *FindParams {
 }
 
 func findURL(path string, resultIndex, matchIndex, line, startChar, endChar int) string {
-	return fmt.Sprintf("find:///%s#R%d-%dL%dC%d-L%dC%d", path, resultIndex, matchIndex, line, startChar, line, endChar)
+	return findRegionURL(path, resultIndex, matchIndex, textpos.NewRegion(line, startChar, line, endChar))
+}
+
+// findRegionURL returns the find:/// url for the given match region,
+// which can span multiple lines, as for structural search matches.
+func findRegionURL(path string, resultIndex, matchIndex int, reg textpos.Region) string {
+	return fmt.Sprintf("find:///%s#R%d-%dL%dC%d-L%dC%d", path, resultIndex, matchIndex, reg.Start.Line, reg.Start.Char, reg.End.Line, reg.End.Char)
 }
 
 // parseFindURL parses and opens given find:/// url from Find, return text
@@ -192,7 +216,7 @@ func (fv *FindPanel) ShowResults(res []search.Results) {
 	outmus := make([]rich.Text, 0, 100) // markups
 	for ri, rs := range res {
 		fp := rs.Filepath
-		fn := cv.Files.RelativePathFrom(fsx.Filename(fp))
+		fn := cv.FileTreeFor(fp).RelativePathFrom(fsx.Filename(fp))
 		lstr := []rune(fmt.Sprintf(`%v: %v`, fn, rs.Count))
 		outlns = append(outlns, []rune{})
 		outmus = append(outmus, rich.NewText(sty, []rune{}))
@@ -203,8 +227,7 @@ func (fv *FindPanel) ShowResults(res []search.Results) {
 			txt = append([]rune("\t"), txt...)
 			ln := mt.Region.Start.Line
 			ch := mt.Region.Start.Char
-			ech := mt.Region.End.Char
-			url := findURL(fp, ri, mi, ln, ch, ech)
+			url := findRegionURL(fp, ri, mi, mt.Region)
 			fnstr := []rune(fmt.Sprintf("%v:%d:%d: ", fn, ln, ch))
 			outlns = append(outlns, append(fnstr, txt...))
 			mu := rich.Text{}
@@ -234,28 +257,36 @@ func (fv *FindPanel) FindAction() {
 	if !fv.CompileRegexp() {
 		return
 	}
+	if fp.Structural {
+		fv.Code.FindStructural(fp.Find, fp.Replace, fp.Loc)
+		return
+	}
 	fv.Code.Find(fp.Find, fp.Replace, fp.IgnoreCase, fp.Regexp, fp.Loc, fp.Languages)
 }
 
-// CheckValidRegexp returns false if using regexp and it is not valid
+// CheckValidRegexp returns false if using regexp or structural
+// search and it is not valid
 func (fv *FindPanel) CheckValidRegexp() bool {
 	fp := fv.Params()
-	if !fp.Regexp {
-		return true
-	}
-	if fv.Re == nil {
-		return fv.CompileRegexp()
+	switch {
+	case fp.Structural:
+		if fv.Pattern == nil || fv.Pattern.Source != fp.Find {
+			return fv.CompileRegexp()
+		}
+	case fp.Regexp:
+		if fv.Re == nil {
+			return fv.CompileRegexp()
+		}
 	}
 	return true
 }
 
-// ReplaceAction performs the replace. if using regexp mode,
-// regexp must be compiled in advance.
+// ReplaceAction performs the replace. if using regexp or structural mode,
+// the regexp or pattern must be compiled in advance.
 func (fv *FindPanel) ReplaceAction() bool {
 	if !fv.CheckValidRegexp() {
 		return false
 	}
-	fp := fv.Params()
 	ftv := fv.TextEditor()
 	tl, _ := ftv.OpenLinkAt(ftv.CursorPos)
 	if tl == nil {
@@ -289,15 +320,13 @@ func (fv *FindPanel) ReplaceAction() bool {
 	reg.Time.SetTime(fv.Time)
 	reg = tv.Lines.AdjustRegion(reg)
 	if !reg.IsNil() {
-		if fp.Regexp {
-			rg := tv.Lines.Region(reg.Start, reg.End)
-			b := rg.ToBytes()
-			rb := fv.Re.ReplaceAll(b, []byte(fp.Replace))
-			tv.Lines.ReplaceText(reg.Start, reg.End, reg.Start, string(rb), false)
-		} else {
-			// MatchCase only if doing IgnoreCase
-			tv.Lines.ReplaceText(reg.Start, reg.End, reg.Start, fp.Replace, fp.IgnoreCase)
+		rg := tv.Lines.Region(reg.Start, reg.End)
+		repl, err := fv.replacement(string(rg.ToBytes()))
+		if err != nil {
+			core.ErrorSnackbar(fv, err)
+			return false
 		}
+		tv.Lines.ReplaceText(reg.Start, reg.End, reg.Start, repl, lines.ReplaceNoMatchCase)
 
 		// delete the link for the just done replace
 		ftvln := ftv.CursorPos.Line
@@ -317,27 +346,61 @@ func (fv *FindPanel) ReplaceAction() bool {
 	return ok
 }
 
-// ReplaceAllAction performs replace all, prompting before proceeding
+// replacement returns the text to replace the given found text with,
+// using the current find / replace mode. In plain mode, the case of the
+// found text is matched if doing IgnoreCase.
+func (fv *FindPanel) replacement(found string) (string, error) {
+	fp := fv.Params()
+	switch {
+	case fp.Structural:
+		rb, err := fv.Pattern.Replace([]byte(found), fp.Replace)
+		return string(rb), err
+	case fp.Regexp:
+		return string(fv.Re.ReplaceAll([]byte(found), []byte(fp.Replace))), nil
+	case fp.IgnoreCase:
+		return lexer.MatchCase(found, fp.Replace), nil
+	}
+	return fp.Replace, nil
+}
+
+// ReplaceAllAction performs replace all, showing a [ReplacePanel] with
+// a preview of all of the replacements, to select the ones to apply.
 func (fv *FindPanel) ReplaceAllAction() {
-	d := core.NewBody("Confirm replace all")
-	core.NewText(d).SetType(core.TextSupporting).SetText("Are you sure you want to replace all?")
-	d.AddBottomBar(func(bar *core.Frame) {
-		d.AddCancel(bar)
-		d.AddOK(bar).SetText("Replace all").OnClick(func(e events.Event) {
-			fv.ReplaceAll()
-		})
-	})
-	d.RunDialog(fv)
+	if !fv.CheckValidRegexp() {
+		return
+	}
+	frs, err := fv.pendingReplacements()
+	if err != nil {
+		core.ErrorSnackbar(fv, err, "Could not prepare the replacements")
+		return
+	}
+	if len(frs) == 0 {
+		core.MessageSnackbar(fv, "There is nothing to replace")
+		return
+	}
+	rv := core.RecycleTabWidget[ReplacePanel](fv.Code.Tabs(), "Replace")
+	rv.SetReplacements(fv, frs)
+	fv.Code.FocusOnPanel(TabsIndex)
 }
 
-// CompileRegexp compiles the regexp if necessary -- returns false if it is invalid
+// CompileRegexp compiles the regexp or structural search pattern
+// if necessary -- returns false if it is invalid
 func (fv *FindPanel) CompileRegexp() bool {
 	fp := fv.Params()
+	fv.Re = nil
+	fv.Pattern = nil
+	var err error
+	if fp.Structural {
+		fv.Pattern, err = structural.Compile(fp.Find)
+		if err != nil {
+			core.ErrorSnackbar(fv, err, "The structural search pattern was invalid")
+			return false
+		}
+		return true
+	}
 	if !fp.Regexp {
-		fv.Re = nil
 		return true
 	}
-	var err error
 	fv.Re, err = regexp.Compile(fp.Find)
 	if err != nil {
 		core.ErrorSnackbar(fv, err, "The regular expression was invalid")
@@ -346,7 +409,8 @@ func (fv *FindPanel) CompileRegexp() bool {
 	return true
 }
 
-// ReplaceAll performs replace all
+// ReplaceAll performs replace all immediately, without the preview
+// of [FindPanel.ReplaceAllAction].
 func (fv *FindPanel) ReplaceAll() {
 	if !fv.CheckValidRegexp() {
 		return
@@ -481,11 +545,29 @@ func (fv *FindPanel) makeFindToolbar(p *tree.Plan) {
 			SetTooltip("use regular expression for search and replace -- see https://github.com/google/re2/wiki/Syntax")
 		w.OnChange(func(e events.Event) {
 			fv.Params().Regexp = w.StateIs(states.Checked)
+			if fv.Params().Regexp {
+				fv.Params().Structural = false
+				w.Parent.(core.Widget).AsWidget().Update()
+			}
 		})
 		w.Updater(func() {
 			w.SetChecked(fv.Params().Regexp)
 		})
 	})
+	tree.AddAt(p, "structural", func(w *core.Switch) {
+		w.SetText("Structural").
+			SetTooltip("use structural search and replace in Go files, where the find string is Go code with $name variables that match any expression, e.g., fmt.Errorf($msg), and the replace string can use the same variables, e.g., errors.New($msg)")
+		w.OnChange(func(e events.Event) {
+			fv.Params().Structural = w.StateIs(states.Checked)
+			if fv.Params().Structural {
+				fv.Params().Regexp = false
+				w.Parent.(core.Widget).AsWidget().Update()
+			}
+		})
+		w.Updater(func() {
+			w.SetChecked(fv.Params().Structural)
+		})
+	})
 
 	ttxt := "location to find in: all = all open folders in browser; file = current active file; dir = directory of current active file; nottop = all except the top-level in browser"
 	tree.Add(p, func(w *core.Text) {
@@ -530,7 +612,7 @@ func (fv *FindPanel) makeReplToolbar(p *tree.Plan) {
 
 	tree.AddAt(p, "repl-str", func(w *core.Chooser) {
 		w.SetEditable(true).SetDefaultNew(true).
-			SetTooltip("String to replace find string -- click for history -- use ${n} for regexp submatch where n = 1 for first submatch, etc")
+			SetTooltip("String to replace find string -- click for history -- use ${n} for regexp submatch where n = 1 for first submatch, etc, or $name for structural search variables")
 		w.Styler(func(s *styles.Style) {
 			s.Grow.Set(1, 0)
 			s.Max.X.Zero()
@@ -546,7 +628,7 @@ func (fv *FindPanel) makeReplToolbar(p *tree.Plan) {
 	})
 
 	tree.Add(p, func(w *core.Button) {
-		w.SetText("All").SetTooltip("replace all find strings with replace string").
+		w.SetText("All").SetTooltip("preview replacing all find strings with replace string, to select the replacements to apply").
 			OnClick(func(e events.Event) {
 				fv.ReplaceAllAction()
 			})
@@ -576,34 +658,113 @@ func (cv *Code) Find(find string, repl string, ignoreCase bool, regExp bool, loc
 	}
 	cv.Settings.Find.IgnoreCase = ignoreCase
 	cv.Settings.Find.Regexp = regExp
+	cv.Settings.Find.Structural = false
 	cv.Settings.Find.Languages = langs
 	cv.Settings.Find.Loc = loc
 	cv.Settings.Find.Find = find
 	cv.Settings.Find.Replace = repl
 
-	tv := cv.Tabs()
-	if tv == nil {
-		return
-	}
-	var err error
-
 	var re *regexp.Regexp
 	if regExp {
+		var err error
 		re, err = regexp.Compile(find)
 		if err != nil {
 			core.ErrorSnackbar(cv, err)
 			return
 		}
 	}
+	cv.findIn(loc, finder{
+		paths: func(paths []string, all bool, exclude []string) ([]search.Results, error) {
+			if cv.IsRemote() {
+				return remote.Search(cv.Remote, paths, all, find, ignoreCase, regExp, langs, exclude...)
+			}
+			if res, ok, err := cv.Index.Search(paths, all, find, ignoreCase, regExp, langs, exclude...); ok {
+				return res, err
+			}
+			if all {
+				return search.All(paths[0], find, ignoreCase, regExp, langs, exclude...)
+			}
+			return search.Paths(paths, find, ignoreCase, regExp, langs, exclude...)
+		},
+		open: func(paths []string) []search.Results {
+			if regExp {
+				return cv.OpenFiles.SearchRegexpInPaths(paths, re, langs)
+			}
+			return cv.OpenFiles.SearchInPaths(paths, find, ignoreCase, langs)
+		},
+		lines: func(ln *lines.Lines) (int, []textpos.Match) {
+			if regExp {
+				return ln.SearchRegexp(re)
+			}
+			return ln.Search([]byte(find), ignoreCase, false)
+		},
+	})
+}
 
-	fbuf, _ := cv.RecycleCmdBuf("Find")
-	fv := core.RecycleTabWidget[FindPanel](tv, "Find")
-	fv.Time = time.Now()
-	fv.UpdateTree()
-	ftv := fv.TextEditor()
-	ftv.SetLines(fbuf)
+// FindStructural does structural Find / Replace in Go files, where find is
+// Go code with $name metavariables that match any expression, and repl can
+// use the same metavariables, e.g., fmt.Errorf($msg) and errors.New($msg).
+// It opens up a main tab with the results and further controls, as for [Code.Find].
+func (cv *Code) FindStructural(find string, repl string, loc Locations) { //types:add
+	if find == "" {
+		return
+	}
+	cv.Settings.Find.Structural = true
+	cv.Settings.Find.Regexp = false
+	cv.Settings.Find.Loc = loc
+	cv.Settings.Find.Find = find
+	cv.Settings.Find.Replace = repl
+
+	pat, err := structural.Compile(find)
+	if err != nil {
+		core.ErrorSnackbar(cv, err)
+		return
+	}
+	var fsys fs.FS
+	if cv.IsRemote() {
+		fsys = cv.Remote
+	}
+	cv.findIn(loc, finder{
+		paths: func(paths []string, all bool, exclude []string) ([]search.Results, error) {
+			return pat.Paths(fsys, paths, all, exclude...)
+		},
+		open: func(paths []string) []search.Results {
+			return cv.OpenFiles.SearchStructuralInPaths(paths, pat)
+		},
+		lines: func(ln *lines.Lines) (int, []textpos.Match) {
+			cnt, matches, _ := pat.Search(ln.Text())
+			return cnt, matches
+		},
+	})
+}
+
+// finder has the functions that search for a given find
+// string in files, for [Code.findIn].
+type finder struct {
+
+	// paths searches the files in the paths, recursively if all,
+	// excluding files matching the exclude patterns.
+	paths func(paths []string, all bool, exclude []string) ([]search.Results, error)
+
+	// open searches the open files contained directly in the paths,
+	// or all open files if paths is nil.
+	open func(paths []string) []search.Results
+
+	// lines searches the given lines.
+	lines func(ln *lines.Lines) (int, []textpos.Match)
+}
+
+// findIn searches in the given location using the given finder,
+// showing the results in the Find panel.
+func (cv *Code) findIn(loc Locations, fd finder) {
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+	var err error
+
+	fv := cv.recycleFindPanel()
 
-	root := string(cv.Files.Filepath)
 	atv := cv.ActiveEditor()
 	adir := ""
 	if atv.Lines != nil {
@@ -611,39 +772,48 @@ func (cv *Code) Find(find string, repl string, ignoreCase bool, regExp bool, loc
 	}
 
 	excludeOpen := cv.OpenFiles.Paths()
+	searchPaths := func(paths []string, all bool) ([]search.Results, error) {
+		return fd.paths(paths, all, excludeOpen)
+	}
 
 	var res []search.Results
 	var openFilesPaths []string
 	switch loc {
 	case Open:
-		openFilesPaths = cv.Files.OpenPaths()
-		res, err = search.Paths(openFilesPaths, find, ignoreCase, regExp, langs, excludeOpen...)
+		for _, ft := range cv.FileTrees() {
+			openFilesPaths = append(openFilesPaths, ft.OpenPaths()...)
+		}
+		res, err = searchPaths(openFilesPaths, false)
 	case All:
-		res, err = search.All(root, find, ignoreCase, regExp, langs, excludeOpen...)
+		var errs []error
+		for _, ft := range cv.FileTrees() {
+			rres, rerr := searchPaths([]string{string(ft.Filepath)}, true)
+			res = append(res, rres...)
+			errs = append(errs, rerr)
+		}
+		err = errors.Join(errs...)
+	case Root:
+		ft := cv.FileTreeFor(adir)
+		openFilesPaths = []string{}
+		for _, ln := range cv.OpenFiles.Values {
+			if fdir := filepath.Dir(ln.Filename()); cv.FileTreeFor(fdir) == ft && !slices.Contains(openFilesPaths, fdir) {
+				openFilesPaths = append(openFilesPaths, fdir)
+			}
+		}
+		res, err = searchPaths([]string{string(ft.Filepath)}, true)
 	case Dir:
 		openFilesPaths = []string{adir}
-		res, err = search.Paths(openFilesPaths, find, ignoreCase, regExp, langs, excludeOpen...)
+		res, err = searchPaths(openFilesPaths, false)
 	case File:
 		if atv.Lines == nil {
 			core.MessageSnackbar(cv, "No buffer for active editor")
 			return
 		}
-		if regExp {
-			cnt, matches := atv.Lines.SearchRegexp(re)
-			res = append(res, search.Results{atv.Lines.Filename(), cnt, matches})
-		} else {
-			cnt, matches := atv.Lines.Search([]byte(find), ignoreCase, false)
-			res = append(res, search.Results{atv.Lines.Filename(), cnt, matches})
-		}
+		cnt, matches := fd.lines(atv.Lines)
+		res = append(res, search.Results{atv.Lines.Filename(), cnt, matches})
 	}
 	if loc != File {
-		if regExp {
-			ores := cv.OpenFiles.SearchRegexpInPaths(openFilesPaths, re, langs)
-			res = append(ores, res...)
-		} else {
-			ores := cv.OpenFiles.SearchInPaths(openFilesPaths, find, ignoreCase, langs)
-			res = append(ores, res...)
-		}
+		res = append(fd.open(openFilesPaths), res...)
 	}
 	if err != nil {
 		core.ErrorSnackbar(cv, err)
@@ -651,3 +821,14 @@ func (cv *Code) Find(find string, repl string, ignoreCase bool, regExp bool, loc
 	fv.ShowResults(res)
 	cv.FocusOnPanel(TabsIndex)
 }
+
+// recycleFindPanel returns the Find panel tab, creating it if needed,
+// with its text reset for a new set of results.
+func (cv *Code) recycleFindPanel() *FindPanel {
+	fbuf, _ := cv.RecycleCmdBuf("Find")
+	fv := core.RecycleTabWidget[FindPanel](cv.Tabs(), "Find")
+	fv.Time = time.Now()
+	fv.UpdateTree()
+	fv.TextEditor().SetLines(fbuf)
+	return fv
+}
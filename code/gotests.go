@@ -0,0 +1,556 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"cogentcore.org/core/tree"
+)
+
+// GoTestKinds are the kinds of nodes in the tree of Go tests.
+type GoTestKinds int32 //enums:enum -trim-prefix Go
+
+const (
+	// GoPackage is a package directory containing tests.
+	GoPackage GoTestKinds = iota
+
+	// GoTest is a TestXxx function.
+	GoTest
+
+	// GoBenchmark is a BenchmarkXxx function.
+	GoBenchmark
+
+	// GoFuzz is a FuzzXxx function.
+	GoFuzz
+
+	// GoSubtest is a subtest started with t.Run.
+	GoSubtest
+)
+
+// TestStatus is the result status of a test.
+type TestStatus int32 //enums:enum -trim-prefix Test
+
+const (
+	// TestNotRun means the test has not been run.
+	TestNotRun TestStatus = iota
+
+	// TestRunning means the test is currently running.
+	TestRunning
+
+	// TestPassed means the test passed.
+	TestPassed
+
+	// TestFailed means the test failed.
+	TestFailed
+
+	// TestSkipped means the test was skipped.
+	TestSkipped
+)
+
+// TestNode is a node in the tree of Go tests, which has packages
+// at the top level, then test functions, then their subtests.
+// The name of the node is the name of the test (or the package path
+// relative to the project root), which for subtests is the name
+// as reported by go test, with spaces replaced by underscores.
+type TestNode struct {
+	tree.NodeBase
+
+	// kind of node
+	Kind GoTestKinds
+
+	// for packages, the package directory
+	Dir string
+
+	// for packages, the import path, used to match test events
+	ImportPath string
+
+	// file where the test is defined, if known
+	Filename string
+
+	// line where the test is defined, if known (1-based)
+	Line int
+
+	// last line of the test definition, if known (1-based)
+	EndLine int
+
+	// result of the last run
+	Status TestStatus
+
+	// elapsed time of the last run, in seconds
+	Elapsed float64
+
+	// output of the last run
+	Output string
+}
+
+// Label returns the name of the test with its status and elapsed time.
+func (tn *TestNode) Label() string {
+	switch tn.Status {
+	case TestNotRun:
+		return tn.Name
+	case TestRunning:
+		return tn.Name + " (running)"
+	}
+	return fmt.Sprintf("%s (%s %.3gs)", tn.Name, strings.ToLower(tn.Status.String()), tn.Elapsed)
+}
+
+// Package returns the package node containing this node.
+func (tn *TestNode) Package() *TestNode {
+	for n := tn; n != nil; {
+		if n.Kind == GoPackage && n.Parent != nil {
+			return n
+		}
+		p, ok := n.Parent.(*TestNode)
+		if !ok {
+			return nil
+		}
+		n = p
+	}
+	return nil
+}
+
+// Path returns the names of the test function and subtests
+// leading to this node, e.g., [TestFoo sub1 sub2].
+func (tn *TestNode) Path() []string {
+	var pth []string
+	for n := tn; n != nil && n.Kind != GoPackage; {
+		pth = append([]string{n.Name}, pth...)
+		n, _ = n.Parent.(*TestNode)
+	}
+	return pth
+}
+
+// Func returns the test function node containing this node.
+func (tn *TestNode) Func() *TestNode {
+	n := tn
+	for n != nil && n.Kind == GoSubtest {
+		n, _ = n.Parent.(*TestNode)
+	}
+	return n
+}
+
+// TestName returns the -run pattern for running just this test
+// (and its subtests), with each element of the path anchored.
+func (tn *TestNode) TestName() string {
+	pth := tn.Path()
+	for i, nm := range pth {
+		pth[i] = "^" + regexp.QuoteMeta(nm) + "$"
+	}
+	return strings.Join(pth, "/")
+}
+
+// ChildByTestName returns the child node with given name, or nil.
+func (tn *TestNode) ChildByTestName(name string) *TestNode {
+	for _, k := range tn.Children {
+		if kn := k.(*TestNode); kn.Name == name {
+			return kn
+		}
+	}
+	return nil
+}
+
+// Reset resets the status and output of this node and all below it.
+func (tn *TestNode) Reset() {
+	tn.WalkDown(func(n tree.Node) bool {
+		kn := n.(*TestNode)
+		kn.Status = TestNotRun
+		kn.Elapsed = 0
+		kn.Output = ""
+		return tree.Continue
+	})
+}
+
+// Counts returns the number of tests and subtests with each status.
+func (tn *TestNode) Counts() [TestStatusN]int {
+	var n [TestStatusN]int
+	tn.WalkDown(func(k tree.Node) bool {
+		kn := k.(*TestNode)
+		if kn.Kind != GoPackage {
+			n[kn.Status]++
+		}
+		return tree.Continue
+	})
+	return n
+}
+
+// Failed returns the test functions that failed in the last run.
+func (tn *TestNode) Failed() []*TestNode {
+	var fails []*TestNode
+	tn.WalkDown(func(k tree.Node) bool {
+		kn := k.(*TestNode)
+		if kn.Kind == GoPackage || kn == tn {
+			return tree.Continue
+		}
+		if kn.Status == TestFailed {
+			fails = append(fails, kn.Func())
+		}
+		return tree.Break // no need to go into subtests
+	})
+	return fails
+}
+
+// NodeAt returns the innermost test at given file and (1-based) line,
+// or nil if there is none.
+func (tn *TestNode) NodeAt(filename string, line int) *TestNode {
+	var at *TestNode
+	tn.WalkDown(func(k tree.Node) bool {
+		kn := k.(*TestNode)
+		if kn.Kind == GoPackage {
+			return tree.Continue
+		}
+		if kn.Filename != filename || line < kn.Line || line > kn.EndLine {
+			return tree.Break
+		}
+		at = kn
+		return tree.Continue
+	})
+	return at
+}
+
+// testFuncKinds are the prefixes of test functions, and their kinds.
+var testFuncKinds = []struct {
+	prefix string
+	kind   GoTestKinds
+}{{"Test", GoTest}, {"Benchmark", GoBenchmark}, {"Fuzz", GoFuzz}}
+
+// testFuncKind returns the kind of test function with given name,
+// following the go test rule that the prefix must not be followed
+// by a lower-case letter. TestMain is not a test, as it runs the tests.
+func testFuncKind(name string) (GoTestKinds, bool) {
+	if name == "TestMain" {
+		return GoTest, false
+	}
+	for _, tk := range testFuncKinds {
+		rest, ok := strings.CutPrefix(name, tk.prefix)
+		if !ok {
+			continue
+		}
+		r, _ := utf8.DecodeRuneInString(rest)
+		if rest == "" || !unicode.IsLower(r) {
+			return tk.kind, true
+		}
+	}
+	return GoTest, false
+}
+
+// RewriteSubtestName rewrites the given subtest name as go test does,
+// replacing spaces with underscores and quoting unprintable characters.
+func RewriteSubtestName(name string) string {
+	b := &strings.Builder{}
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteRune('_')
+		case !strconv.IsPrint(r):
+			s := strconv.QuoteRune(r)
+			b.WriteString(s[1 : len(s)-1])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// DiscoverGoTests returns a tree of the Go tests in all of the package
+// directories under given root directory, found by parsing the _test.go
+// files. Subtests are found for t.Run calls with literal names; others
+// are added when the tests are run.
+func DiscoverGoTests(root string) *TestNode {
+	tr := NewTestNode()
+	tr.SetName("tests")
+	tr.Dir = root
+	modDir, modPath := findModule(root)
+	filepath.WalkDir(root, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		nm := d.Name()
+		if fpath != root && (strings.HasPrefix(nm, ".") || strings.HasPrefix(nm, "_") || nm == "testdata" || nm == "vendor" || nm == "node_modules") {
+			return filepath.SkipDir
+		}
+		if fpath != root && modPath != "" {
+			if _, err := os.Stat(filepath.Join(fpath, "go.mod")); err == nil {
+				return filepath.SkipDir // separate module
+			}
+		}
+		pn := discoverPackage(fpath)
+		if pn == nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, fpath)
+		pn.SetName(filepath.ToSlash(rel))
+		if modPath != "" {
+			mrel, _ := filepath.Rel(modDir, fpath)
+			pn.ImportPath = path.Join(modPath, filepath.ToSlash(mrel))
+		}
+		tr.AddChild(pn)
+		return nil
+	})
+	return tr
+}
+
+// findModule returns the module root directory and module path for
+// the Go module containing given directory, or dir and "" if none.
+func findModule(dir string) (string, string) {
+	for d := dir; ; {
+		b, err := os.ReadFile(filepath.Join(d, "go.mod"))
+		if err == nil {
+			for _, ln := range strings.Split(string(b), "\n") {
+				fs := strings.Fields(ln)
+				if len(fs) >= 2 && fs[0] == "module" {
+					return d, strings.Trim(fs[1], `"`)
+				}
+			}
+			return d, ""
+		}
+		pd := filepath.Dir(d)
+		if pd == d {
+			return dir, ""
+		}
+		d = pd
+	}
+}
+
+// discoverPackage returns a package node with the tests in the
+// _test.go files in given directory, or nil if there are none.
+func discoverPackage(dir string) *TestNode {
+	files, _ := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if len(files) == 0 {
+		return nil
+	}
+	pn := NewTestNode()
+	pn.Kind = GoPackage
+	pn.Dir = dir
+	fset := token.NewFileSet()
+	for _, fn := range files {
+		f, err := parser.ParseFile(fset, fn, nil, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		for _, d := range f.Decls {
+			fd, ok := d.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || fd.Body == nil {
+				continue
+			}
+			kind, ok := testFuncKind(fd.Name.Name)
+			if !ok || fd.Type.Params.NumFields() != 1 {
+				continue
+			}
+			tn := NewTestNode(pn)
+			tn.SetName(fd.Name.Name)
+			tn.Kind = kind
+			tn.setPos(fset, fn, fd)
+			tn.discoverSubtests(fset, fn, fd.Body)
+		}
+	}
+	if !pn.HasChildren() {
+		return nil
+	}
+	return pn
+}
+
+// setPos sets the file position of the node from given syntax node.
+func (tn *TestNode) setPos(fset *token.FileSet, filename string, n ast.Node) {
+	tn.Filename = filename
+	tn.Line = fset.Position(n.Pos()).Line
+	tn.EndLine = fset.Position(n.End()).Line
+}
+
+// discoverSubtests adds the subtests run with a literal name within
+// given syntax node, recursively.
+func (tn *TestNode) discoverSubtests(fset *token.FileSet, filename string, body ast.Node) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		ce, ok := n.(*ast.CallExpr)
+		if !ok || len(ce.Args) != 2 {
+			return true
+		}
+		se, ok := ce.Fun.(*ast.SelectorExpr)
+		if !ok || se.Sel.Name != "Run" {
+			return true
+		}
+		lit, ok := ce.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		name, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return true
+		}
+		name = RewriteSubtestName(name)
+		if tn.ChildByTestName(name) != nil {
+			return true
+		}
+		sn := NewTestNode(tn)
+		sn.SetName(name)
+		sn.Kind = GoSubtest
+		sn.setPos(fset, filename, ce)
+		sn.discoverSubtests(fset, filename, ce.Args[1])
+		return false
+	})
+}
+
+// GoTestArgs returns the go test arguments to run the given tests,
+// which must all be in the same package. A single test is run with
+// its full path so that only that subtest runs; multiple tests run
+// their whole test functions. Benchmarks and their subtests are run
+// with -bench, and no tests.
+func GoTestArgs(tests ...*TestNode) []string {
+	args := []string{"test", "-json"}
+	var runs, benches []string
+	for _, tn := range tests {
+		if tn.Kind == GoPackage {
+			return append(args, ".")
+		}
+		fn := tn.Func()
+		if fn.Kind == GoBenchmark {
+			if len(tests) == 1 {
+				return append(args, "-run", "^$", "-bench", tn.TestName(), ".")
+			}
+			benches = append(benches, regexp.QuoteMeta(fn.Name))
+			continue
+		}
+		if len(tests) == 1 {
+			return append(args, "-run", tn.TestName(), ".")
+		}
+		runs = append(runs, regexp.QuoteMeta(fn.Name))
+	}
+	slices.Sort(runs)
+	runs = slices.Compact(runs)
+	if len(runs) > 0 {
+		args = append(args, "-run", "^("+strings.Join(runs, "|")+")$")
+	} else {
+		args = append(args, "-run", "^$")
+	}
+	if len(benches) > 0 {
+		slices.Sort(benches)
+		benches = slices.Compact(benches)
+		args = append(args, "-bench", "^("+strings.Join(benches, "|")+")$")
+	}
+	return append(args, ".")
+}
+
+// TestEvent is an event in the output of go test -json.
+// See go doc test2json.
+type TestEvent struct {
+	Time       time.Time
+	Action     string
+	Package    string
+	ImportPath string
+	Test       string
+	Elapsed    float64
+	Output     string
+}
+
+// ApplyEvent updates the tests in this package node from given event,
+// adding subtests that were not discovered in the source.
+func (tn *TestNode) ApplyEvent(ev *TestEvent) {
+	n := tn
+	if ev.Test != "" {
+		for i, nm := range strings.Split(ev.Test, "/") {
+			kn := n.ChildByTestName(nm)
+			if kn == nil {
+				kn = NewTestNode(n)
+				kn.SetName(nm)
+				kn.Kind = GoSubtest
+				if i == 0 {
+					kn.Kind, _ = testFuncKind(nm)
+				}
+			}
+			n = kn
+		}
+	}
+	switch ev.Action {
+	case "start":
+		n.Status = TestRunning
+	case "run":
+		n.Status = TestRunning
+		n.Output = ""
+	case "output", "build-output":
+		n.Output += ev.Output
+	case "pass", "bench":
+		n.Status = TestPassed
+		n.Elapsed = ev.Elapsed
+	case "fail", "build-fail":
+		n.Status = TestFailed
+		n.Elapsed = ev.Elapsed
+	case "skip":
+		n.Status = TestSkipped
+		n.Elapsed = ev.Elapsed
+	}
+}
+
+// packageForEvent returns the package node for given event, or
+// the only package if there is one and the event has no package
+// (or the package has no import path).
+func packageForEvent(pkgs []*TestNode, ev *TestEvent) *TestNode {
+	ip := ev.Package
+	if ip == "" {
+		ip, _, _ = strings.Cut(ev.ImportPath, " ")
+	}
+	for _, pn := range pkgs {
+		if pn.ImportPath != "" && pn.ImportPath == ip {
+			return pn
+		}
+	}
+	if len(pkgs) == 1 && (ip == "" || pkgs[0].ImportPath == "") {
+		return pkgs[0]
+	}
+	return nil
+}
+
+// applyEvent applies given event to the package it is for among
+// the given package nodes, or adds its output to this root node
+// if it is not for any of them.
+func (tn *TestNode) applyEvent(pkgs []*TestNode, ev *TestEvent) {
+	pn := packageForEvent(pkgs, ev)
+	if pn == nil {
+		tn.Output += ev.Output
+		return
+	}
+	pn.ApplyEvent(ev)
+}
+
+// ReadTestEvents reads go test -json output from given reader,
+// calling the given function for each event. Lines that are not
+// events, such as build errors, are passed as output events.
+func ReadTestEvents(r io.Reader, fun func(ev *TestEvent)) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		ln := sc.Bytes()
+		ev := &TestEvent{}
+		if len(ln) == 0 || ln[0] != '{' || json.Unmarshal(ln, ev) != nil {
+			ev = &TestEvent{Action: "output", Output: string(ln) + "\n"}
+		}
+		fun(ev)
+	}
+}
+
+// GoTestCommand returns the go test command to run the given tests,
+// which must all be in the given package.
+func GoTestCommand(pkg *TestNode, tests ...*TestNode) *exec.Cmd {
+	cmd := exec.Command("go", GoTestArgs(tests...)...)
+	cmd.Dir = pkg.Dir
+	return cmd
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGoTestsSrc = `package pkg
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	t.Run("small numbers", func(t *testing.T) {
+		t.Run("zero", func(t *testing.T) {})
+	})
+	for _, name := range []string{"a", "b"} {
+		t.Run(name, func(t *testing.T) {})
+	}
+}
+
+func Testing(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+func BenchmarkAdd(b *testing.B) {
+	b.Run("big", func(b *testing.B) {})
+}
+
+func FuzzAdd(f *testing.F) {}
+
+func helper(t *testing.T) {}
+`
+
+func TestDiscoverGoTests(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0666))
+	pdir := filepath.Join(dir, "pkg")
+	assert.NoError(t, os.MkdirAll(pdir, 0777))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "testdata"), 0777))
+	fn := filepath.Join(pdir, "add_test.go")
+	assert.NoError(t, os.WriteFile(fn, []byte(testGoTestsSrc), 0666))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "testdata", "x_test.go"), []byte(testGoTestsSrc), 0666))
+
+	tr := DiscoverGoTests(dir)
+	if !assert.Equal(t, 1, tr.NumChildren()) {
+		return
+	}
+	pn := tr.Child(0).(*TestNode)
+	assert.Equal(t, "pkg", pn.Name)
+	assert.Equal(t, "example.com/m/pkg", pn.ImportPath)
+	var names []string
+	for _, k := range pn.Children {
+		kn := k.(*TestNode)
+		names = append(names, kn.Kind.String()+" "+kn.Name)
+	}
+	assert.Equal(t, []string{"Test TestAdd", "Benchmark BenchmarkAdd", "Fuzz FuzzAdd"}, names)
+
+	add := pn.ChildByTestName("TestAdd")
+	assert.Equal(t, 5, add.Line)
+	assert.Equal(t, 12, add.EndLine)
+	if assert.Equal(t, 1, add.NumChildren()) {
+		sub := add.Child(0).(*TestNode)
+		assert.Equal(t, "small_numbers", sub.Name)
+		assert.Equal(t, GoSubtest, sub.Kind)
+		zero := sub.ChildByTestName("zero")
+		assert.Equal(t, []string{"TestAdd", "small_numbers", "zero"}, zero.Path())
+		assert.Equal(t, "^TestAdd$/^small_numbers$/^zero$", zero.TestName())
+		assert.Equal(t, add, zero.Func())
+		assert.Equal(t, pn, zero.Package())
+
+		assert.Equal(t, zero, tr.NodeAt(fn, 7))
+		assert.Equal(t, sub, tr.NodeAt(fn, 8))
+		assert.Equal(t, add, tr.NodeAt(fn, 10))
+		assert.Nil(t, tr.NodeAt(fn, 14))
+	}
+
+	assert.Equal(t, []string{"test", "-json", "."}, GoTestArgs(pn))
+	assert.Equal(t, []string{"test", "-json", "-run", "^TestAdd$/^small_numbers$", "."}, GoTestArgs(add.Child(0).(*TestNode)))
+	bench := pn.ChildByTestName("BenchmarkAdd")
+	assert.Equal(t, []string{"test", "-json", "-run", "^$", "-bench", "^BenchmarkAdd$", "."}, GoTestArgs(bench))
+	big := bench.ChildByTestName("big")
+	assert.Equal(t, []string{"test", "-json", "-run", "^$", "-bench", "^BenchmarkAdd$/^big$", "."}, GoTestArgs(big))
+	assert.Equal(t, []string{"test", "-json", "-run", "^(FuzzAdd|TestAdd)$", "-bench", "^(BenchmarkAdd)$", "."},
+		GoTestArgs(add.Child(0).(*TestNode), pn.ChildByTestName("FuzzAdd"), big, bench, add))
+}
+
+const testGoTestsOut = `{"Action":"start","Package":"example.com/m/pkg"}
+{"Action":"run","Package":"example.com/m/pkg","Test":"TestAdd"}
+{"Action":"run","Package":"example.com/m/pkg","Test":"TestAdd/a"}
+{"Action":"output","Package":"example.com/m/pkg","Test":"TestAdd/a","Output":"    add_test.go:10: bad sum\n"}
+{"Action":"fail","Package":"example.com/m/pkg","Test":"TestAdd/a","Elapsed":0.01}
+{"Action":"run","Package":"example.com/m/pkg","Test":"TestAdd/b"}
+{"Action":"skip","Package":"example.com/m/pkg","Test":"TestAdd/b","Elapsed":0}
+{"Action":"fail","Package":"example.com/m/pkg","Test":"TestAdd","Elapsed":0.02}
+{"Action":"run","Package":"example.com/m/pkg","Test":"FuzzAdd"}
+{"Action":"pass","Package":"example.com/m/pkg","Test":"FuzzAdd","Elapsed":0}
+{"Action":"output","Package":"example.com/m/other","Output":"?   \texample.com/m/other\t[no test files]\n"}
+# example.com/m/pkg
+{"Action":"fail","Package":"example.com/m/pkg","Elapsed":0.5}
+`
+
+func TestReadTestEvents(t *testing.T) {
+	tr := NewTestNode()
+	pn := NewTestNode(tr)
+	pn.ImportPath = "example.com/m/pkg"
+	add := NewTestNode(pn)
+	add.SetName("TestAdd")
+	add.Kind = GoTest
+	fuzz := NewTestNode(pn)
+	fuzz.SetName("FuzzAdd")
+	fuzz.Kind = GoFuzz
+	ReadTestEvents(strings.NewReader(testGoTestsOut), func(ev *TestEvent) {
+		tr.applyEvent([]*TestNode{pn}, ev)
+	})
+
+	assert.Equal(t, TestFailed, pn.Status)
+	assert.Equal(t, 0.5, pn.Elapsed)
+	assert.Equal(t, TestFailed, add.Status)
+	a := add.ChildByTestName("a")
+	if assert.NotNil(t, a) {
+		assert.Equal(t, GoSubtest, a.Kind)
+		assert.Equal(t, "    add_test.go:10: bad sum\n", a.Output)
+		assert.Equal(t, "a (failed 0.01s)", a.Label())
+	}
+	assert.Equal(t, TestSkipped, add.ChildByTestName("b").Status)
+	assert.Equal(t, TestPassed, fuzz.Status)
+	assert.Contains(t, tr.Output, "[no test files]")
+	assert.Contains(t, pn.Output, "# example.com/m/pkg")
+
+	n := tr.Counts()
+	assert.Equal(t, 1, n[TestPassed])
+	assert.Equal(t, 2, n[TestFailed])
+	assert.Equal(t, 1, n[TestSkipped])
+	assert.Equal(t, []*TestNode{add}, tr.Failed())
+
+	add.Reset()
+	assert.Equal(t, TestNotRun, a.Status)
+	assert.Empty(t, a.Output)
+}
+
+func TestRewriteSubtestName(t *testing.T) {
+	assert.Equal(t, "small_numbers", RewriteSubtestName("small numbers"))
+	assert.Equal(t, `a\x00b`, RewriteSubtestName("a\x00b"))
+}
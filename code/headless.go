@@ -0,0 +1,98 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/base/vcs"
+	"cogentcore.org/core/core"
+)
+
+// OpenHeadless returns a new Code for running commands without a GUI,
+// e.g., in continuous integration, for the project at given path, which is
+// either a .code project file or the root directory of the project.
+// The output and status of the commands are written as plain text to w.
+// It changes the working directory to the project root, so any relative
+// paths, e.g., for the current file, must be made absolute first.
+func OpenHeadless(path string, w io.Writer) (*Code, error) {
+	cv := &Code{Output: w}
+	cv.Defaults()
+	root, _, _, ok := ProjectPathParse(path)
+	if !ok {
+		return nil, fmt.Errorf("could not open project at %q", path)
+	}
+	if strings.HasSuffix(path, ".code") {
+		if err := cv.Settings.Open(core.Filename(path)); err != nil {
+			return nil, err
+		}
+		cv.Settings.ProjectFilename = core.Filename(path)
+		if sr := string(cv.Settings.ProjectRoot); sr != "" { // saved root
+			if !filepath.IsAbs(sr) {
+				sr = filepath.Join(root, sr)
+			}
+			root = sr
+		}
+	}
+	cv.Settings.ProjectRoot = core.Filename(root)
+	cv.ProjectRoot = cv.Settings.ProjectRoot
+	cv.ProjectFilename = cv.Settings.ProjectFilename
+	SetGoMod(cv.Settings.GoMod)
+	cv.ArgVals.Set("", &cv.Settings, nil)
+	return cv, os.Chdir(root)
+}
+
+// RunHeadless runs the command with given name, after any commands that it
+// DependsOn, for a Code made by [OpenHeadless], waiting for each to complete
+// as in [Command.RunBufWait]. The ArgVals must already be set, and the values
+// for any prompts are provided by the Prompter, so that commands with prompts
+// that it does not provide values for are not run. It returns the error of
+// the first command that fails, for which [ExitCode] gives the exit status
+// of a command that exited with a non-zero status.
+func (cv *Code) RunHeadless(cmdName CmdName) error {
+	cm, _, ok := AvailableCommands.CmdByName(cmdName, false)
+	if !ok {
+		return fmt.Errorf("command %v not found", cmdName)
+	}
+	deps, err := cm.Dependencies()
+	if err != nil {
+		return err
+	}
+	for _, c := range append(deps, cm) {
+		if pvals, has := c.HasPrompts(); has {
+			prompted := false
+			c.Prompt(cv, cv.prompter(), pvals, func() { prompted = true })
+			if !prompted {
+				return fmt.Errorf("no values provided for the prompts of command %v", c.Label())
+			}
+		}
+		cv.outputErr = nil
+		if !c.runCmds(cv, cv.ArgVals, nil, true) {
+			if cv.outputErr != nil {
+				return cv.outputErr
+			}
+			return fmt.Errorf("command %v failed", c.Label())
+		}
+	}
+	return nil
+}
+
+// ListCommands writes the available commands that are compatible with
+// given language and version control system to w, one per line,
+// as Cat: Name followed by the description.
+func ListCommands(w io.Writer, lang fileinfo.Known, vcstype vcs.Types) {
+	for _, cc := range AvailableCommands.FilterCmdNames(lang, vcstype) {
+		for _, nm := range cc[1:] {
+			cnm := CommandName(cc[0], nm)
+			cm, _, _ := AvailableCommands.CmdByName(CmdName(cnm), false)
+			fmt.Fprintf(w, "%s\t%s\n", cnm, cm.Desc)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cogentcore.org/core/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunHeadless(t *testing.T) {
+	avail := AvailableCommands
+	defer func() { AvailableCommands = avail }()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	AvailableCommands = Commands{
+		{Cat: "Task", Name: "Hello", Env: []string{"GREETING=hello"},
+			Cmds: []CmdAndArgs{{Cmd: "sh", Args: CmdArgs{"-c", "echo $GREETING {PromptString1} {CurLine}"}}}},
+		{Cat: "Task", Name: "Fail", DependsOn: CmdNames{"Task: Hello"},
+			Cmds: []CmdAndArgs{{Cmd: "sh", Args: CmdArgs{"-c", "exit 3"}}}},
+	}
+	out := &bytes.Buffer{}
+	cv, err := OpenHeadless(t.TempDir(), out)
+	assert.NoError(t, err)
+	err = cv.RunHeadless("Task: Hello")
+	assert.ErrorContains(t, err, "no values provided")
+
+	cv.Prompter = &PresetPrompter{Values: map[string]string{"{PromptString1}": "world"}}
+	cv.ArgVals["{CurLine}"] = "12"
+	assert.NoError(t, cv.RunHeadless("Task: Hello"))
+	assert.Contains(t, out.String(), "hello world 12\n")
+	assert.Contains(t, out.String(), " successful")
+
+	out.Reset()
+	err = cv.RunHeadless("Task: Fail")
+	var ee *exec.ExitError
+	if assert.True(t, errors.As(err, &ee)) {
+		assert.Equal(t, 3, ee.ExitCode())
+	}
+	st, ok := ExitCode(err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, st)
+	assert.Contains(t, out.String(), "hello world")
+	assert.Contains(t, out.String(), " failed")
+
+	assert.ErrorContains(t, cv.RunHeadless("Task: Missing"), "not found")
+}
+
+func TestOpenHeadlessProject(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "src")
+	assert.NoError(t, os.Mkdir(root, 0755))
+	pf := filepath.Join(dir, "proj.code")
+	ps := &ProjectSettings{}
+	ps.ProjectRoot = core.Filename(root)
+	assert.NoError(t, ps.Save(core.Filename(pf)))
+
+	cv, err := OpenHeadless(pf, &bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.Filename(root), cv.ProjectRoot) // saved root kept
+	assert.Equal(t, core.Filename(pf), cv.Settings.ProjectFilename)
+
+	ps.ProjectRoot = ""
+	assert.NoError(t, ps.Save(core.Filename(pf)))
+	cv, err = OpenHeadless(pf, &bytes.Buffer{})
+	assert.NoError(t, err)
+	rd, _ := filepath.EvalSymlinks(dir)
+	pr, _ := filepath.EvalSymlinks(string(cv.ProjectRoot))
+	assert.Equal(t, rd, pr)
+}
+
+func TestRunParallelKill(t *testing.T) {
+	avail := AvailableCommands
+	defer func() { AvailableCommands = avail }()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	cm := &Command{Cat: "Task", Name: "Sleep", Parallel: true,
+		Cmds: []CmdAndArgs{{Cmd: "sleep", Args: CmdArgs{"10"}}, {Cmd: "sleep", Args: CmdArgs{"10"}}}}
+	AvailableCommands = Commands{cm}
+	cv, err := OpenHeadless(t.TempDir(), &bytes.Buffer{})
+	assert.NoError(t, err)
+	av := maps.Clone(cv.ArgVals)
+	done := make(chan bool)
+	go func() {
+		done <- cm.runCmds(cv, av, nil, true)
+	}()
+	assert.Eventually(t, func() bool {
+		_, i1 := cv.RunningCmds.ByName("Task: Sleep [1]")
+		_, i2 := cv.RunningCmds.ByName("Task: Sleep [2]")
+		return i1 >= 0 && i2 >= 0
+	}, 5*time.Second, 10*time.Millisecond)
+	start := time.Now()
+	cv.TabDeleted(cm.Label())
+	assert.False(t, <-done)
+	assert.Less(t, time.Since(start), 5*time.Second)
+	_, idx := cv.RunningCmds.ByName("Task: Sleep [1]")
+	assert.Equal(t, -1, idx)
+}
@@ -0,0 +1,462 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hierarchy computes the call hierarchy of the functions and the
+// type hierarchy of the types in a set of Go packages: the functions that
+// call a function and that it calls, and the types that implement an
+// interface and the interfaces that a type implements.
+package hierarchy
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"cogentcore.org/core/text/textpos"
+	ctoken "cogentcore.org/core/text/token"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Item is a function or type in a hierarchy.
+type Item struct {
+
+	// Name is the name of the item, qualified by its package name and,
+	// for a method, by its receiver type, e.g., code.Code.Find.
+	Name string
+
+	// Kind is the kind of item: [ctoken.NameFunction], [ctoken.NameMethod],
+	// [ctoken.NameInterface], or [ctoken.NameType].
+	Kind ctoken.Tokens
+
+	// Filename is the file that the item is declared in,
+	// which is empty if it is not known.
+	Filename string
+
+	// Region is the region of the name of the item in the file.
+	Region textpos.Region
+
+	// obj is the object of the item.
+	obj types.Object
+}
+
+// Call is one or more calls of a function by another.
+type Call struct {
+
+	// Item is the other function: the function that calls
+	// for incoming calls, or that is called for outgoing calls.
+	Item *Item
+
+	// Filename is the file of the calls, which is that of the calling function.
+	Filename string
+
+	// Sites are the regions of the name of the called function in each call.
+	Sites []textpos.Region
+}
+
+// Graph has the calls between the functions and the types of a set of
+// Go packages, from which the hierarchies of their items are computed.
+type Graph struct {
+
+	// Packages are the packages that the graph is for.
+	Packages []*Package
+
+	fset *token.FileSet
+
+	// items are the items made for each object so far.
+	items map[types.Object]*Item
+
+	// out and in are the positions of the calls from each function
+	// to each other function, and to each from each other.
+	out, in map[*types.Func]map[*types.Func][]token.Pos
+
+	// named are the named types declared in the packages.
+	named []*types.TypeName
+
+	// interfaces are the interfaces with methods declared in the packages
+	// and the packages that they import, including error.
+	interfaces []*types.TypeName
+
+	// sources are the lines of the source files read so far,
+	// for the positions of items in runes.
+	sources map[string][][]byte
+}
+
+// New returns the graph for the given packages,
+// which have been parsed with the given file set.
+func New(fset *token.FileSet, pkgs []*Package) *Graph {
+	g := &Graph{Packages: pkgs, fset: fset, items: map[types.Object]*Item{}, sources: map[string][][]byte{}}
+	g.out = map[*types.Func]map[*types.Func][]token.Pos{}
+	g.in = map[*types.Func]map[*types.Func][]token.Pos{}
+	g.interfaces = append(g.interfaces, types.Universe.Lookup("error").(*types.TypeName))
+	seen := map[*types.Package]bool{}
+	var addInterfaces func(pkg *types.Package)
+	addInterfaces = func(pkg *types.Package) {
+		if seen[pkg] {
+			return
+		}
+		seen[pkg] = true
+		for _, tn := range namedTypes(pkg) {
+			if it, ok := tn.Type().Underlying().(*types.Interface); ok && it.NumMethods() > 0 {
+				g.interfaces = append(g.interfaces, tn)
+			}
+		}
+		for _, imp := range pkg.Imports() {
+			addInterfaces(imp)
+		}
+	}
+	for _, pkg := range pkgs {
+		addInterfaces(pkg.Types)
+		g.named = append(g.named, namedTypes(pkg.Types)...)
+		for _, f := range pkg.Files {
+			g.addCalls(pkg.Info, f)
+		}
+	}
+	return g
+}
+
+// namedTypes returns the named types declared at the package level
+// of the given package, other than aliases and generic types.
+func namedTypes(pkg *types.Package) []*types.TypeName {
+	var tns []*types.TypeName
+	scope := pkg.Scope()
+	for _, nm := range scope.Names() {
+		tn, ok := scope.Lookup(nm).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+		if nt, ok := tn.Type().(*types.Named); ok && nt.TypeParams().Len() == 0 {
+			tns = append(tns, tn)
+		}
+	}
+	return tns
+}
+
+// addCalls adds the calls in the functions of the given file.
+func (g *Graph) addCalls(info *types.Info, f *ast.File) {
+	for _, d := range f.Decls {
+		fd, ok := d.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		caller, ok := info.Defs[fd.Name].(*types.Func)
+		if !ok {
+			continue
+		}
+		ast.Inspect(fd.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			callee, ok := typeutil.Callee(info, call).(*types.Func)
+			if !ok {
+				return true
+			}
+			callee = callee.Origin()
+			pos := calleePos(call)
+			if g.out[caller] == nil {
+				g.out[caller] = map[*types.Func][]token.Pos{}
+			}
+			if g.in[callee] == nil {
+				g.in[callee] = map[*types.Func][]token.Pos{}
+			}
+			g.out[caller][callee] = append(g.out[caller][callee], pos)
+			g.in[callee][caller] = append(g.in[callee][caller], pos)
+			return true
+		})
+	}
+}
+
+// calleePos returns the position of the name of the function
+// that is called in the given call.
+func calleePos(call *ast.CallExpr) token.Pos {
+	fun := ast.Unparen(call.Fun)
+	switch x := fun.(type) {
+	case *ast.IndexExpr:
+		fun = x.X
+	case *ast.IndexListExpr:
+		fun = x.X
+	}
+	if sel, ok := fun.(*ast.SelectorExpr); ok {
+		return sel.Sel.Pos()
+	}
+	return fun.Pos()
+}
+
+// item returns the item for the given object.
+func (g *Graph) item(obj types.Object) *Item {
+	if it, ok := g.items[obj]; ok {
+		return it
+	}
+	it := &Item{Name: qualifiedName(obj), Kind: ctoken.NameType, obj: obj}
+	switch obj := obj.(type) {
+	case *types.Func:
+		it.Kind = ctoken.NameFunction
+		if obj.Signature().Recv() != nil {
+			it.Kind = ctoken.NameMethod
+		}
+	case *types.TypeName:
+		if types.IsInterface(obj.Type()) {
+			it.Kind = ctoken.NameInterface
+		}
+	}
+	if obj.Pos().IsValid() {
+		it.Filename = g.fset.Position(obj.Pos()).Filename
+		it.Region = g.region(obj.Pos(), obj.Name())
+	}
+	g.items[obj] = it
+	return it
+}
+
+// qualifiedName returns the name of the given object qualified by its
+// package name and, for a method, by the name of its receiver type.
+func qualifiedName(obj types.Object) string {
+	nm := obj.Name()
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Signature().Recv(); recv != nil {
+			t := recv.Type()
+			if pt, ok := t.(*types.Pointer); ok {
+				t = pt.Elem()
+			}
+			if nt, ok := types.Unalias(t).(*types.Named); ok {
+				nm = nt.Obj().Name() + "." + nm
+			}
+		}
+	}
+	if obj.Pkg() != nil {
+		nm = obj.Pkg().Name() + "." + nm
+	}
+	return nm
+}
+
+// lines returns the lines of the given source file, nil if it can not be read.
+func (g *Graph) lines(filename string) [][]byte {
+	if lns, ok := g.sources[filename]; ok {
+		return lns
+	}
+	var lns [][]byte
+	if b, err := os.ReadFile(filename); err == nil {
+		lns = bytes.Split(b, []byte("\n"))
+	}
+	g.sources[filename] = lns
+	return lns
+}
+
+// region returns the region of the given name at the given position,
+// with the character positions in runes.
+func (g *Graph) region(pos token.Pos, name string) textpos.Region {
+	ps := g.fset.Position(pos)
+	ln, ch := ps.Line-1, max(ps.Column-1, 0)
+	if lns := g.lines(ps.Filename); ln >= 0 && ln < len(lns) && ch <= len(lns[ln]) {
+		ch = utf8.RuneCount(lns[ln][:ch])
+	}
+	return textpos.Region{Start: textpos.Pos{Line: ln, Char: ch}, End: textpos.Pos{Line: ln, Char: ch + utf8.RuneCountInString(name)}}
+}
+
+// calls returns the given calls to or from other functions as [Call]s,
+// sorted by the name of the other function. The given function is the
+// one that is called, or nil if it is the other function.
+func (g *Graph) calls(calls map[*types.Func][]token.Pos, callee *types.Func) []Call {
+	var cs []Call
+	for other, sites := range calls {
+		c := Call{Item: g.item(other), Filename: g.fset.Position(sites[0]).Filename}
+		name := other.Name()
+		if callee != nil {
+			name = callee.Name()
+		}
+		for _, pos := range sites {
+			c.Sites = append(c.Sites, g.region(pos, name))
+		}
+		cs = append(cs, c)
+	}
+	slices.SortFunc(cs, func(a, b Call) int {
+		return strings.Compare(a.Item.Name, b.Item.Name)
+	})
+	return cs
+}
+
+// Incoming returns the calls of the given function by other functions.
+func (g *Graph) Incoming(it *Item) []Call {
+	fn, ok := it.obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	return g.calls(g.in[fn], fn)
+}
+
+// Outgoing returns the calls of other functions by the given function.
+func (g *Graph) Outgoing(it *Item) []Call {
+	fn, ok := it.obj.(*types.Func)
+	if !ok {
+		return nil
+	}
+	return g.calls(g.out[fn], nil)
+}
+
+// implements returns whether the given type, or a pointer to it,
+// implements the given interface.
+func implements(t types.Type, iface *types.Interface) bool {
+	return types.Implements(t, iface) || (!types.IsInterface(t) && types.Implements(types.NewPointer(t), iface))
+}
+
+// Implementers returns the types in the packages that implement the given
+// interface, including other interfaces that have all of its methods,
+// sorted by name.
+func (g *Graph) Implementers(it *Item) []*Item {
+	tn, ok := it.obj.(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	iface, ok := tn.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	var its []*Item
+	for _, nt := range g.named {
+		if nt != tn && implements(nt.Type(), iface) {
+			its = append(its, g.item(nt))
+		}
+	}
+	return sortItems(its)
+}
+
+// Implements returns the interfaces with methods that the given type
+// implements, from the packages and the packages that they import,
+// sorted by name.
+func (g *Graph) Implements(it *Item) []*Item {
+	tn, ok := it.obj.(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	var its []*Item
+	for _, in := range g.interfaces {
+		if in != tn && implements(tn.Type(), in.Type().Underlying().(*types.Interface)) {
+			its = append(its, g.item(in))
+		}
+	}
+	return sortItems(its)
+}
+
+// sortItems sorts the given items by name and returns them.
+func sortItems(its []*Item) []*Item {
+	slices.SortFunc(its, func(a, b *Item) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return its
+}
+
+// FuncAt returns the function at the given position in the given file:
+// the function that the identifier at the position refers to, or else
+// the function or method declaration containing it, and nil if none.
+func (g *Graph) FuncAt(filename string, pos textpos.Pos) *Item {
+	return g.objectAt(filename, pos, func(obj types.Object) types.Object {
+		if fn, ok := obj.(*types.Func); ok {
+			return fn.Origin()
+		}
+		return nil
+	}, func(info *types.Info, n ast.Node) types.Object {
+		if fd, ok := n.(*ast.FuncDecl); ok {
+			return info.Defs[fd.Name]
+		}
+		return nil
+	})
+}
+
+// TypeAt returns the named type at the given position in the given file:
+// the type that the identifier at the position refers to or has, or else
+// the type declaration or the receiver type of the method containing it,
+// and nil if none.
+func (g *Graph) TypeAt(filename string, pos textpos.Pos) *Item {
+	named := func(t types.Type) types.Object {
+		if pt, ok := t.(*types.Pointer); ok {
+			t = pt.Elem()
+		}
+		if nt, ok := types.Unalias(t).(*types.Named); ok && nt.Obj().Pkg() != nil {
+			return nt.Origin().Obj()
+		}
+		return nil
+	}
+	return g.objectAt(filename, pos, func(obj types.Object) types.Object {
+		switch obj := obj.(type) {
+		case *types.TypeName:
+			return named(obj.Type())
+		case *types.Var:
+			return named(obj.Type())
+		}
+		return nil
+	}, func(info *types.Info, n ast.Node) types.Object {
+		switch n := n.(type) {
+		case *ast.TypeSpec:
+			return info.Defs[n.Name]
+		case *ast.FuncDecl:
+			if n.Recv != nil && len(n.Recv.List) > 0 {
+				return named(info.TypeOf(n.Recv.List[0].Type))
+			}
+		}
+		return nil
+	})
+}
+
+// objectAt returns the item for the object at the given position in the
+// given file, using the given functions to get the object from that of
+// an identifier at the position, or else from the nodes containing it.
+func (g *Graph) objectAt(filename string, pos textpos.Pos, ident func(obj types.Object) types.Object, encl func(info *types.Info, n ast.Node) types.Object) *Item {
+	filename = filepath.Clean(filename)
+	for _, pkg := range g.Packages {
+		for _, f := range pkg.Files {
+			tf := g.fset.File(f.Pos())
+			if tf == nil || filepath.Clean(tf.Name()) != filename {
+				continue
+			}
+			p, ok := g.tokenPos(tf, pos)
+			if !ok {
+				return nil
+			}
+			path, _ := astutil.PathEnclosingInterval(f, p, p)
+			for i, n := range path {
+				id, ok := n.(*ast.Ident)
+				if !ok || i > 0 {
+					continue
+				}
+				obj := pkg.Info.ObjectOf(id)
+				if obj == nil {
+					break
+				}
+				if o := ident(obj); o != nil {
+					return g.item(o)
+				}
+			}
+			for _, n := range path {
+				if o := encl(pkg.Info, n); o != nil {
+					return g.item(o)
+				}
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// tokenPos returns the position in the given file for the
+// given position in lines and runes, and false if it is not in it.
+func (g *Graph) tokenPos(tf *token.File, pos textpos.Pos) (token.Pos, bool) {
+	if pos.Line < 0 || pos.Line >= tf.LineCount() {
+		return token.NoPos, false
+	}
+	off := 0
+	if lns := g.lines(tf.Name()); pos.Line < len(lns) {
+		ln := lns[pos.Line]
+		for i := 0; i < pos.Char && off < len(ln); i++ {
+			_, sz := utf8.DecodeRune(ln[off:])
+			off += sz
+		}
+	}
+	return tf.LineStart(pos.Line+1) + token.Pos(off), true
+}
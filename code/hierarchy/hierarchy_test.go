@@ -0,0 +1,119 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hierarchy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/text/textpos"
+	"cogentcore.org/core/text/token"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSource = `package shapes
+
+import "fmt"
+
+type Shape interface {
+	Area() float64
+}
+
+type Named interface {
+	Shape
+	Name() string
+}
+
+type Square struct{ Side float64 }
+
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+type Circle struct{ R float64 }
+
+func (c *Circle) Area() float64 { return 3 * c.R * c.R }
+
+func (c *Circle) Name() string { return "circle" }
+
+func (c *Circle) String() string { return fmt.Sprint(c.R) }
+
+func Total(ss ...Shape) float64 {
+	t := 0.0
+	for _, s := range ss {
+		t += s.Area()
+	}
+	return t
+}
+
+func Report() string {
+	return fmt.Sprint(Total(Square{1}, &Circle{2}), Total())
+}
+`
+
+func region(ln, st, ed int) textpos.Region {
+	return textpos.Region{Start: textpos.Pos{Line: ln, Char: st}, End: textpos.Pos{Line: ln, Char: ed}}
+}
+
+func names(its []*Item) []string {
+	var nms []string
+	for _, it := range its {
+		nms = append(nms, it.Name)
+	}
+	return nms
+}
+
+func TestGraph(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/shapes\n\ngo 1.23\n"), 0666))
+	fname := filepath.Join(dir, "shapes.go")
+	require.NoError(t, os.WriteFile(fname, []byte(testSource), 0666))
+	g, err := Load(dir, "./...")
+	require.NoError(t, err)
+
+	total := g.FuncAt(fname, textpos.Pos{Line: 25, Char: 6})
+	require.NotNil(t, total)
+	assert.Equal(t, "shapes.Total", total.Name)
+	assert.Equal(t, token.NameFunction, total.Kind)
+	assert.Equal(t, fname, total.Filename)
+	assert.Equal(t, region(25, 5, 10), total.Region)
+	assert.Equal(t, total, g.FuncAt(fname, textpos.Pos{Line: 26, Char: 1}))
+
+	in := g.Incoming(total)
+	require.Len(t, in, 1)
+	assert.Equal(t, "shapes.Report", in[0].Item.Name)
+	assert.Equal(t, fname, in[0].Filename)
+	assert.Equal(t, []textpos.Region{region(34, 19, 24), region(34, 49, 54)}, in[0].Sites)
+
+	out := g.Outgoing(total)
+	require.Len(t, out, 1)
+	assert.Equal(t, "shapes.Shape.Area", out[0].Item.Name)
+	assert.Equal(t, token.NameMethod, out[0].Item.Kind)
+
+	report := g.FuncAt(fname, textpos.Pos{Line: 34, Char: 20})
+	assert.Equal(t, total, report)
+	report = g.FuncAt(fname, textpos.Pos{Line: 34, Char: 1})
+	assert.Equal(t, "shapes.Report", report.Name)
+	var outs []string
+	for _, c := range g.Outgoing(report) {
+		outs = append(outs, c.Item.Name)
+	}
+	assert.Equal(t, []string{"fmt.Sprint", "shapes.Total"}, outs)
+
+	shape := g.TypeAt(fname, textpos.Pos{Line: 4, Char: 6})
+	require.NotNil(t, shape)
+	assert.Equal(t, token.NameInterface, shape.Kind)
+	assert.Equal(t, []string{"shapes.Circle", "shapes.Named", "shapes.Square"}, names(g.Implementers(shape)))
+
+	circle := g.TypeAt(fname, textpos.Pos{Line: 19, Char: 30})
+	require.NotNil(t, circle)
+	assert.Equal(t, "shapes.Circle", circle.Name)
+	assert.Contains(t, names(g.Implements(circle)), "fmt.Stringer")
+	assert.Contains(t, names(g.Implements(circle)), "shapes.Named")
+	assert.Equal(t, []string{"shapes.Shape"}, names(g.Implements(g.TypeAt(fname, textpos.Pos{Line: 13, Char: 5}))))
+
+	assert.Nil(t, g.FuncAt(fname, textpos.Pos{Line: 0, Char: 0}))
+	assert.Nil(t, g.FuncAt(filepath.Join(dir, "other.go"), textpos.Pos{}))
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hierarchy
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Package is a Go package that is type checked from source for a [Graph].
+type Package struct {
+
+	// Path is the import path of the package.
+	Path string
+
+	// Types is the type checked package.
+	Types *types.Package
+
+	// Info is the type information for the syntax of the package.
+	Info *types.Info
+
+	// Files is the syntax of the Go files of the package.
+	Files []*ast.File
+}
+
+// Load loads the Go packages matching the given patterns, e.g., ./...,
+// in the given directory, and returns the graph for them. The packages
+// are type checked from source, using the export data built by the go
+// command for the packages that they import from elsewhere, so that
+// it works with any version of Go. Type errors are ignored, using
+// whatever could be type checked.
+func Load(dir string, patterns ...string) (*Graph, error) {
+	fset := token.NewFileSet()
+	cfg := &packages.Config{Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedExportFile, Dir: dir}
+	roots, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if len(roots) == 0 {
+		return nil, errors.New("hierarchy: no Go packages found in " + dir)
+	}
+	isRoot := map[*packages.Package]bool{}
+	for _, lp := range roots {
+		isRoot[lp] = true
+	}
+	exports := map[string]string{}
+	packages.Visit(roots, nil, func(lp *packages.Package) {
+		exports[lp.PkgPath] = lp.ExportFile
+	})
+	im := &srcImporter{checked: map[string]*types.Package{}}
+	im.gc = importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		if ef := exports[path]; ef != "" {
+			return os.Open(ef)
+		}
+		return nil, fmt.Errorf("hierarchy: no export data for %q", path)
+	})
+	var pkgs []*Package
+	packages.Visit(roots, nil, func(lp *packages.Package) { // in order of dependencies
+		if !isRoot[lp] {
+			return
+		}
+		pkg := &Package{Path: lp.PkgPath}
+		for _, fn := range lp.GoFiles {
+			if f, _ := parser.ParseFile(fset, fn, nil, parser.SkipObjectResolution); f != nil { // partial results are still useful
+				pkg.Files = append(pkg.Files, f)
+			}
+		}
+		pkg.Info = &types.Info{
+			Types:      map[ast.Expr]types.TypeAndValue{},
+			Instances:  map[*ast.Ident]types.Instance{},
+			Defs:       map[*ast.Ident]types.Object{},
+			Uses:       map[*ast.Ident]types.Object{},
+			Selections: map[*ast.SelectorExpr]*types.Selection{},
+		}
+		im.imports = lp.Imports
+		tc := &types.Config{Importer: im, Error: func(err error) {}}
+		pkg.Types, _ = tc.Check(lp.PkgPath, fset, pkg.Files, pkg.Info)
+		im.checked[lp.PkgPath] = pkg.Types
+		pkgs = append(pkgs, pkg)
+	})
+	return New(fset, pkgs), nil
+}
+
+// srcImporter is the importer used to type check the packages from
+// source, which imports the packages already checked, and the others
+// from their export data.
+type srcImporter struct {
+
+	// checked are the packages already checked, by path.
+	checked map[string]*types.Package
+
+	// imports are the imports of the package being checked,
+	// by the path used in the source.
+	imports map[string]*packages.Package
+
+	// gc imports packages from export data.
+	gc types.Importer
+}
+
+func (im *srcImporter) Import(path string) (*types.Package, error) {
+	if lp, ok := im.imports[path]; ok {
+		path = lp.PkgPath
+	}
+	if pkg, ok := im.checked[path]; ok {
+		return pkg, nil
+	}
+	return im.gc.Import(path)
+}
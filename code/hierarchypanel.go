@@ -0,0 +1,343 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"cogentcore.org/cogent/code/hierarchy"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/text/token"
+	"cogentcore.org/core/tree"
+)
+
+// HierarchyKinds are the kinds of hierarchy shown in the [HierarchyPanel].
+type HierarchyKinds int32 //enums:enum -trim-prefix Hierarchy
+
+const (
+	// HierarchyIncomingCalls shows the functions that call the function,
+	// and the functions that call them, and so on.
+	HierarchyIncomingCalls HierarchyKinds = iota
+
+	// HierarchyOutgoingCalls shows the functions that the function calls,
+	// and the functions that they call, and so on.
+	HierarchyOutgoingCalls
+
+	// HierarchyImplementers shows the types that implement the interface.
+	HierarchyImplementers
+
+	// HierarchyImplements shows the interfaces that the type implements.
+	HierarchyImplements
+)
+
+// isCalls returns whether the hierarchy is of function calls.
+func (hk HierarchyKinds) isCalls() bool {
+	return hk == HierarchyIncomingCalls || hk == HierarchyOutgoingCalls
+}
+
+// HierarchyPanel is a widget that shows the call hierarchy of a Go function,
+// or the type hierarchy of a Go type, in a tree that is expanded as it is
+// opened. It is computed from the Go packages in the project, which are
+// loaded when first needed, and again when the file at the cursor is newer.
+type HierarchyPanel struct {
+	core.Frame
+
+	// parent code project
+	Code *Code `json:"-" xml:"-"`
+
+	// kind of hierarchy shown
+	Kind HierarchyKinds
+
+	// function or type at the root of the hierarchy
+	Root *hierarchy.Item `set:"-" json:"-" xml:"-"`
+
+	// graph of the Go packages in the project, nil if not loaded
+	graph *hierarchy.Graph
+
+	// time when the graph was loaded
+	loaded time.Time
+
+	// set while the graph is loading
+	loading bool
+}
+
+func (hp *HierarchyPanel) Init() {
+	hp.Frame.Init()
+	hp.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 1)
+	})
+
+	tree.AddChildAt(hp, "hierarchy-toolbar", func(w *core.Toolbar) {
+		w.Maker(hp.makeToolbar)
+	})
+	tree.AddChildAt(hp, "hierarchy-frame", func(w *core.Frame) {
+		w.Styler(func(s *styles.Style) {
+			s.Grow.Set(1, 1)
+			s.Overflow.Set(styles.OverflowAuto)
+		})
+		tree.AddChildAt(w, "hierarchy", func(w *HierarchyTree) {
+			w.panel = hp
+			w.OnSelect(func(e events.Event) {
+				if len(w.SelectedNodes) == 0 {
+					return
+				}
+				if ht, ok := w.SelectedNodes[0].(*HierarchyTree); ok {
+					hp.showNode(ht)
+				}
+			})
+		})
+	})
+}
+
+func (hp *HierarchyPanel) OnAdd() {
+	hp.Frame.OnAdd()
+	hp.Code, _ = ParentCode(hp)
+}
+
+// Tree returns the root of the hierarchy tree.
+func (hp *HierarchyPanel) Tree() *HierarchyTree {
+	return hp.FindPath("hierarchy-frame/hierarchy").(*HierarchyTree)
+}
+
+func (hp *HierarchyPanel) makeToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("At cursor").SetIcon(icons.Schema).
+			SetTooltip("show the hierarchy of the function or type at the cursor in the active editor").
+			OnClick(func(e events.Event) {
+				hp.ShowAtCursor()
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!hp.loading) })
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Refresh").SetIcon(icons.Update).
+			SetTooltip("load the Go packages in the project again, and update the hierarchy").
+			OnClick(func(e events.Event) {
+				hp.Refresh()
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!hp.loading) })
+	})
+	tree.AddAt(p, "kind-chooser", func(w *core.Chooser) {
+		w.SetEnum(hp.Kind)
+		w.SetTooltip("kind of hierarchy to show")
+		w.OnChange(func(e events.Event) {
+			kind := w.CurrentItem.Value.(HierarchyKinds)
+			calls := kind.isCalls()
+			hp.Kind = kind
+			if hp.Root == nil || calls != (hp.Root.Kind == token.NameFunction || hp.Root.Kind == token.NameMethod) {
+				hp.ShowAtCursor()
+				return
+			}
+			hp.SetRoot(hp.Root)
+		})
+		w.Updater(func() {
+			w.SetCurrentValue(hp.Kind)
+		})
+	})
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			switch {
+			case hp.loading:
+				w.SetText("Loading Go packages...")
+			case hp.Root != nil:
+				w.SetText(hp.Root.Name)
+			default:
+				w.SetText("")
+			}
+		})
+	})
+}
+
+// ShowAtCursor shows the hierarchy of the function or type at the
+// cursor in the active editor, loading the Go packages as needed.
+func (hp *HierarchyPanel) ShowAtCursor() {
+	ed := hp.Code.ActiveEditor()
+	if ed == nil || ed.Lines == nil {
+		return
+	}
+	fname, pos := ed.Lines.Filename(), ed.CursorPos
+	if fi, err := os.Stat(fname); err == nil && fi.ModTime().After(hp.loaded) {
+		hp.graph = nil
+	}
+	hp.withGraph(func(g *hierarchy.Graph) {
+		var it *hierarchy.Item
+		if hp.Kind.isCalls() {
+			it = g.FuncAt(fname, pos)
+		} else {
+			it = g.TypeAt(fname, pos)
+			if it != nil && it.Kind != token.NameInterface && hp.Kind == HierarchyImplementers {
+				hp.Kind = HierarchyImplements
+			}
+		}
+		if it == nil {
+			what := "function"
+			if !hp.Kind.isCalls() {
+				what = "type"
+			}
+			core.MessageSnackbar(hp, "No Go "+what+" at the cursor in the active editor")
+			return
+		}
+		hp.SetRoot(it)
+	})
+}
+
+// Refresh loads the Go packages in the project again,
+// and shows the hierarchy at the cursor.
+func (hp *HierarchyPanel) Refresh() {
+	hp.graph = nil
+	hp.ShowAtCursor()
+}
+
+// withGraph calls the given function with the graph of the Go packages
+// in the project, after loading it in the background if it is not loaded.
+func (hp *HierarchyPanel) withGraph(fun func(g *hierarchy.Graph)) {
+	if hp.graph != nil {
+		fun(hp.graph)
+		return
+	}
+	if hp.loading {
+		return
+	}
+	cv := hp.Code
+	hp.loading = true
+	hp.Update()
+	go func() {
+		st := time.Now()
+		g, err := hierarchy.Load(string(cv.Files.Filepath), "./...")
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+		hp.loading = false
+		if err != nil {
+			core.ErrorSnackbar(hp, err, "Loading Go packages")
+		} else {
+			hp.graph, hp.loaded = g, st
+			fun(g)
+		}
+		hp.Update()
+	}()
+}
+
+// SetRoot shows the hierarchy of the given function or type.
+func (hp *HierarchyPanel) SetRoot(it *hierarchy.Item) {
+	hp.Root = it
+	tr := hp.Tree()
+	tr.DeleteChildren()
+	tr.SelectedNodes = nil
+	tr.setItem(it, nil)
+	tr.expand()
+	tr.SetClosed(false)
+	hp.Update()
+}
+
+// showNode shows the source of the given node: the call site for
+// incoming calls, and otherwise the declaration of the function or type.
+func (hp *HierarchyPanel) showNode(ht *HierarchyTree) {
+	if ht.Call != nil && hp.Kind == HierarchyIncomingCalls && len(ht.Call.Sites) > 0 {
+		hp.Code.OpenFileAtRegion(ht.Call.Filename, ht.Call.Sites[0])
+		return
+	}
+	if ht.Item == nil || ht.Item.Filename == "" {
+		return
+	}
+	hp.Code.OpenFileAtRegion(ht.Item.Filename, ht.Item.Region)
+}
+
+// HierarchyTree is a node in the tree of a [HierarchyPanel],
+// whose children are found when it is first opened.
+type HierarchyTree struct {
+	core.Tree
+
+	// function or type of the node
+	Item *hierarchy.Item `set:"-" json:"-" xml:"-"`
+
+	// for call hierarchies, the calls between the function
+	// of the node and that of its parent, nil for the root
+	Call *hierarchy.Call `set:"-" json:"-" xml:"-"`
+
+	// panel that the tree is in
+	panel *HierarchyPanel
+
+	// whether the children have been found
+	expanded bool
+}
+
+func (ht *HierarchyTree) CanOpen() bool {
+	return !ht.expanded || ht.HasChildren()
+}
+
+func (ht *HierarchyTree) OnOpen() {
+	ht.expand()
+}
+
+// setItem sets the item and call of the node, and its label.
+func (ht *HierarchyTree) setItem(it *hierarchy.Item, call *hierarchy.Call) {
+	ht.Item, ht.Call = it, call
+	ht.expanded = false
+	lbl := it.Name
+	if call != nil && len(call.Sites) > 1 {
+		lbl += fmt.Sprintf(" (%d calls)", len(call.Sites))
+	}
+	ht.SetText(lbl).SetIcon(symbolIcon(it.Kind))
+	if it.Filename != "" {
+		ht.SetTooltip(fmt.Sprintf("%s:%d", it.Filename, it.Region.Start.Line+1))
+	}
+}
+
+// inAncestors returns whether the given item is that of
+// any of the ancestors of the node, which is a cycle.
+func (ht *HierarchyTree) inAncestors(it *hierarchy.Item) bool {
+	for p := ht; p != nil; p, _ = p.Parent.(*HierarchyTree) {
+		if p.Item == it {
+			return true
+		}
+	}
+	return false
+}
+
+// expand adds the children of the node, if not already done.
+func (ht *HierarchyTree) expand() {
+	hp := ht.panel
+	if ht.Root != nil {
+		hp = ht.Root.(*HierarchyTree).panel
+	}
+	if ht.expanded || hp == nil || hp.graph == nil || ht.Item == nil {
+		return
+	}
+	ht.expanded = true
+	g := hp.graph
+	add := func(it *hierarchy.Item, call *hierarchy.Call) {
+		ct := NewHierarchyTree(ht)
+		ct.SetName(fmt.Sprintf("%d", ht.NumChildren()-1))
+		ct.setItem(it, call)
+		ct.SetClosed(true)
+		if ht.inAncestors(it) {
+			ct.expanded = true
+		}
+	}
+	switch hp.Kind {
+	case HierarchyIncomingCalls, HierarchyOutgoingCalls:
+		calls := g.Incoming(ht.Item)
+		if hp.Kind == HierarchyOutgoingCalls {
+			calls = g.Outgoing(ht.Item)
+		}
+		for i := range calls {
+			add(calls[i].Item, &calls[i])
+		}
+	case HierarchyImplementers:
+		for _, it := range g.Implementers(ht.Item) {
+			add(it, nil)
+		}
+	case HierarchyImplements:
+		for _, it := range g.Implements(ht.Item) {
+			add(it, nil)
+		}
+	}
+	ht.Update()
+}
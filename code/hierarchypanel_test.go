@@ -0,0 +1,55 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/cogent/code/hierarchy"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHierarchyPanel(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/rec\n\ngo 1.23\n"), 0666))
+	fname := filepath.Join(dir, "rec.go")
+	src := "package rec\n\nfunc A(n int) int {\n\tif n == 0 {\n\t\treturn B()\n\t}\n\treturn A(n-1) + B()\n}\n\nfunc B() int { return 1 }\n"
+	require.NoError(t, os.WriteFile(fname, []byte(src), 0666))
+	g, err := hierarchy.Load(dir, "./...")
+	require.NoError(t, err)
+
+	b := core.NewBody()
+	hp := NewHierarchyPanel(b)
+	hp.graph = g
+	hp.Kind = HierarchyOutgoingCalls
+	hp.Update()
+	hp.SetRoot(g.FuncAt(fname, textpos.Pos{Line: 2, Char: 5}))
+	tr := hp.Tree()
+	assert.Equal(t, "rec.A", tr.Text)
+	require.Equal(t, 2, tr.NumChildren())
+	a := tr.Child(0).(*HierarchyTree)
+	assert.Equal(t, "rec.A", a.Text)
+	assert.False(t, a.CanOpen()) // recursive call
+	bn := tr.Child(1).(*HierarchyTree)
+	assert.Equal(t, "rec.B (2 calls)", bn.Text)
+	assert.True(t, bn.CanOpen())
+	bn.Open()
+	assert.False(t, bn.HasChildren())
+	assert.False(t, bn.CanOpen())
+
+	hp.Kind = HierarchyIncomingCalls
+	hp.SetRoot(bn.Item)
+	require.Equal(t, 1, tr.NumChildren())
+	a = tr.Child(0).(*HierarchyTree)
+	assert.Equal(t, "rec.A (2 calls)", a.Text)
+	a.Open()
+	require.Equal(t, 1, a.NumChildren())
+	assert.Equal(t, "rec.A", a.Child(0).(*HierarchyTree).Text)
+}
@@ -0,0 +1,479 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package index provides a persistent trigram index of the text files
+// under a project root directory, which is used to quickly find the files
+// that can contain a given string, so that only those files need to be
+// searched, and the Go symbols and file names in the project.
+package index
+
+import (
+	"bytes"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"github.com/fsnotify/fsnotify"
+)
+
+// UpdateDelay is how long after the last change notification
+// the changed files are updated in the index, so that a burst
+// of changes, e.g., from a version control operation, is only
+// processed once.
+var UpdateDelay = 500 * time.Millisecond
+
+// skipDirs are the names of directories that are not indexed.
+var skipDirs = []string{".git", ".hg", ".svn"}
+
+// binarySniffLen is the length of the start of a file that is
+// checked for a zero byte to determine if it is a binary file.
+const binarySniffLen = 8000
+
+// Index is a trigram index of the text files under the Root directory,
+// recording for each sequence of three bytes of their lowercased contents
+// the files that contain it. It is safe for concurrent use, so that it
+// can be built and updated in the background while it is used.
+// Files in version control directories such as .git, and files larger
+// than [core.SystemSettings.BigFileSize], are not indexed, and binary
+// files are recorded but their contents are not indexed, so that
+// they are always candidates for a search.
+type Index struct {
+
+	// Root is the absolute path of the root directory of the index.
+	Root string
+
+	// OnUpdate, if set, is called after the index has been updated
+	// from change notifications, e.g., to save it.
+	OnUpdate func()
+
+	mu sync.RWMutex
+
+	// files are the indexed files, by id, with removed ones having no Path.
+	files []File
+
+	// ids are the ids of the files, by path.
+	ids map[string]uint32
+
+	// dirs are the indexed directories, with their modification times.
+	dirs map[string]int64
+
+	// grams are the sorted ids of the files containing each trigram.
+	grams map[uint32][]uint32
+
+	// built is whether the index has been built and checked
+	// against the file system, since it was created or opened.
+	built bool
+
+	// pending are the paths that have changed and are yet to be updated.
+	pending map[string]bool
+
+	// updating is the number of updates of pending paths in progress.
+	updating int
+
+	// timer is the timer for updating the pending paths.
+	timer *time.Timer
+
+	// watcher watches all of the indexed directories for changes,
+	// once [Index.Watch] has been called.
+	watcher *fsnotify.Watcher
+
+	// watchFailed is whether any of the directories could not be watched,
+	// or change notifications were lost, in which case the index can not
+	// be trusted to be up to date.
+	watchFailed bool
+}
+
+// File is the information recorded for one file in an [Index].
+type File struct {
+
+	// Path is the absolute path of the file.
+	Path string
+
+	// ModTime is the modification time of the file, in Unix nanoseconds.
+	ModTime int64
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// Mode is the file mode, e.g., for determining if it is executable.
+	Mode fs.FileMode
+
+	// Known is the known type of the file.
+	Known fileinfo.Known
+
+	// Generated is whether the file is generated, which are skipped
+	// in searches, as in the search package.
+	Generated bool
+
+	// Binary is whether the file is a binary file, whose contents
+	// are not indexed.
+	Binary bool
+
+	// Symbols are the top-level symbols declared in a Go file.
+	Symbols []Symbol
+}
+
+// New returns a new empty index for given root directory.
+func New(root string) *Index {
+	ix := &Index{}
+	ix.Root, _ = filepath.Abs(root)
+	ix.reset()
+	return ix
+}
+
+// reset resets the index to be empty.
+func (ix *Index) reset() {
+	ix.files = nil
+	ix.ids = map[string]uint32{}
+	ix.dirs = map[string]int64{}
+	ix.grams = map[uint32][]uint32{}
+	ix.pending = map[string]bool{}
+}
+
+// Fresh returns whether the index is up to date with the file system,
+// which is the case once it has been built, while all of its directories
+// are being watched for changes by [Index.Watch], and while there are no
+// changes pending. A search should fall back on scanning the files when
+// it is not. It is false for a nil index.
+func (ix *Index) Fresh() bool {
+	if ix == nil {
+		return false
+	}
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.built && ix.watcher != nil && !ix.watchFailed && len(ix.pending) == 0 && ix.updating == 0
+}
+
+// Watch starts watching all of the directories under the Root for changes,
+// which are updated as in [Index.Changed], until [Index.Close] is called.
+// It should be called before [Index.Build], which adds the directories.
+func (ix *Index) Watch() error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	ix.mu.Lock()
+	ix.watcher = w
+	ix.mu.Unlock()
+	go func() {
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if ev.Op != fsnotify.Chmod {
+					ix.Changed(ev.Name)
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("index: watching %s: %v\n", ix.Root, err)
+				ix.mu.Lock()
+				ix.watchFailed = true // e.g., lost notifications
+				ix.mu.Unlock()
+			}
+		}
+	}()
+	return nil
+}
+
+// watchDir adds given directory to the watcher, if watching.
+func (ix *Index) watchDir(path string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.watcher == nil {
+		return
+	}
+	if err := ix.watcher.Add(path); err != nil {
+		log.Printf("index: watching %s: %v\n", path, err)
+		ix.watchFailed = true
+	}
+}
+
+// Close stops watching the directories for changes.
+func (ix *Index) Close() error {
+	if ix == nil {
+		return nil
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.timer != nil {
+		ix.timer.Stop()
+	}
+	if ix.watcher == nil {
+		return nil
+	}
+	err := ix.watcher.Close()
+	ix.watcher = nil
+	return err
+}
+
+// Covers returns whether given path is within the Root of the index.
+func (ix *Index) Covers(path string) bool {
+	if ix == nil {
+		return false
+	}
+	return path == ix.Root || strings.HasPrefix(path, ix.Root+string(filepath.Separator))
+}
+
+// Build indexes all of the files under the Root, only reindexing
+// the files that have changed for an index that has been opened,
+// and removing those that no longer exist. It is typically called
+// in a separate goroutine, and the index is not [Index.Fresh]
+// until it is done.
+func (ix *Index) Build() error {
+	seen := map[string]bool{}
+	dirs := map[string]int64{}
+	err := filepath.WalkDir(ix.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != ix.Root && slices.Contains(skipDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			if info, err := d.Info(); err == nil {
+				dirs[path] = info.ModTime().UnixNano()
+			}
+			ix.watchDir(path)
+			return nil
+		}
+		seen[path] = true
+		ix.updateFile(path)
+		return nil
+	})
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	for path, id := range ix.ids {
+		if !seen[path] {
+			ix.removeLocked(id)
+		}
+	}
+	ix.dirs = dirs
+	ix.built = true
+	return err
+}
+
+// Changed notifies the index that the file or directory at given path
+// has changed, for example from a [Index.Watch] notification, or
+// from a file being saved. For a directory, its files and any new
+// subdirectories are updated. The update happens after [UpdateDelay],
+// and the index is not [Index.Fresh] until then.
+func (ix *Index) Changed(path string) {
+	if ix == nil || !ix.Covers(path) {
+		return
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.pending[path] = true
+	if ix.timer == nil {
+		ix.timer = time.AfterFunc(UpdateDelay, ix.updatePending)
+	} else {
+		ix.timer.Reset(UpdateDelay)
+	}
+}
+
+// updatePending updates all of the pending changed paths.
+func (ix *Index) updatePending() {
+	ix.mu.Lock()
+	paths := make([]string, 0, len(ix.pending))
+	for p := range ix.pending {
+		paths = append(paths, p)
+	}
+	clear(ix.pending) // changes during the update are pending again
+	ix.updating++
+	ix.mu.Unlock()
+	for _, p := range paths {
+		ix.update(p)
+	}
+	ix.mu.Lock()
+	ix.updating--
+	ix.mu.Unlock()
+	if ix.OnUpdate != nil {
+		ix.OnUpdate()
+	}
+}
+
+// update updates the index for the given changed path.
+func (ix *Index) update(path string) {
+	info, err := os.Stat(path)
+	if err != nil { // removed
+		ix.mu.Lock()
+		if id, ok := ix.ids[path]; ok {
+			ix.removeLocked(id)
+		}
+		prefix := path + string(filepath.Separator)
+		for p, id := range ix.ids {
+			if strings.HasPrefix(p, prefix) {
+				ix.removeLocked(id)
+			}
+		}
+		for d := range ix.dirs {
+			if d == path || strings.HasPrefix(d, prefix) {
+				delete(ix.dirs, d)
+			}
+		}
+		ix.mu.Unlock()
+		return
+	}
+	if !info.IsDir() {
+		ix.updateFile(path)
+		return
+	}
+	ix.watchDir(path) // no-op if already watched
+	des, err := os.ReadDir(path)
+	if err != nil {
+		return
+	}
+	ix.mu.Lock()
+	ix.dirs[path] = info.ModTime().UnixNano()
+	var removed []uint32
+	for p, id := range ix.ids {
+		if filepath.Dir(p) == path {
+			if _, err := os.Stat(p); err != nil {
+				removed = append(removed, id)
+			}
+		}
+	}
+	for _, id := range removed {
+		ix.removeLocked(id)
+	}
+	var subdirs []string
+	for d := range ix.dirs {
+		if filepath.Dir(d) == path && d != path {
+			subdirs = append(subdirs, d)
+		}
+	}
+	ix.mu.Unlock()
+	for _, d := range subdirs {
+		if _, err := os.Stat(d); err != nil { // removed, e.g., from a rename
+			ix.update(d)
+		}
+	}
+	for _, d := range des {
+		fpath := filepath.Join(path, d.Name())
+		if !d.IsDir() {
+			ix.updateFile(fpath)
+			continue
+		}
+		if slices.Contains(skipDirs, d.Name()) {
+			continue
+		}
+		ix.mu.RLock()
+		_, has := ix.dirs[fpath]
+		ix.mu.RUnlock()
+		if !has { // new directory, e.g., from a rename
+			ix.update(fpath)
+		}
+	}
+}
+
+// updateFile indexes the file at given path if it is not already
+// indexed or has changed since it was.
+func (ix *Index) updateFile(path string) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || int(info.Size()) > core.SystemSettings.BigFileSize {
+		return
+	}
+	ix.mu.RLock()
+	id, has := ix.ids[path]
+	if has {
+		f := &ix.files[id]
+		if f.ModTime == info.ModTime().UnixNano() && f.Size == info.Size() {
+			ix.mu.RUnlock()
+			return
+		}
+	}
+	ix.mu.RUnlock()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	f := File{Path: path, ModTime: info.ModTime().UnixNano(), Size: info.Size(), Mode: info.Mode()}
+	fi := &fileinfo.FileInfo{}
+	fi.InitFile(path)
+	f.Known = fi.Known
+	f.Generated = fi.Generated
+	f.Binary = bytes.IndexByte(b[:min(len(b), binarySniffLen)], 0) >= 0
+	var grams []uint32
+	if !f.Binary {
+		grams = trigrams(bytes.ToLower(b))
+		if f.Known == fileinfo.Go {
+			f.Symbols = goSymbols(path, b)
+		}
+	}
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if id, has := ix.ids[path]; has {
+		if cur := &ix.files[id]; cur.ModTime > f.ModTime || (cur.ModTime == f.ModTime && cur.Size == f.Size) {
+			return // already indexed as recently by another update
+		}
+		ix.removeLocked(id)
+	}
+	id = uint32(len(ix.files))
+	ix.files = append(ix.files, f)
+	ix.ids[path] = id
+	for _, g := range grams {
+		ix.grams[g] = append(ix.grams[g], id)
+	}
+}
+
+// removeLocked removes the file with given id, which remains
+// in the postings of its trigrams until the index is compacted.
+// The index must be locked.
+func (ix *Index) removeLocked(id uint32) {
+	delete(ix.ids, ix.files[id].Path)
+	ix.files[id] = File{}
+}
+
+// trigrams returns the sorted unique trigrams of given text.
+func trigrams(b []byte) []uint32 {
+	if len(b) < 3 {
+		return nil
+	}
+	gs := make([]uint32, 0, len(b)-2)
+	for i := 0; i+3 <= len(b); i++ {
+		gs = append(gs, uint32(b[i])<<16|uint32(b[i+1])<<8|uint32(b[i+2]))
+	}
+	slices.Sort(gs)
+	return slices.Compact(gs)
+}
+
+// Files returns all of the indexed files, sorted by path.
+func (ix *Index) Files() []File {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	fls := make([]File, 0, len(ix.ids))
+	for _, f := range ix.files {
+		if f.Path != "" {
+			fls = append(fls, f)
+		}
+	}
+	slices.SortFunc(fls, func(a, b File) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return fls
+}
+
+// Dirs returns the paths of all of the indexed directories, sorted,
+// including the Root.
+func (ix *Index) Dirs() []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	ds := make([]string, 0, len(ix.dirs))
+	for d := range ix.dirs {
+		ds = append(ds, d)
+	}
+	slices.Sort(ds)
+	return ds
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package index
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/text/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for nm, s := range files {
+		fpath := filepath.Join(dir, nm)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(fpath), 0755))
+		assert.NoError(t, os.WriteFile(fpath, []byte(s), 0644))
+	}
+}
+
+func TestIndex(t *testing.T) {
+	core.SystemSettings.BigFileSize = 10000000
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.go":        "package a\n\nfunc Hello() string { return \"Hello, World\" }\n\ntype T struct{}\n\nfunc (t *T) Hi() {}\n",
+		"sub/b.txt":   "hello there\n",
+		"sub/c.txt":   "goodbye\n",
+		"bin/d.bin":   "hello\x00",
+		".git/config": "hello\n",
+	})
+	ix := New(dir)
+	assert.False(t, ix.Fresh())
+	_, ok := ix.Candidates([]string{dir}, true, "hello", false)
+	assert.False(t, ok)
+
+	assert.NoError(t, ix.Build())
+	assert.False(t, ix.Fresh()) // not watching
+	assert.NoError(t, ix.Watch())
+	defer ix.Close()
+	assert.NoError(t, ix.Build())
+	assert.True(t, ix.Fresh())
+	fpaths, ok := ix.Candidates([]string{dir}, true, "HELLO", false)
+	assert.True(t, ok)
+	assert.Equal(t, []string{filepath.Join(dir, "a.go"), filepath.Join(dir, "bin/d.bin"), filepath.Join(dir, "sub/b.txt")}, fpaths)
+	fpaths, _ = ix.Candidates([]string{filepath.Join(dir, "sub")}, false, "hello", false)
+	assert.Equal(t, []string{filepath.Join(dir, "sub/b.txt")}, fpaths)
+	fpaths, _ = ix.Candidates([]string{dir}, true, "good.*", true)
+	assert.Equal(t, []string{filepath.Join(dir, "bin/d.bin"), filepath.Join(dir, "sub/c.txt")}, fpaths)
+	_, ok = ix.Candidates([]string{dir}, true, "he", false)
+	assert.False(t, ok)
+	_, ok = ix.Candidates([]string{t.TempDir()}, true, "hello", false)
+	assert.False(t, ok)
+
+	res, ok, err := ix.Search([]string{dir}, true, "Hello", false, false, nil)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	if assert.Len(t, res, 1) {
+		assert.Equal(t, filepath.Join(dir, "a.go"), res[0].Filepath)
+		assert.Equal(t, 2, res[0].Count)
+	}
+	res, _, _ = ix.Search([]string{dir}, true, "hello", true, false, []fileinfo.Known{fileinfo.Go})
+	assert.Len(t, res, 1)
+	res, _, _ = ix.Search([]string{dir}, true, "hello", true, false, nil, filepath.Join(dir, "a.go"))
+	assert.Len(t, res, 2)
+
+	syms, paths := ix.Symbols()
+	assert.Equal(t, []Symbol{{"Hello", token.NameFunction, 2, 5}, {"T", token.NameType, 4, 5}, {"T.Hi", token.NameMethod, 6, 12}}, syms)
+	assert.Equal(t, filepath.Join(dir, "a.go"), paths[0])
+	assert.Contains(t, ix.Dirs(), filepath.Join(dir, "sub"))
+	assert.NotContains(t, ix.Dirs(), filepath.Join(dir, ".git"))
+
+	UpdateDelay = 10 * time.Millisecond
+	// changes are watched, including in new directories
+	writeFiles(t, dir, map[string]string{"sub/c.txt": "hello again\n", "sub/new/e.txt": "hello new\n"})
+	assert.NoError(t, os.Remove(filepath.Join(dir, "sub/b.txt")))
+	want := []string{filepath.Join(dir, "sub/c.txt"), filepath.Join(dir, "sub/new/e.txt")}
+	assert.Eventually(t, func() bool {
+		fpaths, _ = ix.Candidates([]string{filepath.Join(dir, "sub")}, true, "hello", false)
+		return ix.Fresh() && slices.Equal(want, fpaths)
+	}, 5*time.Second, 10*time.Millisecond)
+	writeFiles(t, dir, map[string]string{"sub/new/f.txt": "hello newer\n"})
+	assert.Eventually(t, func() bool {
+		fpaths, _ = ix.Candidates([]string{filepath.Join(dir, "sub/new")}, true, "newer", false)
+		return ix.Fresh() && len(fpaths) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, os.Remove(filepath.Join(dir, "sub/new/f.txt")))
+	assert.Eventually(t, func() bool {
+		fpaths, _ = ix.Candidates([]string{filepath.Join(dir, "sub")}, true, "hello", false)
+		return ix.Fresh() && slices.Equal(want, fpaths)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	fname := filepath.Join(t.TempDir(), "index", "index.gob")
+	assert.NoError(t, ix.Save(fname))
+	oix, err := Open(dir, fname)
+	assert.NoError(t, err)
+	assert.False(t, oix.Fresh())
+	assert.Equal(t, ix.Files(), oix.Files())
+	assert.NoError(t, oix.Watch())
+	defer oix.Close()
+	assert.NoError(t, oix.Build())
+	ofpaths, _ := oix.Candidates([]string{filepath.Join(dir, "sub")}, true, "hello", false)
+	assert.Equal(t, fpaths, ofpaths)
+
+	oix, err = Open(t.TempDir(), fname)
+	assert.NoError(t, err)
+	assert.Empty(t, oix.Files())
+}
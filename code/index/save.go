@@ -0,0 +1,143 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package index
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+)
+
+// version is the version of the saved index format, which
+// is incremented when it changes, so that old indexes are rebuilt.
+const version = 1
+
+// saved is the saved form of an index, with the ids of the files
+// containing each trigram delta and varint encoded.
+type saved struct {
+	Version int
+	Root    string
+	Files   []File
+	Dirs    map[string]int64
+	Grams   map[uint32][]byte
+}
+
+// Open returns the index for given root directory that was saved in the
+// given file by [Index.Save], or a new empty index if there is no such
+// file, or it is for a different root directory or version of the format.
+// The index must then be updated with [Index.Build].
+func Open(root, filename string) (*Index, error) {
+	ix := New(root)
+	f, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ix, nil
+		}
+		return ix, err
+	}
+	defer f.Close()
+	sv := &saved{}
+	if err := gob.NewDecoder(bufio.NewReader(f)).Decode(sv); err != nil {
+		return ix, err
+	}
+	if sv.Version != version || sv.Root != ix.Root {
+		return ix, nil
+	}
+	ix.files = sv.Files
+	for id, f := range ix.files {
+		ix.ids[f.Path] = uint32(id)
+	}
+	ix.dirs = sv.Dirs
+	for g, b := range sv.Grams {
+		var ids []uint32
+		id := uint64(0)
+		for len(b) > 0 {
+			d, n := binary.Uvarint(b)
+			if n <= 0 {
+				break
+			}
+			id += d
+			ids = append(ids, uint32(id))
+			b = b[n:]
+		}
+		ix.grams[g] = ids
+	}
+	return ix, nil
+}
+
+// Save saves the index to given file, creating its directory if needed,
+// so that it can be opened again with [Open]. The removed files
+// are dropped from the index, which compacts it.
+func (ix *Index) Save(filename string) error {
+	ix.mu.Lock()
+	ix.compactLocked()
+	sv := &saved{Version: version, Root: ix.Root, Files: ix.files, Dirs: ix.dirs, Grams: make(map[uint32][]byte, len(ix.grams))}
+	for g, ids := range ix.grams {
+		var b []byte
+		last := uint32(0)
+		for _, id := range ids {
+			b = binary.AppendUvarint(b, uint64(id-last))
+			last = id
+		}
+		sv.Grams[g] = b
+	}
+	ix.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0750); err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	err = gob.NewEncoder(w).Encode(sv)
+	if err == nil {
+		err = w.Flush()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// compactLocked drops the removed files, renumbering the ids
+// of the remaining ones. The index must be locked.
+func (ix *Index) compactLocked() {
+	if len(ix.ids) == len(ix.files) {
+		return
+	}
+	old := ix.files
+	newIDs := make([]uint32, len(old))
+	ix.files = make([]File, 0, len(ix.ids))
+	for id, f := range old {
+		if f.Path == "" {
+			continue
+		}
+		newIDs[id] = uint32(len(ix.files))
+		ix.ids[f.Path] = newIDs[id]
+		ix.files = append(ix.files, f)
+	}
+	for g, ids := range ix.grams {
+		nids := ids[:0]
+		for _, id := range ids {
+			if old[id].Path != "" {
+				nids = append(nids, newIDs[id])
+			}
+		}
+		if len(nids) == 0 {
+			delete(ix.grams, g)
+		} else {
+			ix.grams[g] = nids
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package index
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/text/search"
+	"cogentcore.org/core/text/textpos"
+)
+
+// Candidates returns the paths of the files in the given list of paths,
+// recursively if all, that can contain the given string, which is the
+// set of files that contain all of its trigrams. For a regexp, the
+// literal prefix of the regexp is used. It returns false if the index
+// can not be used for the search, because it is not [Index.Fresh],
+// does not cover all of the paths, or the string is too short.
+func (ix *Index) Candidates(paths []string, all bool, find string, regExp bool) ([]string, bool) {
+	if !ix.Fresh() {
+		return nil, false
+	}
+	for _, p := range paths {
+		if !ix.Covers(p) {
+			return nil, false
+		}
+	}
+	if regExp {
+		re, err := regexp.Compile(find)
+		if err != nil {
+			return nil, false
+		}
+		find, _ = re.LiteralPrefix()
+	}
+	grams := trigrams([]byte(strings.ToLower(find)))
+	if len(grams) == 0 {
+		return nil, false
+	}
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	var ids []uint32
+	for i, g := range grams {
+		gids := ix.grams[g]
+		if i == 0 {
+			ids = gids
+		} else {
+			ids = intersect(ids, gids)
+		}
+		if len(ids) == 0 {
+			break
+		}
+	}
+	var fpaths []string
+	add := func(f *File) {
+		if f.Path == "" {
+			return
+		}
+		for _, p := range paths {
+			if (all && (f.Path == p || strings.HasPrefix(f.Path, p+string(filepath.Separator)))) || filepath.Dir(f.Path) == p {
+				fpaths = append(fpaths, f.Path)
+				return
+			}
+		}
+	}
+	for _, id := range ids {
+		add(&ix.files[id])
+	}
+	for i := range ix.files {
+		if ix.files[i].Binary {
+			add(&ix.files[i])
+		}
+	}
+	slices.Sort(fpaths)
+	return fpaths, true
+}
+
+// intersect returns the ids that are in both of the given sorted lists.
+func intersect(a, b []uint32) []uint32 {
+	var r []uint32
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			r = append(r, a[i])
+			i++
+			j++
+		}
+	}
+	return r
+}
+
+// Search returns the files in given list of paths that contain the
+// given string, with the same options and results as [search.Paths],
+// or [search.All] if all is true, in which case the paths are searched
+// recursively, including all subdirectories, except that it only
+// searches the [Index.Candidates] for the string, and exclude has the
+// full paths of files to exclude. It returns false if the index can not
+// be used for the search, in which case the files should be searched
+// with the search package instead.
+func (ix *Index) Search(paths []string, all bool, find string, ignoreCase, regExp bool, langs []fileinfo.Known, exclude ...string) ([]search.Results, bool, error) {
+	if find == "" {
+		return nil, false, nil
+	}
+	var re *regexp.Regexp
+	if regExp {
+		var err error
+		re, err = regexp.Compile(find)
+		if err != nil {
+			return nil, true, err
+		}
+	}
+	fpaths, ok := ix.Candidates(paths, all, find, regExp)
+	if !ok {
+		return nil, false, nil
+	}
+	var res []search.Results
+	for _, fpath := range fpaths {
+		if slices.Contains(exclude, fpath) {
+			continue
+		}
+		f, ok := ix.File(fpath)
+		if !ok || f.Generated || !search.LangCheck(&fileinfo.FileInfo{Known: f.Known}, langs) {
+			continue
+		}
+		var cnt int
+		var matches []textpos.Match
+		if regExp {
+			cnt, matches = search.FileRegexp(fpath, re)
+		} else {
+			cnt, matches = search.File(fpath, []byte(find), ignoreCase)
+		}
+		if cnt > 0 {
+			res = append(res, search.Results{Filepath: fpath, Count: cnt, Matches: matches})
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Count > res[j].Count
+	})
+	return res, true, nil
+}
+
+// File returns the indexed file at given path, and whether it was found.
+func (ix *Index) File(path string) (File, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	id, ok := ix.ids[path]
+	if !ok {
+		return File{}, false
+	}
+	return ix.files[id], true
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package index
+
+import (
+	"go/ast"
+	"go/parser"
+	gotoken "go/token"
+	"unicode/utf8"
+
+	"cogentcore.org/core/text/token"
+)
+
+// Symbol is a top-level symbol declared in a Go file.
+type Symbol struct {
+
+	// Name is the name of the symbol, which for a method is
+	// qualified by its receiver type, e.g., Type.Method.
+	Name string
+
+	// Kind is the kind of symbol, e.g., [token.NameFunction].
+	Kind token.Tokens
+
+	// Line and Char are the position of the name of the symbol,
+	// with Char in runes.
+	Line, Char int
+}
+
+// Symbols returns all of the indexed symbols, along with the path of the
+// file that each is in, sorted by path and then by position.
+func (ix *Index) Symbols() (syms []Symbol, paths []string) {
+	for _, f := range ix.Files() {
+		for _, sy := range f.Symbols {
+			syms = append(syms, sy)
+			paths = append(paths, f.Path)
+		}
+	}
+	return
+}
+
+// goSymbols returns the top-level symbols declared in the given Go source.
+func goSymbols(path string, src []byte) []Symbol {
+	fset := gotoken.NewFileSet()
+	f, _ := parser.ParseFile(fset, path, src, parser.SkipObjectResolution) // partial results are still useful
+	if f == nil {
+		return nil
+	}
+	var syms []Symbol
+	add := func(id *ast.Ident, name string, kind token.Tokens) {
+		if id == nil || id.Name == "_" {
+			return
+		}
+		ps := fset.Position(id.Pos())
+		off := ps.Offset - (ps.Column - 1)
+		syms = append(syms, Symbol{Name: name, Kind: kind, Line: ps.Line - 1, Char: utf8.RuneCount(src[off:ps.Offset])})
+	}
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				add(d.Name, recvName(d.Recv.List[0].Type)+"."+d.Name.Name, token.NameMethod)
+			} else {
+				add(d.Name, d.Name.Name, token.NameFunction)
+			}
+		case *ast.GenDecl:
+			for _, sp := range d.Specs {
+				switch sp := sp.(type) {
+				case *ast.TypeSpec:
+					add(sp.Name, sp.Name.Name, token.NameType)
+				case *ast.ValueSpec:
+					kind := token.NameVarGlobal
+					if d.Tok == gotoken.CONST {
+						kind = token.NameConstant
+					}
+					for _, id := range sp.Names {
+						add(id, id.Name, kind)
+					}
+				}
+			}
+		}
+	}
+	return syms
+}
+
+// recvName returns the name of the type of a method receiver.
+func recvName(x ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.StarExpr:
+		return recvName(x.X)
+	case *ast.IndexExpr:
+		return recvName(x.X)
+	case *ast.IndexListExpr:
+		return recvName(x.X)
+	case *ast.Ident:
+		return x.Name
+	}
+	return ""
+}
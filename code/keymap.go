@@ -55,6 +55,14 @@ const (
 	KeyBuildProject
 	// run overall project
 	KeyRunProject
+	// insert a snippet
+	KeyInsertSnippet
+	// start or stop recording a keyboard macro
+	KeyMacroRecord
+	// replay the last keyboard macro
+	KeyMacroReplay
+	// replay the last keyboard macro on each selected line
+	KeyMacroApplyLines
 )
 
 // StandardKeyMaps are the standard extended maps for Code
@@ -99,6 +107,11 @@ var StandardKeyMaps = keymap.Maps{
 		"Control+X Control+M": KeyBuildProject,
 		"Control+X r":         KeyRunProject,
 		"Control+X Control+R": KeyRunProject,
+		"Control+X n":         KeyInsertSnippet,
+		"Control+X Control+N": KeyInsertSnippet,
+		"Control+X q":         KeyMacroRecord,
+		"Control+X e":         KeyMacroReplay,
+		"Control+X a":         KeyMacroApplyLines,
 	}},
 	{"MacEmacs", "Mac with emacs-style navigation -- emacs wins in conflicts", keymap.Map{
 		"Control+Tab":         KeyNextPanel,
@@ -140,6 +153,11 @@ var StandardKeyMaps = keymap.Maps{
 		"Control+X Control+M": KeyBuildProject,
 		"Control+X r":         KeyRunProject,
 		"Control+X Control+R": KeyRunProject,
+		"Control+X n":         KeyInsertSnippet,
+		"Control+X Control+N": KeyInsertSnippet,
+		"Control+X q":         KeyMacroRecord,
+		"Control+X e":         KeyMacroReplay,
+		"Control+X a":         KeyMacroApplyLines,
 	}},
 	{"LinuxEmacs", "Linux with emacs-style navigation -- emacs wins in conflicts", keymap.Map{
 		"Control+Tab":         KeyNextPanel,
@@ -181,6 +199,11 @@ var StandardKeyMaps = keymap.Maps{
 		"Control+X Control+M": KeyBuildProject,
 		"Control+X r":         KeyRunProject,
 		"Control+X Control+R": KeyRunProject,
+		"Control+X n":         KeyInsertSnippet,
+		"Control+X Control+N": KeyInsertSnippet,
+		"Control+X q":         KeyMacroRecord,
+		"Control+X e":         KeyMacroReplay,
+		"Control+X a":         KeyMacroApplyLines,
 	}},
 	{"LinuxStandard", "Standard Linux key map", keymap.Map{
 		"Control+Tab":         KeyNextPanel,
@@ -224,6 +247,11 @@ var StandardKeyMaps = keymap.Maps{
 		"Control+E Control+M": KeyBuildProject,
 		"Control+E r":         KeyRunProject,
 		"Control+E Control+R": KeyRunProject,
+		"Control+E n":         KeyInsertSnippet,
+		"Control+E Control+N": KeyInsertSnippet,
+		"Control+E q":         KeyMacroRecord,
+		"Control+E e":         KeyMacroReplay,
+		"Control+E a":         KeyMacroApplyLines,
 	}},
 	{"WindowsStandard", "Standard Windows key map", keymap.Map{
 		"Control+Tab":         KeyNextPanel,
@@ -267,6 +295,11 @@ var StandardKeyMaps = keymap.Maps{
 		"Control+E Control+M": KeyBuildProject,
 		"Control+E r":         KeyRunProject,
 		"Control+E Control+R": KeyRunProject,
+		"Control+E n":         KeyInsertSnippet,
+		"Control+E Control+N": KeyInsertSnippet,
+		"Control+E q":         KeyMacroRecord,
+		"Control+E e":         KeyMacroReplay,
+		"Control+E a":         KeyMacroApplyLines,
 	}},
 	{"ChromeStd", "Standard chrome-browser and linux-under-chrome bindings", keymap.Map{
 		"Control+Tab":         KeyNextPanel,
@@ -310,5 +343,10 @@ var StandardKeyMaps = keymap.Maps{
 		"Control+E Control+M": KeyBuildProject,
 		"Control+E r":         KeyRunProject,
 		"Control+E Control+R": KeyRunProject,
+		"Control+E n":         KeyInsertSnippet,
+		"Control+E Control+N": KeyInsertSnippet,
+		"Control+E q":         KeyMacroRecord,
+		"Control+E e":         KeyMacroReplay,
+		"Control+E a":         KeyMacroApplyLines,
 	}},
 }
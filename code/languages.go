@@ -8,6 +8,8 @@ import (
 	"log"
 	"path/filepath"
 
+	"cogentcore.org/cogent/code/cdebug/cdap"
+	"cogentcore.org/cogent/code/lsp"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/base/iox/tomlx"
 	"cogentcore.org/core/core"
@@ -19,6 +21,14 @@ type LanguageOptions struct {
 
 	// command(s) to run after a file of this type is saved
 	PostSaveCmds CmdNames
+
+	// language server to use for completion, lookup, etc;
+	// if none is specified, the built-in parse support is used
+	Server lsp.Server
+
+	// debug adapter to use for debugging, which takes precedence over
+	// the built-in debugger for the language (e.g., delve for Go)
+	Debugger cdap.Adapter
 }
 
 // Languages is a map of language options
@@ -109,5 +119,6 @@ var AvailableLanguagesChanged = false
 
 // StandardLanguages is the original compiled-in set of standard language options.
 var StandardLanguages = Languages{
-	fileinfo.Go: {CmdNames{"Go: Imports File"}},
+	fileinfo.Go:     {PostSaveCmds: CmdNames{"Go: Imports File"}, Server: lsp.Server{Cmd: "gopls"}},
+	fileinfo.Python: {Debugger: cdap.Adapter{Cmd: "python3", Args: []string{"-m", "debugpy.adapter"}, ID: "python"}},
 }
@@ -0,0 +1,160 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+)
+
+// LaunchConfig is a named configuration for starting the debugger,
+// saved in [ProjectSettings.Launches], so that different programs,
+// tools and tests can be debugged without editing the settings each time.
+type LaunchConfig struct {
+
+	// name of the configuration, shown in the chooser on the Debug button
+	Name string
+
+	// mode for running the debugger: Exec to debug the program,
+	// Test to debug tests, Attach to attach to a running process,
+	// or Core to inspect a core dump of the program
+	Mode cdebug.Modes
+
+	// path to the program (or package directory) to debug, or the test
+	// package directory for Test mode; if empty, it is the RunExec
+	Program core.Filename
+
+	// name of the test to run in Test mode; if non-empty,
+	// only tests matching this name will be run
+	TestName string
+
+	// process id to attach to, for Attach mode
+	PID uint64
+
+	// core dump file to open, for Core mode
+	CoreFile core.Filename
+
+	// arguments to pass to the program
+	Args []string
+
+	// environment variables for the program, as name=value
+	Env []string
+
+	// working directory for the program; if empty,
+	// it is the directory of the program
+	Dir core.Filename
+
+	// command to run before launching the debugger, such as a build;
+	// the debugger is only launched if it succeeds
+	CmdName CmdName
+}
+
+// Params returns the debugger parameters for the configuration,
+// based on the given parameters, which provide the variable
+// settings and any args for the debugger itself.
+func (lc *LaunchConfig) Params(base *cdebug.Params) cdebug.Params {
+	pars := *base
+	dargs, _ := base.SplitArgs()
+	pars.Args = slices.Clone(dargs)
+	if len(lc.Args) > 0 {
+		pars.Args = append(pars.Args, "--")
+		pars.Args = append(pars.Args, lc.Args...)
+	}
+	pars.Mode = lc.Mode
+	pars.TestName = lc.TestName
+	pars.PID = lc.PID
+	pars.CoreFile = string(lc.CoreFile)
+	pars.Env = lc.Env
+	pars.Dir = string(lc.Dir)
+	return pars
+}
+
+// Launch returns the launch configuration with the given name,
+// or nil if there is none.
+func (se *ProjectSettings) Launch(name string) *LaunchConfig {
+	for i := range se.Launches {
+		if se.Launches[i].Name == name {
+			return &se.Launches[i]
+		}
+	}
+	return nil
+}
+
+// DebugLaunch starts the debugger with the launch configuration of the
+// given name, after running its pre-launch command, if any, which prompts
+// for any values that it needs, as when it is run from the menu.
+func (cv *Code) DebugLaunch(name string) { //types:add
+	lc := cv.Settings.Launch(name)
+	if lc == nil {
+		core.MessageSnackbar(cv, fmt.Sprintf("No launch configuration named %q", name))
+		return
+	}
+	if lc.CmdName == "" {
+		cv.debugLaunch(lc)
+		return
+	}
+	cmd, ok := lc.CmdName.Command()
+	if !ok {
+		core.ErrorSnackbar(cv, fmt.Errorf("pre-launch command %v not found", lc.CmdName), "Could not debug "+lc.Name)
+		return
+	}
+	if err := cmd.applies(cv); err != nil {
+		core.ErrorSnackbar(cv, err, "Could not debug "+lc.Name)
+		return
+	}
+	cv.SetArgVarVals()
+	cbuf, _, _ := cv.RecycleCmdTab(cmd.Label())
+	cmd.run(cv, cbuf, func(ok bool) {
+		if !ok {
+			core.MessageSnackbar(cv, fmt.Sprintf("Not debugging %s: %s failed", lc.Name, cmd.Label()))
+			return
+		}
+		cv.debugLaunch(lc)
+	})
+}
+
+// debugLaunch starts the debugger with the given launch configuration.
+func (cv *Code) debugLaunch(lc *LaunchConfig) {
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+	exePath := string(lc.Program)
+	if exePath == "" {
+		exePath = string(cv.Settings.RunExec)
+	}
+	dv := core.RecycleTabWidget[DebugPanel](tv, "Debug "+lc.Name)
+	dv.Config(cv, cv.debugLanguage(), exePath)
+	lcc := *lc // settings can be edited while debugging
+	dv.Launch = &lcc
+	cv.FocusOnPanel(TabsIndex)
+	dv.Update()
+	dv.Start()
+	cv.CurDbg = dv
+}
+
+// launchChooser configures the given chooser to select the launch
+// configuration used by the Debug button, in [ProjectSettings.DebugLaunch].
+func (cv *Code) launchChooser(w *core.Chooser) {
+	w.SetTooltip("launch configuration for the Debug button; add them in the Launches of the project settings")
+	w.SetIcon(icons.Debug)
+	w.Updater(func() {
+		items := []core.ChooserItem{{Value: "", Text: "RunExec", Tooltip: "debug the RunExec of the project: " + filepath.Base(string(cv.Settings.RunExec))}}
+		for _, lc := range cv.Settings.Launches {
+			items = append(items, core.ChooserItem{Value: lc.Name, Tooltip: fmt.Sprintf("%s %s", lc.Mode, lc.Program)})
+		}
+		w.SetItems(items...)
+		w.SetCurrentValue(cv.Settings.DebugLaunch)
+	})
+	w.OnChange(func(e events.Event) {
+		cv.Settings.DebugLaunch = w.CurrentItem.Value.(string)
+	})
+}
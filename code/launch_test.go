@@ -0,0 +1,48 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/core/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLaunchConfig(t *testing.T) {
+	base := cdebug.DefaultParams
+	base.Args = []string{"--check-go-version=false", "--", "old"}
+	lc := &LaunchConfig{Name: "server", Mode: cdebug.Test, Program: "/proj/server", TestName: "TestServe",
+		Args: []string{"-port", "8080"}, Env: []string{"DEBUG=1"}, Dir: "/proj", CmdName: "Go: Build Dir"}
+	pars := lc.Params(&base)
+	assert.Equal(t, []string{"--check-go-version=false", "--", "-port", "8080"}, pars.Args)
+	assert.Equal(t, cdebug.Test, pars.Mode)
+	assert.Equal(t, "TestServe", pars.TestName)
+	assert.Equal(t, []string{"DEBUG=1"}, pars.Env)
+	assert.Equal(t, "/proj", pars.Dir)
+	assert.Equal(t, base.VarList, pars.VarList)
+	assert.Equal(t, []string{"--check-go-version=false", "--", "old"}, base.Args)
+
+	crash := &LaunchConfig{Name: "crash", Mode: cdebug.Core, Program: "/proj/server", CoreFile: "/tmp/core.1234"}
+	pars = crash.Params(&base)
+	assert.Equal(t, cdebug.Core, pars.Mode)
+	assert.Equal(t, "/tmp/core.1234", pars.CoreFile)
+	assert.Equal(t, []string{"--check-go-version=false"}, pars.Args)
+
+	se := &ProjectSettings{}
+	se.Launches = []LaunchConfig{*lc, {Name: "tool", Args: []string{"-v"}, Env: []string{}}}
+	se.DebugLaunch = "tool"
+	fname := core.Filename(filepath.Join(t.TempDir(), "proj.code"))
+	require.NoError(t, se.Save(fname))
+	ls := &ProjectSettings{}
+	require.NoError(t, ls.Open(fname))
+	assert.Equal(t, se.Launches, ls.Launches)
+	assert.Equal(t, "tool", ls.DebugLaunch)
+	assert.Equal(t, []string{"-v"}, ls.Launch("tool").Args)
+	assert.Nil(t, ls.Launch("none"))
+}
@@ -0,0 +1,678 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"cogentcore.org/cogent/code/lsp"
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/styles/states"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse"
+	"cogentcore.org/core/text/parse/complete"
+	"cogentcore.org/core/text/parse/parser"
+	"cogentcore.org/core/text/search"
+	"cogentcore.org/core/text/textpos"
+)
+
+// LangServers manages the language server clients for a project,
+// with one server per language and project root, started on demand
+// as configured in the [LanguageOptions] Server for each language.
+type LangServers struct {
+
+	// OnDiagnostics, if set, is called with the diagnostics published
+	// by the server for a file, from the server connection goroutine.
+	OnDiagnostics func(lang fileinfo.Known, fpath string, diags []lsp.Diagnostic)
+
+	// clients by language and root
+	clients map[langServerKey]*lsp.Client
+
+	// failed has the times at which servers failed to start,
+	// so that they are not retried until [LangServerRetryDelay] has passed.
+	failed map[langServerKey]time.Time
+
+	// changed has the files that have changed since their text was
+	// last sent to their server, by file path.
+	changed map[string]bool
+
+	// mu protects clients, failed and changed, which can be accessed
+	// from the completion goroutine
+	mu sync.Mutex
+}
+
+// langServerKey is the key for a language server: one per language
+// and project root.
+type langServerKey struct {
+	lang fileinfo.Known
+	root string
+}
+
+// LangServerChangeDelay is how long to wait after the last edit
+// before sending the updated text to the language server.
+var LangServerChangeDelay = 250 * time.Millisecond
+
+// LangServerRetryDelay is how long to wait after a language server
+// failed to start before trying to start it again.
+var LangServerRetryDelay = time.Minute
+
+// Client returns the language server client for given language and
+// project root, starting it if needed. The bool is true if the client
+// was newly started. Returns nil if no server is configured for the
+// language or it could not be started.
+func (ls *LangServers) Client(lang fileinfo.Known, root string) (*lsp.Client, bool) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	key := langServerKey{lang, root}
+	if cl := ls.clients[key]; cl != nil && cl.IsActive() {
+		return cl, false
+	}
+	if ft, has := ls.failed[key]; has && time.Since(ft) < LangServerRetryDelay {
+		return nil, false
+	}
+	lopt, has := AvailableLanguages[lang]
+	if !has || !lopt.Server.IsValid() {
+		return nil, false
+	}
+	if ls.clients == nil {
+		ls.clients = make(map[langServerKey]*lsp.Client)
+		ls.failed = make(map[langServerKey]time.Time)
+	}
+	cl, err := lsp.Start(&lopt.Server, root)
+	if err != nil {
+		log.Printf("code.LangServers: language server %q for %v could not be started: %v\n", lopt.Server.Cmd, lang, err)
+		delete(ls.clients, key)
+		ls.failed[key] = time.Now()
+		return nil, false
+	}
+	delete(ls.failed, key)
+	if ls.OnDiagnostics != nil {
+		cl.SetOnDiagnostics(func(fpath string, diags []lsp.Diagnostic) {
+			ls.OnDiagnostics(lang, fpath, diags)
+		})
+	}
+	ls.clients[key] = cl
+	return cl, true
+}
+
+// Running returns the running client for given language and project
+// root, or nil if it has not been started or is no longer running.
+func (ls *LangServers) Running(lang fileinfo.Known, root string) *lsp.Client {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	cl := ls.clients[langServerKey{lang, root}]
+	if cl == nil || !cl.IsActive() {
+		return nil
+	}
+	return cl
+}
+
+// Shutdown shuts down all the running language servers.
+func (ls *LangServers) Shutdown() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	for key, cl := range ls.clients {
+		if cl.IsActive() {
+			errors.Log(cl.Shutdown())
+		}
+		delete(ls.clients, key)
+	}
+}
+
+// setChanged records whether given file has changed since
+// its text was last sent to its server.
+func (ls *LangServers) setChanged(fpath string, changed bool) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.changed == nil {
+		ls.changed = make(map[string]bool)
+	}
+	if changed {
+		ls.changed[fpath] = true
+	} else {
+		delete(ls.changed, fpath)
+	}
+}
+
+// takeChanged returns whether given file has changed since its
+// text was last sent to its server, and records that it has not.
+func (ls *LangServers) takeChanged(fpath string) bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	chg := ls.changed[fpath]
+	delete(ls.changed, fpath)
+	return chg
+}
+
+// LangServerID returns the language server protocol language
+// identifier for given language.
+func LangServerID(lang fileinfo.Known) string {
+	return strings.ToLower(lang.String())
+}
+
+// langServerRoot returns the project root for the language
+// server of given file.
+func (cv *Code) langServerRoot(fpath string) string {
+	if root := cv.Settings.RootFor(fpath); root.Path != "" {
+		return string(root.Path)
+	}
+	return string(cv.ProjectRoot)
+}
+
+// LangServer returns the language server client for given language and
+// project root, starting it if needed, in which case all open files of
+// that language within the root are opened on the server. Returns nil
+// if there is no server for the language, in which case the parse-based
+// functions should be used.
+func (cv *Code) LangServer(lang fileinfo.Known, root string) *lsp.Client {
+	if lang == fileinfo.Unknown || cv.IsRemote() { // servers run locally
+		return nil
+	}
+	cl, isNew := cv.LangServers.Client(lang, root)
+	if isNew {
+		for _, ln := range cv.OpenFiles.Values {
+			fpath := ln.Filename()
+			if ln.FileInfo().Known == lang && cv.langServerRoot(fpath) == root {
+				cv.LangServers.setChanged(fpath, false)
+				errors.Log(cl.DidOpen(fpath, LangServerID(lang), ln.String()))
+			}
+		}
+	}
+	return cl
+}
+
+// langServerFor returns the language server client for given Lines,
+// or nil if there is none.
+func (cv *Code) langServerFor(ln *lines.Lines) *lsp.Client {
+	return cv.LangServer(ln.FileInfo().Known, cv.langServerRoot(ln.Filename()))
+}
+
+// langServerOpen opens given Lines on the language server for its
+// language, if there is one, and keeps the server updated with any
+// subsequent edits to it until it is closed.
+func (cv *Code) langServerOpen(ln *lines.Lines) {
+	lang := ln.FileInfo().Known
+	fpath := ln.Filename()
+	root := cv.langServerRoot(fpath)
+	cl := cv.LangServer(lang, root)
+	if cl == nil {
+		return
+	}
+	if !cl.IsOpen(fpath) { // could have been opened in LangServer
+		cv.LangServers.setChanged(fpath, false)
+		errors.Log(cl.DidOpen(fpath, LangServerID(lang), ln.String()))
+	}
+	var tm *time.Timer
+	vid := ln.NewView(80) // for the listeners below, deleted on close
+	ln.OnInput(vid, func(e events.Event) {
+		cv.LangServers.setChanged(fpath, true)
+		if tm != nil {
+			tm.Stop()
+		}
+		tm = time.AfterFunc(LangServerChangeDelay, func() {
+			cv.langServerSync(ln)
+		})
+	})
+	ln.OnChange(vid, func(e events.Event) {
+		cv.LangServers.setChanged(fpath, true)
+		cv.langServerSync(ln)
+	})
+	ln.OnClose(vid, func(e events.Event) {
+		if tm != nil {
+			tm.Stop()
+		}
+		cv.LangServers.setChanged(fpath, false)
+		if cl := cv.LangServers.Running(lang, root); cl != nil {
+			errors.Log(cl.DidClose(fpath))
+		}
+		ln.DeleteView(vid)
+	})
+}
+
+// langServerSync sends the current text of given Lines to its language
+// server if it has changed since it was last sent, returning the server
+// client, or nil if none.
+func (cv *Code) langServerSync(ln *lines.Lines) *lsp.Client {
+	if ln == nil {
+		return nil
+	}
+	cl := cv.langServerFor(ln)
+	if cl == nil {
+		return nil
+	}
+	if fpath := ln.Filename(); cv.LangServers.takeChanged(fpath) {
+		errors.Log(cl.DidChange(fpath, ln.String()))
+	}
+	return cl
+}
+
+// langServerSaved tells the language server that given Lines was saved.
+func (cv *Code) langServerSaved(ln *lines.Lines) {
+	if cl := cv.langServerSync(ln); cl != nil {
+		errors.Log(cl.DidSave(ln.Filename()))
+	}
+}
+
+// langServerDiagnostics sets the diagnostics published by the language
+// server for given file as its problems, replacing any previous ones.
+func (cv *Code) langServerDiagnostics(lang fileinfo.Known, fpath string, diags []lsp.Diagnostic) {
+	source := AvailableLanguages[lang].Server.Cmd
+	probs := make([]*Problem, len(diags))
+	for i, dg := range diags {
+		pr := &Problem{Filename: fpath, Line: dg.Range.Start.Line + 1, Col: dg.Range.Start.Character + 1, Message: dg.Message, Source: source}
+		switch dg.Severity {
+		case lsp.SeverityError:
+			pr.Severity = ProblemError
+		case lsp.SeverityWarning:
+			pr.Severity = ProblemWarning
+		default:
+			pr.Severity = ProblemInfo
+		}
+		probs[i] = pr
+	}
+	cv.SetFileProblems(source, fpath, probs)
+}
+
+// lspPosition returns the language server position for given
+// position in given Lines.
+func lspPosition(ln *lines.Lines, pos textpos.Pos) lsp.Position {
+	return lsp.Position{Line: pos.Line, Character: lsp.RuneToUTF16(ln.Line(pos.Line), pos.Char)}
+}
+
+// fileRunes returns the lines of given file as runes, using the
+// open Lines for it if there is one, and otherwise reading the file.
+func (cv *Code) fileRunes(fpath string) [][]rune {
+	var txt []byte
+	if ln := cv.GetOpenFile(fpath); ln != nil {
+		txt = ln.Text()
+	} else {
+		b, err := os.ReadFile(fpath)
+		if errors.Log(err) != nil {
+			return nil
+		}
+		txt = b
+	}
+	bls := bytes.Split(txt, []byte("\n"))
+	rls := make([][]rune, len(bls))
+	for i, bl := range bls {
+		rls[i] = []rune(string(bl))
+	}
+	return rls
+}
+
+// lspRegion returns the text region for given language server range
+// within given file lines, clamped to the lines.
+func lspRegion(rls [][]rune, rng lsp.Range) textpos.Region {
+	pos := func(p lsp.Position) textpos.Pos {
+		switch {
+		case len(rls) == 0:
+			return textpos.Pos{}
+		case p.Line < 0:
+			return textpos.Pos{}
+		case p.Line >= len(rls):
+			last := len(rls) - 1
+			return textpos.Pos{Line: last, Char: len(rls[last])}
+		}
+		return textpos.Pos{Line: p.Line, Char: lsp.UTF16ToRune(rls[p.Line], p.Character)}
+	}
+	return textpos.Region{Start: pos(rng.Start), End: pos(rng.End)}
+}
+
+// isIdentRune returns true if r can be part of an identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+////////  Completion
+
+// setCompleter sets the completion functions of given editor to the
+// Code versions, which use a language server when available.
+func (cv *Code) setCompleter(ed *TextEditor) {
+	if ed.Complete == nil {
+		return
+	}
+	ed.Complete.MatchFunc = cv.CompleteFun
+	ed.Complete.EditFunc = cv.CompleteEditFun
+	ed.Complete.LookupFunc = cv.LookupFun
+	if ed.snippetComplete != ed.Complete {
+		ed.snippetComplete = ed.Complete
+		ed.Complete.OnSelect(func(e events.Event) {
+			cv.completeSnippet(ed, e)
+		})
+	}
+}
+
+// CompleteFun is the completion system Match function, which uses the
+// language server for the file if there is one, and otherwise parse,
+// along with the snippets for the language.
+func (cv *Code) CompleteFun(data any, txt string, posLine, posChar int) (md complete.Matches) {
+	sfs := data.(*parse.FileStates)
+	if sfs == nil {
+		return md
+	}
+	ln := cv.GetOpenFile(sfs.Filename)
+	if cl := cv.langServerSync(ln); cl != nil {
+		md = cv.completeLangServer(cl, ln, txt, textpos.Pos{Line: posLine, Char: posChar})
+	} else if lp, err := parse.LanguageSupport.Properties(sfs.Known); err == nil && lp.Lang != nil {
+		parser.GUIActive = true // see LookupFun
+		md = lp.Lang.CompleteLine(sfs, txt, textpos.Pos{Line: posLine, Char: posChar})
+	}
+	completeSnippets(&md, sfs.Known, txt)
+	return md
+}
+
+// completeLangServer returns completions from given language server.
+func (cv *Code) completeLangServer(cl *lsp.Client, ln *lines.Lines, txt string, pos textpos.Pos) (md complete.Matches) {
+	items, err := cl.Completion(ln.Filename(), lspPosition(ln, pos))
+	if err != nil {
+		log.Printf("CompleteFun: %v\n", err)
+		return md
+	}
+	md.Seed = complete.SeedAfter(txt, func(r rune) bool {
+		return !isIdentRune(r)
+	})
+	comps := make(complete.Completions, len(items))
+	for i, it := range items {
+		comps[i] = complete.Completion{Text: it.Text(), Label: it.Label, Desc: it.Detail}
+		if it.Documentation != nil && it.Documentation.Value != "" {
+			comps[i].Desc += "\n" + it.Documentation.Value
+		}
+	}
+	md.Matches = complete.MatchSeedCompletion(comps, md.Seed)
+	return md
+}
+
+// CompleteEditFun is the completion system Edit function, which uses
+// parse for languages that support it, and otherwise just inserts
+// the completion text.
+func (cv *Code) CompleteEditFun(data any, txt string, cursorPos int, comp complete.Completion, seed string) (ed complete.Edit) {
+	sfs := data.(*parse.FileStates)
+	if sfs == nil {
+		return ed
+	}
+	lp, err := parse.LanguageSupport.Properties(sfs.Known)
+	if err != nil || lp.Lang == nil {
+		ed.NewText = comp.Text
+		return ed
+	}
+	return lp.Lang.CompleteEdit(sfs, txt, cursorPos, comp, seed)
+}
+
+// lookupLangServer returns the lookup result for the definition at
+// given position from given language server.
+func (cv *Code) lookupLangServer(cl *lsp.Client, ln *lines.Lines, pos textpos.Pos) (ld complete.Lookup) {
+	locs, err := cl.Definition(ln.Filename(), lspPosition(ln, pos))
+	if err != nil {
+		log.Printf("LookupFun: %v\n", err)
+		return ld
+	}
+	if len(locs) == 0 {
+		return ld
+	}
+	rng := locs[0].Range
+	ld.SetFile(lsp.URIToPath(locs[0].URI), rng.Start.Line, rng.End.Line)
+	return ld
+}
+
+////////  Hover
+
+// hoverState is the language server hover documentation for a
+// position in an editor, which is requested asynchronously.
+type hoverState struct {
+
+	// lines the hover is for
+	lines *lines.Lines
+
+	// pos is the text position of the hover
+	pos textpos.Pos
+
+	// text is the documentation, which is "" until it is received
+	text string
+}
+
+// HoverAtPos returns the language server hover documentation for the
+// given mouse position, if there is a language server for the file and
+// the documentation has been received. Otherwise, it requests it in the
+// background, and shows it in a tooltip once it is received, if the mouse
+// is still hovering.
+func (ed *TextEditor) HoverAtPos(pos image.Point) string {
+	if ed.Lines == nil || ed.Code == nil {
+		return ""
+	}
+	pt := ed.PointToRelPos(pos)
+	tpos := ed.PixelToCursor(pt)
+	if !ed.Lines.IsValidLine(tpos.Line) {
+		return ""
+	}
+	if hv := ed.hover; hv != nil && hv.lines == ed.Lines && hv.pos == tpos {
+		return hv.text
+	}
+	cv, ln := ed.Code, ed.Lines
+	hv := &hoverState{lines: ln, pos: tpos}
+	ed.hover = hv
+	go func() {
+		cl := cv.langServerSync(ln)
+		if cl == nil {
+			return
+		}
+		txt, err := cl.Hover(ln.Filename(), lspPosition(ln, tpos))
+		if err != nil {
+			log.Printf("HoverAtPos: %v\n", err)
+			return
+		}
+		txt = html.EscapeString(strings.TrimSpace(txt))
+		if txt == "" {
+			return
+		}
+		ed.AsyncLock()
+		defer ed.AsyncUnlock()
+		hv.text = txt
+		if ed.hover == hv && ed.StateIs(states.LongHovered) {
+			ed.HandleEvent(events.NewMouse(events.LongHoverStart, events.NoButton, pos, 0))
+		}
+	}()
+	return ""
+}
+
+////////  Navigation
+
+// cursorLangServer returns the active editor and its language server,
+// which is nil if there is no server for it.
+func (cv *Code) cursorLangServer() (*TextEditor, *lsp.Client) {
+	tv := cv.ActiveEditor()
+	if tv.Lines == nil {
+		return tv, nil
+	}
+	return tv, cv.langServerSync(tv.Lines)
+}
+
+// FindDefinition goes to the definition of the symbol at the cursor
+// in the active editor, using the language server if there is one,
+// and otherwise the parse-based Lookup.
+func (cv *Code) FindDefinition() { //types:add
+	tv, cl := cv.cursorLangServer()
+	if tv.Lines == nil {
+		return
+	}
+	if cl == nil {
+		tv.Lookup()
+		return
+	}
+	locs, err := cl.Definition(tv.Lines.Filename(), lspPosition(tv.Lines, tv.CursorPos))
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Find definition")
+		return
+	}
+	if len(locs) == 0 {
+		core.MessageSnackbar(cv, "No definition found")
+		return
+	}
+	fpath := lsp.URIToPath(locs[0].URI)
+	tv.Lines.PosHistorySave(tv.CursorPos)
+	cv.OpenFileAtRegion(fpath, lspRegion(cv.fileRunes(fpath), locs[0].Range))
+}
+
+// FindReferences shows all the references to the symbol at the cursor
+// in the active editor in the Find panel, using the language server if
+// there is one, and otherwise finding the word across the project.
+func (cv *Code) FindReferences() { //types:add
+	tv, cl := cv.cursorLangServer()
+	if tv.Lines == nil {
+		return
+	}
+	wr := tv.Lines.WordAt(tv.CursorPos)
+	var word string
+	if tbe := tv.Lines.Region(wr.Start, wr.End); tbe != nil {
+		word = string(tbe.ToBytes())
+	}
+	if cl == nil {
+		cv.Find(word, "", false, false, All, nil)
+		return
+	}
+	locs, err := cl.References(tv.Lines.Filename(), lspPosition(tv.Lines, tv.CursorPos), true)
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Find references")
+		return
+	}
+	cv.Settings.Find.Find = word
+	cv.Settings.Find.Replace = ""
+	fv := cv.recycleFindPanel()
+	fv.ShowResults(cv.locationResults(locs))
+	cv.FocusOnPanel(TabsIndex)
+}
+
+// locationResults returns the given locations as search results,
+// grouped by file, for display in the Find panel.
+func (cv *Code) locationResults(locs []lsp.Location) []search.Results {
+	slices.SortStableFunc(locs, func(a, b lsp.Location) int {
+		if c := strings.Compare(a.URI, b.URI); c != 0 {
+			return c
+		}
+		return a.Range.Start.Line - b.Range.Start.Line
+	})
+	var res []search.Results
+	var rls [][]rune
+	for _, loc := range locs {
+		fpath := lsp.URIToPath(loc.URI)
+		if len(res) == 0 || res[len(res)-1].Filepath != fpath {
+			rls = cv.fileRunes(fpath)
+			res = append(res, search.Results{Filepath: fpath})
+		}
+		reg := lspRegion(rls, loc.Range)
+		if reg.Start.Line >= len(rls) {
+			continue
+		}
+		rs := &res[len(res)-1]
+		ech := reg.End.Char
+		if reg.End.Line != reg.Start.Line {
+			ech = len(rls[reg.Start.Line])
+		}
+		rs.Matches = append(rs.Matches, textpos.NewMatch(rls[reg.Start.Line], reg.Start.Char, ech, reg.Start.Line))
+		rs.Count++
+	}
+	return res
+}
+
+// RenameSymbol renames the symbol at the cursor in the active editor,
+// and all references to it, to the given new name, using the language
+// server. Files are opened as needed to apply the changes, and each can
+// be undone separately. Without a language server, it falls back on
+// renaming the whole word at the cursor within the active file.
+func (cv *Code) RenameSymbol(newName string) { //types:add
+	tv, cl := cv.cursorLangServer()
+	if tv.Lines == nil || newName == "" {
+		return
+	}
+	if cl == nil {
+		cv.renameWord(tv, newName)
+		return
+	}
+	we, err := cl.Rename(tv.Lines.Filename(), lspPosition(tv.Lines, tv.CursorPos), newName)
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Rename symbol")
+		return
+	}
+	nedits := 0
+	chgs := we.AllChanges()
+	for uri, eds := range chgs {
+		ln, _ := cv.RecycleFile(lsp.URIToPath(uri))
+		if ln == nil {
+			continue
+		}
+		nedits += applyTextEdits(ln, eds)
+	}
+	cv.SetStatus(fmt.Sprintf("Renamed to %s: %d edits in %d files", newName, nedits, len(chgs)))
+}
+
+// renameWord renames all whole-word occurrences of the word at the
+// cursor in given editor to given new name, as one undo group.
+func (cv *Code) renameWord(tv *TextEditor, newName string) {
+	wr := tv.Lines.WordAt(tv.CursorPos)
+	tbe := tv.Lines.Region(wr.Start, wr.End)
+	if tbe == nil {
+		return
+	}
+	word := string(tbe.ToBytes())
+	_, matches := tv.Lines.SearchRegexp(regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`))
+	regs := make([]textpos.Region, len(matches))
+	for i, m := range matches {
+		regs[i] = m.Region
+	}
+	n := replaceRegions(tv.Lines, regs, func(i int) string { return newName })
+	cv.SetStatus(fmt.Sprintf("Renamed %s to %s: %d edits", word, newName, n))
+}
+
+// applyTextEdits applies given language server edits to given Lines,
+// as one undo group, returning the number of edits applied.
+func applyTextEdits(ln *lines.Lines, eds []lsp.TextEdit) int {
+	rls := make([][]rune, ln.NumLines())
+	for i := range rls {
+		rls[i] = ln.Line(i)
+	}
+	regs := make([]textpos.Region, len(eds))
+	for i, ed := range eds {
+		regs[i] = lspRegion(rls, ed.Range)
+	}
+	return replaceRegions(ln, regs, func(i int) string { return eds[i].NewText })
+}
+
+// replaceRegions replaces given regions of given Lines with the text
+// for each region, as one undo group, returning the number replaced.
+func replaceRegions(ln *lines.Lines, regs []textpos.Region, text func(i int) string) int {
+	// apply from the end so earlier regions remain valid
+	idxs := make([]int, len(regs))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	slices.SortFunc(idxs, func(a, b int) int {
+		switch {
+		case regs[b].Start.IsLess(regs[a].Start):
+			return -1
+		case regs[a].Start.IsLess(regs[b].Start):
+			return 1
+		}
+		return 0
+	})
+	ln.NewUndoGroup()
+	for _, i := range idxs {
+		reg := regs[i]
+		ln.ReplaceText(reg.Start, reg.End, reg.Start, text(i), lines.ReplaceNoMatchCase)
+	}
+	return len(regs)
+}
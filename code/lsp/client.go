@@ -0,0 +1,358 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lsp provides a minimal Language Server Protocol client,
+// used by Code for completion, hover, definition, references and
+// rename, for languages that have a language server configured.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// Server specifies the command for running a language server
+// that communicates over stdin / stdout.
+type Server struct {
+
+	// Cmd is the language server executable, e.g., gopls.
+	// If empty, no language server is used.
+	Cmd string
+
+	// Args are any additional arguments to pass to the server.
+	Args []string
+}
+
+// IsValid returns true if a server command has been specified.
+func (sv *Server) IsValid() bool {
+	return sv.Cmd != ""
+}
+
+// DefaultTimeout is the default time to wait for responses from the server.
+var DefaultTimeout = 5 * time.Second
+
+// Client is a client connection to a language server for one
+// project root. Files must be opened with [Client.DidOpen] and kept
+// in sync with [Client.DidChange] for results to be accurate.
+type Client struct {
+
+	// RootPath is the project root path given to the server.
+	RootPath string
+
+	// Timeout is how long to wait for responses; defaults to [DefaultTimeout].
+	Timeout time.Duration
+
+	// Capabilities are the capabilities reported by the server.
+	Capabilities map[string]json.RawMessage
+
+	conn *Conn
+	cmd  *exec.Cmd
+
+	// onDiagnostics is set by [Client.SetOnDiagnostics]
+	onDiagnostics func(fpath string, diags []Diagnostic)
+
+	// mu protects versions and onDiagnostics
+	mu sync.Mutex
+
+	// versions has the current version of each open file, by file path
+	versions map[string]int
+}
+
+// Start starts the given language server command for given root path,
+// and returns an initialized client for it.
+func Start(sv *Server, rootPath string) (*Client, error) {
+	if !sv.IsValid() {
+		return nil, errors.New("lsp: no server command specified")
+	}
+	cmd := exec.Command(sv.Cmd, sv.Args...)
+	cmd.Dir = rootPath
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	cl, err := NewClient(&pipeRWC{out, in}, rootPath)
+	if err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return nil, err
+	}
+	cl.cmd = cmd
+	return cl, nil
+}
+
+// NewClient returns a new client communicating with a server over
+// given stream, which has been initialized for given root path.
+func NewClient(rwc io.ReadWriteCloser, rootPath string) (*Client, error) {
+	cl := &Client{RootPath: rootPath, Timeout: DefaultTimeout, versions: make(map[string]int)}
+	cl.conn = NewConn(rwc, cl.handle)
+	if err := cl.initialize(); err != nil {
+		cl.conn.Close()
+		return nil, err
+	}
+	return cl, nil
+}
+
+// initialize does the initialize handshake with the server.
+func (cl *Client) initialize() error {
+	pars := &InitializeParams{
+		ProcessID: os.Getpid(),
+		RootURI:   FileURI(cl.RootPath),
+		Capabilities: map[string]any{
+			"textDocument": map[string]any{
+				"synchronization": map[string]any{"didSave": true},
+				"completion":      map[string]any{"completionItem": map[string]any{"snippetSupport": false}},
+				"hover":           map[string]any{"contentFormat": []string{"plaintext", "markdown"}},
+			},
+		},
+	}
+	res := &InitializeResult{}
+	// servers can be slow to start, so give it more time
+	if err := cl.conn.Call("initialize", pars, res, 6*cl.Timeout); err != nil {
+		return err
+	}
+	cl.Capabilities = res.Capabilities
+	return cl.conn.Notify("initialized", struct{}{})
+}
+
+// handle handles requests and notifications from the server.
+func (cl *Client) handle(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "textDocument/publishDiagnostics":
+		cl.mu.Lock()
+		fun := cl.onDiagnostics
+		cl.mu.Unlock()
+		if fun == nil {
+			return nil, nil
+		}
+		pd := &PublishDiagnosticsParams{}
+		if err := json.Unmarshal(params, pd); err != nil {
+			return nil, err
+		}
+		fun(URIToPath(pd.URI), pd.Diagnostics)
+	case "window/showMessage", "window/logMessage":
+	case "workspace/configuration":
+		// one null config per requested item
+		var pars struct {
+			Items []json.RawMessage `json:"items"`
+		}
+		json.Unmarshal(params, &pars)
+		return make([]any, len(pars.Items)), nil
+	case "window/workDoneProgress/create", "client/registerCapability", "client/unregisterCapability":
+	default:
+		return nil, &Error{Code: MethodNotFound, Message: method}
+	}
+	return nil, nil
+}
+
+// SetOnDiagnostics sets the function called with the diagnostics
+// published by the server for a file. It is called from the connection
+// read goroutine and must not block.
+func (cl *Client) SetOnDiagnostics(fun func(fpath string, diags []Diagnostic)) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	cl.onDiagnostics = fun
+}
+
+// IsActive returns true if the connection to the server is still open.
+func (cl *Client) IsActive() bool {
+	select {
+	case <-cl.conn.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// IsOpen returns true if the given file has been opened with [Client.DidOpen].
+func (cl *Client) IsOpen(fpath string) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	_, ok := cl.versions[fpath]
+	return ok
+}
+
+// DidOpen tells the server that given file is open, with given
+// language id (e.g., "go") and current text.
+func (cl *Client) DidOpen(fpath, langID, text string) error {
+	cl.mu.Lock()
+	cl.versions[fpath] = 1
+	cl.mu.Unlock()
+	return cl.conn.Notify("textDocument/didOpen", &DidOpenTextDocumentParams{
+		TextDocument: TextDocumentItem{URI: FileURI(fpath), LanguageID: langID, Version: 1, Text: text}})
+}
+
+// DidChange sends the full new text of given open file to the server.
+func (cl *Client) DidChange(fpath, text string) error {
+	cl.mu.Lock()
+	vers, ok := cl.versions[fpath]
+	if !ok {
+		cl.mu.Unlock()
+		return nil
+	}
+	vers++
+	cl.versions[fpath] = vers
+	cl.mu.Unlock()
+	return cl.conn.Notify("textDocument/didChange", &DidChangeTextDocumentParams{
+		TextDocument:   VersionedTextDocumentIdentifier{URI: FileURI(fpath), Version: vers},
+		ContentChanges: []TextDocumentContentChangeEvent{{Text: text}}})
+}
+
+// DidSave tells the server that given file was saved.
+func (cl *Client) DidSave(fpath string) error {
+	if !cl.IsOpen(fpath) {
+		return nil
+	}
+	return cl.conn.Notify("textDocument/didSave", &DidSaveTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{URI: FileURI(fpath)}})
+}
+
+// DidClose tells the server that given file is no longer open.
+func (cl *Client) DidClose(fpath string) error {
+	cl.mu.Lock()
+	_, ok := cl.versions[fpath]
+	delete(cl.versions, fpath)
+	cl.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return cl.conn.Notify("textDocument/didClose", &DidCloseTextDocumentParams{
+		TextDocument: TextDocumentIdentifier{URI: FileURI(fpath)}})
+}
+
+func positionParams(fpath string, pos Position) TextDocumentPositionParams {
+	return TextDocumentPositionParams{TextDocument: TextDocumentIdentifier{URI: FileURI(fpath)}, Position: pos}
+}
+
+// Completion returns completions at given position in given file.
+func (cl *Client) Completion(fpath string, pos Position) ([]CompletionItem, error) {
+	var raw json.RawMessage
+	if err := cl.conn.Call("textDocument/completion", positionParams(fpath, pos), &raw, cl.Timeout); err != nil {
+		return nil, err
+	}
+	var items []CompletionItem
+	if json.Unmarshal(raw, &items) == nil {
+		return items, nil
+	}
+	cls := &completionList{}
+	if err := json.Unmarshal(raw, cls); err != nil {
+		return nil, err
+	}
+	return cls.Items, nil
+}
+
+// Hover returns the hover documentation at given position in given file,
+// which is empty if there is none.
+func (cl *Client) Hover(fpath string, pos Position) (string, error) {
+	hv := &Hover{}
+	if err := cl.conn.Call("textDocument/hover", positionParams(fpath, pos), &hv, cl.Timeout); err != nil {
+		return "", err
+	}
+	if hv == nil {
+		return "", nil
+	}
+	return hv.Contents.Value, nil
+}
+
+// Definition returns the location(s) of the definition of the symbol
+// at given position in given file.
+func (cl *Client) Definition(fpath string, pos Position) ([]Location, error) {
+	var raw json.RawMessage
+	if err := cl.conn.Call("textDocument/definition", positionParams(fpath, pos), &raw, cl.Timeout); err != nil {
+		return nil, err
+	}
+	return decodeLocations(raw)
+}
+
+// References returns the locations of all references to the symbol
+// at given position in given file, optionally including its declaration.
+func (cl *Client) References(fpath string, pos Position, includeDecl bool) ([]Location, error) {
+	pars := &ReferenceParams{TextDocumentPositionParams: positionParams(fpath, pos)}
+	pars.Context.IncludeDeclaration = includeDecl
+	var locs []Location
+	err := cl.conn.Call("textDocument/references", pars, &locs, cl.Timeout)
+	return locs, err
+}
+
+// Rename returns the edits needed to rename the symbol at given
+// position in given file to newName. The edits are not applied.
+func (cl *Client) Rename(fpath string, pos Position, newName string) (*WorkspaceEdit, error) {
+	pars := &RenameParams{TextDocumentPositionParams: positionParams(fpath, pos), NewName: newName}
+	we := &WorkspaceEdit{}
+	if err := cl.conn.Call("textDocument/rename", pars, we, cl.Timeout); err != nil {
+		return nil, err
+	}
+	return we, nil
+}
+
+// Shutdown asks the server to shut down and exit, and closes the connection.
+func (cl *Client) Shutdown() error {
+	err := cl.conn.Call("shutdown", nil, nil, cl.Timeout)
+	if err == nil {
+		cl.conn.Notify("exit", nil)
+	}
+	cl.conn.Close()
+	if cl.cmd != nil {
+		done := make(chan struct{})
+		go func() {
+			cl.cmd.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(cl.Timeout):
+			log.Printf("lsp: server %s did not exit; killing it\n", cl.cmd.Path)
+			cl.cmd.Process.Kill()
+		}
+	}
+	return err
+}
+
+// decodeLocations decodes a definition result, which can be a single
+// Location, a list of Locations, or a list of LocationLinks.
+func decodeLocations(raw json.RawMessage) ([]Location, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] != '[' {
+		loc := Location{}
+		err := json.Unmarshal(raw, &loc)
+		return []Location{loc}, err
+	}
+	var links []locationLink
+	if err := json.Unmarshal(raw, &links); err == nil && len(links) > 0 && links[0].TargetURI != "" {
+		locs := make([]Location, len(links))
+		for i, ln := range links {
+			locs[i] = Location{URI: ln.TargetURI, Range: ln.TargetSelectionRange}
+		}
+		return locs, nil
+	}
+	var locs []Location
+	err := json.Unmarshal(raw, &locs)
+	return locs, err
+}
+
+// pipeRWC combines the stdout and stdin pipes of a server process.
+type pipeRWC struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (p *pipeRWC) Close() error {
+	werr := p.WriteCloser.Close()
+	rerr := p.ReadCloser.Close()
+	return errors.Join(werr, rerr)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServer is an in-process language server that records the
+// document text it is sent and returns canned results.
+type fakeServer struct {
+	mu    sync.Mutex
+	texts map[string]string
+	conn  *Conn
+}
+
+func (fs *fakeServer) handle(method string, params json.RawMessage) (any, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	switch method {
+	case "initialize":
+		return map[string]any{"capabilities": map[string]any{"hoverProvider": true}}, nil
+	case "textDocument/didOpen":
+		p := &DidOpenTextDocumentParams{}
+		json.Unmarshal(params, p)
+		fs.texts[p.TextDocument.URI] = p.TextDocument.Text
+		fs.conn.Notify("textDocument/publishDiagnostics", &PublishDiagnosticsParams{URI: p.TextDocument.URI,
+			Diagnostics: []Diagnostic{{Severity: SeverityWarning, Message: "opened"}}})
+	case "textDocument/didChange":
+		p := &DidChangeTextDocumentParams{}
+		json.Unmarshal(params, p)
+		fs.texts[p.TextDocument.URI] = p.ContentChanges[0].Text
+	case "textDocument/completion":
+		return completionList{Items: []CompletionItem{{Label: "Println", Kind: FunctionCompletion}, {Label: "Printf", InsertText: "Printf"}}}, nil
+	case "textDocument/hover":
+		p := &TextDocumentPositionParams{}
+		json.Unmarshal(params, p)
+		if p.Position.Line > 0 {
+			return nil, nil
+		}
+		return map[string]any{"contents": map[string]any{"kind": "markdown", "value": "func main()"}}, nil
+	case "textDocument/definition":
+		p := &TextDocumentPositionParams{}
+		json.Unmarshal(params, p)
+		return []locationLink{{TargetURI: p.TextDocument.URI, TargetSelectionRange: Range{Start: Position{2, 5}, End: Position{2, 9}}}}, nil
+	case "textDocument/references":
+		p := &ReferenceParams{}
+		json.Unmarshal(params, p)
+		locs := []Location{{URI: p.TextDocument.URI, Range: Range{Start: Position{4, 1}, End: Position{4, 5}}}}
+		if p.Context.IncludeDeclaration {
+			locs = append(locs, Location{URI: p.TextDocument.URI, Range: Range{Start: Position{2, 5}, End: Position{2, 9}}})
+		}
+		return locs, nil
+	case "textDocument/rename":
+		p := &RenameParams{}
+		json.Unmarshal(params, p)
+		return WorkspaceEdit{DocumentChanges: []TextDocumentEdit{{TextDocument: VersionedTextDocumentIdentifier{URI: p.TextDocument.URI},
+			Edits: []TextEdit{{Range: Range{Start: Position{2, 5}, End: Position{2, 9}}, NewText: p.NewName}}}}}, nil
+	case "shutdown":
+		return nil, nil
+	}
+	return nil, nil
+}
+
+func newFakeClient(t *testing.T) (*Client, *fakeServer) {
+	cc, sc := net.Pipe()
+	fs := &fakeServer{texts: make(map[string]string)}
+	fs.conn = NewConn(sc, fs.handle)
+	cl, err := NewClient(cc, t.TempDir())
+	assert.NoError(t, err)
+	t.Cleanup(func() { fs.conn.Close() })
+	return cl, fs
+}
+
+func TestClient(t *testing.T) {
+	cl, fs := newFakeClient(t)
+	assert.Contains(t, cl.Capabilities, "hoverProvider")
+
+	diags := make(chan []Diagnostic, 1)
+	cl.SetOnDiagnostics(func(fpath string, ds []Diagnostic) {
+		diags <- ds
+	})
+
+	fpath := filepath.Join(cl.RootPath, "main.go")
+	uri := FileURI(fpath)
+	assert.NoError(t, cl.DidOpen(fpath, "go", "package main\n"))
+	assert.True(t, cl.IsOpen(fpath))
+	ds := <-diags
+	assert.Equal(t, "opened", ds[0].Message)
+
+	assert.NoError(t, cl.DidChange(fpath, "package main\n\nfunc main() {}\n"))
+
+	items, err := cl.Completion(fpath, Position{2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, "Println", items[0].Text())
+
+	// completion is a request, so the prior change must have been handled
+	fs.mu.Lock()
+	assert.Equal(t, "package main\n\nfunc main() {}\n", fs.texts[uri])
+	fs.mu.Unlock()
+
+	hv, err := cl.Hover(fpath, Position{0, 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "func main()", hv)
+	hv, err = cl.Hover(fpath, Position{1, 0})
+	assert.NoError(t, err)
+	assert.Equal(t, "", hv)
+
+	locs, err := cl.Definition(fpath, Position{4, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []Location{{URI: uri, Range: Range{Position{2, 5}, Position{2, 9}}}}, locs)
+	assert.Equal(t, fpath, URIToPath(locs[0].URI))
+
+	locs, err = cl.References(fpath, Position{2, 6}, true)
+	assert.NoError(t, err)
+	assert.Len(t, locs, 2)
+
+	we, err := cl.Rename(fpath, Position{2, 6}, "run")
+	assert.NoError(t, err)
+	chg := we.AllChanges()
+	assert.Equal(t, "run", chg[uri][0].NewText)
+
+	assert.NoError(t, cl.DidClose(fpath))
+	assert.False(t, cl.IsOpen(fpath))
+	assert.NoError(t, cl.Shutdown())
+	assert.False(t, cl.IsActive())
+}
+
+func TestUTF16(t *testing.T) {
+	line := []rune("a😀b")
+	assert.Equal(t, 3, RuneToUTF16(line, 2))
+	assert.Equal(t, 2, UTF16ToRune(line, 3))
+	assert.Equal(t, 3, UTF16ToRune(line, 10))
+}
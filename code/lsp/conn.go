@@ -0,0 +1,160 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"cogentcore.org/cogent/code/baseproto"
+)
+
+// ErrClosed is returned for calls on a closed connection.
+var ErrClosed = errors.New("lsp: connection closed")
+
+// Error is a JSON-RPC error returned in response to a request.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("lsp: error %d: %s", e.Code, e.Message)
+}
+
+// MethodNotFound is the JSON-RPC error code for an unsupported method.
+const MethodNotFound = -32601
+
+// message is a JSON-RPC 2.0 request, notification, or response.
+type message struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *Error           `json:"error,omitempty"`
+}
+
+// Handler handles incoming requests and notifications on a [Conn].
+// For requests, the returned result or error is sent back as the response;
+// for notifications, the return values are ignored.
+type Handler func(method string, params json.RawMessage) (any, error)
+
+// Conn is a JSON-RPC 2.0 connection using the LSP base protocol,
+// where each message has a Content-Length header. It is symmetric,
+// and is used for both the client and (in tests) the server side.
+type Conn struct {
+	conn    *baseproto.Conn[message]
+	handler Handler
+}
+
+// NewConn returns a new connection reading and writing given stream,
+// with given handler for incoming messages (can be nil).
+// It starts a goroutine reading incoming messages.
+func NewConn(rwc io.ReadWriteCloser, handler Handler) *Conn {
+	c := &Conn{handler: handler}
+	c.conn = baseproto.NewConn(rwc, c.dispatch)
+	return c
+}
+
+// Call sends a request and waits up to timeout for the response,
+// which is decoded into result (if non-nil). A zero timeout waits forever.
+func (c *Conn) Call(method string, params, result any, timeout time.Duration) error {
+	resp, err := c.conn.Call(func(id int64) (*message, error) {
+		rid := json.RawMessage(strconv.FormatInt(id, 10))
+		return newMessage(&message{ID: &rid, Method: method}, params)
+	}, timeout)
+	switch {
+	case errors.Is(err, baseproto.ErrClosed):
+		return ErrClosed
+	case errors.Is(err, baseproto.ErrTimeout):
+		return fmt.Errorf("lsp: %s timed out after %v", method, timeout)
+	case err != nil:
+		return err
+	case resp.Error != nil:
+		return resp.Error
+	case result == nil || len(resp.Result) == 0:
+		return nil
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// Notify sends a notification, which has no response.
+func (c *Conn) Notify(method string, params any) error {
+	msg, err := newMessage(&message{Method: method}, params)
+	if err != nil {
+		return err
+	}
+	return c.conn.Write(msg)
+}
+
+// Close closes the connection and the underlying stream.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Done returns a channel that is closed when the connection is closed.
+func (c *Conn) Done() <-chan struct{} {
+	return c.conn.Done()
+}
+
+// newMessage returns given message with the version and params set.
+func newMessage(msg *message, params any) (*message, error) {
+	msg.JSONRPC = "2.0"
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = b
+	}
+	return msg, nil
+}
+
+// dispatch handles an incoming message, returning the id of a response.
+func (c *Conn) dispatch(msg *message) (int64, bool) {
+	switch {
+	case msg.Method != "" && msg.ID != nil:
+		go c.reply(msg)
+	case msg.Method != "":
+		if c.handler != nil {
+			c.handler(msg.Method, msg.Params)
+		}
+	case msg.ID != nil:
+		id, err := strconv.ParseInt(string(*msg.ID), 10, 64)
+		return id, err == nil
+	}
+	return 0, false
+}
+
+// reply handles an incoming request and sends the response.
+func (c *Conn) reply(req *message) {
+	resp := &message{JSONRPC: "2.0", ID: req.ID}
+	if c.handler == nil {
+		resp.Error = &Error{Code: MethodNotFound, Message: req.Method}
+		c.conn.Write(resp)
+		return
+	}
+	res, err := c.handler(req.Method, req.Params)
+	if err != nil {
+		var rerr *Error
+		if !errors.As(err, &rerr) {
+			rerr = &Error{Code: -32603, Message: err.Error()}
+		}
+		resp.Error = rerr
+	} else {
+		b, err := json.Marshal(res)
+		if err != nil {
+			resp.Error = &Error{Code: -32603, Message: err.Error()}
+		} else {
+			resp.Result = b
+		}
+	}
+	c.conn.Write(resp)
+}
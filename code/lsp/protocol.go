@@ -0,0 +1,328 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lsp
+
+import (
+	"encoding/json"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf16"
+)
+
+// This file contains the subset of the Language Server Protocol types
+// that are used by the [Client]. Field names follow the specification at
+// https://microsoft.github.io/language-server-protocol/specifications/specification-current
+
+// Position is a zero-based line and character offset in a text document.
+// Character is measured in UTF-16 code units, per the specification;
+// use [RuneToUTF16] and [UTF16ToRune] to convert.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a range in a text document, with an exclusive End.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Location is a range inside a document with given URI.
+type Location struct {
+	URI   string `json:"uri"`
+	Range Range  `json:"range"`
+}
+
+// locationLink is the alternative form of a definition result.
+type locationLink struct {
+	TargetURI            string `json:"targetUri"`
+	TargetRange          Range  `json:"targetRange"`
+	TargetSelectionRange Range  `json:"targetSelectionRange"`
+}
+
+// TextDocumentIdentifier identifies a text document by URI.
+type TextDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+// VersionedTextDocumentIdentifier identifies a specific version of a text document.
+type VersionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+// TextDocumentItem is used to transfer a text document to the server.
+type TextDocumentItem struct {
+	URI        string `json:"uri"`
+	LanguageID string `json:"languageId"`
+	Version    int    `json:"version"`
+	Text       string `json:"text"`
+}
+
+// TextDocumentPositionParams is a position within a text document.
+type TextDocumentPositionParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+	Position     Position               `json:"position"`
+}
+
+// TextDocumentContentChangeEvent is a change to a text document.
+// If Range is nil, Text is the full new content of the document.
+type TextDocumentContentChangeEvent struct {
+	Range *Range `json:"range,omitempty"`
+	Text  string `json:"text"`
+}
+
+// DidOpenTextDocumentParams are the params for textDocument/didOpen.
+type DidOpenTextDocumentParams struct {
+	TextDocument TextDocumentItem `json:"textDocument"`
+}
+
+// DidChangeTextDocumentParams are the params for textDocument/didChange.
+type DidChangeTextDocumentParams struct {
+	TextDocument   VersionedTextDocumentIdentifier  `json:"textDocument"`
+	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
+}
+
+// DidSaveTextDocumentParams are the params for textDocument/didSave.
+type DidSaveTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// DidCloseTextDocumentParams are the params for textDocument/didClose.
+type DidCloseTextDocumentParams struct {
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
+// ReferenceParams are the params for textDocument/references.
+type ReferenceParams struct {
+	TextDocumentPositionParams
+	Context struct {
+		IncludeDeclaration bool `json:"includeDeclaration"`
+	} `json:"context"`
+}
+
+// RenameParams are the params for textDocument/rename.
+type RenameParams struct {
+	TextDocumentPositionParams
+	NewName string `json:"newName"`
+}
+
+// TextEdit is a textual edit applicable to a text document.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// TextDocumentEdit is a set of edits to a specific version of a document.
+type TextDocumentEdit struct {
+	TextDocument VersionedTextDocumentIdentifier `json:"textDocument"`
+	Edits        []TextEdit                      `json:"edits"`
+}
+
+// WorkspaceEdit represents changes to many documents, e.g., from a rename.
+// Servers may use either Changes or DocumentChanges; use [WorkspaceEdit.AllChanges]
+// to get both in one map.
+type WorkspaceEdit struct {
+	Changes         map[string][]TextEdit `json:"changes,omitempty"`
+	DocumentChanges []TextDocumentEdit    `json:"documentChanges,omitempty"`
+}
+
+// AllChanges returns all the edits in the workspace edit, keyed by URI.
+func (we *WorkspaceEdit) AllChanges() map[string][]TextEdit {
+	all := make(map[string][]TextEdit)
+	for uri, eds := range we.Changes {
+		all[uri] = append(all[uri], eds...)
+	}
+	for _, dc := range we.DocumentChanges {
+		all[dc.TextDocument.URI] = append(all[dc.TextDocument.URI], dc.Edits...)
+	}
+	return all
+}
+
+// MarkupContent is a string value with a kind (plaintext or markdown).
+// It also accepts the deprecated MarkedString forms when decoding.
+type MarkupContent struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+func (mc *MarkupContent) UnmarshalJSON(b []byte) error {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		mc.Kind = "plaintext"
+		mc.Value = s
+		return nil
+	}
+	var ms []json.RawMessage
+	if json.Unmarshal(b, &ms) == nil {
+		var vals []string
+		for _, m := range ms {
+			var sub MarkupContent
+			if err := sub.UnmarshalJSON(m); err != nil {
+				return err
+			}
+			vals = append(vals, sub.Value)
+		}
+		mc.Kind = "markdown"
+		mc.Value = strings.Join(vals, "\n\n")
+		return nil
+	}
+	var obj struct {
+		Kind     string `json:"kind"`
+		Language string `json:"language"`
+		Value    string `json:"value"`
+	}
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return err
+	}
+	mc.Kind = obj.Kind
+	mc.Value = obj.Value
+	return nil
+}
+
+// Hover is the result of a textDocument/hover request.
+type Hover struct {
+	Contents MarkupContent `json:"contents"`
+	Range    *Range        `json:"range,omitempty"`
+}
+
+// CompletionItemKind is the kind of a completion entry.
+type CompletionItemKind int
+
+// Completion item kinds used by [CompletionItem.Kind]; not all are listed.
+const (
+	TextCompletion        CompletionItemKind = 1
+	MethodCompletion      CompletionItemKind = 2
+	FunctionCompletion    CompletionItemKind = 3
+	ConstructorCompletion CompletionItemKind = 4
+	FieldCompletion       CompletionItemKind = 5
+	VariableCompletion    CompletionItemKind = 6
+	ClassCompletion       CompletionItemKind = 7
+	InterfaceCompletion   CompletionItemKind = 8
+	ModuleCompletion      CompletionItemKind = 9
+	PropertyCompletion    CompletionItemKind = 10
+	KeywordCompletion     CompletionItemKind = 14
+	SnippetCompletion     CompletionItemKind = 15
+	ConstantCompletion    CompletionItemKind = 21
+	StructCompletion      CompletionItemKind = 22
+	TypeParamCompletion   CompletionItemKind = 25
+)
+
+// CompletionItem is one completion result.
+type CompletionItem struct {
+	Label         string             `json:"label"`
+	Kind          CompletionItemKind `json:"kind,omitempty"`
+	Detail        string             `json:"detail,omitempty"`
+	Documentation *MarkupContent     `json:"documentation,omitempty"`
+	InsertText    string             `json:"insertText,omitempty"`
+	TextEdit      *TextEdit          `json:"textEdit,omitempty"`
+}
+
+// Text returns the text to insert for the item.
+func (ci *CompletionItem) Text() string {
+	switch {
+	case ci.TextEdit != nil:
+		return ci.TextEdit.NewText
+	case ci.InsertText != "":
+		return ci.InsertText
+	}
+	return ci.Label
+}
+
+// completionList is the alternative form of a completion result.
+type completionList struct {
+	IsIncomplete bool             `json:"isIncomplete"`
+	Items        []CompletionItem `json:"items"`
+}
+
+// InitializeParams are the params for the initialize request.
+type InitializeParams struct {
+	ProcessID    int            `json:"processId"`
+	RootURI      string         `json:"rootUri"`
+	Capabilities map[string]any `json:"capabilities"`
+}
+
+// InitializeResult is the result of the initialize request.
+// Capabilities are left raw, as they are only checked for presence.
+type InitializeResult struct {
+	Capabilities map[string]json.RawMessage `json:"capabilities"`
+}
+
+// DiagnosticSeverity is the severity of a [Diagnostic].
+type DiagnosticSeverity int
+
+const (
+	SeverityError       DiagnosticSeverity = 1
+	SeverityWarning     DiagnosticSeverity = 2
+	SeverityInformation DiagnosticSeverity = 3
+	SeverityHint        DiagnosticSeverity = 4
+)
+
+// Diagnostic is a compiler error, warning, etc reported by the server.
+type Diagnostic struct {
+	Range    Range              `json:"range"`
+	Severity DiagnosticSeverity `json:"severity,omitempty"`
+	Source   string             `json:"source,omitempty"`
+	Message  string             `json:"message"`
+}
+
+// PublishDiagnosticsParams are the params for textDocument/publishDiagnostics.
+type PublishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+////////  Helpers
+
+// FileURI returns the file:// URI for given file path.
+func FileURI(fpath string) string {
+	fpath, _ = filepath.Abs(fpath)
+	fpath = filepath.ToSlash(fpath)
+	if runtime.GOOS == "windows" {
+		fpath = "/" + fpath
+	}
+	u := url.URL{Scheme: "file", Path: fpath}
+	return u.String()
+}
+
+// URIToPath returns the file path for given file:// URI.
+// Non-file URIs are returned as-is.
+func URIToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	fpath := u.Path
+	if runtime.GOOS == "windows" {
+		fpath = strings.TrimPrefix(fpath, "/")
+	}
+	return filepath.FromSlash(fpath)
+}
+
+// RuneToUTF16 converts a rune index within given line to
+// a UTF-16 code unit offset, as used in [Position].
+func RuneToUTF16(line []rune, ch int) int {
+	ch = min(ch, len(line))
+	n := 0
+	for _, r := range line[:ch] {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// UTF16ToRune converts a UTF-16 code unit offset within given line
+// to a rune index.
+func UTF16ToRune(line []rune, u int) int {
+	n := 0
+	for i, r := range line {
+		if n >= u {
+			return i
+		}
+		n += utf16.RuneLen(r)
+	}
+	return len(line)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"cogentcore.org/cogent/code/lsp"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLSPRegion(t *testing.T) {
+	rls := [][]rune{[]rune("package main"), []rune("func añb() {}")}
+	rng := func(sl, sc, el, ec int) lsp.Range {
+		return lsp.Range{Start: lsp.Position{Line: sl, Character: sc}, End: lsp.Position{Line: el, Character: ec}}
+	}
+	assert.Equal(t, textpos.Region{Start: textpos.Pos{Line: 1, Char: 5}, End: textpos.Pos{Line: 1, Char: 8}}, lspRegion(rls, rng(1, 5, 1, 8)))
+	// out of range lines are clamped
+	assert.Equal(t, textpos.Region{Start: textpos.Pos{}, End: textpos.Pos{Line: 1, Char: 13}}, lspRegion(rls, rng(-1, 0, 2, 0)))
+	assert.Equal(t, textpos.Region{}, lspRegion(nil, rng(0, 3, 1, 0)))
+}
+
+func TestReplaceRegions(t *testing.T) {
+	ln := lines.NewLines()
+	ln.SetText([]byte("a := foo\nfoo(a)"))
+	regs := []textpos.Region{
+		{Start: textpos.Pos{Line: 0, Char: 5}, End: textpos.Pos{Line: 0, Char: 8}},
+		{Start: textpos.Pos{Line: 1, Char: 0}, End: textpos.Pos{Line: 1, Char: 3}},
+	}
+	n := replaceRegions(ln, regs, func(i int) string { return "barbaz" })
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "a := barbaz", string(ln.Line(0)))
+	assert.Equal(t, "barbaz(a)", string(ln.Line(1)))
+}
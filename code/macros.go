@@ -0,0 +1,410 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/iox/tomlx"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/events/key"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/keymap"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/styles/states"
+	"cogentcore.org/core/text/textpos"
+	"cogentcore.org/core/tree"
+)
+
+// MacroStep is one step of a keyboard [Macro], which is either
+// a key chord sent to the active editor, or a command that is run.
+type MacroStep struct {
+
+	// Key is the key chord, e.g., Control+A.
+	Key key.Chord `toml:",omitempty"`
+
+	// Command is the name of the command that is run, if Key is empty.
+	Command CmdName `toml:",omitempty"`
+}
+
+// Macro is a recorded sequence of key chords and commands
+// that can be replayed in the active editor.
+type Macro struct {
+
+	// Key is the key chord or two-key sequence that replays the macro,
+	// e.g., Control+X 1, if any. It takes precedence over the keymap.
+	Key key.Chord `toml:",omitempty"`
+
+	// Steps are the steps of the macro.
+	Steps []MacroStep
+}
+
+// Macros is a list of named keyboard macros
+type Macros map[string]Macro //types:add
+
+// MacroName has an associated ValueView for selecting from the list of
+// available named macros, where empty is the last recorded macro.
+type MacroName string
+
+// AvailableMacros are available named macros.  can be loaded / saved /
+// edited with settings.
+var AvailableMacros Macros
+
+// AvailableMacroNames are the names of the current AvailableMacros -- used for some choosers
+var AvailableMacroNames []string
+
+// MacroSettingsFilename is the name of the settings file in the app settings
+// directory for saving / loading the default AvailableMacros
+var MacroSettingsFilename = "macro-settings.toml"
+
+// ByKey returns the name of the macro that is replayed by
+// the given key chord or two-key sequence, if any.
+func (mc *Macros) ByKey(kc key.Chord) (MacroName, bool) {
+	if kc == "" {
+		return "", false
+	}
+	for _, nm := range mc.Names() {
+		if (*mc)[nm].Key == kc {
+			return MacroName(nm), true
+		}
+	}
+	return "", false
+}
+
+// Names returns a slice of current macro names
+func (mc *Macros) Names() []string {
+	nms := make([]string, 0, len(*mc))
+	for key := range *mc {
+		nms = append(nms, key)
+	}
+	slices.Sort(nms)
+	return nms
+}
+
+// Open opens named macros from a toml-formatted file.
+func (mc *Macros) Open(filename core.Filename) error { //types:add
+	*mc = make(Macros) // reset
+	return errors.Log(tomlx.Open(mc, string(filename)))
+}
+
+// Save saves named macros to a toml-formatted file.
+func (mc *Macros) Save(filename core.Filename) error { //types:add
+	return errors.Log(tomlx.Save(mc, string(filename)))
+}
+
+// OpenSettings opens the Macros from the app settings directory,
+// using MacroSettingsFilename.
+func (mc *Macros) OpenSettings() error { //types:add
+	pdir := core.TheApp.AppDataDir()
+	pnm := filepath.Join(pdir, MacroSettingsFilename)
+	AvailableMacrosChanged = false
+	err := mc.Open(core.Filename(pnm))
+	if err == nil {
+		AvailableMacroNames = mc.Names()
+	}
+	return err
+}
+
+// SaveSettings saves the Macros to the app settings directory,
+// using MacroSettingsFilename.
+func (mc *Macros) SaveSettings() error { //types:add
+	pdir := core.TheApp.AppDataDir()
+	pnm := filepath.Join(pdir, MacroSettingsFilename)
+	AvailableMacrosChanged = false
+	AvailableMacroNames = mc.Names()
+	return mc.Save(core.Filename(pnm))
+}
+
+// AvailableMacrosChanged is used to update toolbars via following menu, toolbar
+// properties update methods -- not accurate if editing any other map but works for
+// now..
+var AvailableMacrosChanged = false
+
+// MacrosView opens a view of a macros table
+func MacrosView(pt *Macros) {
+	if core.RecycleMainWindow(pt) {
+		return
+	}
+	d := core.NewBody().SetTitle("Cogent Code Macros").SetData(pt)
+	d.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+	})
+
+	core.NewText(d).SetText("Available Macros: can duplicate an existing (using context menu) as starting point for new one").SetType(core.TextHeadlineSmall)
+
+	tv := core.NewKeyedList(d).SetMap(pt)
+
+	AvailableMacrosChanged = false
+	tv.OnChange(func(e events.Event) {
+		AvailableMacrosChanged = true
+	})
+
+	d.AddTopBar(func(bar *core.Frame) {
+		core.NewToolbar(bar).Maker(func(p *tree.Plan) {
+			tree.Add(p, func(w *core.FuncButton) {
+				w.SetFunc(pt.SaveSettings).SetText("Save to settings").
+					SetIcon(icons.Save).SetKey(keymap.Save).
+					FirstStyler(func(s *styles.Style) {
+						s.SetEnabled(AvailableMacrosChanged && pt == &AvailableMacros)
+					})
+			})
+			tree.Add(p, func(w *core.FuncButton) {
+				w.SetFunc(pt.Open).SetText("Open").SetIcon(icons.Open).SetKey(keymap.Open)
+				w.Args[0].SetTag(`extension:".toml"`)
+			})
+			tree.Add(p, func(w *core.FuncButton) {
+				w.SetFunc(pt.Save).SetText("Save as").SetIcon(icons.SaveAs).SetKey(keymap.SaveAs)
+				w.Args[0].SetTag(`extension:".toml"`)
+			})
+		})
+	})
+
+	d.RunWindow()
+}
+
+// Value registers [core.Chooser] as the [core.Value] widget
+// for [MacroName]
+func (mn MacroName) Value() core.Value {
+	return core.NewChooser().SetStrings(append([]string{""}, AvailableMacroNames...)...)
+}
+
+// trimKeys removes the last n key chords from the macro,
+// which are those of the key sequence that stopped recording it.
+func (mc *Macro) trimKeys(n int) {
+	for ; n > 0 && len(mc.Steps) > 0; n-- {
+		if mc.Steps[len(mc.Steps)-1].Key == "" {
+			return
+		}
+		mc.Steps = mc.Steps[:len(mc.Steps)-1]
+	}
+}
+
+// macroLines returns the range of lines that a macro is applied
+// to for the given selected region, not including the last line
+// if the selection ends at its start.
+func macroLines(reg textpos.Region) (st, ed int) {
+	st, ed = reg.Start.Line, reg.End.Line
+	if ed > st && reg.End.Char == 0 {
+		ed--
+	}
+	return
+}
+
+// RecordMacro starts recording a keyboard macro of the key chords
+// typed in the active editor and the commands that are run, or stops
+// recording it if it is already being recorded, in which case it
+// becomes the last macro that can be replayed or saved.
+func (cv *Code) RecordMacro() { //types:add
+	if cv.macroRecord == nil {
+		cv.macroRecord = &Macro{}
+		cv.SetStatus("Recording macro")
+		return
+	}
+	cv.lastMacro = *cv.macroRecord
+	cv.macroRecord = nil
+	cv.SetStatus(fmt.Sprintf("Recorded macro with %d steps", len(cv.lastMacro.Steps)))
+}
+
+// recordMacroKey records the given key chord typed in the active editor
+// if a macro is being recorded.
+func (cv *Code) recordMacroKey(kc key.Chord) {
+	if cv.macroRecord == nil || cv.macroReplaying {
+		return
+	}
+	tv := cv.ActiveEditor()
+	if tv == nil || !tv.StateIs(states.Focused) {
+		return
+	}
+	cv.macroRecord.Steps = append(cv.macroRecord.Steps, MacroStep{Key: kc})
+}
+
+// recordMacroCommand records the given command if a macro is being recorded.
+func (cv *Code) recordMacroCommand(cmdName CmdName) {
+	if cv.macroRecord == nil || cv.macroReplaying {
+		return
+	}
+	cv.macroRecord.Steps = append(cv.macroRecord.Steps, MacroStep{Command: cmdName})
+}
+
+// macroByName returns the macro of the given name, which is the
+// last recorded macro if the name is empty.
+func (cv *Code) macroByName(name MacroName) (Macro, error) {
+	if name == "" {
+		if len(cv.lastMacro.Steps) == 0 {
+			return Macro{}, errors.New("no macro has been recorded")
+		}
+		return cv.lastMacro, nil
+	}
+	mc, ok := AvailableMacros[string(name)]
+	if !ok {
+		return Macro{}, fmt.Errorf("macro named %q not found", name)
+	}
+	return mc, nil
+}
+
+// SaveMacro saves the last recorded macro with the given name in
+// the macros in the settings, replacing any with the same name.
+// It is replayed by the given key chord or two-key sequence,
+// e.g., Control+X 1, if it is not empty, which is removed from
+// any other macro that it replays.
+func (cv *Code) SaveMacro(name string, key key.Chord) { //types:add
+	if len(cv.lastMacro.Steps) == 0 {
+		core.MessageSnackbar(cv, "No macro has been recorded")
+		return
+	}
+	if AvailableMacros == nil {
+		AvailableMacros = make(Macros)
+	}
+	AvailableMacros.setKey(key)
+	AvailableMacros[name] = Macro{Key: key, Steps: slices.Clone(cv.lastMacro.Steps)}
+	errors.Log(AvailableMacros.SaveSettings())
+}
+
+// setKey removes the given key chord from the macros that it replays,
+// if it is not empty, so that it can be used for another one.
+func (mc Macros) setKey(kc key.Chord) {
+	if kc == "" {
+		return
+	}
+	for nm, m := range mc {
+		if m.Key == kc {
+			m.Key = ""
+			mc[nm] = m
+		}
+	}
+}
+
+// ReplayMacro replays the macro of the given name the given number of
+// times in the active editor, where the last recorded macro is replayed
+// if the name is empty.
+func (cv *Code) ReplayMacro(name MacroName, times int) { //types:add
+	mc, err := cv.macroByName(name)
+	if err != nil {
+		core.ErrorSnackbar(cv, err)
+		return
+	}
+	var next func(i int)
+	next = func(i int) {
+		if i < max(times, 1) {
+			cv.replayMacro(mc.Steps, func() { next(i + 1) })
+		}
+	}
+	next(0)
+}
+
+// ApplyMacroToLines replays the macro of the given name once for each
+// line in the selection of the active editor, with the cursor at the
+// start of the line, from the last line to the first so that the lines
+// are not affected by changes to the line count. The last recorded macro
+// is replayed if the name is empty.
+func (cv *Code) ApplyMacroToLines(name MacroName) { //types:add
+	mc, err := cv.macroByName(name)
+	if err != nil {
+		core.ErrorSnackbar(cv, err)
+		return
+	}
+	tv := cv.ActiveEditor()
+	if tv == nil || tv.Lines == nil {
+		return
+	}
+	st, ed := tv.CursorPos.Line, tv.CursorPos.Line
+	if tv.HasSelection() {
+		st, ed = macroLines(tv.SelectRegion)
+	}
+	var next func(ln int)
+	next = func(ln int) {
+		if ln < st {
+			return
+		}
+		tv.SelectReset()
+		tv.SetCursorShow(textpos.Pos{Line: ln})
+		cv.replayMacro(mc.Steps, func() { next(ln - 1) })
+	}
+	next(ed)
+}
+
+// replayMacro replays the given steps of a macro once in the active
+// editor, and then calls done. Key chords are first handled by the key
+// functions of Code, and then sent to the active editor if they are not
+// handled. Each command is run to completion before the steps after it
+// are replayed, and the rest of the steps are not replayed if it fails
+// or is cancelled.
+func (cv *Code) replayMacro(steps []MacroStep, done func()) {
+	cv.macroReplaying = true
+	defer func() { cv.macroReplaying = false }()
+	for i, st := range steps {
+		if st.Key == "" {
+			rest := steps[i+1:]
+			if cv.execCmdName(st.Command, func(ok bool) {
+				if ok {
+					cv.replayMacro(rest, done)
+				}
+			}) {
+				return
+			}
+			continue
+		}
+		tv := cv.ActiveEditor()
+		if tv == nil {
+			return
+		}
+		r, code, mods, err := st.Key.Decode()
+		if errors.Log(err) != nil {
+			continue
+		}
+		e := events.NewKey(events.KeyChord, r, code, mods)
+		cv.codeKeys(e)
+		if !e.IsHandled() {
+			tv.HandleEvent(e)
+		}
+	}
+	done()
+}
+
+// macroKeyChord replays the macro that is replayed by the given key
+// chord or two-key sequence, if any, returning true if there is one.
+// It does nothing while a macro is being replayed.
+func (cv *Code) macroKeyChord(kc key.Chord) bool {
+	name, ok := AvailableMacros.ByKey(kc)
+	if !ok {
+		return false
+	}
+	if !cv.macroReplaying {
+		cv.ReplayMacro(name, 1)
+	}
+	return true
+}
+
+// macroKeys handles the key functions for keyboard macros,
+// where seq is whether the key chord ends a two-key sequence.
+// It returns true if the key function is a macro one, which
+// does nothing while a macro is being replayed.
+func (cv *Code) macroKeys(kf keymap.Functions, seq bool) bool {
+	if cv.macroReplaying {
+		return slices.Contains([]keymap.Functions{KeyMacroRecord, KeyMacroReplay, KeyMacroApplyLines}, kf)
+	}
+	switch kf {
+	case KeyMacroRecord:
+		if cv.macroRecord != nil {
+			n := 1
+			if seq {
+				n = 2
+			}
+			cv.macroRecord.trimKeys(n)
+		}
+		cv.RecordMacro()
+	case KeyMacroReplay:
+		cv.ReplayMacro("", 1)
+	case KeyMacroApplyLines:
+		cv.ApplyMacroToLines("")
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/events/key"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMacros(t *testing.T) {
+	mc := Macro{Steps: []MacroStep{{Key: "a"}, {Command: "Go Build"}, {Key: "Control+X"}, {Key: "q"}}}
+	mc.trimKeys(2)
+	assert.Equal(t, []MacroStep{{Key: "a"}, {Command: "Go Build"}}, mc.Steps)
+	mc.trimKeys(1)
+	assert.Equal(t, []MacroStep{{Key: "a"}, {Command: "Go Build"}}, mc.Steps)
+
+	fn := core.Filename(filepath.Join(t.TempDir(), "macros.toml"))
+	ms := Macros{"x": mc, "enter": {Key: "Control+X 1", Steps: []MacroStep{{Key: "Control+A"}, {Key: "ReturnEnter"}}}}
+	assert.NoError(t, ms.Save(fn))
+	var om Macros
+	assert.NoError(t, om.Open(fn))
+	assert.Equal(t, ms, om)
+	assert.Equal(t, []string{"enter", "x"}, om.Names())
+
+	nm, ok := om.ByKey("Control+X 1")
+	assert.True(t, ok)
+	assert.Equal(t, MacroName("enter"), nm)
+	_, ok = om.ByKey("Control+X 2")
+	assert.False(t, ok)
+	_, ok = om.ByKey("")
+	assert.False(t, ok)
+	om.setKey("Control+X 1")
+	_, ok = om.ByKey("Control+X 1")
+	assert.False(t, ok)
+
+	for _, kc := range []key.Chord{"a", "A", "Control+A", "ReturnEnter", "Shift+Tab"} {
+		r, code, mods, err := kc.Decode()
+		assert.NoError(t, err)
+		assert.Equal(t, kc, events.NewKey(events.KeyChord, r, code, mods).KeyChord())
+	}
+}
+
+func TestMacroLines(t *testing.T) {
+	st, ed := macroLines(textpos.Region{Start: textpos.Pos{Line: 2, Char: 3}, End: textpos.Pos{Line: 5}})
+	assert.Equal(t, []int{2, 4}, []int{st, ed})
+	st, ed = macroLines(textpos.Region{Start: textpos.Pos{Line: 2, Char: 3}, End: textpos.Pos{Line: 5, Char: 1}})
+	assert.Equal(t, []int{2, 5}, []int{st, ed})
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"image"
+	"slices"
+
+	"cogentcore.org/core/base/metadata"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/lexer"
+	"cogentcore.org/core/text/token"
+)
+
+// Marker kinds identify the markers that Code sets in the line number
+// gutter and the text of lines, independent of their colors and tokens,
+// which depend on the color scheme and are shared with other markers,
+// e.g., breakpoints and spelling errors.
+const (
+	problemMarker  = "problem"
+	coverageMarker = "coverage"
+)
+
+// gutterMarkersKey is the lines metadata key for the [gutterMarker]s.
+const gutterMarkersKey = "CodeGutterMarkers"
+
+// gutterMarker records a marker set in the line number gutter.
+type gutterMarker struct {
+
+	// kind of marker
+	kind string
+
+	// color it was set with, to detect it being replaced
+	color image.Image
+}
+
+// gutterMarkers returns the markers set in the line number gutter
+// of given lines, by line.
+func gutterMarkers(ln *lines.Lines) map[int]gutterMarker {
+	gm, err := metadata.GetFromData[map[int]gutterMarker](ln.Meta, gutterMarkersKey)
+	if err != nil || gm == nil {
+		gm = map[int]gutterMarker{}
+		ln.Meta.Set(gutterMarkersKey, gm)
+	}
+	return gm
+}
+
+// setGutterMarker sets a marker of given kind and color
+// in the line number gutter of given line.
+func setGutterMarker(ln *lines.Lines, li int, kind string, clr image.Image) {
+	ln.SetLineColor(li, clr)
+	gutterMarkers(ln)[li] = gutterMarker{kind: kind, color: clr}
+}
+
+// gutterMarkerKind returns the kind of marker in the line number gutter
+// of given line, which is "" if there is none or it is another color,
+// e.g., for a breakpoint.
+func gutterMarkerKind(ln *lines.Lines, li int) string {
+	m, ok := gutterMarkers(ln)[li]
+	if !ok {
+		return ""
+	}
+	if clr, has := ln.LineColor(li); !has || clr != m.color {
+		return ""
+	}
+	return m.kind
+}
+
+// clearGutterMarkers removes all markers of given kind
+// from the line number gutter of given lines.
+func clearGutterMarkers(ln *lines.Lines, kind string) {
+	gm := gutterMarkers(ln)
+	for li, m := range gm {
+		if m.kind != kind {
+			continue
+		}
+		if clr, has := ln.LineColor(li); has && clr == m.color {
+			ln.DeleteLineColor(li)
+		}
+		delete(gm, li)
+	}
+}
+
+// addMarkerTag adds a tag with given token to the text of given line,
+// marked as being of given kind of marker.
+func addMarkerTag(ln *lines.Lines, li, st, ed int, kind string, tok token.Tokens) {
+	tr := lexer.NewLex(token.KeyToken{Token: tok, Key: kind}, st, ed)
+	tr.Time.Now()
+	tags := slices.Clone(ln.AdjustedTags(li))
+	tags.AddSort(tr)
+	ln.SetTags(li, tags)
+	ln.MarkupLines(li, li)
+}
+
+// clearMarkerTags removes all tags of given kind of marker
+// from the text of given lines.
+func clearMarkerTags(ln *lines.Lines, kind string) {
+	for li := range ln.NumLines() {
+		tags := ln.AdjustedTags(li)
+		ntags := slices.DeleteFunc(slices.Clone(tags), func(lx lexer.Lex) bool {
+			return lx.Token.Key == kind
+		})
+		if len(ntags) != len(tags) {
+			ln.SetTags(li, ntags)
+			ln.MarkupLines(li, li)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/textpos"
+)
+
+// Conflict is a merge conflict region in a file, delimited by conflict
+// markers as written by git and other version control systems, with an
+// optional base section as written in the diff3 conflict style.
+// Line indexes are 0-based.
+type Conflict struct {
+
+	// line of the <<<<<<< marker starting the conflict
+	Start int
+
+	// line of the ||||||| marker starting the base section, -1 if none
+	BaseStart int
+
+	// line of the ======= separator
+	Sep int
+
+	// line of the >>>>>>> marker ending the conflict
+	End int
+
+	// label after the <<<<<<< marker, e.g., HEAD
+	OursLabel string
+
+	// label after the >>>>>>> marker, e.g., the merged branch
+	TheirsLabel string
+
+	// our lines, before the separator
+	Ours []string
+
+	// base lines, before both changes, if there is a base section
+	Base []string
+
+	// their lines, after the separator
+	Theirs []string
+}
+
+// MergeChoices are the ways of resolving a merge [Conflict].
+type MergeChoices int32 //enums:enum -trim-prefix Merge
+
+const (
+	// MergeOurs accepts our lines.
+	MergeOurs MergeChoices = iota
+
+	// MergeTheirs accepts their lines.
+	MergeTheirs
+
+	// MergeBoth accepts our lines followed by their lines.
+	MergeBoth
+)
+
+// conflict marker prefixes
+const (
+	conflictStart = "<<<<<<<"
+	conflictBase  = "|||||||"
+	conflictSep   = "======="
+	conflictEnd   = ">>>>>>>"
+)
+
+// isMarker returns true if given line is the given conflict marker,
+// which must be followed by a space and label, or nothing.
+func isMarker(ln, marker string) bool {
+	return ln == marker || strings.HasPrefix(ln, marker+" ")
+}
+
+// ParseConflicts returns the merge conflicts in given lines of text.
+// Incomplete conflicts are ignored.
+func ParseConflicts(lns []string) []*Conflict {
+	var cs []*Conflict
+	var c *Conflict
+	for i, ln := range lns {
+		switch {
+		case isMarker(ln, conflictStart):
+			c = &Conflict{Start: i, BaseStart: -1, Sep: -1, OursLabel: strings.TrimSpace(ln[len(conflictStart):])}
+		case c == nil:
+		case isMarker(ln, conflictBase) && c.BaseStart < 0 && c.Sep < 0:
+			c.BaseStart = i
+		case ln == conflictSep && c.Sep < 0:
+			c.Sep = i
+		case isMarker(ln, conflictEnd) && c.Sep >= 0:
+			c.End = i
+			c.TheirsLabel = strings.TrimSpace(ln[len(conflictEnd):])
+			oend := c.Sep
+			if c.BaseStart >= 0 {
+				oend = c.BaseStart
+				c.Base = lns[c.BaseStart+1 : c.Sep]
+			}
+			c.Ours = lns[c.Start+1 : oend]
+			c.Theirs = lns[c.Sep+1 : c.End]
+			cs = append(cs, c)
+			c = nil
+		}
+	}
+	return cs
+}
+
+// Resolved returns the lines resolving the conflict with given choice.
+func (c *Conflict) Resolved(choice MergeChoices) []string {
+	switch choice {
+	case MergeOurs:
+		return c.Ours
+	case MergeTheirs:
+		return c.Theirs
+	}
+	return append(append([]string{}, c.Ours...), c.Theirs...)
+}
+
+// sameAs returns true if the conflict has the same labels
+// and lines as the given one, wherever it is in the file.
+func (c *Conflict) sameAs(o *Conflict) bool {
+	return c.OursLabel == o.OursLabel && c.TheirsLabel == o.TheirsLabel &&
+		(c.BaseStart < 0) == (o.BaseStart < 0) && slices.Equal(c.Ours, o.Ours) &&
+		slices.Equal(c.Base, o.Base) && slices.Equal(c.Theirs, o.Theirs)
+}
+
+// LinesConflicts returns the merge conflicts in given lines.
+func LinesConflicts(ln *lines.Lines) []*Conflict {
+	return ParseConflicts(ln.Strings(false))
+}
+
+// ResolveConflict replaces the given conflict in given lines,
+// including its markers, with the lines for given choice.
+func ResolveConflict(ln *lines.Lines, c *Conflict, choice MergeChoices) {
+	res := c.Resolved(choice)
+	txt := strings.Join(res, "\n")
+	st := textpos.Pos{Line: c.Start}
+	end := textpos.Pos{Line: c.End + 1}
+	if c.End+1 < ln.NumLines() {
+		if len(res) > 0 {
+			txt += "\n"
+		}
+	} else { // last line, with no final newline
+		end = ln.EndPos()
+	}
+	ln.ReplaceText(st, end, st, txt, false)
+}
+
+// resolveShown resolves the given conflict, shown at given index, in given
+// lines with given choice, finding it again first, as the lines may have
+// been edited since it was shown. It returns false if it is no longer there.
+func resolveShown(ln *lines.Lines, shown *Conflict, idx int, choice MergeChoices) bool {
+	cur := LinesConflicts(ln)
+	i := idx
+	if i >= len(cur) || !cur[i].sameAs(shown) {
+		i = slices.IndexFunc(cur, shown.sameAs)
+	}
+	if i < 0 {
+		return false
+	}
+	ResolveConflict(ln, cur[i], choice)
+	return true
+}
+
+// conflictedFiles returns the full paths of the files with unresolved
+// merge conflicts in the git repository containing given directory.
+func conflictedFiles(dir string) []string {
+	top, err := exec.Command("git", "-C", dir, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return nil
+	}
+	root := strings.TrimSpace(string(top))
+	out, err := exec.Command("git", "-C", root, "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, f := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if f != "" {
+			files = append(files, filepath.Join(root, filepath.FromSlash(f)))
+		}
+	}
+	return files
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+const testMergeText = `package x
+
+<<<<<<< HEAD
+var a = 1
+||||||| base
+var a = 0
+=======
+var a = 2
+>>>>>>> feature
+func f() {}
+<<<<<<< HEAD
+=======
+var b = 3
+>>>>>>> feature
+// end
+<<<<<<< incomplete
+`
+
+func TestParseConflicts(t *testing.T) {
+	ln := lines.NewLines()
+	ln.SetString(testMergeText)
+	cs := LinesConflicts(ln)
+	if !assert.Len(t, cs, 2) {
+		return
+	}
+	c := cs[0]
+	assert.Equal(t, 2, c.Start)
+	assert.Equal(t, 4, c.BaseStart)
+	assert.Equal(t, 6, c.Sep)
+	assert.Equal(t, 8, c.End)
+	assert.Equal(t, "HEAD", c.OursLabel)
+	assert.Equal(t, "feature", c.TheirsLabel)
+	assert.Equal(t, []string{"var a = 1"}, c.Ours)
+	assert.Equal(t, []string{"var a = 0"}, c.Base)
+	assert.Equal(t, []string{"var a = 2"}, c.Theirs)
+	assert.Equal(t, []string{"var a = 1", "var a = 2"}, c.Resolved(MergeBoth))
+	assert.Equal(t, -1, cs[1].BaseStart)
+	assert.Empty(t, cs[1].Ours)
+
+	ResolveConflict(ln, cs[1], MergeOurs)
+	cs = LinesConflicts(ln)
+	assert.Len(t, cs, 1)
+	ResolveConflict(ln, cs[0], MergeTheirs)
+	assert.Empty(t, LinesConflicts(ln))
+	assert.Equal(t, "package x\n\nvar a = 2\nfunc f() {}\n// end\n<<<<<<< incomplete\n", ln.String())
+}
+
+func TestResolveShown(t *testing.T) {
+	ln := lines.NewLines()
+	ln.SetString(testMergeText)
+	cs := LinesConflicts(ln)
+	assert.Len(t, cs, 2)
+
+	ln.InsertText(textpos.Pos{}, []rune("// edited\n")) // shifts the shown conflicts
+	assert.True(t, resolveShown(ln, cs[1], 1, MergeTheirs))
+	assert.Equal(t, "var b = 3", string(ln.Line(11)))
+	assert.True(t, resolveShown(ln, cs[0], 0, MergeOurs))
+	assert.Equal(t, "var a = 1", string(ln.Line(3)))
+	assert.Empty(t, LinesConflicts(ln))
+	assert.False(t, resolveShown(ln, cs[0], 0, MergeOurs))
+}
@@ -0,0 +1,271 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/styles/units"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/tree"
+)
+
+// MergePanel is a widget for resolving the merge conflicts in a file,
+// showing our, base and their lines side by side for each conflict,
+// with actions to accept either or both of them.
+type MergePanel struct {
+	core.Frame
+
+	// parent code project
+	Code *Code `json:"-" xml:"-"`
+
+	// lines of the file with the conflicts
+	Lines *lines.Lines `set:"-" json:"-" xml:"-"`
+
+	// current merge conflicts in the file
+	Conflicts []*Conflict `set:"-" json:"-" xml:"-"`
+}
+
+func (mv *MergePanel) Init() {
+	mv.Frame.Init()
+	mv.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 1)
+	})
+
+	tree.AddChildAt(mv, "mergebar", func(w *core.Toolbar) {
+		w.Maker(mv.makeToolbar)
+	})
+	tree.AddChildAt(mv, "conflicts", func(w *core.Frame) {
+		w.Styler(func(s *styles.Style) {
+			s.Direction = styles.Column
+			s.Grow.Set(1, 1)
+			s.Overflow.Set(styles.OverflowAuto)
+		})
+		w.Maker(mv.makeConflicts)
+	})
+}
+
+func (mv *MergePanel) OnAdd() {
+	mv.Frame.OnAdd()
+	mv.Code, _ = ParentCode(mv)
+}
+
+// SetLines sets the lines to resolve the merge conflicts in.
+func (mv *MergePanel) SetLines(ln *lines.Lines) {
+	mv.Lines = ln
+	mv.Refresh()
+}
+
+// Refresh finds the merge conflicts in the lines again.
+func (mv *MergePanel) Refresh() {
+	mv.Conflicts = nil
+	if mv.Lines != nil {
+		mv.Conflicts = LinesConflicts(mv.Lines)
+	}
+	mv.Update()
+}
+
+// Resolve resolves the conflict at given index with given choice.
+// The conflicts are found again first, as the lines may have been
+// edited since they were shown.
+func (mv *MergePanel) Resolve(idx int, choice MergeChoices) {
+	if mv.Lines == nil || idx < 0 || idx >= len(mv.Conflicts) {
+		return
+	}
+	if !resolveShown(mv.Lines, mv.Conflicts[idx], idx, choice) {
+		core.MessageSnackbar(mv, "The conflict has been edited; check the conflicts again")
+	}
+	mv.Refresh()
+}
+
+// GoToConflict shows the conflict at given index in the editor.
+func (mv *MergePanel) GoToConflict(idx int) {
+	if mv.Lines == nil || idx < 0 || idx >= len(mv.Conflicts) {
+		return
+	}
+	tv, err := mv.Code.ShowFile(mv.Lines.Filename(), mv.Conflicts[idx].Start+1)
+	if err == nil {
+		tv.SetFocus()
+	}
+}
+
+// MarkResolved saves the file, and adds it to the repository index to mark
+// the conflicts as resolved, once there are no conflicts left in it.
+func (mv *MergePanel) MarkResolved() error {
+	if mv.Lines == nil {
+		return nil
+	}
+	mv.Refresh()
+	if n := len(mv.Conflicts); n > 0 {
+		err := fmt.Errorf("there are still %d merge conflicts", n)
+		core.ErrorSnackbar(mv, err)
+		return err
+	}
+	fname := mv.Lines.Filename()
+	repo := GetVCSRepo(mv.Lines)
+	if repo == nil {
+		err := errors.New("file is not in a version control repository: " + fname)
+		core.ErrorSnackbar(mv, err)
+		return err
+	}
+	if mv.Lines.IsNotSaved() {
+		if err := textcore.Save(mv.Scene, mv.Lines); err != nil {
+			core.ErrorSnackbar(mv, err, "Could not save file")
+			return err
+		}
+	}
+	if err := repo.Add(fname); err != nil {
+		core.ErrorSnackbar(mv, err, "Could not mark file as resolved")
+		return err
+	}
+	mv.Code.Files.UpdatePath(filepath.Dir(fname))
+	core.MessageSnackbar(mv, "Marked as resolved: "+fname)
+	return nil
+}
+
+func (mv *MergePanel) makeToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			fname := "no file"
+			if mv.Lines != nil {
+				fname = filepath.Base(mv.Lines.Filename())
+			}
+			w.SetText(fmt.Sprintf("%s: %d conflicts", fname, len(mv.Conflicts)))
+		})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Refresh").SetIcon(icons.Update).
+			SetTooltip("find the merge conflicts in the file again, after editing it").
+			OnClick(func(e events.Event) {
+				mv.Refresh()
+			})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Mark resolved").SetIcon(icons.Check).
+			SetTooltip("save the file and add it to the repository index (git add) to mark the conflicts as resolved").
+			OnClick(func(e events.Event) {
+				mv.MarkResolved()
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(mv.Lines != nil && len(mv.Conflicts) == 0) })
+	})
+}
+
+func (mv *MergePanel) makeConflicts(p *tree.Plan) {
+	for i := range mv.Conflicts {
+		tree.AddAt(p, fmt.Sprintf("conflict-%d", i), func(w *core.Frame) {
+			mv.makeConflict(w, i)
+		})
+	}
+}
+
+// makeConflict configures the given frame to show the conflict at given index.
+func (mv *MergePanel) makeConflict(w *core.Frame, idx int) {
+	w.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 0)
+		s.Border.Width.Bottom.Dp(1)
+		s.Border.Color.Bottom = colors.Scheme.OutlineVariant
+	})
+	tree.AddChild(w, func(w *core.Frame) {
+		w.Styler(func(s *styles.Style) {
+			s.Align.Items = styles.Center
+		})
+		tree.AddChild(w, func(w *core.Text) {
+			w.Updater(func() {
+				c := mv.Conflicts[idx]
+				w.SetText(fmt.Sprintf("Conflict %d of %d at line %d", idx+1, len(mv.Conflicts), c.Start+1))
+			})
+		})
+		tree.AddChild(w, func(w *core.Button) {
+			w.SetText("Accept ours").SetIcon(icons.ArrowBack).
+				SetTooltip("resolve the conflict using our lines (left)").
+				OnClick(func(e events.Event) {
+					mv.Resolve(idx, MergeOurs)
+				})
+		})
+		tree.AddChild(w, func(w *core.Button) {
+			w.SetText("Accept both").SetIcon(icons.Merge).
+				SetTooltip("resolve the conflict using our lines followed by their lines").
+				OnClick(func(e events.Event) {
+					mv.Resolve(idx, MergeBoth)
+				})
+		})
+		tree.AddChild(w, func(w *core.Button) {
+			w.SetText("Accept theirs").SetIcon(icons.ArrowForward).
+				SetTooltip("resolve the conflict using their lines (right)").
+				OnClick(func(e events.Event) {
+					mv.Resolve(idx, MergeTheirs)
+				})
+		})
+		tree.AddChild(w, func(w *core.Button) {
+			w.SetText("Go to").SetIcon(icons.Edit).
+				SetTooltip("show the conflict in the editor, to resolve it by hand").
+				OnClick(func(e events.Event) {
+					mv.GoToConflict(idx)
+				})
+		})
+	})
+	tree.AddChild(w, func(w *core.Frame) {
+		w.Styler(func(s *styles.Style) {
+			s.Grow.Set(1, 0)
+		})
+		w.Maker(func(p *tree.Plan) {
+			mv.makeSide(p, "ours", idx, func(c *Conflict) (string, []string) { return "Ours: " + c.OursLabel, c.Ours })
+			if mv.Conflicts[idx].BaseStart >= 0 {
+				mv.makeSide(p, "base", idx, func(c *Conflict) (string, []string) { return "Base", c.Base })
+			}
+			mv.makeSide(p, "theirs", idx, func(c *Conflict) (string, []string) { return "Theirs: " + c.TheirsLabel, c.Theirs })
+		})
+	})
+}
+
+// makeSide adds a read-only editor showing one side of the conflict at
+// given index, with the label and lines returned by given function.
+func (mv *MergePanel) makeSide(p *tree.Plan, name string, idx int, side func(c *Conflict) (string, []string)) {
+	tree.AddAt(p, name, func(w *core.Frame) {
+		w.Styler(func(s *styles.Style) {
+			s.Direction = styles.Column
+			s.Grow.Set(1, 0)
+			s.Min.X.Ch(20)
+		})
+		tree.AddChild(w, func(w *core.Text) {
+			w.SetType(core.TextLabelLarge)
+			w.Updater(func() {
+				lbl, _ := side(mv.Conflicts[idx])
+				w.SetText(lbl)
+			})
+		})
+		tree.AddChild(w, func(w *textcore.Editor) {
+			w.SetReadOnly(true)
+			w.Styler(func(s *styles.Style) {
+				s.Grow.Set(1, 0)
+				s.Min.X.Ch(20)
+				s.Min.Y.Em(4)
+				s.Max.Y.Em(20)
+				s.Padding.Set(units.Dp(2))
+			})
+			w.Updater(func() {
+				_, lns := side(mv.Conflicts[idx])
+				if w.Lines == nil {
+					w.SetLines(lines.NewLines())
+				}
+				if mv.Lines != nil {
+					w.Lines.SetLanguage(mv.Lines.FileInfo().Known)
+				}
+				w.Lines.SetString(strings.Join(lns, "\n"))
+			})
+		})
+	})
+}
@@ -0,0 +1,360 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/token"
+)
+
+// GoProblemRegexp matches the file:line:col: message lines output by
+// the go build, vet and test commands.
+const GoProblemRegexp = `^\s*(?:vet: )?(?P<file>[^\s:]+\.go):(?P<line>\d+)(?::(?P<col>\d+))?: (?P<msg>.+)$`
+
+// GoTestProblemRegexp matches the file:line:col: message lines output by
+// the go test command for build and vet errors, which have a column, but
+// not the file:line: message lines logged by tests, which can pass.
+// Failed tests are reported as problems by the Tests panel.
+const GoTestProblemRegexp = `^\s*(?:vet: )?(?P<file>[^\s:]+\.go):(?P<line>\d+):(?P<col>\d+): (?P<msg>.+)$`
+
+// ProblemSeverities are the severity levels of a [Problem].
+type ProblemSeverities int32 //enums:enum -trim-prefix Problem
+
+const (
+	// ProblemError is an error that prevents building or passing.
+	ProblemError ProblemSeverities = iota
+
+	// ProblemWarning is a warning, e.g., from a linter.
+	ProblemWarning
+
+	// ProblemInfo is an informational note or hint.
+	ProblemInfo
+)
+
+// Problem is one diagnostic parsed from the output of a command.
+type Problem struct {
+
+	// full path to the file
+	Filename string
+
+	// 1-based line number
+	Line int
+
+	// 1-based column number, or 0 if not known
+	Col int
+
+	// severity of the problem
+	Severity ProblemSeverities
+
+	// problem message
+	Message string
+
+	// label of the command that reported the problem
+	Source string
+}
+
+// String returns the problem in the standard file:line:col: message format.
+func (pr *Problem) String() string {
+	if pr.Col > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", pr.Filename, pr.Line, pr.Col, pr.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", pr.Filename, pr.Line, pr.Message)
+}
+
+// ParseProblems parses the problems in given command output using given
+// regexp, which must have named groups file and line, and optionally col,
+// severity and msg. Relative file names are resolved against dir.
+// The severity group is matched on its prefix (warn, info, note, hint),
+// and defaults to [ProblemError]. Duplicate problems are only included once.
+func ParseProblems(re *regexp.Regexp, out, dir, source string) []*Problem {
+	var probs []*Problem
+	has := map[Problem]bool{}
+	fi, li, ci, si, mi := re.SubexpIndex("file"), re.SubexpIndex("line"), re.SubexpIndex("col"), re.SubexpIndex("severity"), re.SubexpIndex("msg")
+	if fi < 0 || li < 0 {
+		return nil
+	}
+	group := func(m []string, i int) string {
+		if i < 0 {
+			return ""
+		}
+		return m[i]
+	}
+	for _, ln := range strings.Split(out, "\n") {
+		m := re.FindStringSubmatch(strings.TrimRight(ln, "\r"))
+		if m == nil {
+			continue
+		}
+		line, err := strconv.Atoi(m[li])
+		if err != nil {
+			continue
+		}
+		pr := Problem{Filename: m[fi], Line: line, Message: strings.TrimSpace(group(m, mi)), Source: source}
+		pr.Col, _ = strconv.Atoi(group(m, ci))
+		if !filepath.IsAbs(pr.Filename) {
+			pr.Filename = filepath.Join(dir, pr.Filename)
+		}
+		sev := strings.ToLower(group(m, si))
+		switch {
+		case strings.HasPrefix(sev, "warn"):
+			pr.Severity = ProblemWarning
+		case strings.HasPrefix(sev, "info"), strings.HasPrefix(sev, "note"), strings.HasPrefix(sev, "hint"):
+			pr.Severity = ProblemInfo
+		}
+		if has[pr] {
+			continue
+		}
+		has[pr] = true
+		probs = append(probs, &pr)
+	}
+	return probs
+}
+
+// Problems holds the problems reported by each command, keyed by
+// command label, so that rerunning a command replaces its problems.
+// It is safe for concurrent use.
+type Problems struct {
+	mu      sync.Mutex
+	sources map[string][]*Problem
+}
+
+// Set sets the problems for given source command, replacing any
+// existing ones, and returns the files that had problems before or after.
+func (ps *Problems) Set(source string, probs []*Problem) []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.sources == nil {
+		ps.sources = make(map[string][]*Problem)
+	}
+	var files []string
+	for _, pr := range ps.sources[source] {
+		files = append(files, pr.Filename)
+	}
+	for _, pr := range probs {
+		files = append(files, pr.Filename)
+	}
+	if len(probs) == 0 {
+		delete(ps.sources, source)
+	} else {
+		ps.sources[source] = probs
+	}
+	slices.Sort(files)
+	return slices.Compact(files)
+}
+
+// SetFile sets the problems for given source command in given file,
+// replacing any existing ones in that file only.
+func (ps *Problems) SetFile(source, fpath string, probs []*Problem) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if ps.sources == nil {
+		ps.sources = make(map[string][]*Problem)
+	}
+	sprobs := slices.DeleteFunc(slices.Clone(ps.sources[source]), func(pr *Problem) bool {
+		return pr.Filename == fpath
+	})
+	sprobs = append(sprobs, probs...)
+	if len(sprobs) == 0 {
+		delete(ps.sources, source)
+	} else {
+		ps.sources[source] = sprobs
+	}
+}
+
+// Clear removes all problems, returning the files that had problems.
+func (ps *Problems) Clear() []string {
+	var files []string
+	for _, pr := range ps.All() {
+		files = append(files, pr.Filename)
+	}
+	ps.mu.Lock()
+	ps.sources = nil
+	ps.mu.Unlock()
+	return slices.Compact(files)
+}
+
+// All returns all of the problems, sorted by file, line and column.
+func (ps *Problems) All() []*Problem {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	var all []*Problem
+	for _, probs := range ps.sources {
+		all = append(all, probs...)
+	}
+	slices.SortStableFunc(all, func(a, b *Problem) int {
+		if c := strings.Compare(a.Filename, b.Filename); c != 0 {
+			return c
+		}
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Col - b.Col
+	})
+	return all
+}
+
+// ForFile returns the problems for given file, sorted by line.
+func (ps *Problems) ForFile(fpath string) []*Problem {
+	var probs []*Problem
+	for _, pr := range ps.All() {
+		if pr.Filename == fpath {
+			probs = append(probs, pr)
+		}
+	}
+	return probs
+}
+
+// Counts returns the number of problems at each severity.
+func (ps *Problems) Counts() [ProblemSeveritiesN]int {
+	var n [ProblemSeveritiesN]int
+	for _, pr := range ps.All() {
+		n[pr.Severity]++
+	}
+	return n
+}
+
+////////  Markers
+
+// problemColor returns the line number gutter color for given severity,
+// which is distinct from the [DebugBreakColors].
+func problemColor(sev ProblemSeverities) image.Image {
+	switch sev {
+	case ProblemWarning:
+		return colors.Scheme.Warn.Container
+	case ProblemInfo:
+		return colors.Scheme.Primary.Container
+	}
+	return colors.Scheme.Error.Container
+}
+
+// problemToken returns the tag token used to mark problems of given severity.
+func problemToken(sev ProblemSeverities) token.Tokens {
+	if sev == ProblemError {
+		return token.TextStyleError
+	}
+	return token.TextStyleEmph
+}
+
+// ClearProblemMarkers removes all problem markers from given lines,
+// leaving any other markers in place, e.g., breakpoints and spelling errors.
+func ClearProblemMarkers(ln *lines.Lines) {
+	clearGutterMarkers(ln, problemMarker)
+	clearMarkerTags(ln, problemMarker)
+}
+
+// SetProblemMarkers marks the given problems in the line number gutter
+// and text of given lines, replacing any existing problem markers.
+// The most severe problem on a line determines its gutter color,
+// breakpoints take precedence over problems, and problems over coverage.
+func SetProblemMarkers(ln *lines.Lines, probs []*Problem) {
+	ClearProblemMarkers(ln)
+	nln := ln.NumLines()
+	sevs := map[int]ProblemSeverities{}
+	for _, pr := range probs {
+		li := pr.Line - 1
+		if li < 0 || li >= nln {
+			continue
+		}
+		if sev, ok := sevs[li]; !ok || pr.Severity < sev {
+			sevs[li] = pr.Severity
+		}
+		txt := ln.Line(li)
+		st := max(pr.Col-1, 0)
+		if pr.Col == 0 {
+			for st < len(txt) && (txt[st] == ' ' || txt[st] == '\t') {
+				st++
+			}
+		}
+		if st >= len(txt) {
+			continue
+		}
+		ed := st
+		for ed < len(txt) && isIdentRune(txt[ed]) {
+			ed++
+		}
+		if ed == st {
+			ed = len(txt)
+		}
+		addMarkerTag(ln, li, st, ed, problemMarker, problemToken(pr.Severity))
+	}
+	for li, sev := range sevs {
+		if _, has := ln.LineColor(li); !has || gutterMarkerKind(ln, li) == coverageMarker {
+			setGutterMarker(ln, li, problemMarker, problemColor(sev))
+		}
+	}
+}
+
+////////  Code
+
+// SetProblems sets the problems reported by given source command,
+// and updates the markers in open files and the Problems panel.
+// It can be called from any goroutine.
+func (cv *Code) SetProblems(source string, probs []*Problem) {
+	files := cv.Problems.Set(source, probs)
+	if cv.Output != nil { // no GUI
+		return
+	}
+	go func() { // avoid deadlock when called from the event loop
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+		cv.updateProblems(files)
+	}()
+}
+
+// SetFileProblems sets the problems reported by given source in given
+// file, e.g., by a language server, and updates the markers in the file
+// if it is open and the Problems panel. It can be called from any goroutine.
+func (cv *Code) SetFileProblems(source, fpath string, probs []*Problem) {
+	cv.Problems.SetFile(source, fpath, probs)
+	if cv.Output != nil { // no GUI
+		return
+	}
+	go func() { // avoid deadlock when called from the event loop
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+		cv.updateProblems([]string{fpath})
+	}()
+}
+
+// ClearProblems removes all problems and their markers.
+func (cv *Code) ClearProblems() { //types:add
+	cv.updateProblems(cv.Problems.Clear())
+}
+
+// updateProblems updates the markers for given files if they are
+// open, and the Problems panel if it is open.
+func (cv *Code) updateProblems(files []string) {
+	for _, fpath := range files {
+		ln := cv.GetOpenFile(fpath)
+		if ln == nil {
+			continue
+		}
+		SetProblemMarkers(ln, cv.Problems.ForFile(fpath))
+		cv.coverageOpened(ln)
+		if ed, _, ok := cv.EditorForLines(ln); ok {
+			ed.NeedsRender()
+		}
+	}
+	if pp := cv.problemsPanel(); pp != nil {
+		pp.UpdateProblems()
+	}
+}
+
+// problemsOpened applies any problem markers to newly opened lines.
+func (cv *Code) problemsOpened(ln *lines.Lines) {
+	probs := cv.Problems.ForFile(ln.Filename())
+	if len(probs) > 0 {
+		SetProblemMarkers(ln, probs)
+	}
+}
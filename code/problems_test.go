@@ -0,0 +1,120 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"image/color"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProblems(t *testing.T) {
+	dir := filepath.FromSlash("/proj/pkg")
+	out := `cd /proj/pkg (from: {FileDirPath})
+# example.com/pkg
+./main.go:12:5: undefined: foo
+./main.go:12:5: undefined: foo
+vet: util.go:3: unreachable code
+    main_test.go:40: got 2, want 3
+/abs/other.go:7:1: syntax error
+not a problem: here
+`
+	probs := ParseProblems(regexp.MustCompile(GoProblemRegexp), out, dir, "Go: Build")
+	if assert.Len(t, probs, 4) {
+		assert.Equal(t, Problem{Filename: filepath.Join(dir, "main.go"), Line: 12, Col: 5, Message: "undefined: foo", Source: "Go: Build"}, *probs[0])
+		assert.Equal(t, filepath.Join(dir, "util.go"), probs[1].Filename)
+		assert.Equal(t, 0, probs[1].Col)
+		assert.Equal(t, "got 2, want 3", probs[2].Message)
+		assert.Equal(t, "/abs/other.go", probs[3].Filename)
+	}
+
+	// test logs are not problems, as the test can pass
+	probs = ParseProblems(regexp.MustCompile(GoTestProblemRegexp), out, dir, "Go: Test")
+	if assert.Len(t, probs, 2) {
+		assert.Equal(t, "undefined: foo", probs[0].Message)
+		assert.Equal(t, "/abs/other.go", probs[1].Filename)
+	}
+
+	re := regexp.MustCompile(`^(?P<file>[^:]+):(?P<line>\d+): (?P<severity>\w+): (?P<msg>.*)$`)
+	probs = ParseProblems(re, "a.c:1: warning: unused\na.c:2: note: here\na.c:3: error: bad", dir, "Make")
+	if assert.Len(t, probs, 3) {
+		assert.Equal(t, ProblemWarning, probs[0].Severity)
+		assert.Equal(t, ProblemInfo, probs[1].Severity)
+		assert.Equal(t, ProblemError, probs[2].Severity)
+	}
+
+	assert.Nil(t, ParseProblems(regexp.MustCompile(`(?P<file>\S+)`), "a.go", dir, "x"))
+}
+
+func TestProblems(t *testing.T) {
+	ps := Problems{}
+	a := &Problem{Filename: "/a.go", Line: 5}
+	b := &Problem{Filename: "/b.go", Line: 2, Severity: ProblemWarning}
+	c := &Problem{Filename: "/a.go", Line: 1}
+	assert.Equal(t, []string{"/a.go", "/b.go"}, ps.Set("build", []*Problem{a, b}))
+	assert.Equal(t, []string{"/a.go"}, ps.Set("vet", []*Problem{c}))
+	assert.Equal(t, []*Problem{c, a, b}, ps.All())
+	assert.Equal(t, []*Problem{c, a}, ps.ForFile("/a.go"))
+	assert.Equal(t, [ProblemSeveritiesN]int{2, 1, 0}, ps.Counts())
+
+	// rerunning replaces the problems for that command only
+	assert.Equal(t, []string{"/a.go", "/b.go"}, ps.Set("build", nil))
+	assert.Equal(t, []*Problem{c}, ps.All())
+	assert.Equal(t, []string{"/a.go"}, ps.Clear())
+	assert.Empty(t, ps.All())
+
+	// language servers replace the problems for one file at a time
+	ps.SetFile("gopls", "/a.go", []*Problem{a, c})
+	ps.SetFile("gopls", "/b.go", []*Problem{b})
+	ps.SetFile("gopls", "/a.go", []*Problem{c})
+	assert.Equal(t, []*Problem{c, b}, ps.All())
+	ps.SetFile("gopls", "/a.go", nil)
+	ps.SetFile("gopls", "/b.go", nil)
+	assert.Empty(t, ps.All())
+}
+
+func TestProblemMarkers(t *testing.T) {
+	ln := lines.NewLines()
+	ln.SetText([]byte("package main\n\nfunc main() {\n\tfoo()\n}\n"))
+	ln.SetLineColor(2, DebugBreakColors[DebugBreakActive])
+	ln.AddTag(0, 0, 7, token.TextStyleError) // e.g., a spelling error
+	SetProblemMarkers(ln, []*Problem{{Line: 3, Col: 1, Severity: ProblemWarning}, {Line: 4}})
+
+	clr, _ := ln.LineColor(2)
+	assert.Equal(t, DebugBreakColors[DebugBreakActive], clr) // breakpoint kept
+	clr, _ = ln.LineColor(3)
+	assert.Equal(t, problemColor(ProblemError), clr)
+	tags := ln.AdjustedTags(3)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, token.TextStyleError, tags[0].Token.Token)
+		assert.Equal(t, 1, tags[0].Start)
+		assert.Equal(t, 4, tags[0].End)
+	}
+	assert.Len(t, ln.AdjustedTags(2), 1)
+
+	ClearProblemMarkers(ln)
+	_, has := ln.LineColor(2)
+	assert.True(t, has)
+	_, has = ln.LineColor(3)
+	assert.False(t, has)
+	assert.Empty(t, ln.AdjustedTags(2))
+	assert.Empty(t, ln.AdjustedTags(3))
+	assert.Len(t, ln.AdjustedTags(0), 1) // not a problem marker
+
+	// markers are found by kind, not color, e.g., after a theme change
+	SetProblemMarkers(ln, []*Problem{{Line: 4}})
+	ln.SetLineColor(3, colors.Uniform(color.Black))
+	assert.Len(t, ln.AdjustedTags(3), 1)
+	ClearProblemMarkers(ln)
+	_, has = ln.LineColor(3)
+	assert.True(t, has) // replaced by another marker
+	assert.Empty(t, ln.AdjustedTags(3))
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/tree"
+)
+
+// ProblemsPanel is a widget that displays the problems reported
+// by commands, grouped by file, with links to their locations.
+type ProblemsPanel struct {
+	core.Frame
+
+	// parent code project
+	Code *Code `json:"-" xml:"-"`
+}
+
+func (pv *ProblemsPanel) Init() {
+	pv.Frame.Init()
+	pv.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 1)
+	})
+
+	tree.AddChildAt(pv, "problemsbar", func(w *core.Toolbar) {
+		w.Maker(pv.makeToolbar)
+	})
+	tree.AddChildAt(pv, "problemstext", func(w *textcore.Editor) {
+		ConfigOutputTextEditor(w)
+		w.Styler(func(s *styles.Style) {
+			w.AutoscrollOnInput = false
+		})
+		w.LinkHandler = func(tl *rich.Hyperlink) {
+			pv.Code.OpenFileURL(tl.URL, nil)
+		}
+	})
+}
+
+func (pv *ProblemsPanel) OnAdd() {
+	pv.Frame.OnAdd()
+	pv.Code, _ = ParentCode(pv)
+}
+
+func (pv *ProblemsPanel) makeToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			n := pv.Code.Problems.Counts()
+			w.SetText(fmt.Sprintf("Errors: %d  Warnings: %d  Info: %d", n[ProblemError], n[ProblemWarning], n[ProblemInfo]))
+		})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Clear").SetIcon(icons.ClearAll).
+			SetTooltip("clear all problems and their markers in the editors").
+			OnClick(func(e events.Event) {
+				pv.Code.ClearProblems()
+			})
+	})
+}
+
+// TextEditor returns the problems text editor.
+func (pv *ProblemsPanel) TextEditor() *textcore.Editor {
+	return textcore.AsEditor(pv.ChildByName("problemstext", 1))
+}
+
+// problemURL returns the file:/// url for the location of given problem.
+func problemURL(pr *Problem) string {
+	if pr.Col > 0 {
+		return fmt.Sprintf("file:///%s#L%dC%d", pr.Filename, pr.Line, pr.Col)
+	}
+	return fmt.Sprintf("file:///%s#L%d", pr.Filename, pr.Line)
+}
+
+// UpdateProblems shows the current problems of the Code project.
+func (pv *ProblemsPanel) UpdateProblems() {
+	cv := pv.Code
+	ptv := pv.TextEditor()
+	pbuf := ptv.Lines
+	sty := pbuf.FontStyle()
+	bold := sty.Clone().SetWeight(rich.Bold)
+	link := sty.Clone().SetLinkStyle()
+	sevSty := [ProblemSeveritiesN]*rich.Style{
+		sty.Clone().SetWeight(rich.Bold).SetFillColor(colors.ToUniform(colors.Scheme.Error.Base)),
+		sty.Clone().SetWeight(rich.Bold).SetFillColor(colors.ToUniform(colors.Scheme.Warn.Base)),
+		sty.Clone().SetWeight(rich.Bold).SetFillColor(colors.ToUniform(colors.Scheme.Primary.Base)),
+	}
+	pbuf.Settings.LineNumbers = false
+	outlns := make([][]rune, 0, 100)
+	outmus := make([]rich.Text, 0, 100) // markups
+	probs := cv.Problems.All()
+	for i, pr := range probs {
+		fn := cv.FileTreeFor(pr.Filename).RelativePathFrom(fsx.Filename(pr.Filename))
+		if i == 0 || probs[i-1].Filename != pr.Filename {
+			n := 1
+			for _, op := range probs[i+1:] {
+				if op.Filename != pr.Filename {
+					break
+				}
+				n++
+			}
+			lstr := []rune(fmt.Sprintf("%v: %v", fn, n))
+			outlns = append(outlns, []rune{}, lstr)
+			outmus = append(outmus, rich.NewText(sty, []rune{}), rich.NewText(bold, lstr))
+		}
+		posstr := fmt.Sprintf("%d", pr.Line)
+		if pr.Col > 0 {
+			posstr += fmt.Sprintf(":%d", pr.Col)
+		}
+		locstr := fmt.Sprintf("\t%v:%v: ", fn, posstr)
+		sevstr := pr.Severity.String() + ": "
+		msgstr := fmt.Sprintf("%v (%v)", pr.Message, pr.Source)
+		outlns = append(outlns, []rune(locstr+sevstr+msgstr))
+		mu := rich.Text{}
+		mu.AddSpan(sty, []rune("\t")).AddLink(link, problemURL(pr), locstr[1:]).
+			AddSpan(sevSty[pr.Severity], []rune(sevstr)).AddSpan(sty, []rune(msgstr))
+		outmus = append(outmus, mu)
+	}
+	pbuf.SetReadOnly(true)
+	pbuf.SetText(nil)
+	pbuf.AppendTextMarkup(outlns, outmus)
+	ptv.CursorStartDoc()
+	pv.Update()
+}
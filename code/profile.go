@@ -0,0 +1,469 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"cmp"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"cogentcore.org/core/base/datasize"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/paint/render"
+	"cogentcore.org/core/styles/sides"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/textpos"
+	"github.com/google/pprof/profile"
+)
+
+// ProfileCost is the cost of a function or line in a [Profile]:
+// the value of the samples in it, and in it and everything it calls.
+type ProfileCost struct {
+
+	// Flat is the value of the samples in the function or line itself.
+	Flat int64
+
+	// Cum is the cumulative value of the samples in the function
+	// or line and everything that it calls.
+	Cum int64
+}
+
+// ProfileFunc is a function in a [Profile], with its cost.
+type ProfileFunc struct {
+
+	// Name is the full name of the function, e.g., net/http.(*Server).Serve.
+	Name string `width:"60"`
+
+	// Flat is the cost of the function itself.
+	Flat string
+
+	// FlatPct is the flat cost as a percent of the total.
+	FlatPct float32 `format:"%.1f%%"`
+
+	// Cum is the cumulative cost of the function and everything it calls.
+	Cum string
+
+	// CumPct is the cumulative cost as a percent of the total.
+	CumPct float32 `format:"%.1f%%"`
+
+	// File is the base name of the file the function is in.
+	File string
+
+	// Line is the 1-based line that the function starts on.
+	Line int
+
+	// FPath is the absolute path to the file, "" if not in the project.
+	FPath string `table:"-"`
+
+	// Cost is the cost of the function.
+	Cost ProfileCost `table:"-"`
+}
+
+// ProfileNode is a node in the call tree of a [Profile],
+// for a function called along a given path from the root.
+type ProfileNode struct {
+
+	// Name is the full name of the function.
+	Name string
+
+	// FPath is the absolute path to the file of the function,
+	// "" if not in the project.
+	FPath string
+
+	// Line is the 1-based line that the function starts on.
+	Line int
+
+	// Cost is the cost of the function along this path.
+	Cost ProfileCost
+
+	// Children are the functions called from this node,
+	// sorted by cumulative cost.
+	Children []*ProfileNode
+}
+
+// Depth returns the depth of the call tree below the node,
+// which is 0 if it has no children.
+func (pn *ProfileNode) Depth() int {
+	d := 0
+	for _, ch := range pn.Children {
+		d = max(d, ch.Depth()+1)
+	}
+	return d
+}
+
+// child returns the child for the given function, adding it if needed.
+func (pn *ProfileNode) child(name, fpath string, line int) *ProfileNode {
+	for _, ch := range pn.Children {
+		if ch.Name == name {
+			return ch
+		}
+	}
+	ch := &ProfileNode{Name: name, FPath: fpath, Line: line}
+	pn.Children = append(pn.Children, ch)
+	return ch
+}
+
+// sort sorts the children of the node and all of its descendants.
+func (pn *ProfileNode) sort() {
+	slices.SortStableFunc(pn.Children, func(a, b *ProfileNode) int {
+		return cmp.Compare(b.Cost.Cum, a.Cost.Cum)
+	})
+	for _, ch := range pn.Children {
+		ch.sort()
+	}
+}
+
+// Profile is a CPU or memory profile in the pprof format, as written by
+// go test -cpuprofile or -memprofile, or by runtime/pprof, summarized for
+// one of its sample types by function, by call tree, and by source line.
+type Profile struct {
+
+	// Filename is the file the profile was opened from.
+	Filename string
+
+	// SampleTypes are the types of values in the samples, e.g., samples
+	// and cpu for a CPU profile, or alloc_space and inuse_space for a
+	// memory profile.
+	SampleTypes []string
+
+	// SampleType is the index of the sample type that is summarized.
+	SampleType int
+
+	// Unit is the unit of the values of the sample type,
+	// e.g., nanoseconds or bytes.
+	Unit string
+
+	// Total is the total value of all of the samples.
+	Total int64
+
+	// Funcs are the functions in the profile, sorted by flat cost.
+	Funcs []*ProfileFunc
+
+	// Root is the root of the call tree, with the total cost, whose
+	// children are the functions at the bottom of the stacks.
+	Root *ProfileNode
+
+	// Lines are the costs of the lines in each file,
+	// by absolute path and 1-based line.
+	Lines map[string]map[int]ProfileCost
+
+	// prof is the parsed profile.
+	prof *profile.Profile
+
+	// path returns the absolute path for a file in the profile.
+	path func(file string) string
+}
+
+// OpenProfile opens the pprof profile in given file, finding the
+// files in the module containing given directory.
+func OpenProfile(fname, dir string) (*Profile, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := profile.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", fname, err)
+	}
+	root, mod := findModule(dir)
+	pr := NewProfile(p, func(file string) string {
+		switch {
+		case filepath.IsAbs(file):
+			return file
+		case mod != "" && strings.HasPrefix(file, mod+"/"):
+			return filepath.Join(root, filepath.FromSlash(file[len(mod)+1:]))
+		}
+		return "" // not in this module, e.g., with -trimpath
+	})
+	pr.Filename = fname
+	return pr, nil
+}
+
+// NewProfile returns the given parsed profile summarized for its default
+// sample type, using given function to get the absolute path for each file
+// in the profile, which returns "" for files that are not available.
+func NewProfile(p *profile.Profile, path func(file string) string) *Profile {
+	pr := &Profile{prof: p, path: path}
+	pr.SampleType = len(p.SampleType) - 1
+	for i, st := range p.SampleType {
+		pr.SampleTypes = append(pr.SampleTypes, st.Type)
+		if st.Type == p.DefaultSampleType {
+			pr.SampleType = i
+		}
+	}
+	pr.SetSampleType(pr.SampleType)
+	return pr
+}
+
+// profileFrame is a function call in a sample, at a given line.
+type profileFrame struct {
+	name  string
+	fpath string
+	start int
+	line  int
+}
+
+// frames returns the frames of the given sample, from the root of the
+// stack to the leaf, including inlined functions.
+func (pr *Profile) frames(s *profile.Sample) []profileFrame {
+	var fs []profileFrame
+	for i := len(s.Location) - 1; i >= 0; i-- {
+		loc := s.Location[i]
+		for j := len(loc.Line) - 1; j >= 0; j-- { // last is the caller of inlined ones
+			ln := loc.Line[j]
+			fr := profileFrame{line: int(ln.Line)}
+			if fn := ln.Function; fn != nil {
+				fr.name, fr.start = fn.Name, int(fn.StartLine)
+				if fn.Filename != "" {
+					fr.fpath = pr.path(fn.Filename)
+				}
+			}
+			if fr.name == "" {
+				fr.name = fmt.Sprintf("%#x", loc.Address)
+			}
+			fs = append(fs, fr)
+		}
+	}
+	return fs
+}
+
+// SetSampleType summarizes the profile for the sample type with given index.
+func (pr *Profile) SetSampleType(idx int) {
+	if idx < 0 || idx >= len(pr.prof.SampleType) {
+		return
+	}
+	pr.SampleType = idx
+	pr.Unit = pr.prof.SampleType[idx].Unit
+	pr.Total = 0
+	pr.Root = &ProfileNode{Name: "root"}
+	pr.Lines = map[string]map[int]ProfileCost{}
+	funcs := map[string]*ProfileFunc{}
+	type fileLine struct {
+		fpath string
+		line  int
+	}
+	for _, s := range pr.prof.Sample {
+		v := s.Value[idx]
+		if v == 0 {
+			continue
+		}
+		pr.Total += v
+		fs := pr.frames(s)
+		nd := pr.Root
+		nd.Cost.Cum += v
+		seenFunc := map[string]bool{}
+		seenLine := map[fileLine]bool{}
+		for i, fr := range fs {
+			leaf := i == len(fs)-1
+			nd = nd.child(fr.name, fr.fpath, fr.start)
+			nd.Cost.Cum += v
+			fn := funcs[fr.name]
+			if fn == nil {
+				fn = &ProfileFunc{Name: fr.name, FPath: fr.fpath, Line: fr.start}
+				if fr.fpath != "" {
+					fn.File = filepath.Base(fr.fpath)
+				}
+				funcs[fr.name] = fn
+			}
+			if !seenFunc[fr.name] {
+				seenFunc[fr.name] = true
+				fn.Cost.Cum += v
+			}
+			if leaf {
+				nd.Cost.Flat += v
+				fn.Cost.Flat += v
+			}
+			if fr.fpath == "" || fr.line <= 0 {
+				continue
+			}
+			fl := fileLine{fr.fpath, fr.line}
+			lc := pr.Lines[fr.fpath]
+			if lc == nil {
+				lc = map[int]ProfileCost{}
+				pr.Lines[fr.fpath] = lc
+			}
+			c := lc[fr.line]
+			if !seenLine[fl] {
+				seenLine[fl] = true
+				c.Cum += v
+			}
+			if leaf {
+				c.Flat += v
+			}
+			lc[fr.line] = c
+		}
+	}
+	pr.Root.sort()
+	pr.Funcs = make([]*ProfileFunc, 0, len(funcs))
+	for _, fn := range funcs {
+		fn.Flat = pr.FormatValue(fn.Cost.Flat)
+		fn.Cum = pr.FormatValue(fn.Cost.Cum)
+		fn.FlatPct = pr.Percent(fn.Cost.Flat)
+		fn.CumPct = pr.Percent(fn.Cost.Cum)
+		pr.Funcs = append(pr.Funcs, fn)
+	}
+	slices.SortFunc(pr.Funcs, func(a, b *ProfileFunc) int {
+		return cmp.Or(cmp.Compare(b.Cost.Flat, a.Cost.Flat), cmp.Compare(b.Cost.Cum, a.Cost.Cum), strings.Compare(a.Name, b.Name))
+	})
+}
+
+// Func returns the function with given full name, or nil if there is none.
+func (pr *Profile) Func(name string) *ProfileFunc {
+	for _, fn := range pr.Funcs {
+		if fn.Name == name {
+			return fn
+		}
+	}
+	return nil
+}
+
+// Percent returns the given value as a percent of the total.
+func (pr *Profile) Percent(v int64) float32 {
+	if pr.Total == 0 {
+		return 0
+	}
+	return 100 * float32(v) / float32(pr.Total)
+}
+
+// FormatValue returns the given value of the sample type
+// formatted for its unit, e.g., 1.2s or 3.4 MB.
+func (pr *Profile) FormatValue(v int64) string {
+	switch pr.Unit {
+	case "nanoseconds":
+		d := time.Duration(v)
+		switch {
+		case d >= time.Second:
+			return d.Round(10 * time.Millisecond).String()
+		case d >= time.Millisecond:
+			return d.Round(10 * time.Microsecond).String()
+		}
+		return d.String()
+	case "bytes":
+		if v < 0 { // diff profile
+			return "-" + datasize.Size(-v).String()
+		}
+		return datasize.Size(v).String()
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// LineCost returns the cost of the given 1-based line of the file
+// with given absolute path, and false if it has none.
+func (pr *Profile) LineCost(fpath string, line int) (ProfileCost, bool) {
+	c, ok := pr.Lines[fpath][line]
+	return c, ok
+}
+
+// HasFile returns whether there are costs for the file with given absolute path.
+func (pr *Profile) HasFile(fpath string) bool {
+	return len(pr.Lines[fpath]) > 0
+}
+
+////////  Gutter
+
+// profileChars is the width of the profile gutter text, in characters.
+const profileChars = 16
+
+// profileGutterChars returns the width of the profile gutter in
+// characters, which is 0 if there is no profile for the file.
+func (ed *TextEditor) profileGutterChars() float32 {
+	if ed.Code == nil || ed.Code.Profile == nil || ed.Lines == nil || !ed.Code.Profile.HasFile(ed.Lines.Filename()) {
+		return 0
+	}
+	return profileChars + 1
+}
+
+// profileLabel returns the label of given line cost in the profile gutter,
+// with the flat and cumulative cost, and . for no flat cost, as in pprof.
+func (pr *Profile) profileLabel(c ProfileCost) string {
+	flat := "."
+	if c.Flat != 0 {
+		flat = pr.FormatValue(c.Flat)
+	}
+	return fmt.Sprintf("%7s %7s", flat, pr.FormatValue(c.Cum))
+}
+
+// profileTooltip returns the tooltip for the profile gutter at given position.
+func (ed *TextEditor) profileTooltip(pos image.Point) string {
+	pgc := ed.profileGutterChars()
+	if pgc == 0 {
+		return ""
+	}
+	pt := ed.PointToRelPos(pos)
+	chw := ed.Styles.Padding.Left.Dots / (pgc + ed.vcsGutterChars())
+	if float32(pt.X) >= -ed.vcsGutterChars()*chw {
+		return ""
+	}
+	pr := ed.Code.Profile
+	c, ok := pr.LineCost(ed.Lines.Filename(), ed.PixelToCursor(pt).Line+1)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("flat %s (%.1f%%), cum %s (%.1f%%)", pr.FormatValue(c.Flat), pr.Percent(c.Flat), pr.FormatValue(c.Cum), pr.Percent(c.Cum))
+}
+
+// renderProfileGutter renders the flat and cumulative cost of each line in
+// the profile gutter, to the left of the version control gutter, with a bar
+// showing the cumulative cost relative to the total.
+func (ed *TextEditor) renderProfileGutter() {
+	pgc := ed.profileGutterChars()
+	pc := &ed.Scene.Painter
+	if pgc == 0 || pc.State == nil || !ed.IsVisible() {
+		return
+	}
+	lht := ed.Styles.LineHeightDots()
+	gw := ed.Styles.Padding.Left.Dots
+	if lht <= 0 || gw <= 0 {
+		return
+	}
+	chw := gw / (pgc + ed.vcsGutterChars())
+	cpos := ed.Geom.Pos.Content
+	csz := ed.Geom.Size.Actual.Content
+	x0 := cpos.X - gw
+	var scroll float32
+	if ed.HasScroll[math32.Y] && ed.Scrolls[math32.Y] != nil {
+		scroll = ed.Scrolls[math32.Y].Value / lht
+	}
+	frac := scroll - math32.Floor(scroll)
+
+	pc.PushContext(nil, render.NewBoundsRect(ed.Geom.TotalBBox, sides.NewFloats()))
+	defer pc.PopContext()
+	sh := ed.Scene.TextShaper()
+	sty, tsty := ed.Styles.NewRichText()
+	sty.SetBackground(nil)
+	sty.SetFillColor(colors.ToUniform(colors.Scheme.OnSurfaceVariant))
+	pr := ed.Code.Profile
+	fname := ed.Lines.Filename()
+	lastln := -1
+	for r := 0; ; r++ {
+		y := (float32(r) - frac) * lht
+		if y >= csz.Y {
+			break
+		}
+		tp := ed.PixelToCursor(image.Pt(0, int(y+0.5*lht)))
+		if tp == textpos.PosErr || tp.Line == lastln {
+			continue // wrapped line
+		}
+		lastln = tp.Line
+		c, ok := pr.LineCost(fname, tp.Line+1)
+		if !ok {
+			continue
+		}
+		top := cpos.Y + y
+		pc.Fill.Color = colors.Scheme.Warn.Container
+		pc.Rectangle(x0, top, profileChars*chw*min(pr.Percent(c.Cum), 100)/100, lht)
+		pc.Draw()
+		tx := rich.NewText(sty, []rune(pr.profileLabel(c)))
+		lns := sh.WrapLines(tx, sty, tsty, &rich.DefaultSettings, math32.Vec2(profileChars*chw, lht))
+		pc.DrawText(lns, math32.Vec2(x0, top))
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProfile returns a CPU profile of main calling work, which calls
+// sum, which is inlined, and of main calling the runtime.
+func testProfile() *profile.Profile {
+	fn := func(id uint64, name, file string, start int64) *profile.Function {
+		return &profile.Function{ID: id, Name: name, SystemName: name, Filename: file, StartLine: start}
+	}
+	main := fn(1, "main.main", "example.com/m/main.go", 3)
+	work := fn(2, "main.work", "example.com/m/main.go", 10)
+	sum := fn(3, "main.sum", "example.com/m/main.go", 20)
+	gc := fn(4, "runtime.gcBgMarkWorker", "runtime/mgc.go", 100)
+	loc := func(id uint64, lines ...profile.Line) *profile.Location {
+		return &profile.Location{ID: id, Address: id * 0x10, Line: lines}
+	}
+	lmain := loc(1, profile.Line{Function: main, Line: 5})
+	lwork := loc(2, profile.Line{Function: sum, Line: 21}, profile.Line{Function: work, Line: 12})
+	lwork2 := loc(3, profile.Line{Function: work, Line: 13})
+	lgc := loc(4, profile.Line{Function: gc, Line: 110})
+	return &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Sample: []*profile.Sample{
+			{Location: []*profile.Location{lwork, lmain}, Value: []int64{3, 30e6}},
+			{Location: []*profile.Location{lwork2, lmain}, Value: []int64{1, 10e6}},
+			{Location: []*profile.Location{lgc}, Value: []int64{6, 60e6}},
+		},
+		Location:   []*profile.Location{lmain, lwork, lwork2, lgc},
+		Function:   []*profile.Function{main, work, sum, gc},
+		PeriodType: &profile.ValueType{Type: "cpu", Unit: "nanoseconds"},
+		Period:     10e6,
+	}
+}
+
+func TestProfile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0666))
+	fname := filepath.Join(dir, "cpu.pprof")
+	f, err := os.Create(fname)
+	require.NoError(t, err)
+	require.NoError(t, testProfile().Write(f))
+	require.NoError(t, f.Close())
+
+	pr, err := OpenProfile(fname, dir)
+	require.NoError(t, err)
+	mainGo := filepath.Join(dir, "main.go")
+	assert.Equal(t, []string{"samples", "cpu"}, pr.SampleTypes)
+	assert.Equal(t, 1, pr.SampleType)
+	assert.Equal(t, int64(100e6), pr.Total)
+	assert.Equal(t, "100ms", pr.FormatValue(pr.Total))
+
+	require.Len(t, pr.Funcs, 4)
+	gc := pr.Funcs[0]
+	assert.Equal(t, "runtime.gcBgMarkWorker", gc.Name)
+	assert.Equal(t, "", gc.FPath) // not in the module
+	assert.Equal(t, float32(60), gc.FlatPct)
+	sum := pr.Func("main.sum")
+	require.NotNil(t, sum)
+	assert.Equal(t, ProfileCost{Flat: 30e6, Cum: 30e6}, sum.Cost)
+	assert.Equal(t, "30ms", sum.Flat)
+	assert.Equal(t, mainGo, sum.FPath)
+	assert.Equal(t, "main.go", sum.File)
+	assert.Equal(t, 20, sum.Line)
+	work := pr.Func("main.work")
+	assert.Equal(t, ProfileCost{Flat: 10e6, Cum: 40e6}, work.Cost)
+	assert.Equal(t, float32(40), work.CumPct)
+
+	require.Len(t, pr.Root.Children, 2)
+	assert.Equal(t, "runtime.gcBgMarkWorker", pr.Root.Children[0].Name)
+	mn := pr.Root.Children[1]
+	assert.Equal(t, ProfileCost{Cum: 40e6}, mn.Cost)
+	require.Len(t, mn.Children, 1)
+	assert.Equal(t, "main.sum", mn.Children[0].Children[0].Name)
+	assert.Equal(t, 3, pr.Root.Depth())
+
+	c, ok := pr.LineCost(mainGo, 12)
+	assert.True(t, ok)
+	assert.Equal(t, ProfileCost{Cum: 30e6}, c)
+	c, _ = pr.LineCost(mainGo, 5)
+	assert.Equal(t, ProfileCost{Cum: 40e6}, c)
+	c, _ = pr.LineCost(mainGo, 21)
+	assert.Equal(t, ProfileCost{Flat: 30e6, Cum: 30e6}, c)
+	_, ok = pr.LineCost(mainGo, 6)
+	assert.False(t, ok)
+	assert.True(t, pr.HasFile(mainGo))
+	assert.Equal(t, "      .    40ms", pr.profileLabel(ProfileCost{Cum: 40e6}))
+
+	pr.SetSampleType(0)
+	assert.Equal(t, int64(10), pr.Total)
+	assert.Equal(t, "10", pr.FormatValue(pr.Total))
+	assert.Equal(t, int64(6), pr.Funcs[0].Cost.Flat)
+
+	_, err = OpenProfile(filepath.Join(dir, "go.mod"), dir)
+	assert.Error(t, err)
+}
+
+func TestProfilePanel(t *testing.T) {
+	b := core.NewBody()
+	pp := NewProfilePanel(b)
+	pp.Code = &Code{}
+	pp.Code.Profile = NewProfile(testProfile(), func(file string) string { return "/m/" + filepath.Base(file) })
+	pp.Update()
+	pp.setProfile()
+	assert.Len(t, pp.funcs, 4)
+	fg := pp.FlameGraph()
+	assert.Equal(t, pp.Code.Profile.Root, fg.Root)
+	ct := pp.CallTree()
+	assert.Equal(t, "root  100ms (100.0%)", ct.Text)
+	require.Equal(t, 2, ct.NumChildren())
+	mn := ct.Child(1).(*ProfileTree)
+	assert.Equal(t, "main.main  40ms (40.0%)", mn.Text)
+	assert.True(t, mn.CanOpen())
+	mn.OnOpen()
+	assert.Equal(t, 1, mn.NumChildren())
+	assert.False(t, ct.Child(0).(*ProfileTree).CanOpen()) // runtime.gcBgMarkWorker calls nothing
+
+	pp.Code.Profile = nil
+	pp.setProfile()
+	assert.Empty(t, pp.funcs)
+	assert.Nil(t, fg.Root)
+	assert.Equal(t, 0, ct.NumChildren())
+}
@@ -0,0 +1,470 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"image"
+	"path/filepath"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/paint/render"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/styles/abilities"
+	"cogentcore.org/core/styles/sides"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/textpos"
+	"cogentcore.org/core/tree"
+)
+
+// ProfilePanel is a widget that shows the [Code.Profile]: the top functions
+// by cost in a table, the stacks in a flame graph, and the call tree,
+// where clicking on a function shows its source.
+type ProfilePanel struct {
+	core.Frame
+
+	// parent code project
+	Code *Code `json:"-" xml:"-"`
+
+	// functions shown in the table
+	funcs []*ProfileFunc
+}
+
+func (pp *ProfilePanel) Init() {
+	pp.Frame.Init()
+	pp.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 1)
+	})
+	pp.Updater(func() {
+		pp.funcs = nil
+		if pr := pp.profile(); pr != nil {
+			pp.funcs = pr.Funcs
+		}
+	})
+
+	tree.AddChildAt(pp, "profile-toolbar", func(w *core.Toolbar) {
+		w.Maker(pp.makeToolbar)
+	})
+	tree.AddChildAt(pp, "profile-tabs", func(w *core.Tabs) {
+		w.Styler(func(s *styles.Style) {
+			s.Grow.Set(1, 1)
+		})
+		top, _ := w.NewTab("Top")
+		tree.AddChildAt(top, "funcs", func(w *core.Table) {
+			w.SetReadOnly(true)
+			w.SetSlice(&pp.funcs)
+			w.SetTooltip("the functions with the highest cost; click on a function to show its source")
+			w.OnSelect(func(e events.Event) {
+				if idx := w.SelectedIndex; idx >= 0 && idx < len(pp.funcs) {
+					fn := pp.funcs[idx]
+					pp.showSource(fn.FPath, fn.Line)
+				}
+			})
+		})
+		flame, _ := w.NewTab("Flame graph")
+		tree.AddChildAt(flame, "flame", func(w *FlameGraph) {
+			w.panel = pp
+		})
+		calls, _ := w.NewTab("Call tree")
+		tree.AddChildAt(calls, "call-tree", func(w *ProfileTree) {
+			w.panel = pp
+			w.OnSelect(func(e events.Event) {
+				if len(w.SelectedNodes) == 0 {
+					return
+				}
+				if pt, ok := w.SelectedNodes[0].(*ProfileTree); ok && pt.Node != nil {
+					pp.showSource(pt.Node.FPath, pt.Node.Line)
+				}
+			})
+		})
+	})
+}
+
+func (pp *ProfilePanel) OnAdd() {
+	pp.Frame.OnAdd()
+	pp.Code, _ = ParentCode(pp)
+}
+
+// profile returns the profile shown, nil if none.
+func (pp *ProfilePanel) profile() *Profile {
+	if pp.Code == nil {
+		return nil
+	}
+	return pp.Code.Profile
+}
+
+// Tabs returns the tabs of the panel.
+func (pp *ProfilePanel) Tabs() *core.Tabs {
+	return pp.ChildByName("profile-tabs", 1).(*core.Tabs)
+}
+
+// FlameGraph returns the flame graph.
+func (pp *ProfilePanel) FlameGraph() *FlameGraph {
+	return pp.Tabs().TabByName("Flame graph").ChildByName("flame").(*FlameGraph)
+}
+
+// CallTree returns the root of the call tree.
+func (pp *ProfilePanel) CallTree() *ProfileTree {
+	return pp.Tabs().TabByName("Call tree").ChildByName("call-tree").(*ProfileTree)
+}
+
+func (pp *ProfilePanel) makeToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(pp.Code.ViewProfile).SetText("Open").SetIcon(icons.Open)
+	})
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(pp.Code.ProfileTest).SetText("Profile tests").SetIcon(icons.PlayArrow)
+	})
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(pp.Code.ClearProfile).SetText("Clear").SetIcon(icons.Close)
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(pp.profile() != nil) })
+	})
+	tree.AddAt(p, "sample-type", func(w *core.Chooser) {
+		w.SetTooltip("type of the sample values shown, e.g., cpu, or alloc_space or inuse_space for a memory profile")
+		w.OnChange(func(e events.Event) {
+			if pr := pp.profile(); pr != nil {
+				pr.SetSampleType(w.CurrentItem.Value.(int))
+				pp.Code.updateProfile()
+			}
+		})
+		w.Updater(func() {
+			pr := pp.profile()
+			if pr == nil {
+				w.SetItems()
+				return
+			}
+			items := make([]core.ChooserItem, len(pr.SampleTypes))
+			for i, st := range pr.SampleTypes {
+				items[i] = core.ChooserItem{Value: i, Text: st}
+			}
+			w.SetItems(items...)
+			w.SetCurrentValue(pr.SampleType)
+		})
+	})
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			pr := pp.profile()
+			if pr == nil {
+				w.SetText("No profile")
+				return
+			}
+			w.SetText(fmt.Sprintf("%s: total %s", filepath.Base(pr.Filename), pr.FormatValue(pr.Total)))
+		})
+	})
+}
+
+// setProfile updates the flame graph and call tree for the current profile.
+func (pp *ProfilePanel) setProfile() {
+	var root *ProfileNode
+	if pr := pp.profile(); pr != nil {
+		root = pr.Root
+	}
+	pp.FlameGraph().SetRoot(root)
+	ct := pp.CallTree()
+	ct.DeleteChildren()
+	ct.SelectedNodes = nil
+	ct.setNode(root)
+	ct.expand()
+	ct.SetClosed(false)
+	pp.Update()
+}
+
+// showSource shows the source at the given 1-based line of given file.
+func (pp *ProfilePanel) showSource(fpath string, line int) {
+	if fpath == "" {
+		core.MessageSnackbar(pp, "The source of the function is not in the project")
+		return
+	}
+	ln := max(line-1, 0)
+	pp.Code.OpenFileAtRegion(fpath, textpos.NewRegion(ln, 0, ln, 0))
+}
+
+// ProfileTree is a node in the call tree of a [ProfilePanel],
+// whose children are added when it is first opened.
+type ProfileTree struct {
+	core.Tree
+
+	// node of the call tree
+	Node *ProfileNode `set:"-" json:"-" xml:"-"`
+
+	// panel that the tree is in
+	panel *ProfilePanel
+
+	// whether the children have been added
+	expanded bool
+}
+
+func (pt *ProfileTree) CanOpen() bool {
+	return pt.Node != nil && len(pt.Node.Children) > 0
+}
+
+func (pt *ProfileTree) OnOpen() {
+	pt.expand()
+}
+
+// setNode sets the node of the call tree, and the label.
+func (pt *ProfileTree) setNode(nd *ProfileNode) {
+	pt.Node = nd
+	pt.expanded = false
+	if nd == nil {
+		pt.SetText("No profile")
+		return
+	}
+	pr := pt.rootPanel().profile()
+	lbl := nd.Name
+	if pr != nil {
+		lbl = fmt.Sprintf("%s  %s (%.1f%%)", nd.Name, pr.FormatValue(nd.Cost.Cum), pr.Percent(nd.Cost.Cum))
+	}
+	pt.SetText(lbl).SetIcon(icons.Function)
+}
+
+// rootPanel returns the panel of the root of the tree.
+func (pt *ProfileTree) rootPanel() *ProfilePanel {
+	if pt.Root != nil {
+		return pt.Root.(*ProfileTree).panel
+	}
+	return pt.panel
+}
+
+// expand adds the children of the node, if not already done.
+func (pt *ProfileTree) expand() {
+	if pt.expanded || pt.Node == nil {
+		return
+	}
+	pt.expanded = true
+	for i, ch := range pt.Node.Children {
+		ct := NewProfileTree(pt)
+		ct.SetName(fmt.Sprintf("%d", i))
+		ct.setNode(ch)
+		ct.SetClosed(true)
+	}
+	pt.Update()
+}
+
+// FlameGraph is a widget that shows the call tree of a profile as a flame
+// graph, with the root at the top, and each function below its caller, with
+// a width proportional to its cumulative cost. Clicking on a function shows
+// its source, and double-clicking zooms in on it, or out from the top one.
+type FlameGraph struct {
+	core.WidgetBase
+
+	// Root is the node at the top of the graph, which is
+	// the root of the call tree unless zoomed in.
+	Root *ProfileNode `set:"-" json:"-" xml:"-"`
+
+	// panel that the graph is in
+	panel *ProfilePanel
+
+	// rectangles of the nodes as of the last render, for finding them
+	rects []flameRect
+}
+
+// flameRect is the rectangle of a node in a [FlameGraph].
+type flameRect struct {
+	node *ProfileNode
+	rect math32.Box2
+}
+
+// flameRowHeight is the height of each row of a [FlameGraph], in ems.
+const flameRowHeight = 1.5
+
+func (fg *FlameGraph) Init() {
+	fg.WidgetBase.Init()
+	fg.Styler(func(s *styles.Style) {
+		s.SetAbilities(true, abilities.Clickable, abilities.DoubleClickable, abilities.LongHoverable)
+		s.Grow.Set(1, 0)
+		s.Min.X.Em(20)
+		rows := 1
+		if fg.Root != nil {
+			rows += fg.Root.Depth()
+		}
+		s.Min.Y.Em(flameRowHeight * float32(rows))
+	})
+	fg.OnClick(func(e events.Event) {
+		if nd := fg.nodeAt(e.Pos()); nd != nil && fg.panel != nil {
+			fg.panel.showSource(nd.FPath, nd.Line)
+		}
+	})
+	fg.OnDoubleClick(func(e events.Event) {
+		nd := fg.nodeAt(e.Pos())
+		if nd == nil {
+			return
+		}
+		if nd == fg.Root && fg.panel != nil && fg.panel.profile() != nil {
+			nd = fg.panel.profile().Root
+		}
+		fg.Root = nd
+		fg.Style()
+		fg.NeedsLayout()
+	})
+}
+
+// SetRoot sets the node at the top of the graph.
+func (fg *FlameGraph) SetRoot(nd *ProfileNode) {
+	fg.Root = nd
+	fg.Style()
+	fg.NeedsLayout()
+}
+
+// nodeAt returns the node at the given position, nil if none.
+func (fg *FlameGraph) nodeAt(pos image.Point) *ProfileNode {
+	pt := math32.FromPoint(pos)
+	for _, fr := range fg.rects {
+		if fr.rect.ContainsPoint(pt) {
+			return fr.node
+		}
+	}
+	return nil
+}
+
+func (fg *FlameGraph) WidgetTooltip(pos image.Point) (string, image.Point) {
+	if pos == image.Pt(-1, -1) {
+		return "_", image.Point{}
+	}
+	nd := fg.nodeAt(pos)
+	if nd == nil || fg.panel == nil || fg.panel.profile() == nil {
+		return "", pos
+	}
+	pr := fg.panel.profile()
+	return fmt.Sprintf("%s\ncum %s (%.1f%%), flat %s (%.1f%%)", nd.Name, pr.FormatValue(nd.Cost.Cum), pr.Percent(nd.Cost.Cum),
+		pr.FormatValue(nd.Cost.Flat), pr.Percent(nd.Cost.Flat)), pos
+}
+
+func (fg *FlameGraph) Render() {
+	fg.WidgetBase.Render()
+	fg.rects = fg.rects[:0]
+	if fg.Root == nil || fg.Root.Cost.Cum <= 0 {
+		return
+	}
+	pc := &fg.Scene.Painter
+	pos := fg.Geom.Pos.Content
+	sz := fg.Geom.Size.Actual.Content
+	rowHt := sz.Y / float32(fg.Root.Depth()+1)
+	pc.PushContext(nil, render.NewBoundsRect(fg.Geom.TotalBBox, sides.NewFloats()))
+	defer pc.PopContext()
+	sh := fg.Scene.TextShaper()
+	sty, tsty := fg.Styles.NewRichText()
+	sty.SetBackground(nil)
+	sty.SetFillColor(colors.ToUniform(colors.Scheme.OnSurface))
+	chw := fg.Styles.Font.FontHeight() / 2
+	var draw func(nd *ProfileNode, x, w, y float32)
+	draw = func(nd *ProfileNode, x, w, y float32) {
+		if w < 1 {
+			return // too small to see, and so are its children
+		}
+		rect := math32.Box2{Min: math32.Vec2(x, y), Max: math32.Vec2(x+w, y+rowHt)}
+		fg.rects = append(fg.rects, flameRect{node: nd, rect: rect})
+		pc.Fill.Color = colors.Scheme.SurfaceContainerHighest
+		if nd.FPath != "" {
+			pc.Fill.Color = colors.Scheme.Primary.Container
+		}
+		pc.Stroke.Color = colors.Scheme.Surface
+		pc.Rectangle(x, y, w, rowHt)
+		pc.Draw()
+		pc.Stroke.Color = nil
+		if w > 4*chw {
+			tx := rich.NewText(sty, []rune(nd.Name))
+			lns := sh.WrapLines(tx, sty, tsty, &rich.DefaultSettings, math32.Vec2(w-chw, rowHt))
+			pc.DrawText(lns, math32.Vec2(x+chw/2, y))
+		}
+		cx := x
+		for _, ch := range nd.Children {
+			cw := w * float32(ch.Cost.Cum) / float32(nd.Cost.Cum)
+			draw(ch, cx, cw, y+rowHt)
+			cx += cw
+		}
+	}
+	draw(fg.Root, pos.X, sz.X, pos.Y)
+}
+
+////////  Code
+
+// SetProfile sets the profile shown in the Profile panel and the editors.
+// It can be called from any goroutine.
+func (cv *Code) SetProfile(pr *Profile) {
+	if cv.Output != nil { // no GUI
+		cv.Profile = pr
+		return
+	}
+	go func() { // avoid deadlock when called from the event loop
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+		cv.Profile = pr
+		cv.ShowProfile()
+	}()
+}
+
+// ViewProfile opens the pprof CPU or memory profile in given file,
+// e.g., as written by go test -cpuprofile, and shows it in the Profile
+// panel, and the cost of each line in the editors.
+func (cv *Code) ViewProfile(fname core.Filename) { //types:add
+	pr, err := OpenProfile(string(fname), string(cv.Files.Filepath))
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Could not open profile")
+		return
+	}
+	cv.Profile = pr
+	cv.ShowProfile()
+}
+
+// ProfileTest runs go test with a CPU profile in the directory of the
+// active editor, for the given test(s), and then shows the profile.
+func (cv *Code) ProfileTest(testName string) { //types:add
+	cmd, ok := CmdName(CommandName("Go", "Test CPU Profile")).Command()
+	if !ok {
+		core.ErrorSnackbar(cv, errors.New("the Go: Test CPU Profile command is not available"), "Could not profile tests")
+		return
+	}
+	cv.SetArgVarVals()
+	cv.ArgVals["{PromptString1}"] = ""
+	if testName != "" {
+		cv.ArgVals["{PromptString1}"] = "-run=" + testName
+	}
+	CmdNoUserPrompt = true // don't prompt for the tests
+	cbuf, _, _ := cv.RecycleCmdTab(cmd.Label())
+	cmd.Run(cv, cbuf)
+}
+
+// ShowProfile shows the Profile panel.
+func (cv *Code) ShowProfile() { //types:add
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+	pp := core.RecycleTabWidget[ProfilePanel](tv, "Profile")
+	pp.Update()
+	cv.updateProfile()
+	cv.FocusOnPanel(TabsIndex)
+}
+
+// ClearProfile removes the profile from the Profile panel and the editors.
+func (cv *Code) ClearProfile() { //types:add
+	cv.Profile = nil
+	cv.updateProfile()
+}
+
+// updateProfile updates the profile gutter in all of the editors,
+// and the Profile panel.
+func (cv *Code) updateProfile() {
+	for i := range NTextEditors {
+		if ed := cv.EditorByIndex(i); ed != nil {
+			ed.Style()
+			ed.NeedsLayout()
+		}
+	}
+	if tv := cv.Tabs(); tv != nil {
+		if tb := tv.TabByName("Profile"); tb != nil && tb.NumChildren() > 0 {
+			if pp, ok := tb.Child(0).(*ProfilePanel); ok {
+				pp.setProfile()
+			}
+		}
+	}
+}
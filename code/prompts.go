@@ -0,0 +1,315 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/styles"
+)
+
+// Prompter provides the values for the {Prompt*} argument variables of
+// commands, e.g., by prompting the user in a dialog. Each method is given
+// the command and the name of the variable being prompted for, along with
+// its current value, and calls done with the new value, asynchronously
+// if needed. It does not call done if the prompt is cancelled.
+// All of the {PromptFile*} variables are prompted for as {PromptFilename}.
+type Prompter interface {
+
+	// PromptString prompts for a string.
+	PromptString(cm *Command, name, cur string, done func(val string))
+
+	// PromptFile prompts for a file path.
+	PromptFile(cm *Command, name, cur string, done func(fpath string))
+
+	// PromptFiles prompts for a list of file paths.
+	PromptFiles(cm *Command, name string, cur []string, done func(fpaths []string))
+
+	// PromptChoice prompts to choose one of the given items.
+	PromptChoice(cm *Command, name string, items []string, cur string, done func(val string))
+
+	// PromptConfirm prompts for a yes or no answer.
+	PromptConfirm(cm *Command, name string, cur bool, done func(ok bool))
+}
+
+// CmdPromptFileVals holds last values for PromptFilename per command,
+// so that each such command has its own appropriate history
+var CmdPromptFileVals = map[string]string{}
+
+// CmdPromptFilesVals holds last values for PromptFiles per command,
+// so that each such command has its own appropriate history
+var CmdPromptFilesVals = map[string][]string{}
+
+// CmdPromptChoiceVals holds last values for PromptChoice per command,
+// so that each such command has its own appropriate history
+var CmdPromptChoiceVals = map[string]string{}
+
+// CmdPromptConfirmVals holds last values for PromptConfirm per command,
+// so that each such command has its own appropriate history
+var CmdPromptConfirmVals = map[string]bool{}
+
+// prompter returns the Prompter for the project, which shows
+// dialogs to the user unless another one has been set,
+// or there is no GUI.
+func (cv *Code) prompter() Prompter {
+	if cv.Prompter != nil {
+		return cv.Prompter
+	}
+	if cv.Output != nil { // no GUI, so nothing to prompt with
+		return &PresetPrompter{}
+	}
+	return &dialogPrompter{cv: cv}
+}
+
+// Prompt gets the values for the given prompt variables from the given
+// prompter, one after the other, setting them in the ArgVals of the project,
+// and calls done once all of them have been provided, which it does not
+// do if any of the prompts are cancelled.
+func (cm *Command) Prompt(cv *Code, pr Prompter, pvals map[string]struct{}, done func()) {
+	if cv.ArgVals == nil {
+		cv.ArgVals = make(ArgVarVals, len(ArgVars))
+	}
+	names := slices.Sorted(maps.Keys(pvals))
+	var next func(i int)
+	next = func(i int) {
+		if i == len(names) {
+			done()
+			return
+		}
+		cm.promptVar(cv, pr, names[i], func() { next(i + 1) })
+	}
+	next(0)
+}
+
+// promptVar prompts for the given variable, calling done once it is set.
+func (cm *Command) promptVar(cv *Code, pr Prompter, pv string, done func()) {
+	label := cm.Label()
+	switch pv {
+	case "{PromptString1}", "{PromptString2}":
+		cmvals := CmdPrompt1Vals
+		if pv == "{PromptString2}" {
+			cmvals = CmdPrompt2Vals
+		}
+		cur := cmvals[label]
+		if cur == "" && len(cm.Cmds) > 0 {
+			cur = cm.Cmds[0].Default
+		}
+		pr.PromptString(cm, pv, cur, func(val string) {
+			cmvals[label] = val
+			cv.ArgVals[pv] = val
+			done()
+		})
+	case "{PromptFilename}":
+		cur := CmdPromptFileVals[label]
+		if cur == "" {
+			cur = string(cv.ActiveFilename)
+		}
+		pr.PromptFile(cm, pv, cur, func(fpath string) {
+			CmdPromptFileVals[label] = fpath
+			cv.ArgVals.SetPromptFile(fpath, &cv.Settings)
+			done()
+		})
+	case "{PromptFiles}":
+		pr.PromptFiles(cm, pv, CmdPromptFilesVals[label], func(fpaths []string) {
+			CmdPromptFilesVals[label] = fpaths
+			cv.ArgVals[pv] = quoteArgs(fpaths)
+			done()
+		})
+	case "{PromptChoice}":
+		pr.PromptChoice(cm, pv, cm.Choices, CmdPromptChoiceVals[label], func(val string) {
+			CmdPromptChoiceVals[label] = val
+			cv.ArgVals[pv] = val
+			done()
+		})
+	case "{PromptConfirm}":
+		pr.PromptConfirm(cm, pv, CmdPromptConfirmVals[label], func(ok bool) {
+			CmdPromptConfirmVals[label] = ok
+			cv.ArgVals[pv] = strconv.FormatBool(ok)
+			done()
+		})
+	case "{PromptBranch}":
+		ln := cv.ActiveLines()
+		if ln == nil {
+			return
+		}
+		repo := GetVCSRepo(ln)
+		if repo == nil {
+			core.MessageSnackbar(cv, "No version control repository for the active file")
+			return
+		}
+		cur, br, err := RepoCurBranches(repo)
+		if err != nil {
+			core.ErrorSnackbar(cv, err)
+			return
+		}
+		pr.PromptChoice(cm, pv, br, cur, func(val string) {
+			cv.ArgVals[pv] = val
+			done()
+		})
+	default:
+		done()
+	}
+}
+
+// quoteArgs returns the given args as a single string, quoted as needed
+// so that they are split back into the same args in [CmdAndArgs.BindArgs].
+func quoteArgs(args []string) string {
+	qs := make([]string, len(args))
+	for i, a := range args {
+		if a != "" && !strings.ContainsAny(a, " \t\n'\"\\$`") {
+			qs[i] = a
+			continue
+		}
+		qs[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(qs, " ")
+}
+
+////////  Dialogs
+
+// dialogPrompter is the default [Prompter], which prompts the user in dialogs.
+type dialogPrompter struct {
+	cv *Code
+}
+
+// context returns the widget to show dialogs for.
+func (dp *dialogPrompter) context() core.Widget {
+	if tv := dp.cv.ActiveEditor(); tv != nil {
+		return tv
+	}
+	return dp.cv
+}
+
+// newBody returns a new dialog body for given command.
+func (dp *dialogPrompter) newBody(cm *Command, title string) *core.Body {
+	d := core.NewBody(title)
+	core.NewText(d).SetType(core.TextSupporting).SetText(fmt.Sprintf("Command: %v: %v", cm.Name, cm.Desc))
+	return d
+}
+
+func (dp *dialogPrompter) PromptString(cm *Command, name, cur string, done func(val string)) {
+	d := dp.newBody(cm, "Code Command Prompt")
+	tf := core.NewTextField(d).SetText(cur)
+	tf.Styler(func(s *styles.Style) {
+		s.Min.X.Ch(100)
+	})
+	d.AddBottomBar(func(bar *core.Frame) {
+		d.AddCancel(bar)
+		d.AddOK(bar).OnClick(func(e events.Event) {
+			done(tf.Text())
+		})
+	})
+	d.RunDialog(dp.context()) // SetModal(false).
+}
+
+func (dp *dialogPrompter) PromptFile(cm *Command, name, cur string, done func(fpath string)) {
+	d := dp.newBody(cm, "Choose file")
+	fp := core.NewFilePicker(d).SetFilename(cur)
+	d.AddBottomBar(func(bar *core.Frame) {
+		d.AddCancel(bar)
+		d.AddOK(bar).OnClick(func(e events.Event) {
+			done(fp.SelectedFile())
+		})
+	})
+	d.RunWindowDialog(dp.context())
+}
+
+func (dp *dialogPrompter) PromptFiles(cm *Command, name string, cur []string, done func(fpaths []string)) {
+	d := dp.newBody(cm, "Choose files")
+	fnms := make([]core.Filename, len(cur))
+	for i, f := range cur {
+		fnms[i] = core.Filename(f)
+	}
+	core.NewList(d).SetSlice(&fnms)
+	d.AddBottomBar(func(bar *core.Frame) {
+		d.AddCancel(bar)
+		d.AddOK(bar).OnClick(func(e events.Event) {
+			fpaths := make([]string, 0, len(fnms))
+			for _, f := range fnms {
+				if f != "" {
+					fpaths = append(fpaths, string(f))
+				}
+			}
+			done(fpaths)
+		})
+	})
+	d.RunWindowDialog(dp.context())
+}
+
+func (dp *dialogPrompter) PromptChoice(cm *Command, name string, items []string, cur string, done func(val string)) {
+	if len(items) == 0 {
+		core.MessageSnackbar(dp.cv, "No Choices to choose from for command "+cm.Label())
+		return
+	}
+	ctx := dp.context()
+	m := core.NewMenuFromStrings(items, cur, func(idx int) {
+		done(items[idx])
+	})
+	m.Name = "prompt-choice"
+	core.NewMenuStage(m, ctx, ctx.AsWidget().ContextMenuPos(nil)).Run()
+}
+
+func (dp *dialogPrompter) PromptConfirm(cm *Command, name string, cur bool, done func(ok bool)) {
+	d := dp.newBody(cm, "Confirm")
+	sw := core.NewSwitch(d).SetType(core.SwitchCheckbox).SetText("Yes").SetChecked(cur)
+	d.AddBottomBar(func(bar *core.Frame) {
+		d.AddCancel(bar)
+		d.AddOK(bar).OnClick(func(e events.Event) {
+			done(sw.IsChecked())
+		})
+	})
+	d.RunDialog(dp.context())
+}
+
+////////  Preset
+
+// PresetPrompter is a [Prompter] that provides preset values without any
+// user interaction, e.g., for testing and for running commands without a GUI.
+// A prompt for a variable with no preset value is cancelled, as is a choice
+// of a value that is not one of the items.
+type PresetPrompter struct {
+
+	// Values are the values for the string, file, choice and confirm prompts,
+	// by variable name, e.g., {PromptString1}; confirm values are parsed as
+	// bools, e.g., true or false.
+	Values map[string]string
+
+	// Files are the values for the multi-file prompts, by variable name.
+	Files map[string][]string
+}
+
+func (pp *PresetPrompter) PromptString(cm *Command, name, cur string, done func(val string)) {
+	if val, ok := pp.Values[name]; ok {
+		done(val)
+	}
+}
+
+func (pp *PresetPrompter) PromptFile(cm *Command, name, cur string, done func(fpath string)) {
+	pp.PromptString(cm, name, cur, done)
+}
+
+func (pp *PresetPrompter) PromptFiles(cm *Command, name string, cur []string, done func(fpaths []string)) {
+	if fpaths, ok := pp.Files[name]; ok {
+		done(fpaths)
+	}
+}
+
+func (pp *PresetPrompter) PromptChoice(cm *Command, name string, items []string, cur string, done func(val string)) {
+	if val, ok := pp.Values[name]; ok && slices.Contains(items, val) {
+		done(val)
+	}
+}
+
+func (pp *PresetPrompter) PromptConfirm(cm *Command, name string, cur bool, done func(ok bool)) {
+	if b, err := strconv.ParseBool(pp.Values[name]); err == nil {
+		done(b)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrompt(t *testing.T) {
+	cv := &Code{}
+	cv.Settings.ProjectRoot = "/work/app"
+	cm := &Command{Cat: "Task", Name: "Deploy", Choices: []string{"staging", "prod"},
+		Cmds: []CmdAndArgs{{Cmd: "deploy", Args: CmdArgs{"{PromptChoice}", "-force={PromptConfirm}", "{PromptFileDirProjectRel}", "{PromptFiles}"}}}}
+	pvals, has := cm.HasPrompts()
+	assert.True(t, has)
+	assert.Len(t, pvals, 4)
+
+	pr := &PresetPrompter{
+		Values: map[string]string{"{PromptChoice}": "prod", "{PromptConfirm}": "true", "{PromptFilename}": "/work/app/cmd/main.go"},
+		Files:  map[string][]string{"{PromptFiles}": {"/work/a.txt", "/work/my file.txt"}},
+	}
+	ran := false
+	cm.Prompt(cv, pr, pvals, func() { ran = true })
+	assert.True(t, ran)
+	assert.Equal(t, "main.go", cv.ArgVals["{PromptFilename}"])
+	assert.Equal(t, "/work/app/cmd", cv.ArgVals["{PromptFileDirPath}"])
+	assert.Equal(t, "/work/app/cmd/main.go", CmdPromptFileVals[cm.Label()])
+	assert.True(t, CmdPromptConfirmVals[cm.Label()])
+	assert.Equal(t, []string{"prod", "-force=true", "cmd", "/work/a.txt", "/work/my file.txt"}, cm.Cmds[0].BindArgs(&cv.ArgVals))
+
+	pr.Values["{PromptChoice}"] = "dev" // not one of the Choices
+	ran = false
+	cm.Prompt(cv, pr, pvals, func() { ran = true })
+	assert.False(t, ran)
+}
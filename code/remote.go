@@ -0,0 +1,200 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"path"
+
+	"cogentcore.org/cogent/code/remote"
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/textcore"
+)
+
+// IsRemote returns true if the project is on a remote host,
+// opened at an ssh:// url, so that its files are accessed over
+// SFTP and its commands are run through the SSH connection.
+func (cv *Code) IsRemote() bool {
+	return cv.Remote != nil
+}
+
+// remoteUnsupported returns true, after reporting it, if the project is
+// remote, for the given feature, which runs local tools on the project files.
+func (cv *Code) remoteUnsupported(feature string) bool {
+	if !cv.IsRemote() {
+		return false
+	}
+	core.ErrorSnackbar(cv, fmt.Errorf("%s is not supported for a remote project", feature))
+	return true
+}
+
+// closeRemote closes the connection to the remote host, if any.
+func (cv *Code) closeRemote() {
+	if cv.Remote == nil {
+		return
+	}
+	errors.Log(cv.Remote.Close())
+	cv.Remote = nil
+}
+
+// remoteProjectRoot returns the root directory and project name
+// for the project at given ssh:// url, without connecting to the host.
+func remoteProjectRoot(ur string) (root, projnm string) {
+	u, err := remote.ParseURL(ur)
+	if err != nil {
+		return "", "blank"
+	}
+	return u.Path, path.Base(u.Path)
+}
+
+// openRemote opens the project at given ssh:// url, connecting to its
+// host, which can be for a folder or a specific file within it, as for
+// openPath. It opens in the current Code object if it is empty, or
+// otherwise in a new window.
+func (cv *Code) openRemote(ur string) *Code {
+	if !cv.IsEmpty() {
+		return NewCodeProjectPath(ur)
+	}
+	u, err := remote.ParseURL(ur)
+	if err != nil {
+		core.ErrorSnackbar(cv, err)
+		return cv
+	}
+	rfs, err := remote.Dial(u, nil)
+	if err != nil {
+		core.ErrorSnackbar(cv, err, "Could not connect to "+u.Host)
+		return cv
+	}
+	root, fnm := u.Path, ""
+	info, err := rfs.Stat(root)
+	if err != nil {
+		rfs.Close()
+		core.ErrorSnackbar(cv, err, "Could not open remote path")
+		return cv
+	}
+	if !info.IsDir() {
+		root, fnm = path.Dir(root), root
+	}
+	cv.Defaults()
+	cv.Remote = rfs
+	RecentPaths.AddPath(ur, core.SystemSettings.SavedPathsMax)
+	SavePaths()
+	pnm := path.Base(root)
+	cv.ProjectRoot = core.Filename(root)
+	cv.SetName(pnm)
+	cv.Scene.SetName(pnm)
+	cv.Settings.ProjectRoot = cv.ProjectRoot
+	cv.SetWindowNameTitle()
+	cv.SplitsSetView(SplitName(AvailableSplitNames[0]))
+	if fnm != "" {
+		cv.NextViewFile(fnm)
+	}
+	return cv
+}
+
+// openRemoteLines opens the given remote file into the given lines.
+func (cv *Code) openRemoteLines(ln *lines.Lines, fpath string) error {
+	if err := ln.OpenFS(cv.Remote, fpath); err != nil {
+		return err
+	}
+	fi := &fileinfo.FileInfo{}
+	fi.InitFileFS(cv.Remote, fpath)
+	ln.SetFileInfo(fi)
+	ln.Autosave = false
+	return nil
+}
+
+// saveLines saves the given lines to their file, on the remote host
+// for a remote project.
+func (cv *Code) saveLines(ln *lines.Lines) error {
+	if !cv.IsRemote() {
+		return textcore.Save(cv.Scene, ln)
+	}
+	fname := ln.Filename()
+	if fname == "" {
+		return errors.New("filename is empty for save")
+	}
+	if err := cv.Remote.WriteFile(fname, ln.Text(), 0644); err != nil {
+		core.ErrorSnackbar(cv, err, "Could not save remote file")
+		return err
+	}
+	ln.ClearNotSaved()
+	return nil
+}
+
+// revertLines reverts the given lines to the saved contents of their file,
+// on the remote host for a remote project.
+func (cv *Code) revertLines(ln *lines.Lines) bool {
+	if !cv.IsRemote() {
+		return ln.Revert()
+	}
+	b, err := cv.Remote.ReadFile(ln.Filename())
+	if errors.Log(err) != nil {
+		return false
+	}
+	ob := lines.NewLines()
+	ob.SetText(b)
+	ln.PatchFrom(ob, ln.Diffs(ob))
+	ln.ClearNotSaved()
+	return true
+}
+
+// runRemote runs the given command on the remote host, with output to the
+// buffer if non-nil, incrementally if !wait, as for the local RunBuf,
+// RunBufWait and RunNoBuf. It returns overall command success.
+func (cm *Command) runRemote(cv *Code, av ArgVarVals, buf *lines.Lines, cma *CmdAndArgs, wait bool) bool {
+	cmd, cmdstr := cma.PrepCmd(&av)
+	if cmd == nil {
+		return false
+	}
+	rc := cv.Remote.Command(cmd.Args[0], cmd.Args[1:]...)
+	rc.Dir = cm.stepDir(av, cma)
+	rc.Env = cm.bindEnv(av)
+	cv.RunningCmds.Add(&CmdRun{Name: cm.Label(), CmdStr: cmdstr, CmdArgs: cma, Remote: rc})
+	if buf == nil || wait {
+		out, err := rc.CombinedOutput()
+		if buf != nil {
+			cm.AppendCmdOut(cv, buf, []rune(string(out)))
+		}
+		return cm.RunStatus(cv, av, buf, cmdstr, err, out)
+	}
+	stdout, err := rc.StdoutPipe()
+	if err == nil {
+		rc.Stderr = rc.Stdout
+		err = rc.Start()
+		if err == nil {
+			obuf := textcore.OutputBuffer{}
+			obuf.SetOutput(stdout).SetLines(buf).SetMarkupFunc(cm.MarkupCmdOutput)
+			obuf.MonitorOutput()
+			err = rc.Wait()
+		}
+	}
+	return cm.RunStatus(cv, av, buf, cmdstr, err, nil)
+}
+
+// runRemoteStep runs the given index of the Cmds on the remote host
+// for [Command.runParallelStep].
+func (cm *Command) runRemoteStep(cv *Code, av ArgVarVals, buf *lines.Lines, i int) (bool, []byte) {
+	cma := &cm.Cmds[i]
+	cmd, cmdstr := cma.PrepCmd(&av)
+	if cmd == nil {
+		return false, nil
+	}
+	rc := cv.Remote.Command(cmd.Args[0], cmd.Args[1:]...)
+	rc.Dir = cm.stepDir(av, cma)
+	rc.Env = cm.bindEnv(av)
+	cv.RunningCmds.Add(&CmdRun{Name: cm.stepName(i), CmdStr: cmdstr, CmdArgs: cma, Remote: rc})
+	out, err := rc.CombinedOutput()
+	cv.RunningCmds.DeleteByName(cm.stepName(i))
+	cm.AppendCmdOut(cv, buf, []rune(string(out)))
+	ok, _ := cm.stepStatus(cv, buf, cmdstr, err, out)
+	if buf != nil {
+		out = nil
+	}
+	return ok, out
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Cmd is a command run on the remote host, in its own session on the
+// SSH connection, through the user's login shell. It has the same
+// API as [exec.Cmd] for the parts used to run commands in Code.
+// A non-zero exit status is returned as an [*ssh.ExitError].
+type Cmd struct {
+
+	// Args are the command name and arguments, which are quoted
+	// so that they are passed as-is to the command.
+	Args []string
+
+	// Dir is the directory to run the command in,
+	// which is the home directory if empty.
+	Dir string
+
+	// Env are additional environment variables for the command,
+	// in the form NAME=value.
+	Env []string
+
+	// Stdout and Stderr receive the output of the command.
+	Stdout, Stderr io.Writer
+
+	// client is the SSH connection.
+	client *ssh.Client
+
+	// session is the SSH session while running.
+	session *ssh.Session
+
+	// mu protects session, which Kill can access from another goroutine.
+	mu sync.Mutex
+
+	// closeOnce closes the session once, from Wait or Kill.
+	closeOnce sync.Once
+
+	// closeAfterWait are pipe writers to close when the command is done.
+	closeAfterWait []io.Closer
+
+	// done is closed when the command is done, with its error in err.
+	done chan struct{}
+
+	// err is the error from the session when the command is done.
+	err error
+}
+
+// Command returns a command to run the named program
+// with given arguments on the remote host.
+func (f *FS) Command(name string, args ...string) *Cmd {
+	return &Cmd{Args: append([]string{name}, args...), client: f.Client}
+}
+
+// quote quotes given string for the POSIX shell.
+func quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+,./:@%") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// String returns the shell command line that is run on the host.
+func (c *Cmd) String() string {
+	qs := make([]string, len(c.Args))
+	for i, a := range c.Args {
+		qs[i] = quote(a)
+	}
+	cmd := strings.Join(qs, " ")
+	if len(c.Env) > 0 {
+		es := make([]string, len(c.Env))
+		for i, ev := range c.Env {
+			nm, val, _ := strings.Cut(ev, "=")
+			es[i] = nm + "=" + quote(val)
+		}
+		cmd = "env " + strings.Join(es, " ") + " " + cmd
+	}
+	if c.Dir != "" {
+		cmd = "cd " + quote(c.Dir) + " && " + cmd
+	}
+	return cmd
+}
+
+// Start starts the command without waiting for it to complete.
+func (c *Cmd) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.session != nil {
+		return errors.New("remote: command already started")
+	}
+	s, err := c.client.NewSession()
+	if err != nil {
+		return err
+	}
+	s.Stdout = c.Stdout
+	s.Stderr = c.Stderr
+	if err := s.Start(c.String()); err != nil {
+		s.Close()
+		return err
+	}
+	c.session = s
+	c.done = make(chan struct{})
+	go func() {
+		c.err = s.Wait()
+		for _, cl := range c.closeAfterWait {
+			cl.Close()
+		}
+		close(c.done)
+	}()
+	return nil
+}
+
+// Wait waits for the started command to complete.
+func (c *Cmd) Wait() error {
+	if c.running() == nil {
+		return errors.New("remote: command not started")
+	}
+	<-c.done
+	c.closeSession()
+	return c.err
+}
+
+// running returns the session if the command has been started.
+func (c *Cmd) running() *ssh.Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.session
+}
+
+// closeSession closes the session of the started command,
+// if it has not already been closed.
+func (c *Cmd) closeSession() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.running().Close()
+	})
+	return err
+}
+
+// Run starts the command and waits for it to complete.
+func (c *Cmd) Run() error {
+	if err := c.Start(); err != nil {
+		return err
+	}
+	return c.Wait()
+}
+
+// syncBuffer is a buffer that is safe to write to concurrently,
+// for collecting stdout and stderr together.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (sb *syncBuffer) Write(p []byte) (int, error) {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return sb.buf.Write(p)
+}
+
+// CombinedOutput runs the command and returns its stdout and stderr.
+func (c *Cmd) CombinedOutput() ([]byte, error) {
+	sb := &syncBuffer{}
+	c.Stdout = sb
+	c.Stderr = sb
+	err := c.Run()
+	return sb.buf.Bytes(), err
+}
+
+// StdoutPipe returns a pipe connected to the stdout of the command
+// when it starts, which is closed when the command is done.
+func (c *Cmd) StdoutPipe() (io.ReadCloser, error) {
+	if c.running() != nil {
+		return nil, errors.New("remote: StdoutPipe after command started")
+	}
+	pr, pw := io.Pipe()
+	c.Stdout = pw
+	c.closeAfterWait = append(c.closeAfterWait, pw)
+	return pr, nil
+}
+
+// Kill stops the running command, by sending it a kill signal
+// and closing its session. It is safe to call while another goroutine
+// is in Wait, and does nothing if the command is not running.
+func (c *Cmd) Kill() error {
+	s := c.running()
+	if s == nil {
+		return nil
+	}
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+	s.Signal(ssh.SIGKILL)
+	return c.closeSession()
+}
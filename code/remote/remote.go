@@ -0,0 +1,276 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package remote provides access to the files on a remote host over
+// SSH / SFTP, as a file system that can be browsed and edited,
+// and the running of commands on the host through the SSH connection,
+// for projects opened at an ssh://host/path url.
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Scheme is the url scheme for remote paths.
+const Scheme = "ssh"
+
+// IsURL returns true if the given path is a remote ssh:// url.
+func IsURL(p string) bool {
+	return strings.HasPrefix(p, Scheme+"://")
+}
+
+// URL is a parsed remote path of the form ssh://[user@]host[:port]/path.
+type URL struct {
+
+	// User is the user to log in as, which is the current user if empty.
+	User string
+
+	// Host is the host name or address, without the port.
+	Host string
+
+	// Port is the SSH port, which is 22 if empty.
+	Port string
+
+	// Path is the absolute path on the host.
+	Path string
+}
+
+// ParseURL parses the given ssh:// url.
+func ParseURL(s string) (*URL, error) {
+	if !IsURL(s) {
+		return nil, fmt.Errorf("remote: not an %s:// url: %q", Scheme, s)
+	}
+	pu, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if pu.Hostname() == "" {
+		return nil, fmt.Errorf("remote: no host in url: %q", s)
+	}
+	u := &URL{Host: pu.Hostname(), Port: pu.Port(), Path: path.Clean("/" + pu.Path)}
+	if pu.User != nil {
+		u.User = pu.User.Username()
+	}
+	return u, nil
+}
+
+// Addr returns the host:port address to dial.
+func (u *URL) Addr() string {
+	port := u.Port
+	if port == "" {
+		port = "22"
+	}
+	return net.JoinHostPort(u.Host, port)
+}
+
+// String returns the url for the host, with given path on it.
+func (u *URL) String() string {
+	return u.URL(u.Path)
+}
+
+// URL returns the url for given absolute path on the same host.
+func (u *URL) URL(p string) string {
+	s := Scheme + "://"
+	if u.User != "" {
+		s += u.User + "@"
+	}
+	s += u.Host
+	if u.Port != "" {
+		s += ":" + u.Port
+	}
+	return s + path.Clean("/"+p)
+}
+
+// DefaultConfig returns the client config used by [Dial] to log in as
+// given user (the current user if empty), authenticating with the
+// SSH agent if running and the default identity files in ~/.ssh,
+// and checking the host key against ~/.ssh/known_hosts.
+func DefaultConfig(usr string) (*ssh.ClientConfig, error) {
+	if usr == "" {
+		if cu, err := user.Current(); err == nil {
+			usr = cu.Username
+		} else {
+			usr = os.Getenv("USER")
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	sshDir := filepath.Join(home, ".ssh")
+	hostKey, err := knownhosts.New(filepath.Join(sshDir, "known_hosts"))
+	if err != nil {
+		return nil, fmt.Errorf("remote: could not read known hosts: %w", err)
+	}
+	var signers []ssh.Signer
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			if ss, err := agent.NewClient(conn).Signers(); err == nil {
+				signers = append(signers, ss...)
+			}
+		}
+	}
+	for _, id := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+		b, err := os.ReadFile(filepath.Join(sshDir, id))
+		if err != nil {
+			continue
+		}
+		if s, err := ssh.ParsePrivateKey(b); err == nil { // skips keys with passphrases
+			signers = append(signers, s)
+		}
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("remote: no SSH agent or unencrypted identity files in " + sshDir)
+	}
+	return &ssh.ClientConfig{
+		User:            usr,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signers...)},
+		HostKeyCallback: hostKey,
+	}, nil
+}
+
+// FS is the file system of a remote host, accessed over SFTP through
+// an SSH connection, which is also used to run commands on the host.
+// Paths are absolute paths on the host; relative paths are relative
+// to the root directory. It implements [fs.FS], [fs.StatFS],
+// [fs.ReadDirFS] and [fs.ReadFileFS], and also supports writing files.
+type FS struct {
+
+	// URL is the url the connection was made for.
+	URL *URL
+
+	// Client is the SSH connection.
+	Client *ssh.Client
+
+	// SFTP is the SFTP client for accessing the files.
+	SFTP *sftp.Client
+}
+
+// Dial connects to the host of given url, using given client config,
+// or the [DefaultConfig] if nil, and starts an SFTP session on it.
+func Dial(u *URL, config *ssh.ClientConfig) (*FS, error) {
+	if config == nil {
+		var err error
+		config, err = DefaultConfig(u.User)
+		if err != nil {
+			return nil, err
+		}
+	}
+	cl, err := ssh.Dial("tcp", u.Addr(), config)
+	if err != nil {
+		return nil, err
+	}
+	f, err := NewFS(cl)
+	if err != nil {
+		cl.Close()
+		return nil, err
+	}
+	f.URL = u
+	return f, nil
+}
+
+// NewFS returns a new file system for given SSH connection,
+// starting an SFTP session on it.
+func NewFS(cl *ssh.Client) (*FS, error) {
+	sc, err := sftp.NewClient(cl)
+	if err != nil {
+		return nil, err
+	}
+	return &FS{Client: cl, SFTP: sc}, nil
+}
+
+// Close closes the SFTP session and the SSH connection.
+func (f *FS) Close() error {
+	return errors.Join(f.SFTP.Close(), f.Client.Close())
+}
+
+// path returns the absolute path on the host for given name.
+func (f *FS) path(name string) string {
+	if path.IsAbs(name) {
+		return path.Clean(name)
+	}
+	return path.Join("/", name)
+}
+
+// Open opens the named file for reading.
+func (f *FS) Open(name string) (fs.File, error) {
+	return f.SFTP.Open(f.path(name))
+}
+
+// Stat returns the file info for the named file.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	return f.SFTP.Stat(f.path(name))
+}
+
+// ReadDir returns the entries of the named directory, sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	fis, err := f.SFTP.ReadDir(f.path(name))
+	if err != nil {
+		return nil, err
+	}
+	des := make([]fs.DirEntry, len(fis))
+	for i, fi := range fis {
+		des[i] = fs.FileInfoToDirEntry(fi)
+	}
+	return des, nil // sftp already sorts by name
+}
+
+// ReadFile returns the contents of the named file.
+func (f *FS) ReadFile(name string) ([]byte, error) {
+	fp, err := f.SFTP.Open(f.path(name))
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return io.ReadAll(fp)
+}
+
+// WriteFile writes given data to the named file, creating it if needed
+// with given permissions, and truncating it otherwise.
+func (f *FS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	p := f.path(name)
+	_, serr := f.SFTP.Stat(p)
+	fp, err := f.SFTP.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	_, err = fp.Write(data)
+	if cerr := fp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && serr != nil { // new file
+		err = f.SFTP.Chmod(p, perm)
+	}
+	return err
+}
+
+// MkdirAll makes the named directory, along with any parents.
+func (f *FS) MkdirAll(name string) error {
+	return f.SFTP.MkdirAll(f.path(name))
+}
+
+// Remove removes the named file or empty directory.
+func (f *FS) Remove(name string) error {
+	return f.SFTP.Remove(f.path(name))
+}
+
+// Rename renames the old file to the new one, replacing it if it exists.
+func (f *FS) Rename(oldname, newname string) error {
+	return f.SFTP.PosixRename(f.path(oldname), f.path(newname))
+}
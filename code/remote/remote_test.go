@@ -0,0 +1,223 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+)
+
+// startServer starts an in-process SSH server on localhost, serving
+// the sftp subsystem and running exec requests with the local shell,
+// returning its url (with no path) and a client config for it.
+func startServer(t *testing.T) (*URL, *ssh.ClientConfig) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	assert.NoError(t, err)
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveConn(conn, config)
+		}
+	}()
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	cc := &ssh.ClientConfig{User: "test", HostKeyCallback: ssh.FixedHostKey(signer.PublicKey())}
+	return &URL{Host: host, Port: port}, cc
+}
+
+func serveConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, creqs, err := nc.Accept()
+		if err != nil {
+			continue
+		}
+		go serveSession(ch, creqs)
+	}
+}
+
+func serveSession(ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+	for req := range reqs {
+		arg := ""
+		if len(req.Payload) >= 4 {
+			arg = string(req.Payload[4:])
+		}
+		switch {
+		case req.Type == "subsystem" && arg == "sftp":
+			req.Reply(true, nil)
+			if srv, err := sftp.NewServer(ch); err == nil {
+				srv.Serve()
+			}
+			return
+		case req.Type == "exec":
+			req.Reply(true, nil)
+			cmd := exec.Command("sh", "-c", arg)
+			cmd.Stdout = ch
+			cmd.Stderr = ch.Stderr()
+			status := 0
+			if err := cmd.Run(); err != nil {
+				status = 255
+				if ee, ok := err.(*exec.ExitError); ok {
+					status = ee.ExitCode()
+				}
+			}
+			ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, uint32(status)))
+			return
+		default:
+			req.Reply(false, nil)
+		}
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	u, err := ParseURL("ssh://bob@build1:2222/home/bob/repo/")
+	assert.NoError(t, err)
+	assert.Equal(t, &URL{User: "bob", Host: "build1", Port: "2222", Path: "/home/bob/repo"}, u)
+	assert.Equal(t, "build1:2222", u.Addr())
+	assert.Equal(t, "ssh://bob@build1:2222/home/bob/repo/main.go", u.URL("/home/bob/repo/main.go"))
+
+	u, err = ParseURL("ssh://build1")
+	assert.NoError(t, err)
+	assert.Equal(t, "/", u.Path)
+	assert.Equal(t, "build1:22", u.Addr())
+	assert.Equal(t, "ssh://build1/", u.String())
+
+	_, err = ParseURL("/home/bob/repo")
+	assert.Error(t, err)
+	_, err = ParseURL("ssh:///home/bob/repo")
+	assert.Error(t, err)
+	assert.True(t, IsURL("ssh://build1/x"))
+	assert.False(t, IsURL("build1:/x"))
+}
+
+func TestFS(t *testing.T) {
+	u, config := startServer(t)
+	rfs, err := Dial(u, config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rfs.Close()
+
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "sub", "a.go")
+	assert.NoError(t, rfs.MkdirAll(filepath.Dir(fname)))
+	assert.NoError(t, rfs.WriteFile(fname, []byte("package a\n\nfunc A() {}\nfunc B() {}\n"), 0644))
+	assert.NoError(t, rfs.WriteFile(filepath.Join(dir, "b.txt"), []byte("func\n"), 0644))
+	b, err := fs.ReadFile(rfs, fname)
+	assert.NoError(t, err)
+	assert.Equal(t, "package a\n\nfunc A() {}\nfunc B() {}\n", string(b))
+
+	des, err := fs.ReadDir(rfs, dir)
+	assert.NoError(t, err)
+	if assert.Len(t, des, 2) {
+		assert.Equal(t, "b.txt", des[0].Name())
+		assert.True(t, des[1].IsDir())
+	}
+	st, err := fs.Stat(rfs, fname)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(35), st.Size())
+
+	core.SystemSettings.BigFileSize = 10000000
+	res, err := Search(rfs, []string{dir}, true, "func", false, false, nil)
+	assert.NoError(t, err)
+	if assert.Len(t, res, 2) {
+		assert.Equal(t, fname, res[0].Filepath)
+		assert.Equal(t, 2, res[0].Matches[0].Region.Start.Line)
+	}
+	res, err = Search(rfs, []string{dir}, false, "func", false, false, nil, "*.txt")
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+
+	nname := filepath.Join(dir, "c.go")
+	assert.NoError(t, rfs.Rename(fname, nname))
+	_, err = fs.Stat(rfs, fname)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoError(t, rfs.Remove(nname))
+}
+
+func TestCommand(t *testing.T) {
+	u, config := startServer(t)
+	rfs, err := Dial(u, config)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer rfs.Close()
+
+	dir := t.TempDir()
+	cmd := rfs.Command("sh", "-c", `pwd; echo "it's" >&2`)
+	cmd.Dir = dir
+	assert.Equal(t, "cd "+dir+" && sh -c 'pwd; echo \"it'\\''s\" >&2'", cmd.String())
+	out, err := cmd.CombinedOutput()
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), dir+"\n")
+	assert.Contains(t, string(out), "it's\n")
+
+	cmd = rfs.Command("sh", "-c", "echo $GREETING")
+	cmd.Env = []string{"GREETING=hi there"}
+	assert.Equal(t, "env GREETING='hi there' sh -c 'echo $GREETING'", cmd.String())
+	out, err = cmd.CombinedOutput()
+	assert.NoError(t, err)
+	assert.Equal(t, "hi there\n", string(out))
+
+	_, err = rfs.Command("false").CombinedOutput()
+	var ee *ssh.ExitError
+	if assert.True(t, errors.As(err, &ee)) {
+		assert.Equal(t, 1, ee.ExitStatus())
+	}
+
+	cmd = rfs.Command("printf", "a\nb\n")
+	stdout, err := cmd.StdoutPipe()
+	assert.NoError(t, err)
+	assert.NoError(t, cmd.Start())
+	b, err := io.ReadAll(stdout)
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(b))
+	assert.NoError(t, cmd.Wait())
+	assert.NoError(t, cmd.Kill())
+
+	cmd = rfs.Command("sleep", "0.2")
+	assert.NoError(t, cmd.Kill())
+	assert.NoError(t, cmd.Start())
+	waited := make(chan error)
+	go func() {
+		waited <- cmd.Wait()
+	}()
+	cmd.Kill()
+	cmd.Kill()
+	<-waited
+	assert.NoError(t, cmd.Kill())
+}
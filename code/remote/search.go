@@ -0,0 +1,115 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/text/search"
+	"cogentcore.org/core/text/textpos"
+)
+
+// Search returns the files in given list of paths in given file system
+// that contain the given string, with the same options as [search.Paths],
+// or [search.All] if all is true, in which case the paths are searched
+// recursively, including all subdirectories.
+// It is for file systems such as [FS] that are not on the local disk.
+func Search(fsys fs.FS, paths []string, all bool, find string, ignoreCase, regExp bool, langs []fileinfo.Known, exclude ...string) ([]search.Results, error) {
+	if find == "" {
+		return nil, nil
+	}
+	var re *regexp.Regexp
+	if regExp {
+		var err error
+		re, err = regexp.Compile(find)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var res []search.Results
+	var errs []error
+	file := func(fpath string, d fs.DirEntry) {
+		if excluded(exclude, d.Name(), fpath) {
+			return
+		}
+		info, err := d.Info()
+		if err != nil || int(info.Size()) > core.SystemSettings.BigFileSize {
+			return
+		}
+		fi := &fileinfo.FileInfo{}
+		fi.InitFileFS(fsys, fpath)
+		if fi.Generated || !search.LangCheck(fi, langs) {
+			return
+		}
+		b, err := fs.ReadFile(fsys, fpath)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		var cnt int
+		var matches []textpos.Match
+		if regExp {
+			cnt, matches = search.ReaderRegexp(bytes.NewReader(b), re)
+		} else {
+			cnt, matches = search.Reader(bytes.NewReader(b), []byte(find), ignoreCase)
+		}
+		if cnt > 0 {
+			res = append(res, search.Results{Filepath: fpath, Count: cnt, Matches: matches})
+		}
+	}
+	for _, p := range paths {
+		if all {
+			err := fs.WalkDir(fsys, p, func(fpath string, d fs.DirEntry, err error) error {
+				if err != nil {
+					errs = append(errs, err)
+					return nil
+				}
+				if !d.IsDir() {
+					file(fpath, d)
+				}
+				return nil
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		des, err := fs.ReadDir(fsys, p)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, d := range des {
+			if !d.IsDir() {
+				file(path.Join(p, d.Name()), d)
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Count > res[j].Count
+	})
+	return res, errors.Join(errs...)
+}
+
+// excluded returns true if given file name or path matches
+// any of the given exclude glob patterns.
+func excluded(exclude []string, fname, fpath string) bool {
+	for _, ex := range exclude {
+		if m, _ := path.Match(ex, fpath); m {
+			return true
+		}
+		if m, _ := path.Match(ex, fname); m {
+			return true
+		}
+	}
+	return false
+}
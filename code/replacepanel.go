@@ -0,0 +1,455 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/base/metadata"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/styles/states"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/search"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/text/textpos"
+	"cogentcore.org/core/tree"
+)
+
+// Replacement is one pending replacement of text in a file,
+// shown in the [ReplacePanel] preview of a replace all.
+type Replacement struct {
+
+	// Region of the text to replace, with the time of the preview,
+	// so that it can be adjusted for any later edits to the file.
+	Region textpos.Region
+
+	// Old is the text to replace.
+	Old string
+
+	// New is the text to replace it with, which can be edited.
+	New string
+
+	// Accept is whether to apply the replacement.
+	Accept bool
+}
+
+// FileReplacements are the pending replacements in one file.
+type FileReplacements struct {
+
+	// Filepath is the path to the file.
+	Filepath string
+
+	// Replacements are the replacements in the file, in order.
+	Replacements []*Replacement
+
+	// text of the file at the time of the preview, for the diff
+	text []byte
+
+	// diff is the last diff, from [FileReplacements.Diff]
+	diff []byte
+
+	// diffState is the state of the replacements that the diff
+	// is for, from [FileReplacements.state]
+	diffState string
+}
+
+// Accepted returns the number of accepted replacements.
+func (fr *FileReplacements) Accepted() int {
+	n := 0
+	for _, r := range fr.Replacements {
+		if r.Accept {
+			n++
+		}
+	}
+	return n
+}
+
+// SetAccept sets whether to apply all of the replacements.
+func (fr *FileReplacements) SetAccept(accept bool) {
+	for _, r := range fr.Replacements {
+		r.Accept = accept
+	}
+}
+
+// ApplyTo applies the accepted replacements to the given lines of the file,
+// as one undo group, returning the number that were applied. Replacements
+// whose text has since been changed in the lines are skipped.
+func (fr *FileReplacements) ApplyTo(ln *lines.Lines) int {
+	n := 0
+	ln.NewUndoGroup()
+	for i := len(fr.Replacements) - 1; i >= 0; i-- { // from the end, so the regions stay valid
+		r := fr.Replacements[i]
+		if !r.Accept {
+			continue
+		}
+		reg := ln.AdjustRegion(r.Region)
+		if reg.IsNil() {
+			continue
+		}
+		rg := ln.Region(reg.Start, reg.End)
+		if rg == nil || string(rg.ToBytes()) != r.Old {
+			continue
+		}
+		ln.ReplaceText(reg.Start, reg.End, reg.Start, r.New, lines.ReplaceNoMatchCase)
+		n++
+	}
+	ln.NewUndoGroup()
+	return n
+}
+
+// Diff returns the unified diff of the accepted replacements
+// against the text of the file at the time of the preview.
+// It is only computed again if the accepted replacements have changed.
+func (fr *FileReplacements) Diff() []byte {
+	st := fr.state()
+	if st == "" {
+		return nil
+	}
+	if st == fr.diffState {
+		return fr.diff
+	}
+	a := lines.NewLines().SetText(bytes.Clone(fr.text))
+	b := lines.NewLines().SetText(bytes.Clone(fr.text))
+	fr.ApplyTo(b)
+	fr.diff = lines.DiffLinesUnified(a.Strings(true), b.Strings(true), 3, fr.Filepath, "", fr.Filepath, "")
+	fr.diffState = st
+	return fr.diff
+}
+
+// state returns the state of the replacements that the diff depends on:
+// the index and new text of each accepted one, which is empty if none are.
+func (fr *FileReplacements) state() string {
+	var b strings.Builder
+	for i, r := range fr.Replacements {
+		if r.Accept {
+			fmt.Fprintf(&b, "%d:%q;", i, r.New)
+		}
+	}
+	return b.String()
+}
+
+// pendingReplacements returns the replacements for all of the current
+// find results, skipping any that have already been replaced.
+func (fv *FindPanel) pendingReplacements() ([]*FileReplacements, error) {
+	res, _ := metadata.Get[[]search.Results](fv.TextEditor().Lines, "SearchResults")
+	now := time.Now()
+	var frs []*FileReplacements
+	var errs []error
+	for _, rs := range res {
+		ln, err := fv.Code.replaceLines(rs.Filepath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fr := &FileReplacements{Filepath: rs.Filepath, text: ln.Text()}
+		for _, mt := range rs.Matches {
+			reg := mt.Region
+			reg.Time.SetTime(fv.Time)
+			reg = ln.AdjustRegion(reg)
+			if reg.IsNil() {
+				continue
+			}
+			rg := ln.Region(reg.Start, reg.End)
+			if rg == nil {
+				continue
+			}
+			old := string(rg.ToBytes())
+			if !strings.HasPrefix(old, string(mt.Text[mt.TextMatch.Start:mt.TextMatch.End])) {
+				continue // already replaced
+			}
+			repl, err := fv.replacement(old)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s:%d: %w", rs.Filepath, reg.Start.Line+1, err))
+				continue
+			}
+			if repl == old {
+				continue
+			}
+			reg.Time.SetTime(now)
+			fr.Replacements = append(fr.Replacements, &Replacement{Region: reg, Old: old, New: repl, Accept: true})
+		}
+		if len(fr.Replacements) > 0 {
+			frs = append(frs, fr)
+		}
+	}
+	return frs, errors.Join(errs...)
+}
+
+// replaceLines returns the lines of the file at given path for
+// a replace preview, which are the open lines if the file is open,
+// and otherwise new lines with the text of the file.
+func (cv *Code) replaceLines(fpath string) (*lines.Lines, error) {
+	if ln := cv.OpenFiles.At(fpath); ln != nil {
+		return ln, nil
+	}
+	var b []byte
+	var err error
+	if cv.IsRemote() {
+		b, err = fs.ReadFile(cv.Remote, fpath)
+	} else {
+		b, err = os.ReadFile(fpath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return lines.NewLines().SetText(b), nil
+}
+
+// ReplacePanel is a widget for previewing a replace all, showing
+// all of the pending replacements grouped by file, which can each be
+// accepted and edited, along with a unified diff of the accepted
+// replacements. Only the accepted replacements are applied.
+type ReplacePanel struct {
+	core.Frame
+
+	// parent code project
+	Code *Code `json:"-" xml:"-"`
+
+	// find panel with the results that the replacements are for
+	Find *FindPanel `set:"-" json:"-" xml:"-"`
+
+	// pending replacements, grouped by file
+	Files []*FileReplacements `set:"-" json:"-" xml:"-"`
+}
+
+func (rv *ReplacePanel) Init() {
+	rv.Frame.Init()
+	rv.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 1)
+	})
+
+	tree.AddChildAt(rv, "replacebar", func(w *core.Toolbar) {
+		w.Maker(rv.makeToolbar)
+	})
+	tree.AddChildAt(rv, "splits", func(w *core.Splits) {
+		w.SetSplits(0.5, 0.5)
+		tree.AddChild(w, func(w *core.Frame) {
+			w.Styler(func(s *styles.Style) {
+				s.Direction = styles.Column
+				s.Grow.Set(1, 1)
+				s.Overflow.Set(styles.OverflowAuto)
+			})
+			w.Maker(rv.makeFiles)
+		})
+		tree.AddChild(w, func(w *textcore.Editor) {
+			ConfigOutputTextEditor(w)
+			w.Updater(func() {
+				if w.Lines == nil {
+					w.SetLines(lines.NewLines())
+					w.Lines.SetLanguage(fileinfo.Diff)
+				}
+				w.Lines.SetText(rv.Diff())
+			})
+		})
+	})
+}
+
+func (rv *ReplacePanel) OnAdd() {
+	rv.Frame.OnAdd()
+	rv.Code, _ = ParentCode(rv)
+}
+
+// Splits returns the main Splits
+func (rv *ReplacePanel) Splits() *core.Splits {
+	return rv.ChildByName("splits", 1).(*core.Splits)
+}
+
+// TextEditor returns the diff text editor.
+func (rv *ReplacePanel) TextEditor() *textcore.Editor {
+	return textcore.AsEditor(rv.Splits().Child(1))
+}
+
+// SetReplacements sets the pending replacements to preview,
+// for the results of the given find panel.
+func (rv *ReplacePanel) SetReplacements(fv *FindPanel, frs []*FileReplacements) {
+	rv.Find = fv
+	rv.Files = frs
+	rv.Update()
+}
+
+// Accepted returns the number of accepted replacements, and the number
+// of files that they are in.
+func (rv *ReplacePanel) Accepted() (n, files int) {
+	for _, fr := range rv.Files {
+		if na := fr.Accepted(); na > 0 {
+			n += na
+			files++
+		}
+	}
+	return
+}
+
+// SetAccept sets whether to apply all of the replacements.
+func (rv *ReplacePanel) SetAccept(accept bool) {
+	for _, fr := range rv.Files {
+		fr.SetAccept(accept)
+	}
+	rv.Update()
+}
+
+// Diff returns the unified diff of all of the accepted replacements.
+func (rv *ReplacePanel) Diff() []byte {
+	var b []byte
+	for _, fr := range rv.Files {
+		b = append(b, fr.Diff()...)
+	}
+	return b
+}
+
+// Apply applies the accepted replacements, as one undo group per file,
+// opening the files as needed, and then runs the find again.
+func (rv *ReplacePanel) Apply() {
+	n, nfiles := 0, 0
+	for _, fr := range rv.Files {
+		if fr.Accepted() == 0 {
+			continue
+		}
+		ln, _ := rv.Code.RecycleFile(fr.Filepath)
+		if ln == nil {
+			continue
+		}
+		if na := fr.ApplyTo(ln); na > 0 {
+			n += na
+			nfiles++
+		}
+	}
+	rv.Files = nil
+	rv.Update()
+	rv.Code.SetStatus(fmt.Sprintf("Replaced %d in %d files", n, nfiles))
+	if rv.Find != nil && rv.Find.Code != nil {
+		rv.Find.FindAction()
+	}
+}
+
+func (rv *ReplacePanel) makeToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			n, files := rv.Accepted()
+			w.SetText(fmt.Sprintf("%d replacements in %d files", n, files))
+		})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Apply").SetIcon(icons.Check).
+			SetTooltip("apply the accepted replacements, as one undo for each file").
+			OnClick(func(e events.Event) {
+				rv.Apply()
+			})
+		w.FirstStyler(func(s *styles.Style) {
+			n, _ := rv.Accepted()
+			s.SetEnabled(n > 0)
+		})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("All").SetIcon(icons.SelectAll).
+			SetTooltip("accept all of the replacements").
+			OnClick(func(e events.Event) {
+				rv.SetAccept(true)
+			})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("None").SetIcon(icons.Deselect).
+			SetTooltip("accept none of the replacements").
+			OnClick(func(e events.Event) {
+				rv.SetAccept(false)
+			})
+	})
+}
+
+func (rv *ReplacePanel) makeFiles(p *tree.Plan) {
+	for fi, fr := range rv.Files {
+		tree.AddAt(p, fmt.Sprintf("file-%d", fi), func(w *core.Switch) {
+			w.SetType(core.SwitchCheckbox)
+			w.Styler(func(s *styles.Style) {
+				s.Font.Weight = rich.Bold
+			})
+			w.OnChange(func(e events.Event) {
+				fr.SetAccept(w.StateIs(states.Checked))
+				rv.Update()
+			})
+			w.Updater(func() {
+				fn := rv.Code.FileTreeFor(fr.Filepath).RelativePathFrom(fsx.Filename(fr.Filepath))
+				w.SetText(fmt.Sprintf("%s: %d", fn, len(fr.Replacements)))
+				w.SetChecked(fr.Accepted() > 0)
+			})
+		})
+		for ri, r := range fr.Replacements {
+			tree.AddAt(p, fmt.Sprintf("repl-%d-%d", fi, ri), func(w *core.Frame) {
+				rv.makeReplacement(w, fr, r)
+			})
+		}
+	}
+}
+
+// makeReplacement configures the given frame to show the given replacement.
+func (rv *ReplacePanel) makeReplacement(w *core.Frame, fr *FileReplacements, r *Replacement) {
+	w.Styler(func(s *styles.Style) {
+		s.Align.Items = styles.Center
+		s.Padding.Left.Em(1)
+	})
+	tree.AddChild(w, func(w *core.Switch) {
+		w.SetType(core.SwitchCheckbox)
+		w.OnChange(func(e events.Event) {
+			r.Accept = w.StateIs(states.Checked)
+			rv.Update()
+		})
+		w.Updater(func() {
+			w.SetChecked(r.Accept)
+		})
+	})
+	tree.AddChild(w, func(w *core.Button) {
+		w.SetType(core.ButtonText).
+			SetText(fmt.Sprintf("%d:%d", r.Region.Start.Line+1, r.Region.Start.Char)).
+			SetTooltip("show the text to replace in the file").
+			OnClick(func(e events.Event) {
+				if tv, err := rv.Code.ShowFile(fr.Filepath, r.Region.Start.Line+1); err == nil {
+					tv.SetFocus()
+				}
+			})
+	})
+	tree.AddChild(w, func(w *core.Text) {
+		w.SetText(firstLine(r.Old) + " →")
+		w.Styler(func(s *styles.Style) {
+			s.Font.Family = rich.Monospace
+			s.Color = colors.Scheme.Error.Base
+		})
+	})
+	tree.AddChild(w, func(w *core.TextField) {
+		w.SetTooltip("text to replace with, which can be edited")
+		w.Styler(func(s *styles.Style) {
+			s.Font.Family = rich.Monospace
+			s.Grow.Set(1, 0)
+		})
+		w.OnChange(func(e events.Event) {
+			r.New = w.Text()
+			rv.TextEditor().Update()
+		})
+		w.Updater(func() {
+			w.SetText(r.New)
+		})
+	})
+}
+
+// firstLine returns the first line of the given text,
+// with ... appended if it has more lines.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + "..."
+	}
+	return s
+}
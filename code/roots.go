@@ -0,0 +1,57 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/filetree"
+)
+
+// FileTrees returns the file trees for all of the project roots,
+// starting with [Code.Files] for the ProjectRoot, followed by one
+// for each of the additional Roots in the project settings.
+func (cv *Code) FileTrees() []*filetree.Tree {
+	if cv.Files == nil {
+		return nil
+	}
+	var fts []*filetree.Tree
+	for _, k := range cv.Files.Parent.AsTree().Children {
+		if ft, ok := k.(*filetree.Tree); ok {
+			fts = append(fts, ft)
+		}
+	}
+	return fts
+}
+
+// FileTreeFor returns the file tree for the project root containing
+// the given file path, which is the innermost one if roots are nested,
+// and [Code.Files] if none of them contain it.
+func (cv *Code) FileTreeFor(fpath string) *filetree.Tree {
+	fpath = filepath.Clean(fpath)
+	tr, tlen := cv.Files, -1
+	for _, ft := range cv.FileTrees() {
+		rp := strings.TrimSuffix(string(ft.Filepath), string(filepath.Separator))
+		if ft.Filepath != "" && len(rp) > tlen && (fpath == rp || strings.HasPrefix(fpath, rp+string(filepath.Separator))) {
+			tr, tlen = ft, len(rp)
+		}
+	}
+	return tr
+}
+
+// FindFile finds the first node representing given file path
+// in any of the file trees, as in [filetree.Tree.FindFile].
+func (cv *Code) FindFile(fnm string) (*filetree.Node, bool) {
+	if fn, ok := cv.FileTreeFor(fnm).FindFile(fnm); ok {
+		return fn, true
+	}
+	for _, ft := range cv.FileTrees() {
+		if fn, ok := ft.FindFile(fnm); ok {
+			return fn, true
+		}
+	}
+	return nil, false
+}
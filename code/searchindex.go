@@ -0,0 +1,139 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cogentcore.org/cogent/code/index"
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/filetree"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/text/parse/syms"
+	"cogentcore.org/core/text/textpos"
+)
+
+// IndexSaveInterval is the minimum interval between saves of the search
+// index of a project as it is updated from change notifications.
+// The saved index is only a cache that is checked against the files
+// when the project is opened, so it need not be saved after every change.
+var IndexSaveInterval = time.Minute
+
+// DataDir returns the directory for the data that is kept about the project,
+// such as its search index, which is in the Cogent Code data directory,
+// named for the ProjectRoot along with a hash of its full path.
+func (cv *Code) DataDir() string {
+	root := string(cv.ProjectRoot)
+	h := fnv.New32a()
+	h.Write([]byte(root))
+	return filepath.Join(core.TheApp.AppDataDir(), "projects", fmt.Sprintf("%s-%08x", filepath.Base(root), h.Sum32()))
+}
+
+// startIndex opens the search [Code.Index] for the ProjectRoot from
+// the DataDir if it is not already open, and updates it in the background.
+// There is no index for a remote project or for running without a GUI.
+func (cv *Code) startIndex() {
+	if cv.IsRemote() || cv.Output != nil || cv.ProjectRoot == "" {
+		return
+	}
+	root, _ := filepath.Abs(string(cv.ProjectRoot))
+	if cv.Index != nil && cv.Index.Root == root {
+		return
+	}
+	fname := filepath.Join(cv.DataDir(), "index.gob")
+	ix, err := index.Open(root, fname)
+	errors.Log(err)
+	var mu sync.Mutex
+	var lastSave time.Time
+	save := func(force bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !force && time.Since(lastSave) < IndexSaveInterval {
+			return
+		}
+		lastSave = time.Now()
+		errors.Log(ix.Save(fname))
+	}
+	ix.OnUpdate = func() { save(false) }
+	errors.Log(cv.Index.Close())
+	cv.Index = ix
+	errors.Log(ix.Watch())
+	go func() {
+		errors.Log(ix.Build())
+		save(true)
+	}()
+}
+
+// addSearchFilesIndex adds the files and directories in the Index,
+// which is for the given file tree, to the menu search items.
+func (cv *Code) addSearchFilesIndex(items *[]core.ChooserItem, ft *filetree.Tree) {
+	root := string(ft.Filepath)
+	nmpath := func(fpath string) string {
+		rp, err := filepath.Rel(root, fpath)
+		if err != nil {
+			rp = fpath
+		}
+		return filepath.Base(fpath) + ":" + rp
+	}
+	for _, dir := range cv.Index.Dirs() {
+		*items = append(*items, core.ChooserItem{
+			Text: nmpath(dir),
+			Icon: icons.Folder,
+			Func: func() {
+				if fn, ok := cv.FindFile(dir); ok {
+					fn.Open()
+					fn.ScrollToThis()
+				}
+			},
+		})
+	}
+	for _, f := range cv.Index.Files() {
+		fi := &fileinfo.FileInfo{Path: f.Path, Mode: f.Mode, Known: f.Known}
+		if fi.IsExec() {
+			*items = append(*items, core.ChooserItem{
+				Text: nmpath(f.Path),
+				Icon: icons.FileExe,
+				Func: func() {
+					if fn, ok := cv.FindFile(f.Path); ok {
+						cv.FileNodeRunExe(fn)
+					}
+				},
+			})
+			continue
+		}
+		*items = append(*items, core.ChooserItem{
+			Text: nmpath(f.Path),
+			Icon: fileinfo.Icons[f.Known],
+			Func: func() {
+				cv.NextViewFile(f.Path)
+			},
+		})
+	}
+}
+
+// addSearchSymbolsIndex adds the Go symbols in the Index
+// to the menu search items.
+func (cv *Code) addSearchSymbolsIndex(items *[]core.ChooserItem) {
+	isyms, paths := cv.Index.Symbols()
+	for i, isy := range isyms {
+		sy := syms.Symbol{Name: isy.Name, Kind: isy.Kind, Filename: paths[i]}
+		nm := isy.Name[strings.LastIndex(isy.Name, ".")+1:]
+		sy.SelectReg = textpos.NewRegion(isy.Line, isy.Char, isy.Line, isy.Char+len([]rune(nm)))
+		*items = append(*items, core.ChooserItem{
+			Text: sy.Label(),
+			Icon: symbolIcon(sy.Kind),
+			Func: func() {
+				SelectSymbol(cv, sy)
+			},
+		})
+	}
+}
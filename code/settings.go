@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	"cogentcore.org/cogent/code/cdebug"
 	"cogentcore.org/core/base/errors"
@@ -49,6 +50,12 @@ type SettingsData struct { //types:add
 
 	// if set, the current customized set of command parameters (see Edit Cmds) is saved / loaded along with other settings -- if not set, then you always are using the default compiled-in standard set (which will be updated)
 	SaveCmds bool
+
+	// if set, text editors use Vim modal editing, starting in normal mode, with the current mode shown in the status bar
+	VimMode bool
+
+	// if set, text editors show the declaration lines of the function and type enclosing the top of the view above it while scrolling
+	StickyHeaders bool
 }
 
 // FileSettings contains file picker settings
@@ -92,6 +99,7 @@ func (se *SettingsData) Save() error {
 	}
 	AvailableSplits.SaveSettings()
 	AvailableRegisters.SaveSettings()
+	AvailableMacros.SaveSettings()
 	return err
 }
 
@@ -108,6 +116,8 @@ func (se *SettingsData) Open() error {
 	}
 	AvailableSplits.OpenSettings()
 	AvailableRegisters.OpenSettings()
+	AvailableMacros.OpenSettings()
+	errors.Log(OpenSnippetSettings())
 	return err
 }
 
@@ -139,6 +149,9 @@ func (se *SettingsData) MakeToolbar(p *tree.Plan) {
 	tree.Add(p, func(w *core.FuncButton) {
 		w.SetFunc(se.EditRegisters).SetIcon(icons.Variables)
 	})
+	tree.Add(p, func(w *core.FuncButton) {
+		w.SetFunc(se.EditMacros).SetIcon(icons.Keyboard)
+	})
 }
 
 // EditLangOpts opens the LangsView editor to customize options for each type of
@@ -174,6 +187,11 @@ func (se *SettingsData) EditRegisters() { //types:add
 	RegistersView(&AvailableRegisters)
 }
 
+// EditMacros opens the MacrosView editor to customize saved keyboard macros
+func (se *SettingsData) EditMacros() { //types:add
+	MacrosView(&AvailableMacros)
+}
+
 ////////   Project Settings
 
 // ProjectSettings are the settings for saving for a project. This IS the project file
@@ -205,6 +223,11 @@ type ProjectSettings struct { //types:add
 	// the scope of the project. By default it is the path for ProjectFilename
 	ProjectRoot core.Filename
 
+	// additional root directories for a multi-root project, each shown as
+	// a top-level node in the file tree, with its own version control and
+	// build directory. ProjectRoot is always the first root.
+	Roots []RootSettings
+
 	// if true, use Go modules, otherwise use GOPATH -- this sets your effective GO111MODULE environment variable accordingly, dynamically -- updated by toolbar checkbox, dynamically
 	GoMod bool
 
@@ -226,6 +249,14 @@ type ProjectSettings struct { //types:add
 	// custom debugger parameters for this project
 	Debug cdebug.Params
 
+	// named launch configurations for the debugger, which can be chosen
+	// on the Debug button
+	Launches []LaunchConfig
+
+	// name of the launch configuration used by the Debug button;
+	// if empty, the RunExec is debugged with the Debug parameters
+	DebugLaunch string `display:"-"`
+
 	// saved find params
 	Find FindParams `display:"-"`
 
@@ -256,6 +287,62 @@ func (se *ProjectSettings) Update() {
 	}
 }
 
+// RootSettings are the settings for an additional root directory
+// of a multi-root project, in [ProjectSettings.Roots].
+type RootSettings struct {
+
+	// root directory
+	Path core.Filename
+
+	// the type of version control system used in this root (git, svn, etc).
+	// filters commands available for files in this root
+	VersionControl vcs.Types
+
+	// build directory for files in this root, avail as {BuildDir} in commands;
+	// defaults to the root directory if empty
+	BuildDir core.Filename
+}
+
+// AllRoots returns the settings for all of the project roots,
+// starting with the ProjectRoot, followed by any additional Roots.
+func (se *ProjectSettings) AllRoots() []RootSettings {
+	rs := []RootSettings{{Path: se.ProjectRoot, VersionControl: se.VersionControl, BuildDir: se.BuildDir}}
+	return append(rs, se.Roots...)
+}
+
+// RootFor returns the settings for the project root containing the given
+// file path, which is the innermost one if roots are nested, and the
+// ProjectRoot if none of them contain it.
+func (se *ProjectSettings) RootFor(fpath string) RootSettings {
+	r, _ := se.RootContaining(fpath)
+	return r
+}
+
+// RootContaining returns the settings for the project root containing the
+// given file path, as in [ProjectSettings.RootFor], and false if none of the
+// roots contain it, in which case it is the ProjectRoot. An empty path is
+// in the ProjectRoot.
+func (se *ProjectSettings) RootContaining(fpath string) (RootSettings, bool) {
+	rs := se.AllRoots()
+	if fpath == "" {
+		return rs[0], true
+	}
+	fpath, _ = filepath.Abs(fpath)
+	ri, rlen := 0, -1
+	for i, r := range rs {
+		rp, _ := filepath.Abs(string(r.Path))
+		rp = strings.TrimSuffix(rp, string(filepath.Separator))
+		if len(rp) > rlen && (fpath == rp || strings.HasPrefix(fpath, rp+string(filepath.Separator))) {
+			ri, rlen = i, len(rp)
+		}
+	}
+	r := rs[ri]
+	if r.BuildDir == "" {
+		r.BuildDir = r.Path
+	}
+	return r, rlen >= 0
+}
+
 // Open open from file
 func (se *ProjectSettings) Open(filename core.Filename) error { //types:add
 	err := errors.Log(tomlx.Open(se, string(filename)))
@@ -328,7 +415,9 @@ func (cv *Code) ApplySettings() {
 	cv.ProjectRoot = cv.Settings.ProjectRoot
 	if cv.Files != nil {
 		cv.Files.Dirs = cv.Settings.Dirs
-		cv.Files.DirsOnTop = cv.Settings.Files.DirsOnTop
+		for _, ft := range cv.FileTrees() {
+			ft.DirsOnTop = cv.Settings.Files.DirsOnTop
+		}
 	}
 	if len(cv.Children) > 0 {
 		for i := 0; i < NTextEditors; i++ {
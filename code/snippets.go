@@ -0,0 +1,310 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"cogentcore.org/cogent/code/snippets"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/keymap"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/complete"
+	"cogentcore.org/core/text/textpos"
+)
+
+// AvailableSnippets are the snippets and templates for each language, which
+// are the [snippets.Standard] ones along with those in the snippet files in
+// the [SnippetsDir], which can be edited with [Code.EditSnippets].
+var AvailableSnippets = snippets.Standard.Clone()
+
+// SnippetsDirName is the name of the directory in the app settings
+// directory with the snippet files, one for each language, e.g., go.toml,
+// along with any.toml for the snippets in all languages.
+var SnippetsDirName = "snippets"
+
+// snippetIcon is the icon for snippets in the completion menu,
+// which identifies them as snippets.
+var snippetIcon = icons.CodeBlocks
+
+// SnippetsDir returns the directory with the snippet files.
+func SnippetsDir() string {
+	return filepath.Join(core.TheApp.AppDataDir(), SnippetsDirName)
+}
+
+// OpenSnippetSettings sets the [AvailableSnippets] to the standard snippets
+// along with those in the snippet files in the [SnippetsDir].
+func OpenSnippetSettings() error {
+	lb := snippets.Standard.Clone()
+	err := lb.Open(SnippetsDir())
+	AvailableSnippets = lb
+	return err
+}
+
+// TemplateName is the name of a template for a new file, which is
+// the language and name of the template snippet, e.g., Go: main.
+type TemplateName string
+
+// TemplateNames returns the names of all of the available templates.
+func TemplateNames() []string {
+	var nms []string
+	for lang := range AvailableSnippets {
+		for _, nm := range AvailableSnippets.Names(lang, true) {
+			nms = append(nms, lang.String()+": "+nm)
+		}
+	}
+	slices.Sort(nms)
+	return nms
+}
+
+// Template returns the language and snippet for the template,
+// and false if there is no such template.
+func (tn TemplateName) Template() (fileinfo.Known, *snippets.Snippet, bool) {
+	lnm, nm, ok := strings.Cut(string(tn), ": ")
+	if !ok {
+		return fileinfo.Unknown, nil, false
+	}
+	var lang fileinfo.Known
+	if err := lang.SetString(lnm); err != nil {
+		return fileinfo.Unknown, nil, false
+	}
+	sn, ok := AvailableSnippets.Snippet(lang, nm)
+	if !ok || !sn.Template {
+		return lang, nil, false
+	}
+	return lang, sn, true
+}
+
+// Value registers [core.Chooser] as the [core.Value] widget
+// for [TemplateName]
+func (tn TemplateName) Value() core.Value {
+	return core.NewChooser().SetStrings(append([]string{""}, TemplateNames()...)...)
+}
+
+// snippetVars returns the values of the argument variables
+// for a snippet inserted in the given editor.
+func (cv *Code) snippetVars(ed *TextEditor) map[string]string {
+	cv.ArgVals.Set(ed.Lines.Filename(), &cv.Settings, &ed.Editor)
+	return cv.ArgVals
+}
+
+// InsertSnippet prompts for a snippet for the language of the active file,
+// and inserts it at the cursor, replacing any selected text, which is
+// available in the snippet as {CurSel}. Snippets can also be inserted
+// by completing their prefix.
+func (cv *Code) InsertSnippet(ctx core.Widget) { //types:add
+	tv := cv.ActiveEditor()
+	if tv == nil || tv.Lines == nil {
+		return
+	}
+	lang := tv.Lines.FileInfo().Known
+	nms := AvailableSnippets.Names(lang, false)
+	if len(nms) == 0 {
+		core.MessageSnackbar(cv, fmt.Sprintf("There are no snippets for %v", lang))
+		return
+	}
+	m := core.NewMenuFromStrings(nms, "", func(idx int) {
+		sn, _ := AvailableSnippets.Snippet(lang, nms[idx])
+		tv.InsertSnippet(sn)
+	})
+	core.NewMenuStage(m, ctx, ctx.ContextMenuPos(nil)).Run()
+}
+
+// EditSnippets opens the snippet file for the language of the active file
+// in the [SnippetsDir], creating it with the standard snippets for the
+// language if it does not exist yet. The snippets are updated when it is saved.
+func (cv *Code) EditSnippets() { //types:add
+	lang := fileinfo.Any
+	if tv := cv.ActiveEditor(); tv != nil && tv.Lines != nil && tv.Lines.FileInfo().Known != fileinfo.Unknown {
+		lang = tv.Lines.FileInfo().Known
+	}
+	dir := SnippetsDir()
+	fname := filepath.Join(dir, snippets.Filename(lang))
+	if _, err := os.Stat(fname); os.IsNotExist(err) {
+		lb := snippets.Library{lang: snippets.Standard[lang]}
+		if err := lb.Save(dir, lang); err != nil {
+			core.ErrorSnackbar(cv, err, "Could not create snippet file")
+			return
+		}
+	}
+	cv.NextViewFile(fname)
+}
+
+// completeSnippets adds the snippets for the given language whose prefix
+// starts with the word being completed at the end of the given text,
+// replacing any other completions with the same text.
+func completeSnippets(md *complete.Matches, lang fileinfo.Known, txt string) {
+	seed := complete.SeedAfter(txt, func(r rune) bool {
+		return !isIdentRune(r)
+	})
+	if seed == "" || (md.Seed != "" && md.Seed != seed) {
+		return
+	}
+	nms := AvailableSnippets.Prefixed(lang, seed)
+	if len(nms) == 0 {
+		return
+	}
+	md.Seed = seed
+	for _, nm := range nms {
+		sn, _ := AvailableSnippets.Snippet(lang, nm)
+		md.Matches = slices.DeleteFunc(md.Matches, func(c complete.Completion) bool {
+			return c.Text == sn.Prefix
+		})
+		md.Matches = append(md.Matches, complete.Completion{Text: sn.Prefix, Label: sn.Prefix + ": " + nm, Icon: snippetIcon, Desc: sn.Desc})
+	}
+}
+
+// completeSnippet inserts the snippet for the completion selected in the
+// given editor, if it is a snippet, in which case the event is handled.
+func (cv *Code) completeSnippet(ed *TextEditor, e events.Event) {
+	c := ed.Complete.GetCompletion(ed.Complete.Completion)
+	if c.Icon != snippetIcon || ed.Lines == nil {
+		return
+	}
+	lang := ed.Lines.FileInfo().Known
+	for _, nm := range AvailableSnippets.Prefixed(lang, c.Text) {
+		sn, _ := AvailableSnippets.Snippet(lang, nm)
+		if sn.Prefix != c.Text {
+			continue
+		}
+		e.SetHandled()
+		pos := textpos.Pos{Line: ed.Complete.SrcLn, Char: ed.Complete.SrcCh}
+		st := pos
+		st.Char -= len([]rune(ed.Complete.Seed))
+		ed.Lines.DeleteText(st, pos)
+		ed.SetCursorShow(st)
+		ed.InsertSnippet(sn)
+		return
+	}
+}
+
+// snippetStop is a tab stop of a snippet inserted in a [TextEditor].
+// The start and end of the stop are each the start of a region that
+// extends beyond the end of the text, so that they are adjusted for
+// edits before them.
+type snippetStop struct {
+	start, end textpos.Region
+	choices    []string
+}
+
+// snippetStops are the tab stops of a snippet inserted in a [TextEditor].
+type snippetStops struct {
+	stops []snippetStop
+
+	// cur is the index of the current stop
+	cur int
+}
+
+// InsertSnippet inserts the given snippet at the cursor, replacing any
+// selected text, with the indentation of the current line, and selects
+// its first tab stop. Tab and Shift+Tab then move between the tab stops,
+// until the final one.
+func (ed *TextEditor) InsertSnippet(sn *snippets.Snippet) {
+	if ed.Lines == nil || sn == nil {
+		return
+	}
+	vars := ed.Code.snippetVars(ed)
+	ed.Lines.NewUndoGroup()
+	if sel := ed.Selection(); sel != nil {
+		ed.Lines.DeleteText(sel.Region.Start, sel.Region.End)
+		ed.SelectReset()
+		ed.SetCursorShow(sel.Region.Start)
+	}
+	pos := ed.CursorPos
+	ln := ed.Lines.Line(pos.Line)
+	ind := 0
+	for ind < len(ln) && (ln[ind] == ' ' || ln[ind] == '\t') {
+		ind++
+	}
+	ex := snippets.Expand(sn.Body, vars, string(ln[:ind]))
+	ed.Lines.InsertText(pos, []rune(ex.Text))
+	now := time.Now()
+	anchor := func(off int) textpos.Region {
+		reg := textpos.Region{Start: ex.Pos(pos, off), End: textpos.Pos{Line: math.MaxInt32}}
+		reg.Time.SetTime(now)
+		return reg
+	}
+	ss := &snippetStops{}
+	for _, st := range ex.Stops {
+		ss.stops = append(ss.stops, snippetStop{start: anchor(st.Start), end: anchor(st.End), choices: st.Choices})
+	}
+	ed.snippet = ss
+	ed.selectSnippetStop(0)
+}
+
+// selectSnippetStop selects the tab stop of the current snippet at
+// given index, ending the snippet if it is the final one.
+func (ed *TextEditor) selectSnippetStop(idx int) {
+	ss := ed.snippet
+	if ss == nil || idx < 0 || idx >= len(ss.stops) {
+		return
+	}
+	ss.cur = idx
+	if idx == len(ss.stops)-1 {
+		ed.snippet = nil
+	}
+	stop := ss.stops[idx]
+	st := ed.Lines.AdjustRegion(stop.start)
+	en := ed.Lines.AdjustRegion(stop.end)
+	if st.IsNil() || en.IsNil() { // deleted
+		return
+	}
+	reg := textpos.Region{Start: st.Start, End: en.Start}
+	ed.SelectReset()
+	if !reg.IsNil() {
+		ed.SelectRegion = reg
+	}
+	ed.SetCursorShow(reg.End)
+	ed.NeedsRender()
+	if len(stop.choices) > 0 {
+		ed.snippetChoices(reg, stop.choices)
+	}
+}
+
+// snippetChoices shows a menu of the choices for the text in the
+// given region of a snippet tab stop.
+func (ed *TextEditor) snippetChoices(reg textpos.Region, choices []string) {
+	reg.TimeNow()
+	cur := string(ed.Lines.Region(reg.Start, reg.End).ToBytes())
+	m := core.NewMenuFromStrings(choices, cur, func(idx int) {
+		reg := ed.Lines.AdjustRegion(reg)
+		if reg.IsNil() {
+			return
+		}
+		tbe := ed.Lines.ReplaceText(reg.Start, reg.End, reg.Start, choices[idx], lines.ReplaceNoMatchCase)
+		if tbe != nil {
+			ed.SelectRegion = tbe.Region
+			ed.SetCursorShow(tbe.Region.End)
+		}
+	})
+	core.NewMenuStage(m, ed, ed.ContextMenuPos(nil)).Run()
+}
+
+// snippetKeys handles the keys for moving between the tab stops
+// of the current snippet.
+func (ed *TextEditor) snippetKeys(e events.Event) {
+	if ed.snippet == nil {
+		return
+	}
+	switch keymap.Of(e.KeyChord()) {
+	case keymap.FocusNext:
+		e.SetHandled()
+		ed.selectSnippetStop(ed.snippet.cur + 1)
+	case keymap.FocusPrev:
+		e.SetHandled()
+		ed.selectSnippetStop(ed.snippet.cur - 1)
+	case keymap.Abort:
+		ed.snippet = nil
+	}
+}
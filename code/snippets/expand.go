@@ -0,0 +1,193 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snippets
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cogentcore.org/core/text/textpos"
+)
+
+// Expansion is the text of an expanded snippet, with its tab stops.
+type Expansion struct {
+
+	// Text is the expanded text.
+	Text string
+
+	// Stops are the tab stops in the text, in the order to visit them,
+	// which always ends with the final stop, $0.
+	Stops []Stop
+}
+
+// Stop is a tab stop in an [Expansion].
+type Stop struct {
+
+	// Index is the number of the stop, e.g., 1 for $1.
+	Index int
+
+	// Start and End are the rune offsets of the default text of
+	// the stop in the expanded text, which are equal if there is none.
+	Start, End int
+
+	// Choices are the choices for the text of the stop, if any,
+	// the first of which is the default text.
+	Choices []string
+}
+
+// Expand expands the given snippet body, replacing the argument variables
+// with their values in vars, where each variable name includes the braces,
+// e.g., {Filename}, and adding the given indent after each newline.
+// Braces that are not around a variable in vars are left as they are.
+// Tab stops are given by $n, or ${n:default} with default text, which can
+// include variables, or ${n|a,b,c|} with a list of choices, where $0 is the
+// final cursor position, which is at the end of the text if not specified.
+// If a stop appears more than once, the default text is repeated, and the
+// first one is the tab stop. A \ escapes $, {, }, and \ itself.
+func Expand(body string, vars map[string]string, indent string) *Expansion {
+	ex := &Expansion{}
+	var out []rune
+	rs := []rune(body)
+	n := len(rs)
+	var text func(s []rune) // appends text with variables and escapes
+	text = func(s []rune) {
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			switch {
+			case c == '\\' && i+1 < len(s) && strings.ContainsRune(`\${}`, s[i+1]):
+				i++
+				out = append(out, s[i])
+			case c == '\n':
+				out = append(out, c)
+				out = append(out, []rune(indent)...)
+			case c == '{':
+				if e := slices.Index(s[i:], '}'); e > 0 {
+					if val, ok := vars[string(s[i:i+e+1])]; ok {
+						out = append(out, []rune(val)...)
+						i += e
+						continue
+					}
+				}
+				out = append(out, c)
+			default:
+				out = append(out, c)
+			}
+		}
+	}
+	defaults := map[int]string{}
+	stop := func(idx int, def []rune, choices []string) {
+		if d, ok := defaults[idx]; ok {
+			text([]rune(d))
+			return
+		}
+		st := len(out)
+		text(def)
+		defaults[idx] = string(def)
+		ex.Stops = append(ex.Stops, Stop{Index: idx, Start: st, End: len(out), Choices: choices})
+	}
+	start := 0
+	for i := 0; i < n; i++ {
+		c := rs[i]
+		if c == '\\' && i+1 < n {
+			i++
+			continue
+		}
+		if c != '$' || i+1 >= n {
+			continue
+		}
+		idx, def, choices, end, ok := parseStop(rs, i+1)
+		if !ok {
+			continue
+		}
+		text(rs[start:i])
+		stop(idx, def, choices)
+		start = end
+		i = end - 1
+	}
+	text(rs[start:])
+	ex.Text = string(out)
+	slices.SortStableFunc(ex.Stops, func(a, b Stop) int {
+		if a.Index == 0 || b.Index == 0 {
+			return b.Index - a.Index
+		}
+		return a.Index - b.Index
+	})
+	if len(ex.Stops) == 0 || ex.Stops[len(ex.Stops)-1].Index != 0 {
+		ex.Stops = append(ex.Stops, Stop{Start: len(out), End: len(out)})
+	}
+	return ex
+}
+
+// parseStop parses a tab stop starting at given index, just after the $,
+// returning its index, default text, choices, and the index after it,
+// and false if it is not a tab stop.
+func parseStop(rs []rune, i int) (idx int, def []rune, choices []string, end int, ok bool) {
+	digits := func(i int) int {
+		for i < len(rs) && unicode.IsDigit(rs[i]) {
+			i++
+		}
+		return i
+	}
+	if unicode.IsDigit(rs[i]) {
+		end = digits(i)
+		idx, _ = strconv.Atoi(string(rs[i:end]))
+		return idx, nil, nil, end, true
+	}
+	if rs[i] != '{' {
+		return
+	}
+	de := digits(i + 1)
+	if de == i+1 || de >= len(rs) {
+		return
+	}
+	idx, _ = strconv.Atoi(string(rs[i+1 : de]))
+	switch rs[de] {
+	case '}':
+		return idx, nil, nil, de + 1, true
+	case ':':
+		depth := 0
+		for j := de + 1; j < len(rs); j++ {
+			switch rs[j] {
+			case '\\':
+				j++
+			case '{':
+				depth++
+			case '}':
+				if depth == 0 {
+					return idx, rs[de+1 : j], nil, j + 1, true
+				}
+				depth--
+			}
+		}
+	case '|':
+		for j := de + 1; j+1 < len(rs); j++ {
+			if rs[j] == '|' && rs[j+1] == '}' {
+				choices = strings.Split(string(rs[de+1:j]), ",")
+				return idx, []rune(choices[0]), choices, j + 2, true
+			}
+		}
+	}
+	return
+}
+
+// Pos returns the position in the text that the given rune offset in
+// the expanded text is at, when the text starts at the given position.
+func (ex *Expansion) Pos(start textpos.Pos, off int) textpos.Pos {
+	pos := start
+	for i, r := range []rune(ex.Text) {
+		if i >= off {
+			break
+		}
+		if r == '\n' {
+			pos.Line++
+			pos.Char = 0
+		} else {
+			pos.Char++
+		}
+	}
+	return pos
+}
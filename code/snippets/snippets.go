@@ -0,0 +1,198 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package snippets provides parameterized snippets of text, and templates
+// for new files, for each language, which are saved in toml files.
+// The body of a snippet can have tab stops with default text and choices,
+// and argument variables such as {Filename}, as described in [Expand].
+package snippets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/base/iox/tomlx"
+)
+
+// Snippet is a parameterized snippet of text.
+type Snippet struct {
+
+	// Prefix is the text that is completed to the snippet, e.g., iferr.
+	Prefix string
+
+	// Desc is a description of the snippet.
+	Desc string
+
+	// Body is the text of the snippet, with tab stops and argument
+	// variables as described in [Expand].
+	Body string `toml:",multiline"`
+
+	// Template indicates that the snippet is a template for a new file,
+	// which is not offered for completion.
+	Template bool
+}
+
+// Snippets are the snippets for one language, by name.
+type Snippets map[string]*Snippet
+
+// Library has the snippets for each language, with those for
+// [fileinfo.Any] available in all languages.
+type Library map[fileinfo.Known]Snippets
+
+// Extension is the extension of snippet files.
+const Extension = ".toml"
+
+// Filename returns the name of the file for the snippets of given language,
+// which is the lowercase name of the language, e.g., go.toml.
+func Filename(lang fileinfo.Known) string {
+	return strings.ToLower(lang.String()) + Extension
+}
+
+// Language returns the language for the given snippet file name,
+// and false if it is not a snippet file for a known language.
+func Language(filename string) (fileinfo.Known, bool) {
+	nm, ok := strings.CutSuffix(filepath.Base(filename), Extension)
+	if !ok {
+		return fileinfo.Unknown, false
+	}
+	for _, k := range fileinfo.KnownValues() {
+		if k != fileinfo.Unknown && strings.EqualFold(k.String(), nm) {
+			return k, true
+		}
+	}
+	return fileinfo.Unknown, false
+}
+
+// Clone returns a copy of the library, with the same snippets.
+func (lb Library) Clone() Library {
+	cp := make(Library, len(lb))
+	for lang, sn := range lb {
+		cp[lang] = make(Snippets, len(sn))
+		for nm, s := range sn {
+			cp[lang][nm] = s
+		}
+	}
+	return cp
+}
+
+// Open adds the snippets in the snippet files in the given directory
+// to the library, replacing any with the same name. It is not an
+// error if the directory does not exist.
+func (lb Library) Open(dir string) error {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var errs []error
+	for _, de := range des {
+		lang, ok := Language(de.Name())
+		if de.IsDir() || !ok {
+			continue
+		}
+		sn := Snippets{}
+		if err := tomlx.Open(&sn, filepath.Join(dir, de.Name())); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if lb[lang] == nil {
+			lb[lang] = Snippets{}
+		}
+		for nm, s := range sn {
+			lb[lang][nm] = s
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Save saves the snippets for the given language to its snippet file
+// in the given directory, creating the directory if needed.
+func (lb Library) Save(dir string, lang fileinfo.Known) error {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+	sn := lb[lang]
+	if sn == nil {
+		sn = Snippets{}
+	}
+	return tomlx.Save(sn, filepath.Join(dir, Filename(lang)))
+}
+
+// Names returns the sorted names of the snippets for the given language,
+// including those for all languages, which are the templates if template
+// is true, and otherwise the other snippets.
+func (lb Library) Names(lang fileinfo.Known, template bool) []string {
+	var nms []string
+	for _, l := range []fileinfo.Known{lang, fileinfo.Any} {
+		for nm, s := range lb[l] {
+			if s.Template == template && !slices.Contains(nms, nm) {
+				nms = append(nms, nm)
+			}
+		}
+		if lang == fileinfo.Any {
+			break
+		}
+	}
+	slices.Sort(nms)
+	return nms
+}
+
+// Snippet returns the snippet with the given name for the given language,
+// or for all languages, and false if there is no such snippet.
+func (lb Library) Snippet(lang fileinfo.Known, name string) (*Snippet, bool) {
+	if s, ok := lb[lang][name]; ok {
+		return s, true
+	}
+	s, ok := lb[fileinfo.Any][name]
+	return s, ok
+}
+
+// Prefixed returns the names of the snippets for the given language,
+// not including templates, whose prefix starts with the given seed.
+func (lb Library) Prefixed(lang fileinfo.Known, seed string) []string {
+	var nms []string
+	for _, nm := range lb.Names(lang, false) {
+		s, _ := lb.Snippet(lang, nm)
+		if s.Prefix != "" && strings.HasPrefix(s.Prefix, seed) {
+			nms = append(nms, nm)
+		}
+	}
+	return nms
+}
+
+// Standard are the standard snippets that are compiled into the program.
+var Standard = Library{
+	fileinfo.Go: {
+		"if err": {Prefix: "iferr", Desc: "return if there is an error",
+			Body: "if err != nil {\n\treturn ${1:err}\n}$0"},
+		"for range": {Prefix: "forr", Desc: "for range loop",
+			Body: "for ${1:i}, ${2:v} := range ${3:s} {\n\t$0\n}"},
+		"func": {Prefix: "func", Desc: "function declaration",
+			Body: "func ${1:name}($2) $3{\n\t$0\n}"},
+		"method": {Prefix: "meth", Desc: "method declaration",
+			Body: "func (${1:r} ${2:*T}) ${3:name}($4) $5{\n\t$0\n}"},
+		"test": {Prefix: "test", Desc: "test function",
+			Body: "func Test${1:Name}(t *testing.T) {\n\t$0\n}"},
+		"switch type": {Prefix: "switcht", Desc: "type switch",
+			Body: "switch ${1:x} := ${1:x}.(type) {\ncase ${2:T}:\n\t$0\n}"},
+		"printf": {Prefix: "printf", Desc: "formatted print",
+			Body: "fmt.${1|Printf,Sprintf,Errorf|}(\"${2:%v}\\n\", $3)$0"},
+		"package": {Desc: "package file", Template: true,
+			Body: "// Copyright (c) ${1:2025}, ${2:Author}. All rights reserved.\n\npackage ${3:{FileDir}}\n\n$0\n"},
+		"main": {Desc: "main program", Template: true,
+			Body: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"${1:Hello, World!}\")$0\n}\n"},
+		"test file": {Desc: "test file", Template: true,
+			Body: "package ${1:{FileDir}}\n\nimport \"testing\"\n\nfunc Test${2:Name}(t *testing.T) {\n\t$0\n}\n"},
+	},
+	fileinfo.Markdown: {
+		"link": {Prefix: "link", Desc: "link", Body: "[${1:text}](${2:url})$0"},
+		"code": {Prefix: "code", Desc: "code block", Body: "```${1|go,sh,toml|}\n$0\n```"},
+	},
+}
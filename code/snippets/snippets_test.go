@@ -0,0 +1,58 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package snippets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"{FileDir}": "code", "{CurWord}": "x"}
+	ex := Expand("package ${1:{FileDir}}\n\nfunc ${2:name}() {\n\treturn {CurWord}$0\n}", vars, "\t")
+	assert.Equal(t, "package code\n\t\n\tfunc name() {\n\t\treturn x\n\t}", ex.Text)
+	assert.Equal(t, []Stop{{Index: 1, Start: 8, End: 12}, {Index: 2, Start: 21, End: 25}, {Index: 0, Start: 40, End: 40}}, ex.Stops)
+	assert.Equal(t, textpos.Pos{Line: 3, Char: 10}, ex.Pos(textpos.Pos{Line: 0, Char: 1}, 40))
+
+	ex = Expand("fmt.${1|Printf,Errorf|}($2, ${2}) costs \\$5 in {Braces}", vars, "")
+	assert.Equal(t, "fmt.Printf(, ) costs $5 in {Braces}", ex.Text)
+	assert.Equal(t, []Stop{{Index: 1, Start: 4, End: 10, Choices: []string{"Printf", "Errorf"}}, {Index: 2, Start: 11, End: 11}, {Index: 0, Start: 35, End: 35}}, ex.Stops)
+
+	ex = Expand("${2:b} ${1:a}$", nil, "")
+	assert.Equal(t, "b a$", ex.Text)
+	assert.Equal(t, []int{1, 2, 0}, []int{ex.Stops[0].Index, ex.Stops[1].Index, ex.Stops[2].Index})
+}
+
+func TestLibrary(t *testing.T) {
+	lang, ok := Language("/a/go.toml")
+	assert.True(t, ok)
+	assert.Equal(t, fileinfo.Go, lang)
+	_, ok = Language("go.json")
+	assert.False(t, ok)
+
+	dir := t.TempDir()
+	lb := Standard.Clone()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "any.toml"), []byte("[todo]\nPrefix = \"todo\"\nBody = \"TODO: $0\"\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "go.toml"), []byte("[func]\nPrefix = \"fn\"\nBody = \"\"\"\nfunc $1() {\n}\"\"\"\n"), 0644))
+	assert.NoError(t, lb.Open(dir))
+	assert.Equal(t, "fn", lb[fileinfo.Go]["func"].Prefix)
+	assert.Equal(t, "func", Standard[fileinfo.Go]["func"].Prefix)
+	assert.Equal(t, []string{"for range", "func"}, lb.Prefixed(fileinfo.Go, "f"))
+	assert.Equal(t, []string{"todo"}, lb.Prefixed(fileinfo.Go, "to"))
+	assert.Contains(t, lb.Names(fileinfo.Go, true), "main")
+	assert.NotContains(t, lb.Names(fileinfo.Go, false), "main")
+	_, ok = lb.Snippet(fileinfo.Python, "todo")
+	assert.True(t, ok)
+
+	assert.NoError(t, lb.Save(dir, fileinfo.Go))
+	ob := Library{}
+	assert.NoError(t, ob.Open(dir))
+	assert.Equal(t, lb[fileinfo.Go], ob[fileinfo.Go])
+}
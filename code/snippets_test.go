@@ -0,0 +1,56 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"testing"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/complete"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteSnippets(t *testing.T) {
+	md := complete.Matches{Seed: "for", Matches: complete.Completions{{Text: "for"}, {Text: "forr"}, {Text: "format"}}}
+	completeSnippets(&md, fileinfo.Go, "\tforr")
+	assert.Equal(t, "for", md.Seed)
+	md = complete.Matches{}
+	completeSnippets(&md, fileinfo.Go, "\tx := forr")
+	assert.Equal(t, "forr", md.Seed)
+	if assert.Len(t, md.Matches, 1) {
+		assert.Equal(t, "forr", md.Matches[0].Text)
+		assert.Equal(t, snippetIcon, md.Matches[0].Icon)
+	}
+	md = complete.Matches{Seed: "fo", Matches: complete.Completions{{Text: "forr"}, {Text: "format"}}}
+	completeSnippets(&md, fileinfo.Go, "fo")
+	assert.Equal(t, []string{"format", "forr"}, []string{md.Matches[0].Text, md.Matches[1].Text})
+	assert.Equal(t, snippetIcon, md.Matches[1].Icon)
+
+	lang, sn, ok := TemplateName("Go: main").Template()
+	assert.True(t, ok)
+	assert.Equal(t, fileinfo.Go, lang)
+	assert.Equal(t, AvailableSnippets[fileinfo.Go]["main"], sn)
+	_, _, ok = TemplateName("Go: if err").Template()
+	assert.False(t, ok)
+	assert.Contains(t, TemplateNames(), "Go: main")
+}
+
+func TestArgVarText(t *testing.T) {
+	tv := textcore.NewEditor()
+	tv.SetLines(lines.NewLines().SetText([]byte("func hello() {\n\treturn\n}\n")))
+	tv.CursorPos = textpos.Pos{Line: 0, Char: 6}
+	var avp ArgVarVals
+	avp.SetCursor(tv.CursorPos, tv.SelectRegion)
+	avp.SetText(tv)
+	assert.Equal(t, "hello", avp["{CurWord}"])
+	assert.Equal(t, "func hello() {", avp["{CurLineText}"])
+	assert.Equal(t, "", avp["{CurSel}"])
+	tv.SelectRegion = textpos.NewRegion(1, 1, 1, 7)
+	avp.SetText(tv)
+	assert.Equal(t, "return", avp["{CurSel}"])
+}
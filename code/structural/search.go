@@ -0,0 +1,177 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package structural
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/text/search"
+	"cogentcore.org/core/text/textpos"
+)
+
+// Search returns the number of matches of the pattern in the given
+// Go source, and the matches, with positions in runes as for
+// [search.Reader], so that they can be shown with other search results.
+// The region of a match can span multiple lines, in which case its
+// text is that of its first line.
+func (p *Pattern) Search(src []byte) (int, []textpos.Match, error) {
+	ms, err := p.Find(src)
+	if err != nil || len(ms) == 0 {
+		return 0, nil, err
+	}
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	starts := make([]int, len(lines))
+	for i := 1; i < len(lines); i++ {
+		starts[i] = starts[i-1] + len(lines[i-1])
+	}
+	pos := func(off int) textpos.Pos {
+		ln := sort.Search(len(starts), func(i int) bool { return starts[i] > off }) - 1
+		return textpos.Pos{Line: ln, Char: utf8.RuneCount(src[starts[ln]:off])}
+	}
+	matches := make([]textpos.Match, len(ms))
+	for i, m := range ms {
+		st, ed := pos(m.Start), pos(m.End)
+		rn := bytes.Runes(bytes.TrimRight(lines[st.Line], "\r\n"))
+		ech := len(rn)
+		if ed.Line == st.Line {
+			ech = ed.Char
+		}
+		matches[i] = textpos.NewMatch(rn, st.Char, ech, st.Line)
+		matches[i].Region.End = ed
+	}
+	return len(ms), matches, nil
+}
+
+// skipDir returns whether the directory of the given name is skipped
+// when searching recursively: hidden directories, e.g., .git, and
+// those of dependencies.
+func skipDir(name string) bool {
+	return strings.HasPrefix(name, ".") || name == "vendor" || name == "node_modules"
+}
+
+// Paths returns the Go files in given list of paths that contain matches
+// of the pattern, sorted in descending order by number of matches,
+// as for [search.Paths], or [search.All] if all is true, in which case
+// the paths are searched recursively, including all subdirectories.
+// The files are read from the given file system, or the local disk
+// if it is nil. Generated files, and files that match any of the exclude
+// glob patterns, are skipped, as are files that fail to parse, and
+// hidden directories, e.g., .git, and those of dependencies.
+func (p *Pattern) Paths(fsys fs.FS, paths []string, all bool, exclude ...string) ([]search.Results, error) {
+	var res []search.Results
+	var errs []error
+	file := func(fpath string, d fs.DirEntry) {
+		if path.Ext(fpath) != ".go" || excluded(exclude, d.Name(), fpath) {
+			return
+		}
+		info, err := d.Info()
+		if err != nil || int(info.Size()) > core.SystemSettings.BigFileSize {
+			return
+		}
+		var b []byte
+		if fsys != nil {
+			b, err = fs.ReadFile(fsys, fpath)
+		} else {
+			b, err = os.ReadFile(fpath)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		cnt, matches, err := p.Search(b)
+		if cnt == 0 || err != nil {
+			return
+		}
+		fi := &fileinfo.FileInfo{} // only sniffed for generated files when there are matches
+		if fsys != nil {
+			fi.InitFileFS(fsys, fpath)
+		} else {
+			fi.InitFile(fpath)
+		}
+		if !fi.Generated {
+			res = append(res, search.Results{Filepath: fpath, Count: cnt, Matches: matches})
+		}
+	}
+	for _, dir := range paths {
+		if fsys == nil {
+			dir, _ = filepath.Abs(dir)
+		}
+		if all {
+			walk := func(fpath string, d fs.DirEntry, err error) error {
+				if err != nil {
+					errs = append(errs, err)
+					return nil
+				}
+				if d.IsDir() {
+					if fpath != dir && skipDir(d.Name()) {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				file(fpath, d)
+				return nil
+			}
+			var err error
+			if fsys == nil {
+				err = filepath.WalkDir(dir, walk)
+			} else {
+				err = fs.WalkDir(fsys, dir, walk)
+			}
+			if err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		var des []fs.DirEntry
+		var err error
+		if fsys == nil {
+			des, err = os.ReadDir(dir)
+		} else {
+			des, err = fs.ReadDir(fsys, dir)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, d := range des {
+			if d.IsDir() {
+				continue
+			}
+			if fsys == nil {
+				file(filepath.Join(dir, d.Name()), d)
+			} else {
+				file(path.Join(dir, d.Name()), d)
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Count > res[j].Count
+	})
+	return res, errors.Join(errs...)
+}
+
+// excluded returns true if given file name or path matches
+// any of the given exclude glob patterns.
+func excluded(exclude []string, fname, fpath string) bool {
+	for _, ex := range exclude {
+		if m, _ := path.Match(ex, fpath); m {
+			return true
+		}
+		if m, _ := path.Match(ex, fname); m {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,372 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package structural provides structural search and replace for Go,
+// matching syntax trees against a pattern with metavariables, such as
+// fmt.Errorf($msg), which can be replaced with e.g., errors.New($msg).
+package structural
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// varPrefix is the prefix of the identifiers that the $name metavariables
+// are turned into, so that a pattern can be parsed as Go.
+const varPrefix = "__structural_"
+
+// Pattern is a compiled structural search pattern, which is a Go
+// expression, statement or list of statements in which metavariables,
+// written as $name, match any expression (or any statement, when used
+// as a statement on their own). A metavariable that is used more than
+// once must match the same code each time.
+type Pattern struct {
+
+	// Source is the pattern as given to [Compile].
+	Source string
+
+	// nodes are the parsed pattern, which is either a single node
+	// or a sequence of statements.
+	nodes []ast.Node
+}
+
+// Match is one match of a [Pattern] in a source.
+type Match struct {
+
+	// Start and End are the byte offsets of the match in the source.
+	Start, End int
+
+	// Vars are the source text of the code matched by each metavariable,
+	// by name (without the $).
+	Vars map[string]string
+}
+
+// Compile parses the given pattern.
+func Compile(pat string) (*Pattern, error) {
+	src, err := substituteVars(pat)
+	if err != nil {
+		return nil, err
+	}
+	p := &Pattern{Source: pat}
+	if x, err := parser.ParseExpr(src); err == nil {
+		p.nodes = []ast.Node{x}
+	} else {
+		stmts, serr := parseStmts(token.NewFileSet(), []byte(src))
+		if serr != nil {
+			return nil, fmt.Errorf("structural: invalid Go pattern %q: %w", pat, err)
+		}
+		if len(stmts) == 0 {
+			return nil, errors.New("structural: empty pattern")
+		}
+		for _, s := range stmts {
+			p.nodes = append(p.nodes, s)
+		}
+		if len(p.nodes) == 1 {
+			if es, ok := p.nodes[0].(*ast.ExprStmt); ok {
+				p.nodes[0] = es.X
+			}
+		}
+	}
+	if len(p.nodes) == 1 && varName(p.nodes[0]) != "" {
+		return nil, fmt.Errorf("structural: pattern %q matches everything", pat)
+	}
+	return p, nil
+}
+
+// substituteVars returns the given pattern with its $name metavariables
+// turned into identifiers, ignoring any $ in strings and comments.
+func substituteVars(pat string) (string, error) {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	b := []byte(pat)
+	s.Init(fset.AddFile("", -1, len(b)), b, nil, 0)
+	var sb strings.Builder
+	last := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		if tok != token.ILLEGAL || lit != "$" {
+			continue
+		}
+		off := int(pos) - 1
+		npos, ntok, nlit := s.Scan()
+		if ntok != token.IDENT || int(npos)-1 != off+1 {
+			return "", fmt.Errorf("structural: $ must be followed by a name in pattern %q", pat)
+		}
+		sb.WriteString(pat[last:off])
+		sb.WriteString(varPrefix + nlit)
+		last = off + 1 + len(nlit)
+	}
+	sb.WriteString(pat[last:])
+	return sb.String(), nil
+}
+
+// varName returns the name of the metavariable that the given
+// pattern node is, or "" if it is not one.
+func varName(n ast.Node) string {
+	switch x := n.(type) {
+	case *ast.Ident:
+		if x != nil && strings.HasPrefix(x.Name, varPrefix) {
+			return strings.TrimPrefix(x.Name, varPrefix)
+		}
+	case *ast.ExprStmt:
+		if x != nil {
+			return varName(x.X)
+		}
+	}
+	return ""
+}
+
+// stmtsPrefix is the code that a list of statements is wrapped in
+// for parsing, along with the closing brace.
+const stmtsPrefix = "package p; func _() {\n"
+
+// parseStmts parses the given source as a list of statements.
+func parseStmts(fset *token.FileSet, src []byte) ([]ast.Stmt, error) {
+	f, err := parser.ParseFile(fset, "", stmtsPrefix+string(src)+"\n}", parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	return f.Decls[0].(*ast.FuncDecl).Body.List, nil
+}
+
+// source is a parsed source to search in.
+type source struct {
+	fset *token.FileSet
+	root ast.Node
+	src  []byte
+
+	// offset is the offset of the parsed code relative to src,
+	// for statements, which are wrapped in a function to parse them.
+	offset int
+}
+
+// parse parses the given source as a Go file, or otherwise
+// an expression or list of statements, e.g., for the text of a match.
+func parse(src []byte) (*source, error) {
+	sc := &source{fset: token.NewFileSet(), src: src}
+	f, err := parser.ParseFile(sc.fset, "", src, parser.SkipObjectResolution)
+	if err == nil {
+		sc.root = f
+		return sc, nil
+	}
+	if x, xerr := parser.ParseExprFrom(sc.fset, "", src, parser.SkipObjectResolution); xerr == nil {
+		sc.root = x
+		return sc, nil
+	}
+	stmts, serr := parseStmts(sc.fset, src)
+	if serr != nil {
+		return nil, serr
+	}
+	sc.root = &ast.BlockStmt{List: stmts}
+	sc.offset = -len(stmtsPrefix)
+	return sc, nil
+}
+
+// offsets returns the start and end offsets of given node in the source.
+func (sc *source) offsets(n ast.Node) (start, end int) {
+	start = sc.fset.Position(n.Pos()).Offset + sc.offset
+	end = sc.fset.Position(n.End()).Offset + sc.offset
+	return
+}
+
+// Find returns the matches of the pattern in the given Go source, which
+// is a Go file, or an expression or list of statements. Matches do not
+// overlap; where one is nested in another, only the outer one is returned.
+func (p *Pattern) Find(src []byte) ([]Match, error) {
+	sc, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return p.find(sc), nil
+}
+
+func (p *Pattern) find(sc *source) []Match {
+	var ms []Match
+	last := 0
+	add := func(vars map[string]reflect.Value, first, final ast.Node) bool {
+		st, _ := sc.offsets(first)
+		_, ed := sc.offsets(final)
+		if st < last {
+			return false
+		}
+		m := Match{Start: st, End: ed, Vars: make(map[string]string, len(vars))}
+		for nm, v := range vars {
+			vs, ve := sc.offsets(v.Interface().(ast.Node))
+			m.Vars[nm] = string(sc.src[vs:ve])
+		}
+		ms = append(ms, m)
+		last = ed
+		return true
+	}
+	ast.Inspect(sc.root, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		if len(p.nodes) == 1 {
+			vars := map[string]reflect.Value{}
+			if match(vars, reflect.ValueOf(p.nodes[0]), reflect.ValueOf(n)) {
+				return !add(vars, n, n)
+			}
+			return true
+		}
+		var list []ast.Stmt
+		switch x := n.(type) {
+		case *ast.BlockStmt:
+			list = x.List
+		case *ast.CaseClause:
+			list = x.Body
+		case *ast.CommClause:
+			list = x.Body
+		}
+		for i := 0; i+len(p.nodes) <= len(list); i++ {
+			vars := map[string]reflect.Value{}
+			ok := true
+			for j, pn := range p.nodes {
+				if !match(vars, reflect.ValueOf(pn), reflect.ValueOf(list[i+j])) {
+					ok = false
+					break
+				}
+			}
+			if ok && add(vars, list[i], list[i+len(p.nodes)-1]) {
+				i += len(p.nodes) - 1
+			}
+		}
+		return true
+	})
+	return ms
+}
+
+var (
+	identType    = reflect.TypeFor[*ast.Ident]()
+	exprStmtType = reflect.TypeFor[*ast.ExprStmt]()
+	objectType   = reflect.TypeFor[*ast.Object]()
+	scopeType    = reflect.TypeFor[*ast.Scope]()
+	posType      = reflect.TypeFor[token.Pos]()
+	callType     = reflect.TypeFor[*ast.CallExpr]()
+)
+
+// match returns whether the given pattern matches the given value,
+// recording the metavariables in vars; if vars is nil, then metavariables
+// only match themselves, which is used to check that a metavariable that
+// occurs more than once matches the same code each time.
+// This is based on the matching in gofmt -r.
+func match(vars map[string]reflect.Value, pat, val reflect.Value) bool {
+	if vars != nil && pat.IsValid() && val.IsValid() && (pat.Type() == identType || pat.Type() == exprStmtType) {
+		if nm := varName(pat.Interface().(ast.Node)); nm != "" {
+			_, isExpr := val.Interface().(ast.Expr)
+			_, isStmt := val.Interface().(ast.Stmt)
+			if (isExpr || (isStmt && pat.Type() == exprStmtType)) && !val.IsNil() {
+				if old, ok := vars[nm]; ok {
+					return match(nil, old, val)
+				}
+				vars[nm] = val
+				return true
+			}
+		}
+	}
+	if !pat.IsValid() || !val.IsValid() {
+		return !pat.IsValid() && !val.IsValid()
+	}
+	if pat.Type() != val.Type() {
+		return false
+	}
+	switch pat.Type() {
+	case identType:
+		p := pat.Interface().(*ast.Ident)
+		v := val.Interface().(*ast.Ident)
+		return p == nil && v == nil || p != nil && v != nil && p.Name == v.Name
+	case objectType, scopeType, posType:
+		return true
+	case callType:
+		// f(x) and f(x...) differ only in the position of the Ellipsis
+		p := pat.Interface().(*ast.CallExpr)
+		v := val.Interface().(*ast.CallExpr)
+		if p != nil && v != nil && p.Ellipsis.IsValid() != v.Ellipsis.IsValid() {
+			return false
+		}
+	}
+	p := reflect.Indirect(pat)
+	v := reflect.Indirect(val)
+	if !p.IsValid() || !v.IsValid() {
+		return !p.IsValid() && !v.IsValid()
+	}
+	switch p.Kind() {
+	case reflect.Slice:
+		if p.Len() != v.Len() {
+			return false
+		}
+		for i := range p.Len() {
+			if !match(vars, p.Index(i), v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := range p.NumField() {
+			if !match(vars, p.Field(i), v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		if p.IsNil() || v.IsNil() {
+			return p.IsNil() && v.IsNil()
+		}
+		return match(vars, p.Elem(), v.Elem())
+	}
+	return p.Interface() == v.Interface()
+}
+
+// varRegexp matches the $name metavariables in a replacement.
+var varRegexp = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Expand returns the given replacement text with each of its $name
+// metavariables replaced with the code they matched in the given match.
+// It returns an error for a metavariable that is not in the pattern.
+func (m *Match) Expand(repl string) (string, error) {
+	var err error
+	res := varRegexp.ReplaceAllStringFunc(repl, func(s string) string {
+		v, ok := m.Vars[s[1:]]
+		if !ok {
+			err = fmt.Errorf("structural: replacement variable %s is not in the pattern", s)
+			return s
+		}
+		return v
+	})
+	return res, err
+}
+
+// Replace returns the given source with all of the matches of the
+// pattern replaced with the given replacement text, which has the
+// code matched by the pattern metavariables substituted for them
+// as in [Match.Expand]. The source is parsed as in [Pattern.Find],
+// so it can be the text of a match.
+func (p *Pattern) Replace(src []byte, repl string) ([]byte, error) {
+	ms, err := p.Find(src)
+	if err != nil {
+		return nil, err
+	}
+	var b []byte
+	last := 0
+	for _, m := range ms {
+		r, err := m.Expand(repl)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, src[last:m.Start]...)
+		b = append(b, r...)
+		last = m.End
+	}
+	return append(b, src[last:]...), nil
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package structural
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSrc = `package a
+
+import "fmt"
+
+func A(x int) error {
+	if x > 0 {
+		return fmt.Errorf("positive: %d", x)
+	}
+	if err := check(x); err != nil {
+		return err
+	}
+	return fmt.Errorf("négatif")
+}
+
+func B() error {
+	fmt.Println("$msg")
+	return fmt.Errorf(fmt.Sprint(x, x))
+}
+`
+
+func TestFind(t *testing.T) {
+	p, err := Compile(`fmt.Errorf($msg)`)
+	assert.NoError(t, err)
+	ms, err := p.Find([]byte(testSrc))
+	assert.NoError(t, err)
+	if assert.Len(t, ms, 2) {
+		assert.Equal(t, `fmt.Errorf("négatif")`, testSrc[ms[0].Start:ms[0].End])
+		assert.Equal(t, map[string]string{"msg": `"négatif"`}, ms[0].Vars)
+		assert.Equal(t, "fmt.Sprint(x, x)", ms[1].Vars["msg"])
+	}
+
+	p, err = Compile(`fmt.Sprint($x, $x)`)
+	assert.NoError(t, err)
+	ms, _ = p.Find([]byte(testSrc))
+	assert.Len(t, ms, 1)
+	p, err = Compile(`fmt.Errorf($f, $x)`)
+	assert.NoError(t, err)
+	ms, _ = p.Find([]byte(testSrc))
+	assert.Len(t, ms, 1)
+
+	p, err = Compile(`fmt.Println("$msg")`)
+	assert.NoError(t, err)
+	ms, _ = p.Find([]byte(testSrc))
+	assert.Len(t, ms, 1)
+
+	p, err = Compile("if $e := $call; $e != nil {\n\treturn $e\n}")
+	assert.NoError(t, err)
+	ms, _ = p.Find([]byte(testSrc))
+	if assert.Len(t, ms, 1) {
+		assert.Equal(t, "check(x)", ms[0].Vars["call"])
+	}
+
+	p, err = Compile("fmt.Println($x)\n$s")
+	assert.NoError(t, err)
+	ms, _ = p.Find([]byte(testSrc))
+	if assert.Len(t, ms, 1) {
+		assert.Equal(t, "return fmt.Errorf(fmt.Sprint(x, x))", ms[0].Vars["s"])
+	}
+
+	_, err = Compile(`$x`)
+	assert.Error(t, err)
+	_, err = Compile(`fmt.Errorf(`)
+	assert.Error(t, err)
+	_, err = parse([]byte(`fmt.Errorf(`))
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "package", "error of the statements, not the file")
+	}
+	_, err = Compile(`fmt.Errorf($)`)
+	assert.Error(t, err)
+}
+
+func TestReplace(t *testing.T) {
+	p, err := Compile(`fmt.Errorf($msg)`)
+	assert.NoError(t, err)
+	b, err := p.Replace([]byte(`fmt.Errorf("x")`), `errors.New($msg)`)
+	assert.NoError(t, err)
+	assert.Equal(t, `errors.New("x")`, string(b))
+	b, err = p.Replace([]byte("a := fmt.Errorf(\"x\")\nb := fmt.Errorf(y)"), `errors.New($msg)`)
+	assert.NoError(t, err)
+	assert.Equal(t, "a := errors.New(\"x\")\nb := errors.New(y)", string(b))
+	_, err = p.Replace([]byte(`fmt.Errorf("x")`), `errors.New($err)`)
+	assert.Error(t, err)
+}
+
+func TestSearch(t *testing.T) {
+	p, err := Compile(`fmt.Errorf($msg)`)
+	assert.NoError(t, err)
+	cnt, matches, err := p.Search([]byte(testSrc))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cnt)
+	assert.Equal(t, textpos.Pos{Line: 11, Char: 8}, matches[0].Region.Start)
+	assert.Equal(t, textpos.Pos{Line: 11, Char: 29}, matches[0].Region.End)
+	assert.Equal(t, `fmt.Errorf("négatif")`, string(matches[0].Text[matches[0].TextMatch.Start:matches[0].TextMatch.End]))
+
+	p, err = Compile("if $e := $call; $e != nil {\n\treturn $e\n}")
+	assert.NoError(t, err)
+	_, matches, err = p.Search([]byte(testSrc))
+	assert.NoError(t, err)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, textpos.Pos{Line: 8, Char: 1}, matches[0].Region.Start)
+		assert.Equal(t, textpos.Pos{Line: 10, Char: 2}, matches[0].Region.End)
+	}
+
+	core.SystemSettings.BigFileSize = 10000000
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.go"), []byte(testSrc), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b.go"), []byte("package b\n\nvar e = fmt.Errorf(\"b\")\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "c.txt"), []byte("fmt.Errorf(\"c\")\n"), 0644))
+	for _, sd := range []string{".git", "vendor", "node_modules"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, sd), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, sd, "d.go"), []byte("package d\n\nvar e = fmt.Errorf(\"d\")\n"), 0644))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "gen.go"), []byte("// Code generated by x. DO NOT EDIT.\n\npackage b\n\nvar e = fmt.Errorf(\"g\")\n"), 0644))
+	res, err := p.Paths(nil, []string{dir}, true)
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	p, _ = Compile(`fmt.Errorf($msg)`)
+	res, err = p.Paths(nil, []string{dir}, true)
+	assert.NoError(t, err)
+	if assert.Len(t, res, 2) {
+		assert.Equal(t, filepath.Join(dir, "a.go"), res[0].Filepath)
+		assert.Equal(t, 2, res[0].Count)
+	}
+	res, err = p.Paths(nil, []string{dir}, false, "a.go")
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
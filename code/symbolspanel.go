@@ -199,18 +199,17 @@ func SelectSymbol(cv *Code, ssym syms.Symbol) {
 func (sv *SymbolsPanel) OpenPackage() {
 	cv := sv.Code
 	tv := cv.ActiveEditor()
-	if sv.Syms == nil || tv == nil || tv.Lines == nil || !tv.Lines.Highlighter.UsingParse() {
+	if sv.Syms == nil || tv == nil {
 		return
 	}
-	pfs := tv.Lines.ParseFileState()
-	if pfs == nil {
+	pkg, ok := packageSymbols(tv.Lines)
+	if !ok {
 		return
 	}
-	if len(pfs.ParseState.Scopes) == 0 {
+	if pkg == nil {
 		core.MessageSnackbar(sv, "Symbols not yet parsed -- try again in a few moments")
 		return
 	}
-	pkg := pfs.ParseState.Scopes[0] // first scope of parse state is the full set of package symbols
 	sv.Syms.OpenSyms(pkg, "", sv.Match)
 }
 
@@ -218,18 +217,17 @@ func (sv *SymbolsPanel) OpenPackage() {
 func (sv *SymbolsPanel) OpenFile() {
 	cv := sv.Code
 	tv := cv.ActiveEditor()
-	if sv.Syms == nil || tv == nil || tv.Lines == nil || !tv.Lines.Highlighter.UsingParse() {
+	if sv.Syms == nil || tv == nil {
 		return
 	}
-	pfs := tv.Lines.ParseFileState()
-	if pfs == nil {
+	pkg, ok := packageSymbols(tv.Lines)
+	if !ok {
 		return
 	}
-	if len(pfs.ParseState.Scopes) == 0 {
+	if pkg == nil {
 		core.MessageSnackbar(sv, "Symbols not yet parsed -- try again in a few moments")
 		return
 	}
-	pkg := pfs.ParseState.Scopes[0] // first scope of parse state is the full set of package symbols
 	sv.Syms.OpenSyms(pkg, tv.Lines.Filename(), sv.Match)
 }
 
@@ -339,9 +337,14 @@ type SymNode struct {
 
 // GetIcon returns the appropriate Icon for this symbol type
 func (sy *SymNode) GetIcon() icons.Icon {
+	return symbolIcon(sy.Symbol.Kind)
+}
+
+// symbolIcon returns the appropriate Icon for given kind of symbol
+func symbolIcon(kind token.Tokens) icons.Icon {
 	ic := icons.Blank
-	switch sy.Symbol.Kind {
-	case token.NameType:
+	switch kind {
+	case token.NameType, token.NameStruct, token.NameInterface:
 		ic = icons.Title
 	case token.NameVar, token.NameVarGlobal:
 		ic = icons.Variable
@@ -0,0 +1,582 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"image"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/styles"
+	"cogentcore.org/core/styles/states"
+	"cogentcore.org/core/styles/units"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/tree"
+)
+
+// TestPanel is a widget that shows the Go tests in the project in a tree,
+// with their status, and runs them using go test -json.
+type TestPanel struct {
+	core.Frame
+
+	// parent code project
+	Code *Code `json:"-" xml:"-"`
+
+	// all the tests in the project, by package
+	Tests *TestNode `set:"-" json:"-" xml:"-"`
+
+	// currently selected test
+	Selected *TestNode `set:"-" json:"-" xml:"-"`
+
+	// mutex protecting the running state below
+	runMu sync.Mutex
+
+	// command currently running the tests
+	cmd *exec.Cmd
+
+	// set when stopping the tests
+	stopped bool
+}
+
+func (tv *TestPanel) Init() {
+	tv.Frame.Init()
+	tv.Styler(func(s *styles.Style) {
+		s.Direction = styles.Column
+		s.Grow.Set(1, 1)
+	})
+
+	tree.AddChildAt(tv, "testbar", func(w *core.Toolbar) {
+		w.Maker(tv.makeToolbar)
+	})
+	tree.AddChildAt(tv, "splits", func(w *core.Splits) {
+		w.SetSplits(0.4, 0.6)
+		tree.AddChild(w, func(w *core.Frame) {
+			w.Styler(func(s *styles.Style) {
+				s.Grow.Set(1, 1)
+				s.Overflow.Set(styles.OverflowAuto)
+			})
+			tree.AddChild(w, func(w *TestTree) {
+				if tv.Tests == nil {
+					tv.Tests = NewTestNode()
+				}
+				w.SyncTree(tv.Tests)
+				w.OnSelect(func(e events.Event) {
+					if len(w.SelectedNodes) == 0 {
+						return
+					}
+					tv.Selected, _ = w.SelectedNodes[0].AsCoreTree().SyncNode.(*TestNode)
+					tv.showOutput()
+				})
+				w.OnDoubleClick(func(e events.Event) {
+					tv.ShowSelected()
+				})
+			})
+		})
+		tree.AddChild(w, func(w *textcore.Editor) {
+			ConfigOutputTextEditor(w)
+			w.LinkHandler = func(tl *rich.Hyperlink) {
+				tv.Code.OpenFileURL(tl.URL, w)
+			}
+		})
+	})
+}
+
+func (tv *TestPanel) OnAdd() {
+	tv.Frame.OnAdd()
+	tv.Code, _ = ParentCode(tv)
+}
+
+// Splits returns the main Splits
+func (tv *TestPanel) Splits() *core.Splits {
+	return tv.ChildByName("splits", 1).(*core.Splits)
+}
+
+// Tree returns the tree of tests.
+func (tv *TestPanel) Tree() *TestTree {
+	return tv.Splits().Child(0).AsTree().Child(0).(*TestTree)
+}
+
+// TextEditor returns the test output text editor.
+func (tv *TestPanel) TextEditor() *textcore.Editor {
+	return textcore.AsEditor(tv.Splits().Child(1))
+}
+
+func (tv *TestPanel) makeToolbar(p *tree.Plan) {
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Refresh").SetIcon(icons.Update).
+			SetTooltip("find all of the tests in the project again").
+			OnClick(func(e events.Event) {
+				tv.Refresh()
+			})
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Run all").SetIcon(icons.PlayArrow).
+			SetTooltip("run all of the tests in the project").
+			OnClick(func(e events.Event) {
+				tv.RunTests(tv.Tests)
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!tv.IsRunning()) })
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Run").SetIcon(icons.PlayArrow).
+			SetTooltip("run the selected test, or all the tests in the selected package").
+			OnClick(func(e events.Event) {
+				if tv.Selected != nil {
+					tv.RunTests(tv.Selected)
+				}
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!tv.IsRunning() && tv.Selected != nil) })
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Rerun failed").SetIcon(icons.Replay).
+			SetTooltip("run the tests that failed in the last run again").
+			OnClick(func(e events.Event) {
+				tv.RerunFailed()
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!tv.IsRunning()) })
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Run at cursor").SetIcon(icons.PlayArrow).
+			SetTooltip("run the test or subtest at the cursor in the active editor").
+			OnClick(func(e events.Event) {
+				tv.RunAtCursor()
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(!tv.IsRunning()) })
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Debug").SetIcon(icons.Debug).
+			SetTooltip("debug the selected test in the debugger").
+			OnClick(func(e events.Event) {
+				if tv.Selected != nil {
+					tv.DebugTest(tv.Selected)
+				}
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(tv.Selected != nil && tv.Selected.Kind != GoPackage) })
+	})
+	tree.Add(p, func(w *core.Button) {
+		w.SetText("Stop").SetIcon(icons.Stop).
+			SetTooltip("stop running the tests").
+			OnClick(func(e events.Event) {
+				tv.Stop()
+			})
+		w.FirstStyler(func(s *styles.Style) { s.SetEnabled(tv.IsRunning()) })
+	})
+	tree.Add(p, func(w *core.Text) {
+		w.Updater(func() {
+			if tv.Tests == nil {
+				return
+			}
+			n := tv.Tests.Counts()
+			w.SetText(fmt.Sprintf("Passed: %d  Failed: %d  Skipped: %d", n[TestPassed], n[TestFailed], n[TestSkipped]))
+		})
+	})
+}
+
+// Refresh finds all of the tests in the project.
+func (tv *TestPanel) Refresh() {
+	if tv.IsRunning() {
+		return
+	}
+	tv.Tests = DiscoverGoTests(string(tv.Code.Files.Filepath))
+	tv.Selected = nil
+	tr := tv.Tree()
+	tr.SyncTree(tv.Tests)
+	tr.OpenAll()
+	tv.showOutput()
+	tv.Update()
+}
+
+// refreshPackage finds the tests in the package in given directory
+// again, so that they match the current source.
+func (tv *TestPanel) refreshPackage(dir string) {
+	for i, k := range tv.Tests.Children {
+		pn := k.(*TestNode)
+		if pn.Dir != dir {
+			continue
+		}
+		np := DiscoverGoTests(dir)
+		if !np.HasChildren() {
+			return
+		}
+		nn := np.Child(0).(*TestNode)
+		nn.SetName(pn.Name)
+		nn.ImportPath = pn.ImportPath
+		if tv.Selected != nil && tv.Selected.Package() == pn {
+			tv.Selected = nil
+		}
+		tv.Tests.DeleteChildAt(i)
+		tv.Tests.InsertChild(nn, i)
+		tv.Tree().Resync()
+		return
+	}
+}
+
+// IsRunning returns true if tests are running.
+func (tv *TestPanel) IsRunning() bool {
+	tv.runMu.Lock()
+	defer tv.runMu.Unlock()
+	return tv.cmd != nil
+}
+
+// Stop stops running tests.
+func (tv *TestPanel) Stop() {
+	tv.runMu.Lock()
+	defer tv.runMu.Unlock()
+	tv.stopped = true
+	if tv.cmd != nil && tv.cmd.Process != nil {
+		tv.cmd.Process.Kill()
+	}
+}
+
+// testJob is one go test command to run, for given packages.
+type testJob struct {
+	cmd  *exec.Cmd
+	pkgs []*TestNode
+}
+
+// RunTests runs the given tests, which can be packages or the root
+// of all the tests, showing the results as they are reported.
+func (tv *TestPanel) RunTests(tests ...*TestNode) {
+	if tv.IsRunning() || len(tests) == 0 {
+		return
+	}
+	var jobs []testJob
+	if tests[0] == tv.Tests {
+		jobs = tv.allJobs()
+	} else {
+		var pkgs []*TestNode
+		bypkg := map[*TestNode][]*TestNode{}
+		for _, tn := range tests {
+			pn := tn.Package()
+			if pn == nil {
+				continue
+			}
+			if _, has := bypkg[pn]; !has {
+				pkgs = append(pkgs, pn)
+			}
+			bypkg[pn] = append(bypkg[pn], tn)
+		}
+		for _, pn := range pkgs {
+			jobs = append(jobs, testJob{GoTestCommand(pn, bypkg[pn]...), []*TestNode{pn}})
+		}
+	}
+	if len(jobs) == 0 {
+		return
+	}
+	for _, tn := range tests {
+		tn.Reset()
+		if pn := tn.Package(); pn != nil && pn != tn {
+			pn.Status = TestRunning
+			pn.Output = ""
+		}
+	}
+	tv.Tests.Output = ""
+	tv.runMu.Lock()
+	tv.stopped = false
+	tv.cmd = jobs[0].cmd
+	tv.runMu.Unlock()
+	tv.Tree().Resync()
+	tv.Update()
+	go tv.runJobs(jobs)
+}
+
+// allJobs returns the jobs to run all of the tests, which is a single
+// go test ./... if all packages are in the module of the project.
+func (tv *TestPanel) allJobs() []testJob {
+	var pkgs []*TestNode
+	all := true
+	for _, k := range tv.Tests.Children {
+		pn := k.(*TestNode)
+		pkgs = append(pkgs, pn)
+		if pn.ImportPath == "" {
+			all = false
+		}
+	}
+	if len(pkgs) == 0 {
+		return nil
+	}
+	if all {
+		cmd := exec.Command("go", "test", "-json", "./...")
+		cmd.Dir = tv.Tests.Dir
+		return []testJob{{cmd, pkgs}}
+	}
+	jobs := make([]testJob, len(pkgs))
+	for i, pn := range pkgs {
+		jobs[i] = testJob{GoTestCommand(pn, pn), []*TestNode{pn}}
+	}
+	return jobs
+}
+
+// runJobs runs the given jobs in sequence, updating the display
+// as the events are reported.
+func (tv *TestPanel) runJobs(jobs []testJob) {
+	cv := tv.Code
+	defer func() {
+		tv.runMu.Lock()
+		tv.cmd = nil
+		tv.runMu.Unlock()
+		cv.AsyncLock()
+		tv.setProblems(jobs)
+		tv.Tree().Resync()
+		tv.showOutput()
+		tv.Update()
+		cv.AsyncUnlock()
+	}()
+	for _, job := range jobs {
+		tv.runMu.Lock()
+		if tv.stopped {
+			tv.runMu.Unlock()
+			return
+		}
+		tv.cmd = job.cmd
+		out, err := job.cmd.StdoutPipe()
+		if err == nil {
+			job.cmd.Stderr = job.cmd.Stdout
+			err = job.cmd.Start()
+		}
+		tv.runMu.Unlock()
+		if err != nil {
+			cv.AsyncLock()
+			tv.Tests.Output += err.Error() + "\n"
+			cv.AsyncUnlock()
+			continue
+		}
+		var mu sync.Mutex
+		var evs []*TestEvent
+		last := time.Now()
+		flush := func() {
+			mu.Lock()
+			pending := evs
+			evs = nil
+			mu.Unlock()
+			cv.AsyncLock()
+			for _, ev := range pending {
+				tv.Tests.applyEvent(job.pkgs, ev)
+			}
+			tv.Tree().Resync()
+			tv.showOutput()
+			tv.Update()
+			cv.AsyncUnlock()
+			last = time.Now()
+		}
+		ReadTestEvents(out, func(ev *TestEvent) {
+			mu.Lock()
+			evs = append(evs, ev)
+			mu.Unlock()
+			if time.Since(last) > 250*time.Millisecond {
+				flush()
+			}
+		})
+		job.cmd.Wait()
+		flush()
+		cv.AsyncLock()
+		for _, pn := range job.pkgs {
+			if pn.Status == TestRunning { // no result, e.g., when stopped
+				pn.Status = TestNotRun
+			}
+		}
+		cv.AsyncUnlock()
+	}
+}
+
+// setProblems sets the test failures in the given jobs as problems,
+// which marks them in the editors.
+func (tv *TestPanel) setProblems(jobs []testJob) {
+	re := regexp.MustCompile(GoProblemRegexp)
+	var probs []*Problem
+	for _, job := range jobs {
+		for _, pn := range job.pkgs {
+			for _, tn := range pn.Failed() {
+				tn.WalkDown(func(k tree.Node) bool {
+					kn := k.(*TestNode)
+					if kn.Status == TestFailed {
+						probs = append(probs, ParseProblems(re, kn.Output, pn.Dir, "Go tests")...)
+					}
+					return tree.Continue
+				})
+			}
+		}
+	}
+	tv.Code.SetProblems("Go tests", probs)
+}
+
+// RerunFailed runs the tests that failed in the last run again.
+func (tv *TestPanel) RerunFailed() {
+	fails := tv.Tests.Failed()
+	if len(fails) == 0 {
+		core.MessageSnackbar(tv, "No failed tests to run")
+		return
+	}
+	tv.RunTests(fails...)
+}
+
+// TestAtCursor returns the test or subtest at the cursor in the
+// active editor, finding the tests in its package again first.
+func (tv *TestPanel) TestAtCursor() *TestNode {
+	ed := tv.Code.ActiveEditor()
+	if ed == nil || ed.Lines == nil {
+		return nil
+	}
+	fn := ed.Lines.Filename()
+	tv.refreshPackage(filepath.Dir(fn))
+	return tv.Tests.NodeAt(fn, ed.CursorPos.Line+1)
+}
+
+// RunAtCursor runs the test or subtest at the cursor in the active editor.
+func (tv *TestPanel) RunAtCursor() {
+	tn := tv.TestAtCursor()
+	if tn == nil {
+		core.MessageSnackbar(tv, "No test at the cursor in the active editor")
+		return
+	}
+	tv.selectTest(tn)
+	tv.RunTests(tn)
+}
+
+// DebugTest runs the given test in the debugger.
+func (tv *TestPanel) DebugTest(tn *TestNode) {
+	fn := tn.Func()
+	if fn == nil || fn.Filename == "" {
+		return
+	}
+	tv.Code.DebugTestPath(fn.Filename, tn.TestName())
+}
+
+// ShowSelected shows the source of the selected test.
+func (tv *TestPanel) ShowSelected() {
+	tn := tv.Selected
+	if tn == nil || tn.Filename == "" {
+		return
+	}
+	tv.Code.ShowFile(tn.Filename, tn.Line)
+}
+
+// selectTest selects given test in the tree.
+func (tv *TestPanel) selectTest(tn *TestNode) {
+	tr := tv.Tree()
+	tr.Resync()
+	if st := tr.FindSyncNode(tn); st != nil {
+		st.SelectEvent(events.SelectOne)
+		st.ScrollToThis()
+	}
+}
+
+// showOutput shows the output of the selected test,
+// or of go test itself if none is selected.
+func (tv *TestPanel) showOutput() {
+	out := tv.Tests.Output
+	dir := tv.Tests.Dir
+	if tn := tv.Selected; tn != nil {
+		out = tn.Output
+		if pn := tn.Package(); pn != nil {
+			dir = pn.Dir
+		}
+	}
+	ed := tv.TextEditor()
+	ln := ed.Lines
+	lns, mus := markupTestOutput(out, dir, ln.FontStyle())
+	ln.SetReadOnly(true)
+	ln.SetText(nil)
+	ln.AppendTextMarkup(lns, mus)
+}
+
+// markupTestOutput returns the lines of given test output with the
+// file:line locations marked as links, relative to given package directory.
+func markupTestOutput(out, dir string, sty *rich.Style) ([][]rune, []rich.Text) {
+	re := regexp.MustCompile(GoProblemRegexp)
+	fi := re.SubexpIndex("file")
+	li := re.SubexpIndex("line")
+	link := sty.Clone().SetLinkStyle()
+	var lns [][]rune
+	var mus []rich.Text
+	for _, s := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		rl := []rune(s)
+		lns = append(lns, rl)
+		m := re.FindStringSubmatchIndex(s)
+		if m == nil {
+			mus = append(mus, rich.NewText(sty, rl))
+			continue
+		}
+		fn := s[m[2*fi]:m[2*fi+1]]
+		if !filepath.IsAbs(fn) {
+			fn = filepath.Join(dir, fn)
+		}
+		st := utf8.RuneCountInString(s[:m[2*fi]])
+		ed := utf8.RuneCountInString(s[:m[2*li+1]])
+		mu := rich.Text{}
+		mu.AddSpan(sty, rl[:st]).AddLink(link, fmt.Sprintf("file:///%s#L%s", fn, s[m[2*li]:m[2*li+1]]), string(rl[st:ed])).
+			AddSpan(sty, rl[ed:])
+		mus = append(mus, mu)
+	}
+	return lns, mus
+}
+
+// TestTree is a Tree that shows [TestNode]s with an icon for their status.
+type TestTree struct {
+	core.Tree
+}
+
+// TestNode returns the SyncNode as a *code* TestNode
+func (tt *TestTree) TestNode() *TestNode {
+	return tt.SyncNode.(*TestNode)
+}
+
+func (tt *TestTree) Init() {
+	tt.Tree.Init()
+
+	tt.Parts.Styler(func(s *styles.Style) {
+		s.Gap.X.Em(0.4)
+	})
+	tree.AddChildInit(tt.Parts, "branch", func(w *core.Switch) {
+		w.Updater(func() {
+			w.SetIconOn(tt.IconOpen).SetIconOff(tt.IconClosed).SetIconIndeterminate(tt.TestNode().StatusIcon())
+		})
+		w.Styler(func(s *styles.Style) {
+			if s.Is(states.Indeterminate) {
+				s.IconSize.Set(units.Em(1))
+				s.Color = tt.TestNode().StatusColor()
+			}
+		})
+	})
+}
+
+// StatusIcon returns the icon for the status of this test.
+func (tn *TestNode) StatusIcon() icons.Icon {
+	switch tn.Status {
+	case TestRunning:
+		return icons.Pending
+	case TestPassed:
+		return icons.CheckCircle
+	case TestFailed:
+		return icons.Cancel
+	case TestSkipped:
+		return icons.Block
+	}
+	return icons.RadioButtonUnchecked
+}
+
+// StatusColor returns the color for the status of this test.
+func (tn *TestNode) StatusColor() image.Image {
+	switch tn.Status {
+	case TestPassed:
+		return colors.Scheme.Success.Base
+	case TestFailed:
+		return colors.Scheme.Error.Base
+	case TestSkipped:
+		return colors.Scheme.Warn.Base
+	}
+	return colors.Scheme.OnSurfaceVariant
+}
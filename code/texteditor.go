@@ -11,6 +11,7 @@ import (
 	"cogentcore.org/core/events"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/keymap"
+	"cogentcore.org/core/math32"
 	"cogentcore.org/core/styles"
 	"cogentcore.org/core/styles/abilities"
 	"cogentcore.org/core/styles/states"
@@ -26,6 +27,32 @@ type TextEditor struct {
 	textcore.Editor
 
 	Code *Code
+
+	// showBlame is whether to show the blame gutter
+	showBlame bool
+
+	// vcs is the version control state of the lines
+	vcs *vcsLines
+
+	// snippet has the tab stops of the snippet being filled in, if any
+	snippet *snippetStops
+
+	// snippetComplete is the completer that completeSnippet is connected to
+	snippetComplete *core.Complete
+
+	// vim is the state of Vim modal editing, if it is on
+	vim *vimState
+
+	// hover is the language server hover documentation for the
+	// last position it was requested for
+	hover *hoverState
+
+	// symbols caches the symbol paths of the file for the breadcrumbs
+	symbols *symbolPathsCache
+
+	// contextPending is whether an update of the breadcrumbs
+	// is pending, from [TextEditor.needsContextUpdate]
+	contextPending bool
 }
 
 func (ed *TextEditor) Init() {
@@ -33,14 +60,43 @@ func (ed *TextEditor) Init() {
 	ed.AddContextMenu(ed.ContextMenu)
 	ed.Styler(func(s *styles.Style) {
 		s.SetAbilities(true, abilities.LongHoverable)
+		if gw := ed.vcsGutterChars() + ed.profileGutterChars(); gw > 0 {
+			s.Padding.Left.Ch(gw)
+		}
 	})
 
+	ed.OnFirst(events.KeyChord, ed.snippetKeys)
+	ed.OnFirst(events.KeyChord, ed.vimKeys)
 	ed.On(events.Focus, func(e events.Event) {
 		ed.Code.SetActiveEditor(ed)
+		ed.needsContextUpdate()
+	})
+	ed.OnInput(func(e events.Event) {
+		if ed.vcs != nil {
+			ed.vcs.dirty = true
+		}
+		ed.hover = nil
+		ed.needsContextUpdate() // cursor moves and parse updates send input too
+	})
+	ed.On(events.MouseDown, func(e events.Event) {
+		ed.needsContextUpdate()
+	})
+	ed.On(events.SlideMove, func(e events.Event) {
+		ed.needsContextUpdate()
+	})
+	ed.OnChange(func(e events.Event) {
+		if ed.vcs != nil {
+			ed.vcs.dirty = true
+		}
 	})
 	ed.OnDoubleClick(func(e events.Event) {
 		pt := ed.PointToRelPos(e.Pos())
 		tpos := ed.PixelToCursor(pt)
+		if ed.Lines != nil && pt.X < 0 && ed.showBlame {
+			e.SetHandled()
+			ed.ShowBlameCommit(tpos.Line)
+			return
+		}
 		if ed.Lines != nil && pt.X >= 0 && ed.Lines.IsValidLine(tpos.Line) {
 			if pt.X < int(ed.LineNumberPixels()) {
 				e.SetHandled()
@@ -52,13 +108,34 @@ func (ed *TextEditor) Init() {
 	})
 }
 
+func (ed *TextEditor) RenderWidget() {
+	if ed.Lines != nil && (ed.vcs == nil || ed.vcs.lines != ed.Lines) {
+		ed.updateVCS()
+	}
+	ed.Editor.RenderWidget()
+	ed.renderVCSGutter()
+	ed.renderProfileGutter()
+}
+
+func (ed *TextEditor) ScrollChanged(d math32.Dims, sb *core.Slider) {
+	ed.Editor.ScrollChanged(d, sb)
+	ed.needsContextUpdate()
+}
+
 func (ed *TextEditor) WidgetTooltip(pos image.Point) (string, image.Point) {
 	if pos == image.Pt(-1, -1) {
 		return "_", image.Point{}
 	}
-	// todo: look for documentation on symbols here; we don't actually have this
-	// in parse so we need lsp to make this work
-	return ed.DebugVarValueAtPos(pos), pos
+	if pt := ed.profileTooltip(pos); pt != "" {
+		return pt, pos
+	}
+	if bt := ed.blameTooltip(pos); bt != "" {
+		return bt, pos
+	}
+	if val := ed.DebugVarValueAtPos(pos); val != "" {
+		return val, pos
+	}
+	return ed.HoverAtPos(pos), pos
 }
 
 // CurDebug returns the current debugger, true if it is present
@@ -104,7 +181,7 @@ func (ed *TextEditor) HasBreakpoint(ln int) bool {
 		return false
 	}
 	_, has := ed.Lines.LineColor(ln)
-	return has
+	return has && gutterMarkerKind(ed.Lines, ln) == ""
 }
 
 func (ed *TextEditor) ToggleBreakpoint(ln int) {
@@ -215,12 +292,50 @@ func (ed *TextEditor) ContextMenu(m *core.Scene) {
 
 	core.NewSeparator(m)
 	core.NewFuncButton(m).SetFunc(ed.Lookup).SetIcon(icons.Search)
+	core.NewFuncButton(m).SetFunc(ed.Code.FindDefinition).SetIcon(icons.Search)
+	core.NewFuncButton(m).SetFunc(ed.Code.FindReferences).SetIcon(icons.ManageSearch)
+	core.NewFuncButton(m).SetFunc(ed.Code.CallHierarchy).SetIcon(icons.Schema)
+	core.NewFuncButton(m).SetFunc(ed.Code.TypeHierarchy).SetIcon(icons.Schema)
+	core.NewFuncButton(m).SetFunc(ed.Code.RenameSymbol).SetIcon(icons.Edit)
 
 	fn := ed.Code.FileNodeForFile(ed.Lines.Filename())
 	if fn != nil {
 		fn.SelectEvent(events.SelectOne)
 		fn.VCSContextMenu(m)
 	}
+	if GetVCSRepo(ed.Lines) != nil {
+		core.NewSeparator(m)
+		blame := "Show blame"
+		if ed.showBlame {
+			blame = "Hide blame"
+		}
+		core.NewButton(m).SetText(blame).SetIcon(icons.Person).
+			SetTooltip("show the revision, author and date of the last change to each line").
+			OnClick(func(e events.Event) {
+				ed.ToggleBlame()
+			})
+		if len(LinesConflicts(ed.Lines)) > 0 {
+			core.NewFuncButton(m).SetFunc(ed.Code.ShowMerge).SetText("Resolve merge conflicts").SetIcon(icons.Merge)
+		}
+		ln := ed.CursorPos.Line
+		if _, ok := ed.HunkAtLine(ln); ok {
+			core.NewButton(m).SetText("Revert hunk").SetIcon(icons.Undo).
+				SetTooltip("revert the changed lines at the cursor to the HEAD version").
+				OnClick(func(e events.Event) {
+					ed.RevertHunk(ln)
+				})
+			core.NewButton(m).SetText("Stage hunk").SetIcon(icons.Add).
+				SetTooltip("stage the changed lines at the cursor for the next commit").
+				OnClick(func(e events.Event) {
+					ed.StageHunk(ln)
+				})
+			core.NewButton(m).SetText("View hunk diff").SetIcon(icons.Difference).
+				SetTooltip("show the changes in the lines at the cursor relative to HEAD").
+				OnClick(func(e events.Event) {
+					ed.DiffHunk(ln)
+				})
+		}
+	}
 
 	if ed.Code.CurDebug() != nil {
 		core.NewSeparator(m)
@@ -240,5 +355,12 @@ func (ed *TextEditor) ContextMenu(m *core.Scene) {
 			OnClick(func(e events.Event) {
 				ed.FindFrames(ed.CursorPos.Line)
 			})
+		if fun := ed.funcAtCursor(); fun != "" {
+			core.NewButton(m).SetText("Debug: Tasks in " + fun).SetIcon(icons.Search).
+				SetTooltip("Shows only the tasks (goroutines) in the debugger with this function on their stack, such as those blocked in it").
+				OnClick(func(e events.Event) {
+					ed.ShowTasksInFunc()
+				})
+		}
 	}
 }
@@ -93,9 +93,7 @@ func (cv *Code) SetActiveEditor(av *TextEditor) int {
 	cv.ActiveEditorIndex = cv.EditorIndex(av)
 	if av.Lines != nil {
 		av.UpdateNewFile()
-		if av.Complete != nil {
-			av.Complete.LookupFunc = cv.LookupFun
-		}
+		cv.setCompleter(av)
 		cv.SetActiveFileInfo(av.Lines)
 		av.Lines.FileModCheck()
 	}
@@ -0,0 +1,366 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/base/vcs"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/paint/render"
+	"cogentcore.org/core/styles/sides"
+	"cogentcore.org/core/text/difflib"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/rich"
+	"cogentcore.org/core/text/textcore"
+	"cogentcore.org/core/text/textpos"
+)
+
+// vcsLines is the version control state of the lines shown in a
+// [TextEditor], used for the change bars and the blame gutter.
+type vcsLines struct {
+
+	// lines this is for
+	lines *lines.Lines
+
+	// repository for the file, nil if not in one
+	repo vcs.Repo
+
+	// file that the state is for
+	fname string
+
+	// HEAD revision that head and blame are for
+	rev string
+
+	// contents of the file at HEAD, nil if not committed
+	head []string
+
+	// saved contents of the file that blame is for
+	saved []string
+
+	// blame for the saved lines, nil if blame is not shown
+	blame []BlameLine
+
+	// current contents of the lines, as of the last update
+	cur []string
+
+	// hunks of changes from head to cur
+	hunks lines.Diffs
+
+	// blame for the cur lines
+	curBlame []BlameLine
+
+	// whether cur needs to be updated from the lines
+	dirty bool
+}
+
+// vcsGutterChars returns the width of the version control gutter in
+// characters, which is 0 if the file is not in a repository.
+func (ed *TextEditor) vcsGutterChars() float32 {
+	if ed.vcs == nil || ed.vcs.repo == nil {
+		return 0
+	}
+	if ed.vcs.blame != nil {
+		return blameChars + 2
+	}
+	return 1
+}
+
+// updateVCS loads the HEAD contents of the file, and the blame if shown,
+// in a separate goroutine, updating the change bars and blame gutter.
+// They are only loaded again if the HEAD revision has changed, or for
+// the blame, the saved file. It is called when the file is opened or
+// saved, and after version control commands.
+func (ed *TextEditor) updateVCS() {
+	ln := ed.Lines
+	if ln == nil {
+		ed.vcs = nil
+		return
+	}
+	if ed.vcs == nil || ed.vcs.lines != ln {
+		ed.vcs = &vcsLines{lines: ln} // placeholder until loaded
+	}
+	repo := GetVCSRepo(ln)
+	if repo == nil { // including for a remote project, as git runs locally
+		return
+	}
+	fname := ln.Filename()
+	blame := ed.showBlame
+	prev := ed.vcs
+	go func() {
+		vl := &vcsLines{lines: ln, repo: repo, fname: fname, dirty: true}
+		vl.rev, _ = repo.Version()
+		same := vl.rev != "" && prev.repo == repo && prev.fname == fname && prev.rev == vl.rev
+		if same {
+			vl.head = prev.head
+		} else if head, err := repo.FileContents(fname, "HEAD"); err == nil {
+			vl.head = splitLines(head)
+		}
+		if blame {
+			saved, serr := os.ReadFile(fname)
+			vl.saved = splitLines(saved)
+			switch {
+			case same && prev.blame != nil && serr == nil && slices.Equal(prev.saved, vl.saved):
+				vl.blame = prev.blame
+			case serr == nil:
+				if out, err := repo.Blame(fname); err == nil {
+					vl.blame = ParseBlame(repo.Type(), out)
+				}
+			}
+			if vl.blame == nil {
+				vl.blame = []BlameLine{}
+			}
+		}
+		ed.AsyncLock()
+		defer ed.AsyncUnlock()
+		if ed.Lines != ln {
+			return
+		}
+		ed.vcs = vl
+		ed.Style()
+		ed.NeedsLayout()
+	}()
+}
+
+// updateVCS updates the version control state of all of the editors,
+// as in [TextEditor.updateVCS], e.g., after a command that can change
+// the HEAD revision.
+func (cv *Code) updateVCS() {
+	for i := range NTextEditors {
+		if ed := cv.EditorByIndex(i); ed != nil {
+			ed.updateVCS()
+		}
+	}
+}
+
+// vcsCurrent returns the version control state, updated for
+// any changes to the lines, or nil if there is none.
+func (ed *TextEditor) vcsCurrent() *vcsLines {
+	vl := ed.vcs
+	if vl == nil || vl.repo == nil || vl.lines != ed.Lines {
+		return nil
+	}
+	if !vl.dirty {
+		return vl
+	}
+	vl.dirty = false
+	vl.cur = splitLines(ed.Lines.Text())
+	vl.hunks = nil
+	if vl.head != nil {
+		vl.hunks = ChangeHunks(vl.head, vl.cur)
+	}
+	vl.curBlame = nil
+	if vl.blame != nil {
+		vl.curBlame = BlameForLines(vl.blame, vl.saved, vl.cur)
+	}
+	return vl
+}
+
+// ToggleBlame toggles the blame gutter showing the revision, author
+// and date of the last change to each line.
+func (ed *TextEditor) ToggleBlame() {
+	ed.showBlame = !ed.showBlame
+	ed.updateVCS()
+	if !ed.showBlame && ed.vcs != nil && ed.vcs.blame != nil {
+		ed.vcs.blame = nil
+		ed.vcs.dirty = true
+		ed.Style()
+		ed.NeedsLayout()
+	}
+}
+
+// HunkAtLine returns the hunk of changes relative to HEAD
+// at given line, and false if there is none.
+func (ed *TextEditor) HunkAtLine(ln int) (difflib.OpCode, bool) {
+	vl := ed.vcsCurrent()
+	if vl == nil {
+		return difflib.OpCode{}, false
+	}
+	return HunkAt(vl.hunks, ln, len(vl.cur))
+}
+
+// RevertHunk reverts the hunk of changes at given line to the HEAD version.
+func (ed *TextEditor) RevertHunk(ln int) {
+	h, ok := ed.HunkAtLine(ln)
+	if !ok {
+		return
+	}
+	vl := ed.vcs
+	st := textpos.Pos{Line: h.J1}
+	end := textpos.Pos{Line: h.J2}
+	txt := strings.Join(vl.head[h.I1:h.I2], "\n")
+	switch {
+	case h.J2 < ed.Lines.NumLines():
+		if h.I2 > h.I1 {
+			txt += "\n"
+		}
+	case h.J1 > 0: // at the end, with no final newline
+		st = textpos.Pos{Line: h.J1 - 1, Char: ed.Lines.LineLen(h.J1 - 1)}
+		end = ed.Lines.EndPos()
+		if h.I2 > h.I1 {
+			txt = "\n" + txt
+		}
+	default:
+		end = ed.Lines.EndPos()
+	}
+	ed.Lines.ReplaceText(st, end, st, txt, false)
+	vl.dirty = true
+	ed.SetCursorShow(st)
+}
+
+// StageHunk stages the hunk of changes at given line.
+func (ed *TextEditor) StageHunk(ln int) {
+	if _, ok := ed.HunkAtLine(ln); !ok {
+		return
+	}
+	fname := ed.Lines.Filename()
+	err := StageHunk(ed.vcs.repo, fname, ed.Lines.Text(), ln)
+	if err != nil {
+		core.ErrorSnackbar(ed, err, "Could not stage hunk")
+		return
+	}
+	core.MessageSnackbar(ed, "Staged hunk in "+fname)
+}
+
+// DiffHunk shows the differences in the hunk of changes at given
+// line relative to HEAD, with surrounding lines for context.
+func (ed *TextEditor) DiffHunk(ln int) {
+	h, ok := ed.HunkAtLine(ln)
+	if !ok {
+		return
+	}
+	vl := ed.vcs
+	const context = 3
+	a0, a1 := max(h.I1-context, 0), min(h.I2+context, len(vl.head))
+	b0, b1 := max(h.J1-context, 0), min(h.J2+context, len(vl.cur))
+	fname := ed.Lines.Filename()
+	textcore.DiffEditorDialog(ed, fmt.Sprintf("Hunk diff: %s:%d", fname, h.J1+1), vl.head[a0:a1], vl.cur[b0:b1], fname, fname, "HEAD", "")
+}
+
+// blameAtLine returns the blame for given line, and false if none.
+func (ed *TextEditor) blameAtLine(ln int) (BlameLine, bool) {
+	vl := ed.vcsCurrent()
+	if vl == nil || ln < 0 || ln >= len(vl.curBlame) {
+		return BlameLine{}, false
+	}
+	return vl.curBlame[ln], true
+}
+
+// ShowBlameCommit shows the description of the commit that last changed given line.
+func (ed *TextEditor) ShowBlameCommit(ln int) {
+	b, ok := ed.blameAtLine(ln)
+	if !ok || b.Rev == "" {
+		return
+	}
+	out, err := ed.vcs.repo.CommitDesc(b.Rev, false)
+	if err != nil {
+		core.ErrorSnackbar(ed, err, "Could not get commit "+b.Rev)
+		return
+	}
+	cbuf, _, _ := ed.Code.RecycleCmdTab("Commit")
+	cbuf.SetText(out)
+}
+
+// blameTooltip returns the tooltip for the blame gutter at given position.
+func (ed *TextEditor) blameTooltip(pos image.Point) string {
+	pt := ed.PointToRelPos(pos)
+	if pt.X >= 0 {
+		return ""
+	}
+	b, ok := ed.blameAtLine(ed.PixelToCursor(pt).Line)
+	if !ok {
+		return ""
+	}
+	if b.Rev == "" {
+		return "Not committed yet"
+	}
+	return fmt.Sprintf("%s by %s %s (double-click to show commit)", b.Rev, b.Author, b.Date)
+}
+
+// hunkColor returns the change bar color for given hunk.
+func hunkColor(h difflib.OpCode) image.Image {
+	switch h.Tag {
+	case 'i':
+		return colors.Scheme.Success.Base
+	case 'd':
+		return colors.Scheme.Error.Base
+	}
+	return colors.Scheme.Primary.Base
+}
+
+// renderVCSGutter renders the change bars and the blame gutter to the
+// left of the line numbers, in space reserved by the left padding.
+func (ed *TextEditor) renderVCSGutter() {
+	vl := ed.vcsCurrent()
+	pc := &ed.Scene.Painter
+	if vl == nil || pc.State == nil || !ed.IsVisible() {
+		return
+	}
+	lht := ed.Styles.LineHeightDots()
+	gw := ed.Styles.Padding.Left.Dots
+	if lht <= 0 || gw <= 0 {
+		return
+	}
+	chw := gw / (ed.vcsGutterChars() + ed.profileGutterChars())
+	cpos := ed.Geom.Pos.Content
+	csz := ed.Geom.Size.Actual.Content
+	x0 := cpos.X - ed.vcsGutterChars()*chw
+	barX := cpos.X - chw
+	var scroll float32
+	if ed.HasScroll[math32.Y] && ed.Scrolls[math32.Y] != nil {
+		scroll = ed.Scrolls[math32.Y].Value / lht
+	}
+	frac := scroll - math32.Floor(scroll)
+
+	pc.PushContext(nil, render.NewBoundsRect(ed.Geom.TotalBBox, sides.NewFloats()))
+	defer pc.PopContext()
+	sh := ed.Scene.TextShaper()
+	sty, tsty := ed.Styles.NewRichText()
+	sty.SetBackground(nil)
+	sty.SetFillColor(colors.ToUniform(colors.Scheme.OnSurfaceVariant))
+	lastln := -1
+	for r := 0; ; r++ {
+		y := (float32(r) - frac) * lht
+		if y >= csz.Y {
+			break
+		}
+		tp := ed.PixelToCursor(image.Pt(0, int(y+0.5*lht)))
+		if tp == textpos.PosErr || tp.Line >= len(vl.cur) {
+			continue
+		}
+		top := cpos.Y + y
+		if h, ok := HunkAt(vl.hunks, tp.Line, len(vl.cur)); ok {
+			pc.Fill.Color = hunkColor(h)
+			if h.Tag == 'd' {
+				dy := float32(0)
+				if h.J1 > tp.Line { // deletion at the end, after the line
+					dy = lht
+				}
+				pc.Rectangle(barX, top+dy-0.1*lht, 0.5*chw, 0.2*lht)
+			} else {
+				pc.Rectangle(barX, top, 0.3*chw, lht)
+			}
+			pc.Draw()
+		}
+		if tp.Line == lastln || tp.Line >= len(vl.curBlame) {
+			continue // wrapped line
+		}
+		b := vl.curBlame[tp.Line]
+		if lastln >= 0 && vl.curBlame[tp.Line-1].Rev == b.Rev {
+			lastln = tp.Line
+			continue // only show at start of each run of the same revision
+		}
+		lastln = tp.Line
+		tx := rich.NewText(sty, []rune(b.String()))
+		lns := sh.WrapLines(tx, sty, tsty, &rich.DefaultSettings, math32.Vec2(blameChars*chw, lht))
+		pc.DrawText(lns, math32.Vec2(x0, top))
+	}
+}
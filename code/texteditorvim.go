@@ -0,0 +1,670 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"cogentcore.org/cogent/code/vim"
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/fileinfo"
+	"cogentcore.org/core/base/fileinfo/mimedata"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/events/key"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/parse/lexer"
+	"cogentcore.org/core/text/textpos"
+)
+
+// vimState is the state of the Vim emulation in a [TextEditor],
+// which is used when [SettingsData.VimMode] is on.
+type vimState struct {
+
+	// mode is the current mode
+	mode vim.Modes
+
+	// keys are the keys typed so far for a command in normal or visual mode
+	keys []string
+
+	// chords are the key chords of keys, for repeating a change
+	chords []key.Chord
+
+	// ex is the ex command typed so far in ex mode
+	ex string
+
+	// visualStart is the position where visual mode started
+	visualStart textpos.Pos
+
+	// change has the key chords of the change being made in insert
+	// mode, which becomes lastChange when it ends, or nil if it can
+	// not be repeated
+	change []key.Chord
+
+	// lastChange has the key chords of the last change, which is repeated with .
+	lastChange []key.Chord
+
+	// repeating is whether the last change is being repeated
+	repeating bool
+}
+
+// vimKey returns the key for the given key event, which is the
+// character for printable characters typed without a Control,
+// Meta, or Alt modifier, and the key chord otherwise.
+func vimKey(e events.Event) string {
+	r := e.KeyRune()
+	if unicode.IsPrint(r) && !e.HasAnyModifier(key.Control, key.Meta, key.Alt) {
+		return string(r)
+	}
+	return string(e.KeyChord())
+}
+
+// vimStatus returns the Vim mode and any command being typed,
+// for the status bar.
+func (ed *TextEditor) vimStatus() string {
+	vs := ed.vim
+	if !Settings.VimMode || vs == nil {
+		return ""
+	}
+	switch vs.mode {
+	case vim.Insert:
+		return "-- INSERT --"
+	case vim.Visual:
+		return "-- VISUAL -- " + strings.Join(vs.keys, "")
+	case vim.VisualLine:
+		return "-- VISUAL LINE -- " + strings.Join(vs.keys, "")
+	case vim.Ex:
+		return ":" + vs.ex
+	}
+	return "NORMAL " + strings.Join(vs.keys, "")
+}
+
+// vimKeys handles the keys for Vim modal editing, if [SettingsData.VimMode]
+// is on. Keys in insert mode other than Escape are handled by the editor as
+// usual, as are other keys that are not Vim commands, e.g., arrow keys.
+func (ed *TextEditor) vimKeys(e events.Event) {
+	if !Settings.VimMode || ed.Lines == nil || ed.ISearch.On || ed.QReplace.On {
+		return
+	}
+	if ed.vim == nil {
+		ed.vim = &vimState{}
+	}
+	vs := ed.vim
+	defer func() {
+		if ed.Code != nil {
+			ed.Code.UpdateStatusText()
+		}
+	}()
+	k := vimKey(e)
+	switch vs.mode {
+	case vim.Insert:
+		if vs.change != nil {
+			vs.change = append(vs.change, e.KeyChord())
+		}
+		if k != "Escape" {
+			return
+		}
+		e.SetHandled()
+		ed.snippet = nil
+		if vs.change != nil {
+			vs.lastChange = vs.change
+		}
+		vs.change = nil
+		ed.vimSetMode(vim.Normal)
+		ed.vimSetCursor(textpos.Pos{Line: ed.CursorPos.Line, Char: ed.CursorPos.Char - 1})
+		return
+	case vim.Ex:
+		e.SetHandled()
+		ed.vimExKey(k)
+		return
+	}
+	if len(vs.keys) == 0 && !vim.Handles(k) {
+		return
+	}
+	e.SetHandled()
+	if k == "Escape" {
+		if len(vs.keys) == 0 && vs.mode != vim.Normal {
+			ed.vimSetMode(vim.Normal)
+		}
+		vs.keys, vs.chords = nil, nil
+		return
+	}
+	vs.keys = append(vs.keys, k)
+	vs.chords = append(vs.chords, e.KeyChord())
+	cmd, done, err := vim.Parse(vs.keys, vs.mode == vim.Visual || vs.mode == vim.VisualLine)
+	if err != nil || !done {
+		if err != nil {
+			vs.keys, vs.chords = nil, nil
+		}
+		return
+	}
+	chords := vs.chords
+	vs.keys, vs.chords = nil, nil
+	if ed.vimExec(cmd) && !vs.repeating {
+		if vs.mode == vim.Insert {
+			vs.change = chords
+		} else {
+			vs.lastChange = chords
+		}
+	}
+}
+
+// vimSetMode sets the Vim mode, updating the selection for visual mode.
+func (ed *TextEditor) vimSetMode(mode vim.Modes) {
+	vs := ed.vim
+	prev := vs.mode
+	vs.mode = mode
+	switch mode {
+	case vim.Visual, vim.VisualLine:
+		if prev != vim.Visual && prev != vim.VisualLine {
+			vs.visualStart = ed.CursorPos
+		}
+		ed.vimSelect()
+	case vim.Ex:
+		vs.ex = ""
+		ed.SelectReset()
+	default:
+		ed.SelectReset()
+	}
+}
+
+// vimSetCursor sets the cursor to the given position, which in normal
+// and visual mode is on a character, not at the end of the line.
+func (ed *TextEditor) vimSetCursor(pos textpos.Pos) {
+	pos.Line = min(max(pos.Line, 0), ed.Lines.NumLines()-1)
+	mx := ed.Lines.LineLen(pos.Line)
+	if ed.vim.mode != vim.Insert {
+		mx = max(mx-1, 0)
+	}
+	pos.Char = min(max(pos.Char, 0), mx)
+	ed.SetCursorShow(pos)
+	if ed.vim.mode == vim.Visual || ed.vim.mode == vim.VisualLine {
+		ed.vimSelect()
+	}
+}
+
+// vimSelect selects the text from the start of visual mode to the cursor.
+func (ed *TextEditor) vimSelect() {
+	vs := ed.vim
+	if vs.mode == vim.VisualLine {
+		ed.SelectRegion = vim.LinesRegion(ed.Lines, vs.visualStart.Line, ed.CursorPos.Line)
+	} else {
+		ed.SelectRegion = vim.Range(ed.Lines, vs.visualStart, ed.CursorPos, vim.Inclusive)
+	}
+	ed.NeedsRender()
+}
+
+// vimFirstNonBlank returns the position of the first
+// non-blank character of the given line.
+func (ed *TextEditor) vimFirstNonBlank(ln int) textpos.Pos {
+	ln = min(max(ln, 0), ed.Lines.NumLines()-1)
+	return textpos.Pos{Line: ln, Char: vim.FirstNonBlank(ed.Lines, ln)}
+}
+
+// vimExec executes the given command, returning whether
+// it is a change that can be repeated.
+func (ed *TextEditor) vimExec(cmd vim.Command) bool {
+	vs := ed.vim
+	visual := vs.mode == vim.Visual || vs.mode == vim.VisualLine
+	if cmd.IsMotion() {
+		if pos, _, ok := vim.Move(ed.Lines, ed.CursorPos, cmd); ok {
+			ed.vimSetCursor(pos)
+		}
+		return false
+	}
+	if cmd.Operator != 0 {
+		start, end, kind := ed.CursorPos, ed.CursorPos, vim.Linewise
+		switch {
+		case visual:
+			start = vs.visualStart
+			if vs.mode == vim.Visual {
+				kind = vim.Inclusive
+			}
+			ed.vimSetMode(vim.Normal)
+		case cmd.Key == string(cmd.Operator):
+			end.Line = min(start.Line+cmd.N()-1, ed.Lines.NumLines()-1)
+		default:
+			var ok bool
+			end, kind, ok = vim.Move(ed.Lines, start, cmd)
+			if !ok {
+				return false
+			}
+		}
+		ed.vimOperate(cmd, start, end, kind)
+		return !visual && cmd.Operator != 'y'
+	}
+	return ed.vimAction(cmd, visual)
+}
+
+// vimOperate applies the operator of the given command
+// to the text between the given positions.
+func (ed *TextEditor) vimOperate(cmd vim.Command, start, end textpos.Pos, kind vim.Kinds) {
+	if end.IsLess(start) {
+		start, end = end, start
+	}
+	reg := vim.Range(ed.Lines, start, end, kind)
+	txt := string(ed.Lines.Region(reg.Start, reg.End).ToBytes())
+	if kind == vim.Linewise {
+		txt = vim.LinesText(ed.Lines, start.Line, end.Line)
+	}
+	switch cmd.Operator {
+	case 'y':
+		ed.vimSetRegister(cmd.Register, txt)
+		if kind == vim.Linewise {
+			start.Char = ed.CursorPos.Char
+		}
+		ed.vimSetCursor(start)
+	case 'd':
+		ed.vimSetRegister(cmd.Register, txt)
+		ed.Lines.NewUndoGroup()
+		ed.Lines.DeleteText(reg.Start, reg.End)
+		if kind == vim.Linewise {
+			ed.vimSetCursor(ed.vimFirstNonBlank(start.Line))
+		} else {
+			ed.vimSetCursor(reg.Start)
+		}
+	case 'c':
+		ed.vimSetRegister(cmd.Register, txt)
+		if kind == vim.Linewise {
+			reg = textpos.Region{Start: ed.vimFirstNonBlank(start.Line), End: textpos.Pos{Line: end.Line, Char: ed.Lines.LineLen(end.Line)}}
+			if ed.Lines.LineLen(start.Line) == 0 {
+				reg.Start.Char = 0
+			}
+		}
+		ed.Lines.NewUndoGroup()
+		ed.Lines.DeleteText(reg.Start, reg.End)
+		ed.vimSetMode(vim.Insert)
+		ed.vimSetCursor(reg.Start)
+	case '>', '<':
+		ed.Lines.NewUndoGroup()
+		for ln := start.Line; ln <= end.Line; ln++ {
+			if ed.Lines.LineLen(ln) == 0 {
+				continue
+			}
+			ind, _ := lexer.LineIndent(ed.Lines.Line(ln), ed.Styles.Text.TabSize)
+			if cmd.Operator == '>' {
+				ind++
+			} else {
+				ind--
+			}
+			ed.Lines.IndentLine(ln, max(ind, 0))
+		}
+		ed.vimSetCursor(ed.vimFirstNonBlank(start.Line))
+	}
+}
+
+// vimAction executes the given command that is not a motion or operator,
+// returning whether it is a change that can be repeated.
+func (ed *TextEditor) vimAction(cmd vim.Command, visual bool) bool {
+	vs := ed.vim
+	pos := ed.CursorPos
+	n := cmd.N()
+	insert := func(pos textpos.Pos) bool {
+		ed.vimSetMode(vim.Insert)
+		ed.vimSetCursor(pos)
+		return true
+	}
+	switch cmd.Key {
+	case "i":
+		return insert(pos)
+	case "a":
+		return insert(textpos.Pos{Line: pos.Line, Char: pos.Char + 1})
+	case "I":
+		return insert(ed.vimFirstNonBlank(pos.Line))
+	case "A":
+		return insert(textpos.Pos{Line: pos.Line, Char: ed.Lines.LineLen(pos.Line)})
+	case "o", "O":
+		if visual {
+			vs.visualStart, pos = pos, vs.visualStart
+			ed.vimSetCursor(pos)
+			return false
+		}
+		ln := pos.Line
+		ed.Lines.NewUndoGroup()
+		if cmd.Key == "o" {
+			ed.Lines.InsertText(textpos.Pos{Line: ln, Char: ed.Lines.LineLen(ln)}, []rune("\n"))
+			ln++
+		} else {
+			ed.Lines.InsertText(textpos.Pos{Line: ln}, []rune("\n"))
+		}
+		_, _, ch := ed.Lines.AutoIndent(ln)
+		return insert(textpos.Pos{Line: ln, Char: ch})
+	case "p", "P":
+		ed.vimPaste(cmd, visual)
+		return !visual
+	case "r":
+		if visual {
+			return false
+		}
+		end := textpos.Pos{Line: pos.Line, Char: pos.Char + n}
+		if end.Char > ed.Lines.LineLen(pos.Line) {
+			return false
+		}
+		ed.Lines.NewUndoGroup()
+		ed.Lines.ReplaceText(pos, end, pos, strings.Repeat(string(cmd.Char), n), lines.ReplaceNoMatchCase)
+		ed.vimSetCursor(textpos.Pos{Line: pos.Line, Char: end.Char - 1})
+		return true
+	case "J":
+		st, lns := pos.Line, max(n-1, 1)
+		if visual {
+			st, lns = min(vs.visualStart.Line, pos.Line), max(abs(vs.visualStart.Line-pos.Line), 1)
+			ed.vimSetMode(vim.Normal)
+		}
+		ed.vimJoin(st, lns)
+		return !visual
+	case "~":
+		ed.vimToggleCase(cmd, visual)
+		return !visual
+	case "u":
+		for range n {
+			ed.vimUndo(ed.Lines.Undo(), true)
+		}
+	case "Control+R":
+		for range n {
+			ed.vimUndo(ed.Lines.Redo(), false)
+		}
+	case "v", "V":
+		mode := vim.Visual
+		if cmd.Key == "V" {
+			mode = vim.VisualLine
+		}
+		if vs.mode == mode {
+			mode = vim.Normal
+		}
+		ed.vimSetMode(mode)
+	case ".":
+		ed.vimRepeat(n)
+	case ":":
+		ed.vimSetMode(vim.Ex)
+	}
+	return false
+}
+
+// vimPaste pastes the text in the register of the given command, after
+// the cursor for p and before it for P, which is on the next or current
+// line for text from a linewise motion, or replaces the selection in
+// visual mode.
+func (ed *TextEditor) vimPaste(cmd vim.Command, visual bool) {
+	txt := ed.vimRegister(cmd.Register)
+	if txt == "" {
+		return
+	}
+	txt = strings.Repeat(txt, cmd.N())
+	linewise := strings.HasSuffix(txt, "\n")
+	pos := ed.CursorPos
+	ed.Lines.NewUndoGroup()
+	if visual {
+		vs := ed.vim
+		reg := ed.SelectRegion
+		if vs.mode == vim.VisualLine {
+			reg = vim.Range(ed.Lines, vs.visualStart, pos, vim.Linewise)
+		}
+		ed.vimSetMode(vim.Normal)
+		ed.Lines.DeleteText(reg.Start, reg.End)
+		pos = reg.Start
+		if linewise && pos.Char > 0 {
+			pos = textpos.Pos{Line: pos.Line + 1}
+			if pos.Line >= ed.Lines.NumLines() {
+				ed.Lines.InsertText(reg.Start, []rune("\n"))
+			}
+		}
+		cmd.Key = "P"
+	}
+	switch {
+	case linewise && cmd.Key == "P":
+		pos = textpos.Pos{Line: pos.Line}
+	case linewise && pos.Line+1 < ed.Lines.NumLines():
+		pos = textpos.Pos{Line: pos.Line + 1}
+	case linewise:
+		pos = textpos.Pos{Line: pos.Line, Char: ed.Lines.LineLen(pos.Line)}
+		txt = "\n" + strings.TrimSuffix(txt, "\n")
+	case cmd.Key == "p":
+		pos.Char = min(pos.Char+1, ed.Lines.LineLen(pos.Line))
+	}
+	tbe := ed.Lines.InsertText(pos, []rune(txt))
+	if tbe == nil {
+		return
+	}
+	if linewise {
+		ln := tbe.Region.Start.Line
+		if tbe.Region.Start.Char > 0 {
+			ln++
+		}
+		ed.vimSetCursor(ed.vimFirstNonBlank(ln))
+		return
+	}
+	end := tbe.Region.End
+	end.Char--
+	ed.vimSetCursor(end)
+}
+
+// vimJoin joins the given number of lines after the given line onto it,
+// replacing the indentation of each joined line with a space.
+func (ed *TextEditor) vimJoin(ln, lns int) {
+	ed.Lines.NewUndoGroup()
+	for range lns {
+		if ln+1 >= ed.Lines.NumLines() {
+			break
+		}
+		end := ed.Lines.LineLen(ln)
+		next := ed.Lines.Line(ln + 1)
+		ind := 0
+		for ind < len(next) && (next[ind] == ' ' || next[ind] == '\t') {
+			ind++
+		}
+		sep := " "
+		if end == 0 || ind == len(next) || next[ind] == ')' {
+			sep = ""
+		}
+		ed.Lines.ReplaceText(textpos.Pos{Line: ln, Char: end}, textpos.Pos{Line: ln + 1, Char: ind}, textpos.Pos{Line: ln, Char: end}, sep, lines.ReplaceNoMatchCase)
+		ed.vimSetCursor(textpos.Pos{Line: ln, Char: end})
+	}
+}
+
+// vimToggleCase toggles the case of the characters from the cursor,
+// or of the selection in visual mode.
+func (ed *TextEditor) vimToggleCase(cmd vim.Command, visual bool) {
+	pos := ed.CursorPos
+	reg := textpos.Region{Start: pos, End: textpos.Pos{Line: pos.Line, Char: min(pos.Char+cmd.N(), ed.Lines.LineLen(pos.Line))}}
+	if visual {
+		reg = ed.SelectRegion
+		ed.vimSetMode(vim.Normal)
+	}
+	if reg.IsNil() {
+		return
+	}
+	rs := []rune(string(ed.Lines.Region(reg.Start, reg.End).ToBytes()))
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			rs[i] = unicode.ToLower(r)
+		} else {
+			rs[i] = unicode.ToUpper(r)
+		}
+	}
+	ed.Lines.NewUndoGroup()
+	ed.Lines.ReplaceText(reg.Start, reg.End, reg.Start, string(rs), lines.ReplaceNoMatchCase)
+	if visual {
+		ed.vimSetCursor(reg.Start)
+	} else {
+		ed.vimSetCursor(reg.End)
+	}
+}
+
+// vimUndo sets the cursor after the given edits were undone or redone.
+func (ed *TextEditor) vimUndo(tbes []*textpos.Edit, undo bool) {
+	if len(tbes) == 0 {
+		return
+	}
+	tbe := tbes[len(tbes)-1]
+	if tbe.Delete == undo {
+		ed.vimSetCursor(tbe.Region.End)
+	} else {
+		ed.vimSetCursor(tbe.Region.Start)
+	}
+}
+
+// vimRepeat repeats the last change the given number of times,
+// by sending its key chords to the editor again.
+func (ed *TextEditor) vimRepeat(n int) {
+	vs := ed.vim
+	chords := vs.lastChange
+	vs.repeating = true
+	defer func() { vs.repeating = false }()
+	for range n {
+		for _, kc := range chords {
+			r, code, mods, err := kc.Decode()
+			if errors.Log(err) != nil {
+				return
+			}
+			ed.HandleEvent(events.NewKey(events.KeyChord, r, code, mods))
+		}
+	}
+}
+
+// vimRegisters are the registers of Vim mode, which are only kept in memory,
+// unlike the [AvailableRegisters] saved in the settings, so that the text
+// that is yanked and deleted is not saved.
+var vimRegisters = Registers{}
+
+// vimRegister returns the text in the given register, where 0 is the
+// unnamed register ", and + and * are the system clipboard. A named register
+// that has not been set in Vim mode is one of the [AvailableRegisters].
+func (ed *TextEditor) vimRegister(r rune) string {
+	if r == '+' || r == '*' {
+		md := ed.Clipboard().Read([]string{fileinfo.TextPlain})
+		if md == nil {
+			return ""
+		}
+		return string(md.TypeData(fileinfo.TextPlain))
+	}
+	if r == 0 {
+		r = '"'
+	}
+	nm := string(unicode.ToLower(r))
+	if txt, ok := vimRegisters[nm]; ok || r == '"' {
+		return txt
+	}
+	return AvailableRegisters[nm]
+}
+
+// vimSetRegister sets the given register, as in [TextEditor.vimRegister],
+// to the given text, which is also set in the unnamed register. An upper
+// case register name appends to the register.
+func (ed *TextEditor) vimSetRegister(r rune, txt string) {
+	if r == '+' || r == '*' {
+		ed.Clipboard().Write(mimedata.NewText(txt))
+		return
+	}
+	vimRegisters[`"`] = txt
+	if r != 0 && r != '"' {
+		if unicode.IsUpper(r) {
+			txt = ed.vimRegister(r) + txt
+		}
+		vimRegisters[string(unicode.ToLower(r))] = txt
+	}
+}
+
+// vimExKey handles the given key typed in ex mode.
+func (ed *TextEditor) vimExKey(k string) {
+	vs := ed.vim
+	switch k {
+	case "Escape":
+		ed.vimSetMode(vim.Normal)
+	case "Backspace":
+		if vs.ex == "" {
+			ed.vimSetMode(vim.Normal)
+			return
+		}
+		rs := []rune(vs.ex)
+		vs.ex = string(rs[:len(rs)-1])
+	case "ReturnEnter", "KeypadEnter":
+		ex := vs.ex
+		ed.vimSetMode(vim.Normal)
+		ed.vimEx(ex)
+	default:
+		if len([]rune(k)) == 1 {
+			vs.ex += k
+		}
+	}
+}
+
+// vimEx executes the given ex command: a line number to go to, w to save,
+// q to close if saved, q! to close discarding changes, wq or x to do both,
+// and s to replace in the current line or all lines with %. %s with the
+// g flag finds all of the matches in the Find panel, where they can be
+// replaced.
+func (ed *TextEditor) vimEx(s string) {
+	cv := ed.Code
+	ex, err := vim.ParseEx(s)
+	if err != nil {
+		cv.SetStatus(err.Error())
+		return
+	}
+	switch ex.Name {
+	case "":
+		ed.vimSetCursor(ed.vimFirstNonBlank(ex.Line - 1))
+	case "w":
+		cv.SaveActiveView()
+	case "q":
+		if ed.Lines.IsNotSaved() {
+			if !ex.Bang {
+				cv.SetStatus("vim: no write since last change (add ! to override)")
+				return
+			}
+			ed.Lines.ClearNotSaved()
+		}
+		cv.CloseActiveView()
+	case "wq", "x":
+		cv.SaveActiveView()
+		cv.CloseActiveView()
+	case "s":
+		if ex.All && ex.Global {
+			cv.Find(ex.Find, ex.Replace, ex.IgnoreCase, true, File, nil)
+			return
+		}
+		ed.vimSubstitute(ex)
+	}
+}
+
+// vimSubstitute replaces the matches of the given s command
+// in the current line, or all lines if it has the % range.
+func (ed *TextEditor) vimSubstitute(ex *vim.ExCommand) {
+	re, err := ex.Regexp()
+	if err != nil {
+		ed.Code.SetStatus("vim: " + err.Error())
+		return
+	}
+	st, end := ed.CursorPos.Line, ed.CursorPos.Line
+	if ex.All {
+		st, end = 0, ed.Lines.NumLines()-1
+	}
+	n := 0
+	for ln := st; ln <= end; ln++ {
+		line := ed.Lines.Line(ln)
+		nl, ok := ex.Substitute(re, string(line))
+		if !ok {
+			continue
+		}
+		if n == 0 {
+			ed.Lines.NewUndoGroup()
+		}
+		ed.Lines.ReplaceText(textpos.Pos{Line: ln}, textpos.Pos{Line: ln, Char: len(line)}, textpos.Pos{Line: ln}, nl, lines.ReplaceNoMatchCase)
+		n++
+	}
+	if n == 0 {
+		ed.Code.SetStatus("vim: pattern not found: " + ex.Find)
+		return
+	}
+	ed.Code.SetStatus(fmt.Sprintf("vim: %d lines changed", n))
+	ed.vimSetCursor(ed.vimFirstNonBlank(ed.CursorPos.Line))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
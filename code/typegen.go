@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"time"
 
+	"cogentcore.org/cogent/code/cdebug"
+	"cogentcore.org/cogent/code/structural"
 	"cogentcore.org/core/base/fileinfo"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/text/parse/lexer"
@@ -14,7 +16,25 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.Code", IDName: "code", Doc: "Code is the core editor and tab viewer widget for the Code system. The\ndefault view has a tree browser of files on the left, editor panels in the\nmiddle, and a tabbed viewer on the right.", Methods: []types.Method{{Name: "UpdateFiles", Doc: "UpdateFiles updates the list of files saved in project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "OpenRecent", Doc: "OpenRecent opens a recently used file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}}, {Name: "OpenFile", Doc: "OpenFile opens file in an open project if it has the same path as the file\nor in a new window.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnm"}}, {Name: "OpenPath", Doc: "OpenPath creates a new project by opening given path, which can either be a\nspecific file or a folder containing multiple files of interest -- opens in\ncurrent Code object if it is empty, or otherwise opens a new window.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path"}, Returns: []string{"Code"}}, {Name: "openPath", Doc: "openPath creates a new project by opening given path, which can either be a\nspecific file or a folder containing multiple files of interest -- opens in\ncurrent Code object if it is empty, or otherwise opens a new window.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path"}, Returns: []string{"Code"}}, {Name: "OpenProject", Doc: "OpenProject opens .code project file and its settings from given filename,\nin a standard toml-formatted file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"Code"}}, {Name: "openProject", Doc: "openProject opens .code project file and its settings from given filename,\nin a standard toml-formatted file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"Code"}}, {Name: "NewProject", Doc: "NewProject creates a new project at given path, making a new folder in that\npath -- all Code projects are essentially defined by a path to a folder\ncontaining files.  If the folder already exists, then use OpenPath.\nCan also specify main language and version control type.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path", "folder", "mainLang", "versionControl"}, Returns: []string{"Code"}}, {Name: "NewFile", Doc: "NewFile creates a new file in the project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename", "addToVcs"}}, {Name: "SaveProject", Doc: "SaveProject saves project file containing custom project settings, in a\nstandard toml-formatted file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SaveProjectAs", Doc: "SaveProjectAs saves project custom settings to given filename, in a standard\ntoml-formatted file\nsaveAllFiles indicates if user should be prompted for saving all files\nreturns true if the user was prompted, false otherwise", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"bool"}}, {Name: "ExecCmdNameActive", Doc: "ExecCmdNameActive calls given command on current active texteditor", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"cmdName"}}, {Name: "ExecCmd", Doc: "ExecCmd pops up a menu to select a command appropriate for the current\nactive text view, and shows output in Tab with name of command", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RunBuild", Doc: "RunBuild runs the BuildCmds set for this project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Run", Doc: "Run runs the RunCmds set for this project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Commit", Doc: "Commit commits the current changes using relevant VCS tool.\nChecks for VCS setting and for unsaved files.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CursorToHistPrev", Doc: "CursorToHistPrev moves back to the previous history item.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "CursorToHistNext", Doc: "CursorToHistNext moves forward to the next history item.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "ReplaceInActive", Doc: "ReplaceInActive does query-replace in active file only", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CutRect", Doc: "CutRect cuts rectangle in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CopyRect", Doc: "CopyRect copies rectangle in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "PasteRect", Doc: "PasteRect cuts rectangle in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RegisterCopy", Doc: "RegisterCopy saves current selection in active text view\nto register of given name returns true if saved.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"regNm"}}, {Name: "RegisterPaste", Doc: "RegisterPaste prompts user for available registers,\nand pastes selected one into active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"ctx"}}, {Name: "CommentOut", Doc: "CommentOut comments-out selected lines in active text view\nand uncomments if already commented\nIf multiple lines are selected and any line is uncommented all will be commented", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "Indent", Doc: "Indent indents selected lines in active view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "ReCase", Doc: "ReCase replaces currently selected text in current active view with given case", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"c"}, Returns: []string{"string"}}, {Name: "JoinParaLines", Doc: "JoinParaLines merges sequences of lines with hard returns forming paragraphs,\nseparated by blank lines, into a single line per paragraph,\nfor given selected region (full text if no selection)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "TabsToSpaces", Doc: "TabsToSpaces converts tabs to spaces\nfor given selected region (full text if no selection)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SpacesToTabs", Doc: "SpacesToTabs converts spaces to tabs\nfor given selected region (full text if no selection)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "DiffFiles", Doc: "DiffFiles shows the differences between two given files\nin side-by-side DiffEditor and in the console as a context diff.\nIt opens the files as file nodes and uses existing contents if open already.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnmA", "fnmB"}}, {Name: "DiffFileLines", Doc: "DiffFileLines shows the differences between given file node as the A file,\nand another given file as the B file,\nin side-by-side DiffEditor and in the console as a context diff.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"lna", "fnmB"}}, {Name: "CountWords", Doc: "CountWords counts number of words (and lines) in active file\nreturns a string report thereof.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"string"}}, {Name: "CountWordsRegion", Doc: "CountWordsRegion counts number of words (and lines) in selected region in file\nif no selection, returns numbers for entire file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"string"}}, {Name: "SaveActiveView", Doc: "SaveActiveView saves the contents of the currently active texteditor.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SaveActiveViewAs", Doc: "SaveActiveViewAs save with specified filename the contents of the\ncurrently active texteditor", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}}, {Name: "RevertActiveView", Doc: "RevertActiveView revert active view to saved version.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CloseActiveView", Doc: "CloseActiveView closes the buffer associated with active view.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ViewFile", Doc: "ViewFile views file in an existing TextEditor if it is already viewing that\nfile, otherwise opens ViewLines in active buffer.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnm"}, Returns: []string{"TextEditor", "int", "bool"}}, {Name: "NextViewFile", Doc: "NextViewFile sets the next text view to view given file name.\nWill use a more robust search of file tree if file path is not\ndirectly openable. Returns texteditor and its index, false if not found.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnm"}, Returns: []string{"TextEditor", "int", "bool"}}, {Name: "CloneActiveView", Doc: "CloneActiveView sets the next text view to view the same file currently being vieweds\nin the active view. returns text view and index", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"TextEditor", "int"}}, {Name: "SaveAll", Doc: "SaveAll saves all of the open filenodes to their current file names\nand saves the project state if it has been saved before (i.e., the .code file exists)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Find", Doc: "Find does Find / Replace in files, using given options and filters -- opens up a\nmain tab with the results and further controls.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"find", "repl", "ignoreCase", "regExp", "loc", "langs"}}, {Name: "FocusNextPanel", Doc: "FocusNextPanel moves the keyboard focus to the next panel to the right", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "FocusPrevPanel", Doc: "FocusPrevPanel moves the keyboard focus to the previous panel to the left", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditProjectSettings", Doc: "EditProjectSettings allows editing of project settings (settings specific to this project)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SplitsSetView", Doc: "SplitsSetView sets split view splitters to given named setting", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"split"}}, {Name: "SplitsSave", Doc: "SplitsSave saves current splitter settings to named splitter settings under\nexisting name, and saves to prefs file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"split"}}, {Name: "SplitsSaveAs", Doc: "SplitsSaveAs saves current splitter settings to new named splitter settings, and\nsaves to prefs file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name", "desc"}}, {Name: "SplitsEdit", Doc: "SplitsEdit opens the SplitsView editor to customize saved splitter settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Spell", Doc: "Spell checks spelling in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Symbols", Doc: "Symbols displays the Symbols of a file or package", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Debug", Doc: "Debug starts the debugger on the RunExec executable.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "DebugTest", Doc: "DebugTest runs the debugger using testing mode in current active texteditor path.\ntestName specifies which test(s) to run according to the standard go test -run\nspecification.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"testName"}}, {Name: "DebugAttach", Doc: "DebugAttach runs the debugger by attaching to an already-running process.\npid is the process id to attach to.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"pid"}}, {Name: "VCSUpdateAll", Doc: "VCSUpdateAll does an Update (e.g., Pull) on all VCS repositories within\nthe open tree nodes in FileTree.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "VCSLog", Doc: "VCSLog shows the VCS log of commits in this project,\nin an interactive browser from which any revisions can be\ncompared and diffs browsed.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"Log", "error"}}, {Name: "OpenConsoleTab", Doc: "OpenConsoleTab opens a main tab displaying console output (stdout, stderr)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ChooseRunExec", Doc: "ChooseRunExec selects the executable to run for the project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"exePath"}}, {Name: "HelpWiki", Doc: "HelpWiki opens wiki page for code on github", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "ProjectRoot", Doc: "root directory for the project -- all projects must be organized within a top-level root directory, with all the files therein constituting the scope of the project -- by default it is the path for ProjectFilename"}, {Name: "ProjectFilename", Doc: "current project filename for saving / loading specific Code configuration information in a .code file (optional)"}, {Name: "ActiveFilename", Doc: "filename of the currently active texteditor"}, {Name: "ActiveLang", Doc: "language for current active filename"}, {Name: "ActiveVCS", Doc: "VCS repo for current active filename"}, {Name: "ActiveVCSInfo", Doc: "VCS info for current active filename (typically branch or revision) -- for status"}, {Name: "Changed", Doc: "has the root changed?  we receive update signals from root for changes"}, {Name: "StatusMessage", Doc: "the last status update message"}, {Name: "LastSaveTStamp", Doc: "timestamp for when a file was last saved -- provides dirty state for various updates including rebuilding in debugger"}, {Name: "Files", Doc: "all the files in the project directory and subdirectories"}, {Name: "ActiveEditorIndex", Doc: "index of the currently active texteditor -- new files will be viewed in other views if available"}, {Name: "OpenFiles", Doc: "list of open files, most recent first"}, {Name: "CmdBufs", Doc: "the command buffers for commands run in this project"}, {Name: "CmdHistory", Doc: "history of commands executed in this session"}, {Name: "RunningCmds", Doc: "currently running commands in this project"}, {Name: "ArgVals", Doc: "current arg var vals"}, {Name: "Settings", Doc: "settings for this project -- this is what is saved in a .code project file"}, {Name: "CurDbg", Doc: "current debug view"}, {Name: "KeySeq1", Doc: "first key in sequence if needs2 key pressed"}, {Name: "UpdateMu", Doc: "mutex for protecting overall updates to Code"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.Breadcrumbs", IDName: "breadcrumbs", Doc: "Breadcrumbs is the bar above a [TextEditor] that shows the package,\ntype, and function or method at the cursor, using the same symbols\nas the [SymbolsPanel], each with a menu of the other symbols in its\nscope to go to.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Editor", Doc: "Editor is the editor that the breadcrumbs are for."}, {Name: "pkg", Doc: "pkg is the package-level symbols, nil if not parsed."}, {Name: "path", Doc: "path is the path of symbols at the cursor, from [symbolPath]."}}})
+
+// NewBreadcrumbs returns a new [Breadcrumbs] with the given optional parent:
+// Breadcrumbs is the bar above a [TextEditor] that shows the package,
+// type, and function or method at the cursor, using the same symbols
+// as the [SymbolsPanel], each with a menu of the other symbols in its
+// scope to go to.
+func NewBreadcrumbs(parent ...tree.Node) *Breadcrumbs { return tree.New[Breadcrumbs](parent...) }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.StickyHeaders", IDName: "sticky-headers", Doc: "StickyHeaders shows the declaration lines of the function and type that\nenclose the first visible line of a [TextEditor] above it, which are\notherwise scrolled out of view, if [SettingsData.StickyHeaders] is on.\nClicking on a line goes to the declaration.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Editor", Doc: "Editor is the editor that the headers are for."}, {Name: "symbols", Doc: "symbols are the symbols whose declarations are shown,\nfrom [stickySymbols]."}}})
+
+// NewStickyHeaders returns a new [StickyHeaders] with the given optional parent:
+// StickyHeaders shows the declaration lines of the function and type that
+// enclose the first visible line of a [TextEditor] above it, which are
+// otherwise scrolled out of view, if [SettingsData.StickyHeaders] is on.
+// Clicking on a line goes to the declaration.
+func NewStickyHeaders(parent ...tree.Node) *StickyHeaders { return tree.New[StickyHeaders](parent...) }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.Code", IDName: "code", Doc: "Code is the core editor and tab viewer widget for the Code system. The\ndefault view has a tree browser of files on the left, editor panels in the\nmiddle, and a tabbed viewer on the right.", Methods: []types.Method{{Name: "UpdateFiles", Doc: "UpdateFiles updates the list of files saved in project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "OpenRecent", Doc: "OpenRecent opens a recently used file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}}, {Name: "OpenFile", Doc: "OpenFile opens file in an open project if it has the same path as the file\nor in a new window.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnm"}}, {Name: "OpenPath", Doc: "OpenPath creates a new project by opening given path, which can either be a\nspecific file or a folder containing multiple files of interest -- opens in\ncurrent Code object if it is empty, or otherwise opens a new window.\nThe path can also be an ssh://[user@]host[:port]/path url for a project\non a remote host, which is then edited and built over the SSH connection.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path"}, Returns: []string{"Code"}}, {Name: "openPath", Doc: "openPath creates a new project by opening given path, which can either be a\nspecific file or a folder containing multiple files of interest -- opens in\ncurrent Code object if it is empty, or otherwise opens a new window.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path"}, Returns: []string{"Code"}}, {Name: "OpenProject", Doc: "OpenProject opens .code project file and its settings from given filename,\nin a standard toml-formatted file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"Code"}}, {Name: "openProject", Doc: "openProject opens .code project file and its settings from given filename,\nin a standard toml-formatted file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"Code"}}, {Name: "NewProject", Doc: "NewProject creates a new project at given path, making a new folder in that\npath -- all Code projects are essentially defined by a path to a folder\ncontaining files.  If the folder already exists, then use OpenPath.\nCan also specify main language and version control type.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"path", "folder", "mainLang", "versionControl"}, Returns: []string{"Code"}}, {Name: "NewFile", Doc: "NewFile creates a new file in the project, optionally from the given\ntemplate, which is inserted into the new file as a snippet, with its\ntab stops to fill in.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename", "template", "addToVcs"}}, {Name: "SaveProject", Doc: "SaveProject saves project file containing custom project settings, in a\nstandard toml-formatted file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SaveProjectAs", Doc: "SaveProjectAs saves project custom settings to given filename, in a standard\ntoml-formatted file\nsaveAllFiles indicates if user should be prompted for saving all files\nreturns true if the user was prompted, false otherwise", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"bool"}}, {Name: "ExecCmdNameActive", Doc: "ExecCmdNameActive calls given command on current active texteditor", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"cmdName"}}, {Name: "ExecCmd", Doc: "ExecCmd pops up a menu to select a command appropriate for the current\nactive text view, and shows output in Tab with name of command", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RunBuild", Doc: "RunBuild runs the BuildCmds set for this project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Run", Doc: "Run runs the RunCmds set for this project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Commit", Doc: "Commit commits the current changes using relevant VCS tool.\nChecks for VCS setting and for unsaved files.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ClearCoverage", Doc: "ClearCoverage removes the code coverage and its markers.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CursorToHistPrev", Doc: "CursorToHistPrev moves back to the previous history item.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "CursorToHistNext", Doc: "CursorToHistNext moves forward to the next history item.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "ReplaceInActive", Doc: "ReplaceInActive does query-replace in active file only", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CutRect", Doc: "CutRect cuts rectangle in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CopyRect", Doc: "CopyRect copies rectangle in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "PasteRect", Doc: "PasteRect cuts rectangle in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RegisterCopy", Doc: "RegisterCopy saves current selection in active text view\nto register of given name returns true if saved.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"regNm"}}, {Name: "RegisterPaste", Doc: "RegisterPaste prompts user for available registers,\nand pastes selected one into active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"ctx"}}, {Name: "CommentOut", Doc: "CommentOut comments-out selected lines in active text view\nand uncomments if already commented\nIf multiple lines are selected and any line is uncommented all will be commented", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "Indent", Doc: "Indent indents selected lines in active view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"bool"}}, {Name: "ReCase", Doc: "ReCase replaces currently selected text in current active view with given case", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"c"}, Returns: []string{"string"}}, {Name: "JoinParaLines", Doc: "JoinParaLines merges sequences of lines with hard returns forming paragraphs,\nseparated by blank lines, into a single line per paragraph,\nfor given selected region (full text if no selection)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "TabsToSpaces", Doc: "TabsToSpaces converts tabs to spaces\nfor given selected region (full text if no selection)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SpacesToTabs", Doc: "SpacesToTabs converts spaces to tabs\nfor given selected region (full text if no selection)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "DiffFiles", Doc: "DiffFiles shows the differences between two given files\nin side-by-side DiffEditor and in the console as a context diff.\nIt opens the files as file nodes and uses existing contents if open already.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnmA", "fnmB"}}, {Name: "DiffFileLines", Doc: "DiffFileLines shows the differences between given file node as the A file,\nand another given file as the B file,\nin side-by-side DiffEditor and in the console as a context diff.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"lna", "fnmB"}}, {Name: "CountWords", Doc: "CountWords counts number of words (and lines) in active file\nreturns a string report thereof.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"string"}}, {Name: "CountWordsRegion", Doc: "CountWordsRegion counts number of words (and lines) in selected region in file\nif no selection, returns numbers for entire file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"string"}}, {Name: "SaveActiveView", Doc: "SaveActiveView saves the contents of the currently active texteditor.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SaveActiveViewAs", Doc: "SaveActiveViewAs save with specified filename the contents of the\ncurrently active texteditor", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}}, {Name: "RevertActiveView", Doc: "RevertActiveView revert active view to saved version.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CloseActiveView", Doc: "CloseActiveView closes the buffer associated with active view.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ViewFile", Doc: "ViewFile views file in an existing TextEditor if it is already viewing that\nfile, otherwise opens ViewLines in active buffer.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnm"}, Returns: []string{"TextEditor", "int", "bool"}}, {Name: "NextViewFile", Doc: "NextViewFile sets the next text view to view given file name.\nWill use a more robust search of file tree if file path is not\ndirectly openable. Returns texteditor and its index, false if not found.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fnm"}, Returns: []string{"TextEditor", "int", "bool"}}, {Name: "CloneActiveView", Doc: "CloneActiveView sets the next text view to view the same file currently being vieweds\nin the active view. returns text view and index", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"TextEditor", "int"}}, {Name: "SaveAll", Doc: "SaveAll saves all of the open filenodes to their current file names\nand saves the project state if it has been saved before (i.e., the .code file exists)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Find", Doc: "Find does Find / Replace in files, using given options and filters -- opens up a\nmain tab with the results and further controls.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"find", "repl", "ignoreCase", "regExp", "loc", "langs"}}, {Name: "FindStructural", Doc: "FindStructural does structural Find / Replace in Go files, where find is\nGo code with $name metavariables that match any expression, and repl can\nuse the same metavariables, e.g., fmt.Errorf($msg) and errors.New($msg).\nIt opens up a main tab with the results and further controls, as for [Code.Find].", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"find", "repl", "loc"}}, {Name: "DebugLaunch", Doc: "DebugLaunch starts the debugger with the launch configuration of the\ngiven name, after running its pre-launch command, if any, which prompts\nfor any values that it needs, as when it is run from the menu.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name"}}, {Name: "FindDefinition", Doc: "FindDefinition goes to the definition of the symbol at the cursor\nin the active editor, using the language server if there is one,\nand otherwise the parse-based Lookup.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "FindReferences", Doc: "FindReferences shows all the references to the symbol at the cursor\nin the active editor in the Find panel, using the language server if\nthere is one, and otherwise finding the word across the project.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RenameSymbol", Doc: "RenameSymbol renames the symbol at the cursor in the active editor,\nand all references to it, to the given new name, using the language\nserver. Files are opened as needed to apply the changes, and each can\nbe undone separately. Without a language server, it falls back on\nrenaming the whole word at the cursor within the active file.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"newName"}}, {Name: "RecordMacro", Doc: "RecordMacro starts recording a keyboard macro of the key chords\ntyped in the active editor and the commands that are run, or stops\nrecording it if it is already being recorded, in which case it\nbecomes the last macro that can be replayed or saved.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SaveMacro", Doc: "SaveMacro saves the last recorded macro with the given name in\nthe macros in the settings, replacing any with the same name.\nIt is replayed by the given key chord or two-key sequence,\ne.g., Control+X 1, if it is not empty, which is removed from\nany other macro that it replays.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name", "key"}}, {Name: "ReplayMacro", Doc: "ReplayMacro replays the macro of the given name the given number of\ntimes in the active editor, where the last recorded macro is replayed\nif the name is empty.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name", "times"}}, {Name: "ApplyMacroToLines", Doc: "ApplyMacroToLines replays the macro of the given name once for each\nline in the selection of the active editor, with the cursor at the\nstart of the line, from the last line to the first so that the lines\nare not affected by changes to the line count. The last recorded macro\nis replayed if the name is empty.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name"}}, {Name: "FocusNextPanel", Doc: "FocusNextPanel moves the keyboard focus to the next panel to the right", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "FocusPrevPanel", Doc: "FocusPrevPanel moves the keyboard focus to the previous panel to the left", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ClearProblems", Doc: "ClearProblems removes all problems and their markers.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ViewProfile", Doc: "ViewProfile opens the pprof CPU or memory profile in given file,\ne.g., as written by go test -cpuprofile, and shows it in the Profile\npanel, and the cost of each line in the editors.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fname"}}, {Name: "ProfileTest", Doc: "ProfileTest runs go test with a CPU profile in the directory of the\nactive editor, for the given test(s), and then shows the profile.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"testName"}}, {Name: "ShowProfile", Doc: "ShowProfile shows the Profile panel.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ClearProfile", Doc: "ClearProfile removes the profile from the Profile panel and the editors.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditProjectSettings", Doc: "EditProjectSettings allows editing of project settings (settings specific to this project)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SplitsSetView", Doc: "SplitsSetView sets split view splitters to given named setting", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"split"}}, {Name: "SplitsSave", Doc: "SplitsSave saves current splitter settings to named splitter settings under\nexisting name, and saves to settings file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"split"}}, {Name: "SplitsSaveAs", Doc: "SplitsSaveAs saves current splitter settings to new named splitter settings, and\nsaves to settings file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"name", "desc"}}, {Name: "SplitsEdit", Doc: "SplitsEdit opens the SplitsView editor to customize saved splitter settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "InsertSnippet", Doc: "InsertSnippet prompts for a snippet for the language of the active file,\nand inserts it at the cursor, replacing any selected text, which is\navailable in the snippet as {CurSel}. Snippets can also be inserted\nby completing their prefix.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"ctx"}}, {Name: "EditSnippets", Doc: "EditSnippets opens the snippet file for the language of the active file\nin the [SnippetsDir], creating it with the standard snippets for the\nlanguage if it does not exist yet. The snippets are updated when it is saved.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Spell", Doc: "Spell checks spelling in active text view", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Symbols", Doc: "Symbols displays the Symbols of a file or package", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "CallHierarchy", Doc: "CallHierarchy shows the functions that call the Go function at the\ncursor in the active editor, in the Hierarchy panel, where the functions\nthat it calls can also be shown.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "TypeHierarchy", Doc: "TypeHierarchy shows the types that implement the Go interface at the\ncursor in the active editor, or the interfaces that the type at the\ncursor implements, in the Hierarchy panel.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ShowProblems", Doc: "ShowProblems displays the problems reported by build, vet, test and\nother commands that have a ProblemRegexp.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ShowMerge", Doc: "ShowMerge displays the merge conflicts in the active file, left by\na version control pull, merge or rebase, to resolve them.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ShowTests", Doc: "ShowTests displays the Go tests in the project, which can be\nrun and debugged from there.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "RunTestAtCursor", Doc: "RunTestAtCursor runs the Go test or subtest at the cursor\nin the active editor, showing the results in the Tests panel.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Debug", Doc: "Debug starts the debugger on the RunExec executable, or with the\nlaunch configuration chosen on the Debug button, if any.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "DebugTest", Doc: "DebugTest runs the debugger using testing mode in current active texteditor path.\ntestName specifies which test(s) to run according to the standard go test -run\nspecification.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"testName"}}, {Name: "DebugAttach", Doc: "DebugAttach runs the debugger by attaching to an already-running process.\npid is the process id to attach to.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"pid"}}, {Name: "DebugCore", Doc: "DebugCore runs the debugger on the given core dump of a crashed process\nof the given executable, to inspect its stack, variables, tasks and threads\npost-mortem. The program cannot be run, so the execution commands are disabled.\nexe defaults to the RunExec of the project.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"coreFile", "exe"}}, {Name: "VCSUpdateAll", Doc: "VCSUpdateAll does an Update (e.g., Pull) on all VCS repositories within\nthe open tree nodes in the file trees of all project roots.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ToggleBlame", Doc: "ToggleBlame toggles the blame gutter in the active editor, showing the\nrevision, author and date of the last change to each line.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "VCSLog", Doc: "VCSLog shows the VCS log of commits in this project,\nin an interactive browser from which any revisions can be\ncompared and diffs browsed.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Returns: []string{"Log", "error"}}, {Name: "OpenConsoleTab", Doc: "OpenConsoleTab opens a main tab displaying console output (stdout, stderr)", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "ChooseRunExec", Doc: "ChooseRunExec selects the executable to run for the project", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"exePath"}}, {Name: "HelpWiki", Doc: "HelpWiki opens wiki page for code on github", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "ProjectRoot", Doc: "root directory for the project -- all projects must be organized within a top-level root directory, with all the files therein constituting the scope of the project -- by default it is the path for ProjectFilename"}, {Name: "ProjectFilename", Doc: "current project filename for saving / loading specific Code configuration information in a .code file (optional)"}, {Name: "ActiveFilename", Doc: "filename of the currently active texteditor"}, {Name: "ActiveLang", Doc: "language for current active filename"}, {Name: "ActiveVCS", Doc: "VCS repo for current active filename"}, {Name: "ActiveVCSInfo", Doc: "VCS info for current active filename (typically branch or revision) -- for status"}, {Name: "Changed", Doc: "has the root changed?  we receive update signals from root for changes"}, {Name: "StatusMessage", Doc: "the last status update message"}, {Name: "LastSaveTStamp", Doc: "timestamp for when a file was last saved -- provides dirty state for various updates including rebuilding in debugger"}, {Name: "Files", Doc: "all the files in the project directory and subdirectories"}, {Name: "ActiveEditorIndex", Doc: "index of the currently active texteditor -- new files will be viewed in other views if available"}, {Name: "OpenFiles", Doc: "list of open files, most recent first"}, {Name: "CmdBufs", Doc: "the command buffers for commands run in this project"}, {Name: "CmdHistory", Doc: "history of commands executed in this session"}, {Name: "RunningCmds", Doc: "currently running commands in this project"}, {Name: "ArgVals", Doc: "current arg var vals"}, {Name: "Settings", Doc: "settings for this project -- this is what is saved in a .code project file"}, {Name: "CurDbg", Doc: "current debug view"}, {Name: "LangServers", Doc: "language servers for this project"}, {Name: "Problems", Doc: "problems reported by commands, shown in the Problems panel"}, {Name: "Coverage", Doc: "code coverage loaded from a coverage profile, shown in the editors and file tree"}, {Name: "Profile", Doc: "pprof profile shown in the Profile panel and the editors"}, {Name: "Remote", Doc: "connection to the remote host for a project opened at an ssh:// url, nil if local"}, {Name: "Index", Doc: "trigram index of the files under the ProjectRoot, for fast project-wide search,\nwhich is built in the background; nil for a remote project"}, {Name: "Prompter", Doc: "provides the values for prompted argument variables of commands,\nwhich are prompted for in dialogs if nil"}, {Name: "Output", Doc: "if set, the output and status of commands are written here as plain\ntext instead of being shown in tabs, for running them without a GUI"}, {Name: "outputErr", Doc: "error from the last failed command run with Output"}, {Name: "KeySeq1", Doc: "first key in sequence if needs2 key pressed"}, {Name: "macroRecord", Doc: "keyboard macro being recorded, nil if not recording"}, {Name: "lastMacro", Doc: "last recorded keyboard macro, which is replayed by default"}, {Name: "macroReplaying", Doc: "whether a keyboard macro is being replayed, so that its\nkeys are not recorded again"}, {Name: "UpdateMu", Doc: "mutex for protecting overall updates to Code"}}})
 
 // NewCode returns a new [Code] with the given optional parent:
 // Code is the core editor and tab viewer widget for the Code system. The
@@ -42,7 +62,7 @@ func (t *Code) SetStatusMessage(v string) *Code { t.StatusMessage = v; return t
 // list of open files, most recent first
 func (t *Code) SetOpenFiles(v OpenFiles) *Code { t.OpenFiles = v; return t }
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.DebugPanel", IDName: "debug-panel", Doc: "DebugPanel is the debugger panel.", Methods: []types.Method{{Name: "StepOver", Doc: "StepOver continues to the next source line, not entering function calls.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepInto", Doc: "StepInto continues to the next source line, entering function calls.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepOut", Doc: "StepOut continues to the return point of the current function.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SingleStep", Doc: "StepSingle steps a single CPU instruction.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Stop", Doc: "Stop stops a running process.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "FindFrames", Doc: "FindFrames finds the frames where given file and line are active\nSelects the one that is closest and shows the others in Find Tab.\nThe fpath can be just the path or any string fragment contained\nwithin the target filename.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fpath", "line"}}, {Name: "ListGlobalVars", Doc: "ListGlobalVars lists global vars matching the given optional filter.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filter"}}}, Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Known", Doc: "known file type to determine debugger"}, {Name: "ExePath", Doc: "path to executable / dir to debug"}, {Name: "DbgTime", Doc: "time when dbg was last restarted"}, {Name: "Dbg", Doc: "the debugger"}, {Name: "State", Doc: "all relevant debug state info"}, {Name: "CurFileLoc", Doc: "current ShowFile location -- cleared before next one or run"}, {Name: "BBreaks", Doc: "backup breakpoints list -- to track deletes"}, {Name: "OutputBuffer", Doc: "output from the debugger"}, {Name: "Code", Doc: "parent code project"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.DebugPanel", IDName: "debug-panel", Doc: "DebugPanel is the debugger panel.", Methods: []types.Method{{Name: "StepOver", Doc: "StepOver continues to the next source line, not entering function calls.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepInto", Doc: "StepInto continues to the next source line, entering function calls.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "StepOut", Doc: "StepOut continues to the return point of the current function.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "SingleStep", Doc: "StepSingle steps a single CPU instruction.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "Stop", Doc: "Stop stops a running process.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "AddWatch", Doc: "AddWatch adds the given expression to the watches,\nwhich are evaluated every time the debugger stops.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"expr"}}, {Name: "SetWatchpoint", Doc: "SetWatchpoint sets a watchpoint on the variable given by the\nexpression in the current frame, which stops when its memory is written.\nIt is only supported by some debuggers, and is removed when the\nvariable goes out of scope.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"expr"}}, {Name: "FindFrames", Doc: "FindFrames finds the frames where given file and line are active\nSelects the one that is closest and shows the others in Find Tab.\nThe fpath can be just the path or any string fragment contained\nwithin the target filename.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fpath", "line"}}, {Name: "ListGlobalVars", Doc: "ListGlobalVars lists global vars matching the given optional filter.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filter"}}, {Name: "FilterTasksInFunc", Doc: "FilterTasksInFunc shows only the tasks with the given function on their\nstack in the Tasks tab, e.g., the goroutines blocked in it, where the\nfunction name matches the end of the full name, e.g., Server.Serve.\nAn empty name shows all of the tasks.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"fun"}}, {Name: "DiffTaskStacks", Doc: "DiffTaskStacks shows the differences between the stacks\nof the two tasks with the given IDs.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"taskA", "taskB"}}}, Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Known", Doc: "known file type to determine debugger"}, {Name: "ExePath", Doc: "path to executable / dir to debug"}, {Name: "Launch", Doc: "launch configuration used to start the debugger, if any,\nwhich sets the mode, args and environment"}, {Name: "DbgTime", Doc: "time when dbg was last restarted"}, {Name: "Dbg", Doc: "the debugger"}, {Name: "State", Doc: "all relevant debug state info"}, {Name: "CurFileLoc", Doc: "current ShowFile location -- cleared before next one or run"}, {Name: "BBreaks", Doc: "backup breakpoints list -- to track deletes"}, {Name: "OutputBuffer", Doc: "output from the debugger"}, {Name: "Code", Doc: "parent code project"}, {Name: "TaskGrouping", Doc: "how the tasks are grouped in the Tasks tab"}, {Name: "TaskFilter", Doc: "filter for the tasks shown in the Tasks tab"}, {Name: "taskStacks", Doc: "stacks of the tasks by ID, for grouping and filtering them,\nwhich are got when needed, and cleared when execution continues"}, {Name: "loadingStacks", Doc: "loadingStacks is whether the taskStacks are being got\nin the background, for the stop given by taskStacksStop"}, {Name: "taskStacksStop", Doc: "taskStacksStop counts the times execution stopped, so that task\nstacks got in the background for an earlier stop are discarded"}, {Name: "shownTasks", Doc: "tasks shown in the Tasks tab, after filtering"}, {Name: "taskGroups", Doc: "groups of the shown tasks, if they are grouped"}}})
 
 // NewDebugPanel returns a new [DebugPanel] with the given optional parent:
 // DebugPanel is the debugger panel.
@@ -60,6 +80,17 @@ func (t *DebugPanel) SetExePath(v string) *DebugPanel { t.ExePath = v; return t
 // time when dbg was last restarted
 func (t *DebugPanel) SetDbgTime(v time.Time) *DebugPanel { t.DbgTime = v; return t }
 
+// SetTaskGrouping sets the [DebugPanel.TaskGrouping]:
+// how the tasks are grouped in the Tasks tab
+func (t *DebugPanel) SetTaskGrouping(v cdebug.TaskGroupings) *DebugPanel {
+	t.TaskGrouping = v
+	return t
+}
+
+// SetTaskFilter sets the [DebugPanel.TaskFilter]:
+// filter for the tasks shown in the Tasks tab
+func (t *DebugPanel) SetTaskFilter(v cdebug.TaskFilter) *DebugPanel { t.TaskFilter = v; return t }
+
 var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.VarView", IDName: "var-view", Doc: "VarView shows a debug variable in an inspector-like framework,\nwith sub-variables in a tree.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Var", Doc: "variable being edited"}, {Name: "SelectVar"}, {Name: "FrameInfo", Doc: "frame info"}, {Name: "DbgView", Doc: "parent DebugPanel"}}})
 
 // NewVarView returns a new [VarView] with the given optional parent:
@@ -77,7 +108,7 @@ var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.FileNode", I
 // FileNode is Code version of FileNode for FileTree
 func NewFileNode(parent ...tree.Node) *FileNode { return tree.New[FileNode](parent...) }
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.FindPanel", IDName: "find-panel", Doc: "FindPanel is a find / replace widget that displays results in a [TextEditor]\nand has a toolbar for controlling find / replace process.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Time", Doc: "time of last find"}, {Name: "Re", Doc: "compiled regexp"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.FindPanel", IDName: "find-panel", Doc: "FindPanel is a find / replace widget that displays results in a [TextEditor]\nand has a toolbar for controlling find / replace process.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Time", Doc: "time of last find"}, {Name: "Re", Doc: "compiled regexp"}, {Name: "Pattern", Doc: "compiled structural search pattern"}}})
 
 // NewFindPanel returns a new [FindPanel] with the given optional parent:
 // FindPanel is a find / replace widget that displays results in a [TextEditor]
@@ -96,6 +127,94 @@ func (t *FindPanel) SetTime(v time.Time) *FindPanel { t.Time = v; return t }
 // compiled regexp
 func (t *FindPanel) SetRe(v *regexp.Regexp) *FindPanel { t.Re = v; return t }
 
+// SetPattern sets the [FindPanel.Pattern]:
+// compiled structural search pattern
+func (t *FindPanel) SetPattern(v *structural.Pattern) *FindPanel { t.Pattern = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.TestNode", IDName: "test-node", Doc: "TestNode is a node in the tree of Go tests, which has packages\nat the top level, then test functions, then their subtests.\nThe name of the node is the name of the test (or the package path\nrelative to the project root), which for subtests is the name\nas reported by go test, with spaces replaced by underscores.", Embeds: []types.Field{{Name: "NodeBase"}}, Fields: []types.Field{{Name: "Kind", Doc: "kind of node"}, {Name: "Dir", Doc: "for packages, the package directory"}, {Name: "ImportPath", Doc: "for packages, the import path, used to match test events"}, {Name: "Filename", Doc: "file where the test is defined, if known"}, {Name: "Line", Doc: "line where the test is defined, if known (1-based)"}, {Name: "EndLine", Doc: "last line of the test definition, if known (1-based)"}, {Name: "Status", Doc: "result of the last run"}, {Name: "Elapsed", Doc: "elapsed time of the last run, in seconds"}, {Name: "Output", Doc: "output of the last run"}}})
+
+// NewTestNode returns a new [TestNode] with the given optional parent:
+// TestNode is a node in the tree of Go tests, which has packages
+// at the top level, then test functions, then their subtests.
+// The name of the node is the name of the test (or the package path
+// relative to the project root), which for subtests is the name
+// as reported by go test, with spaces replaced by underscores.
+func NewTestNode(parent ...tree.Node) *TestNode { return tree.New[TestNode](parent...) }
+
+// SetKind sets the [TestNode.Kind]:
+// kind of node
+func (t *TestNode) SetKind(v GoTestKinds) *TestNode { t.Kind = v; return t }
+
+// SetDir sets the [TestNode.Dir]:
+// for packages, the package directory
+func (t *TestNode) SetDir(v string) *TestNode { t.Dir = v; return t }
+
+// SetImportPath sets the [TestNode.ImportPath]:
+// for packages, the import path, used to match test events
+func (t *TestNode) SetImportPath(v string) *TestNode { t.ImportPath = v; return t }
+
+// SetFilename sets the [TestNode.Filename]:
+// file where the test is defined, if known
+func (t *TestNode) SetFilename(v string) *TestNode { t.Filename = v; return t }
+
+// SetLine sets the [TestNode.Line]:
+// line where the test is defined, if known (1-based)
+func (t *TestNode) SetLine(v int) *TestNode { t.Line = v; return t }
+
+// SetEndLine sets the [TestNode.EndLine]:
+// last line of the test definition, if known (1-based)
+func (t *TestNode) SetEndLine(v int) *TestNode { t.EndLine = v; return t }
+
+// SetStatus sets the [TestNode.Status]:
+// result of the last run
+func (t *TestNode) SetStatus(v TestStatus) *TestNode { t.Status = v; return t }
+
+// SetElapsed sets the [TestNode.Elapsed]:
+// elapsed time of the last run, in seconds
+func (t *TestNode) SetElapsed(v float64) *TestNode { t.Elapsed = v; return t }
+
+// SetOutput sets the [TestNode.Output]:
+// output of the last run
+func (t *TestNode) SetOutput(v string) *TestNode { t.Output = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.HierarchyPanel", IDName: "hierarchy-panel", Doc: "HierarchyPanel is a widget that shows the call hierarchy of a Go function,\nor the type hierarchy of a Go type, in a tree that is expanded as it is\nopened. It is computed from the Go packages in the project, which are\nloaded when first needed, and again when the file at the cursor is newer.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Kind", Doc: "kind of hierarchy shown"}, {Name: "Root", Doc: "function or type at the root of the hierarchy"}, {Name: "graph", Doc: "graph of the Go packages in the project, nil if not loaded"}, {Name: "loaded", Doc: "time when the graph was loaded"}, {Name: "loading", Doc: "set while the graph is loading"}}})
+
+// NewHierarchyPanel returns a new [HierarchyPanel] with the given optional parent:
+// HierarchyPanel is a widget that shows the call hierarchy of a Go function,
+// or the type hierarchy of a Go type, in a tree that is expanded as it is
+// opened. It is computed from the Go packages in the project, which are
+// loaded when first needed, and again when the file at the cursor is newer.
+func NewHierarchyPanel(parent ...tree.Node) *HierarchyPanel {
+	return tree.New[HierarchyPanel](parent...)
+}
+
+// SetCode sets the [HierarchyPanel.Code]:
+// parent code project
+func (t *HierarchyPanel) SetCode(v *Code) *HierarchyPanel { t.Code = v; return t }
+
+// SetKind sets the [HierarchyPanel.Kind]:
+// kind of hierarchy shown
+func (t *HierarchyPanel) SetKind(v HierarchyKinds) *HierarchyPanel { t.Kind = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.HierarchyTree", IDName: "hierarchy-tree", Doc: "HierarchyTree is a node in the tree of a [HierarchyPanel],\nwhose children are found when it is first opened.", Embeds: []types.Field{{Name: "Tree"}}, Fields: []types.Field{{Name: "Item", Doc: "function or type of the node"}, {Name: "Call", Doc: "for call hierarchies, the calls between the function\nof the node and that of its parent, nil for the root"}, {Name: "panel", Doc: "panel that the tree is in"}, {Name: "expanded", Doc: "whether the children have been found"}}})
+
+// NewHierarchyTree returns a new [HierarchyTree] with the given optional parent:
+// HierarchyTree is a node in the tree of a [HierarchyPanel],
+// whose children are found when it is first opened.
+func NewHierarchyTree(parent ...tree.Node) *HierarchyTree { return tree.New[HierarchyTree](parent...) }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.MergePanel", IDName: "merge-panel", Doc: "MergePanel is a widget for resolving the merge conflicts in a file,\nshowing our, base and their lines side by side for each conflict,\nwith actions to accept either or both of them.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Lines", Doc: "lines of the file with the conflicts"}, {Name: "Conflicts", Doc: "current merge conflicts in the file"}}})
+
+// NewMergePanel returns a new [MergePanel] with the given optional parent:
+// MergePanel is a widget for resolving the merge conflicts in a file,
+// showing our, base and their lines side by side for each conflict,
+// with actions to accept either or both of them.
+func NewMergePanel(parent ...tree.Node) *MergePanel { return tree.New[MergePanel](parent...) }
+
+// SetCode sets the [MergePanel.Code]:
+// parent code project
+func (t *MergePanel) SetCode(v *Code) *MergePanel { t.Code = v; return t }
+
 var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.PreviewPanel", IDName: "preview-panel", Doc: "PreviewPanel is a widget that displays an interactive live preview of a\nMD, HTML, or SVG file currently open.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "code", Doc: "code is the parent [Code]."}, {Name: "lastRendered", Doc: "lastRendered is the content that was last rendered in the preview."}}})
 
 // NewPreviewPanel returns a new [PreviewPanel] with the given optional parent:
@@ -103,11 +222,63 @@ var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.PreviewPanel
 // MD, HTML, or SVG file currently open.
 func NewPreviewPanel(parent ...tree.Node) *PreviewPanel { return tree.New[PreviewPanel](parent...) }
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.SettingsData", IDName: "settings-data", Doc: "SettingsData is the data type for the overall user settings for Code.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Methods: []types.Method{{Name: "Apply", Doc: "Apply settings updates things according with settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditLangOpts", Doc: "EditLangOpts opens the LangsView editor to customize options for each type of\nlanguage / data / file type.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditCmds", Doc: "EditCmds opens the CmdsView editor to customize commands you can run.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditSplits", Doc: "EditSplits opens the SplitsView editor to customize saved splitter settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditRegisters", Doc: "EditRegisters opens the RegistersView editor to customize saved registers", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Embeds: []types.Field{{Name: "SettingsBase"}}, Fields: []types.Field{{Name: "Files", Doc: "file picker settings"}, {Name: "SaveLangOpts", Doc: "if set, the current customized set of language options (see Edit Lang Opts) is saved / loaded along with other settings -- if not set, then you always are using the default compiled-in standard set (which will be updated)"}, {Name: "SaveCmds", Doc: "if set, the current customized set of command parameters (see Edit Cmds) is saved / loaded along with other settings -- if not set, then you always are using the default compiled-in standard set (which will be updated)"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.ProblemsPanel", IDName: "problems-panel", Doc: "ProblemsPanel is a widget that displays the problems reported\nby commands, grouped by file, with links to their locations.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}}})
+
+// NewProblemsPanel returns a new [ProblemsPanel] with the given optional parent:
+// ProblemsPanel is a widget that displays the problems reported
+// by commands, grouped by file, with links to their locations.
+func NewProblemsPanel(parent ...tree.Node) *ProblemsPanel { return tree.New[ProblemsPanel](parent...) }
+
+// SetCode sets the [ProblemsPanel.Code]:
+// parent code project
+func (t *ProblemsPanel) SetCode(v *Code) *ProblemsPanel { t.Code = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.ProfilePanel", IDName: "profile-panel", Doc: "ProfilePanel is a widget that shows the [Code.Profile]: the top functions\nby cost in a table, the stacks in a flame graph, and the call tree,\nwhere clicking on a function shows its source.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "funcs", Doc: "functions shown in the table"}}})
+
+// NewProfilePanel returns a new [ProfilePanel] with the given optional parent:
+// ProfilePanel is a widget that shows the [Code.Profile]: the top functions
+// by cost in a table, the stacks in a flame graph, and the call tree,
+// where clicking on a function shows its source.
+func NewProfilePanel(parent ...tree.Node) *ProfilePanel { return tree.New[ProfilePanel](parent...) }
+
+// SetCode sets the [ProfilePanel.Code]:
+// parent code project
+func (t *ProfilePanel) SetCode(v *Code) *ProfilePanel { t.Code = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.ProfileTree", IDName: "profile-tree", Doc: "ProfileTree is a node in the call tree of a [ProfilePanel],\nwhose children are added when it is first opened.", Embeds: []types.Field{{Name: "Tree"}}, Fields: []types.Field{{Name: "Node", Doc: "node of the call tree"}, {Name: "panel", Doc: "panel that the tree is in"}, {Name: "expanded", Doc: "whether the children have been added"}}})
+
+// NewProfileTree returns a new [ProfileTree] with the given optional parent:
+// ProfileTree is a node in the call tree of a [ProfilePanel],
+// whose children are added when it is first opened.
+func NewProfileTree(parent ...tree.Node) *ProfileTree { return tree.New[ProfileTree](parent...) }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.FlameGraph", IDName: "flame-graph", Doc: "FlameGraph is a widget that shows the call tree of a profile as a flame\ngraph, with the root at the top, and each function below its caller, with\na width proportional to its cumulative cost. Clicking on a function shows\nits source, and double-clicking zooms in on it, or out from the top one.", Embeds: []types.Field{{Name: "WidgetBase"}}, Fields: []types.Field{{Name: "Root", Doc: "Root is the node at the top of the graph, which is\nthe root of the call tree unless zoomed in."}, {Name: "panel", Doc: "panel that the graph is in"}, {Name: "rects", Doc: "rectangles of the nodes as of the last render, for finding them"}}})
+
+// NewFlameGraph returns a new [FlameGraph] with the given optional parent:
+// FlameGraph is a widget that shows the call tree of a profile as a flame
+// graph, with the root at the top, and each function below its caller, with
+// a width proportional to its cumulative cost. Clicking on a function shows
+// its source, and double-clicking zooms in on it, or out from the top one.
+func NewFlameGraph(parent ...tree.Node) *FlameGraph { return tree.New[FlameGraph](parent...) }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.ReplacePanel", IDName: "replace-panel", Doc: "ReplacePanel is a widget for previewing a replace all, showing\nall of the pending replacements grouped by file, which can each be\naccepted and edited, along with a unified diff of the accepted\nreplacements. Only the accepted replacements are applied.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Find", Doc: "find panel with the results that the replacements are for"}, {Name: "Files", Doc: "pending replacements, grouped by file"}}})
+
+// NewReplacePanel returns a new [ReplacePanel] with the given optional parent:
+// ReplacePanel is a widget for previewing a replace all, showing
+// all of the pending replacements grouped by file, which can each be
+// accepted and edited, along with a unified diff of the accepted
+// replacements. Only the accepted replacements are applied.
+func NewReplacePanel(parent ...tree.Node) *ReplacePanel { return tree.New[ReplacePanel](parent...) }
+
+// SetCode sets the [ReplacePanel.Code]:
+// parent code project
+func (t *ReplacePanel) SetCode(v *Code) *ReplacePanel { t.Code = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.SettingsData", IDName: "settings-data", Doc: "SettingsData is the data type for the overall user settings for Code.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Methods: []types.Method{{Name: "Apply", Doc: "Apply settings updates things according with settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditLangOpts", Doc: "EditLangOpts opens the LangsView editor to customize options for each type of\nlanguage / data / file type.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditCmds", Doc: "EditCmds opens the CmdsView editor to customize commands you can run.", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditSplits", Doc: "EditSplits opens the SplitsView editor to customize saved splitter settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditRegisters", Doc: "EditRegisters opens the RegistersView editor to customize saved registers", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}, {Name: "EditMacros", Doc: "EditMacros opens the MacrosView editor to customize saved keyboard macros", Directives: []types.Directive{{Tool: "types", Directive: "add"}}}}, Embeds: []types.Field{{Name: "SettingsBase"}}, Fields: []types.Field{{Name: "Files", Doc: "file picker settings"}, {Name: "SaveLangOpts", Doc: "if set, the current customized set of language options (see Edit Lang Opts) is saved / loaded along with other settings -- if not set, then you always are using the default compiled-in standard set (which will be updated)"}, {Name: "SaveCmds", Doc: "if set, the current customized set of command parameters (see Edit Cmds) is saved / loaded along with other settings -- if not set, then you always are using the default compiled-in standard set (which will be updated)"}, {Name: "VimMode", Doc: "if set, text editors use Vim modal editing, starting in normal mode, with the current mode shown in the status bar"}, {Name: "StickyHeaders", Doc: "if set, text editors show the declaration lines of the function and type enclosing the top of the view above it while scrolling"}}})
 
 var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.FileSettings", IDName: "file-settings", Doc: "FileSettings contains file picker settings", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Fields: []types.Field{{Name: "DirsOnTop", Doc: "if true, then all directories are placed at the top of the tree -- otherwise everything is alpha sorted"}}})
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.ProjectSettings", IDName: "project-settings", Doc: "ProjectSettings are the settings for saving for a project. This IS the project file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Methods: []types.Method{{Name: "Open", Doc: "Open open from file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}, {Name: "Save", Doc: "Save save to file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}}, Fields: []types.Field{{Name: "Files", Doc: "file picker settings"}, {Name: "Editor", Doc: "editor settings"}, {Name: "SplitName", Doc: "current named-split config in use for configuring the splitters"}, {Name: "MainLang", Doc: "the language associated with the most frequently encountered file\nextension in the file tree -- can be manually set here as well"}, {Name: "VersionControl", Doc: "the type of version control system used in this project (git, svn, etc).\nfilters commands available"}, {Name: "ProjectFilename", Doc: "current project filename for saving / loading specific Code\nconfiguration information in a .code file (optional)"}, {Name: "ProjectRoot", Doc: "root directory for the project. all projects must be organized within\na top-level root directory, with all the files therein constituting\nthe scope of the project. By default it is the path for ProjectFilename"}, {Name: "GoMod", Doc: "if true, use Go modules, otherwise use GOPATH -- this sets your effective GO111MODULE environment variable accordingly, dynamically -- updated by toolbar checkbox, dynamically"}, {Name: "BuildCmds", Doc: "command(s) to run for main Build button"}, {Name: "BuildDir", Doc: "build directory for main Build button -- set this to the directory where you want to build the main target for this project -- avail as {BuildDir} in commands"}, {Name: "BuildTarg", Doc: "build target for main Build button, if relevant for your  BuildCmds"}, {Name: "RunExec", Doc: "executable to run for this project via main Run button -- called by standard Run Project command"}, {Name: "RunCmds", Doc: "command(s) to run for main Run button (typically Run Project)"}, {Name: "Debug", Doc: "custom debugger parameters for this project"}, {Name: "Find", Doc: "saved find params"}, {Name: "Symbols", Doc: "saved structure params"}, {Name: "Dirs", Doc: "directory properties"}, {Name: "Register", Doc: "last register used"}, {Name: "Splits", Doc: "current splitter splits"}, {Name: "TabsUnder", Doc: "current tabUnder setting for splits"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.ProjectSettings", IDName: "project-settings", Doc: "ProjectSettings are the settings for saving for a project. This IS the project file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Methods: []types.Method{{Name: "Open", Doc: "Open open from file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}, {Name: "Save", Doc: "Save save to file", Directives: []types.Directive{{Tool: "types", Directive: "add"}}, Args: []string{"filename"}, Returns: []string{"error"}}}, Fields: []types.Field{{Name: "Files", Doc: "file picker settings"}, {Name: "Editor", Doc: "editor settings"}, {Name: "SplitName", Doc: "current named-split config in use for configuring the splitters"}, {Name: "MainLang", Doc: "the language associated with the most frequently encountered file\nextension in the file tree -- can be manually set here as well"}, {Name: "VersionControl", Doc: "the type of version control system used in this project (git, svn, etc).\nfilters commands available"}, {Name: "ProjectFilename", Doc: "current project filename for saving / loading specific Code\nconfiguration information in a .code file (optional)"}, {Name: "ProjectRoot", Doc: "root directory for the project. all projects must be organized within\na top-level root directory, with all the files therein constituting\nthe scope of the project. By default it is the path for ProjectFilename"}, {Name: "Roots", Doc: "additional root directories for a multi-root project, each shown as\na top-level node in the file tree, with its own version control and\nbuild directory. ProjectRoot is always the first root."}, {Name: "GoMod", Doc: "if true, use Go modules, otherwise use GOPATH -- this sets your effective GO111MODULE environment variable accordingly, dynamically -- updated by toolbar checkbox, dynamically"}, {Name: "BuildCmds", Doc: "command(s) to run for main Build button"}, {Name: "BuildDir", Doc: "build directory for main Build button -- set this to the directory where you want to build the main target for this project -- avail as {BuildDir} in commands"}, {Name: "BuildTarg", Doc: "build target for main Build button, if relevant for your  BuildCmds"}, {Name: "RunExec", Doc: "executable to run for this project via main Run button -- called by standard Run Project command"}, {Name: "RunCmds", Doc: "command(s) to run for main Run button (typically Run Project)"}, {Name: "Debug", Doc: "custom debugger parameters for this project"}, {Name: "Launches", Doc: "named launch configurations for the debugger, which can be chosen\non the Debug button"}, {Name: "DebugLaunch", Doc: "name of the launch configuration used by the Debug button;\nif empty, the RunExec is debugged with the Debug parameters"}, {Name: "Find", Doc: "saved find params"}, {Name: "Symbols", Doc: "saved structure params"}, {Name: "Dirs", Doc: "directory properties"}, {Name: "Register", Doc: "last register used"}, {Name: "Splits", Doc: "current splitter splits"}, {Name: "TabsUnder", Doc: "current tabUnder setting for splits"}}})
 
 var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.SpellPanel", IDName: "spell-panel", Doc: "SpellPanel is a widget that displays results of a spell check.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Text", Doc: "texteditor that we're spell-checking"}, {Name: "Errs", Doc: "current spelling errors"}, {Name: "CurLn", Doc: "current line in text we're on"}, {Name: "CurIndex", Doc: "current index in Errs we're on"}, {Name: "UnkLex", Doc: "current unknown lex token"}, {Name: "UnkWord", Doc: "current unknown word"}, {Name: "Suggest", Doc: "a list of suggestions from spell checker"}, {Name: "LastAction", Doc: "last user action (ignore, change, learn)"}}})
 
@@ -190,7 +361,24 @@ var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.SymTree", ID
 // SymTree is a Tree that knows how to operate on FileNode nodes
 func NewSymTree(parent ...tree.Node) *SymTree { return tree.New[SymTree](parent...) }
 
-var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.TextEditor", IDName: "text-editor", Doc: "TextEditor is the Code-specific version of the TextEditor, with support for\nsetting / clearing breakpoints, etc", Embeds: []types.Field{{Name: "Editor"}}, Fields: []types.Field{{Name: "Code"}}})
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.TestPanel", IDName: "test-panel", Doc: "TestPanel is a widget that shows the Go tests in the project in a tree,\nwith their status, and runs them using go test -json.", Embeds: []types.Field{{Name: "Frame"}}, Fields: []types.Field{{Name: "Code", Doc: "parent code project"}, {Name: "Tests", Doc: "all the tests in the project, by package"}, {Name: "Selected", Doc: "currently selected test"}, {Name: "runMu", Doc: "mutex protecting the running state below"}, {Name: "cmd", Doc: "command currently running the tests"}, {Name: "stopped", Doc: "set when stopping the tests"}}})
+
+// NewTestPanel returns a new [TestPanel] with the given optional parent:
+// TestPanel is a widget that shows the Go tests in the project in a tree,
+// with their status, and runs them using go test -json.
+func NewTestPanel(parent ...tree.Node) *TestPanel { return tree.New[TestPanel](parent...) }
+
+// SetCode sets the [TestPanel.Code]:
+// parent code project
+func (t *TestPanel) SetCode(v *Code) *TestPanel { t.Code = v; return t }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.TestTree", IDName: "test-tree", Doc: "TestTree is a Tree that shows [TestNode]s with an icon for their status.", Embeds: []types.Field{{Name: "Tree"}}})
+
+// NewTestTree returns a new [TestTree] with the given optional parent:
+// TestTree is a Tree that shows [TestNode]s with an icon for their status.
+func NewTestTree(parent ...tree.Node) *TestTree { return tree.New[TestTree](parent...) }
+
+var _ = types.AddType(&types.Type{Name: "cogentcore.org/cogent/code.TextEditor", IDName: "text-editor", Doc: "TextEditor is the Code-specific version of the TextEditor, with support for\nsetting / clearing breakpoints, etc", Embeds: []types.Field{{Name: "Editor"}}, Fields: []types.Field{{Name: "Code"}, {Name: "showBlame", Doc: "showBlame is whether to show the blame gutter"}, {Name: "vcs", Doc: "vcs is the version control state of the lines"}, {Name: "snippet", Doc: "snippet has the tab stops of the snippet being filled in, if any"}, {Name: "snippetComplete", Doc: "snippetComplete is the completer that completeSnippet is connected to"}, {Name: "vim", Doc: "vim is the state of Vim modal editing, if it is on"}, {Name: "hover", Doc: "hover is the language server hover documentation for the\nlast position it was requested for"}, {Name: "symbols", Doc: "symbols caches the symbol paths of the file for the breadcrumbs"}, {Name: "contextPending", Doc: "contextPending is whether an update of the breadcrumbs\nis pending, from [TextEditor.needsContextUpdate]"}}})
 
 // NewTextEditor returns a new [TextEditor] with the given optional parent:
 // TextEditor is the Code-specific version of the TextEditor, with support for
@@ -0,0 +1,244 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/base/vcs"
+	"cogentcore.org/core/text/difflib"
+	"cogentcore.org/core/text/lines"
+)
+
+// BlameLine is the version control blame information for one line:
+// the revision, author and date of the last change to it.
+// Rev is empty for lines that have not been committed.
+type BlameLine struct {
+	Rev    string
+	Author string
+	Date   string
+}
+
+// ParseBlame parses the output of [vcs.Repo.Blame] for given type
+// of repository, returning the blame for each line. Git and svn
+// output formats are supported.
+func ParseBlame(typ vcs.Types, out []byte) []BlameLine {
+	var bl []BlameLine
+	for _, ln := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		if typ == vcs.Git {
+			bl = append(bl, parseGitBlame(ln))
+			continue
+		}
+		b := BlameLine{}
+		fs := strings.Fields(ln)
+		if len(fs) > 0 && fs[0] != "-" {
+			b.Rev = fs[0]
+		}
+		if len(fs) > 1 && fs[1] != "-" {
+			b.Author = fs[1]
+		}
+		bl = append(bl, b)
+	}
+	return bl
+}
+
+// parseGitBlame parses one line of default git blame output:
+// rev [file] (author date time zone line) text
+func parseGitBlame(ln string) BlameLine {
+	b := BlameLine{}
+	fs := strings.Fields(ln)
+	if len(fs) == 0 {
+		return b
+	}
+	b.Rev = strings.TrimPrefix(fs[0], "^")
+	if strings.Trim(b.Rev, "0") == "" {
+		b.Rev = ""
+	}
+	st := strings.Index(ln, " (")
+	if st < 0 {
+		return b
+	}
+	ed := strings.Index(ln[st:], ")")
+	if ed < 0 {
+		return b
+	}
+	fs = strings.Fields(ln[st+2 : st+ed])
+	n := len(fs)
+	if n < 5 {
+		return b
+	}
+	b.Author = strings.Join(fs[:n-4], " ")
+	b.Date = fs[n-4]
+	return b
+}
+
+// String returns a fixed-width summary of the blame, for the blame gutter.
+func (b *BlameLine) String() string {
+	if b.Rev == "" {
+		return fmt.Sprintf("%-*s", blameChars, "uncommitted")
+	}
+	rev := b.Rev
+	if len(rev) > 8 {
+		rev = rev[:8]
+	}
+	author := []rune(b.Author)
+	if len(author) > 10 {
+		author = author[:10]
+	}
+	return fmt.Sprintf("%-8s %-10s %s", rev, string(author), b.Date)
+}
+
+// blameChars is the width of the blame gutter in characters.
+const blameChars = 30
+
+// splitLines splits given text into lines, without a final empty line
+// for text ending in a newline.
+func splitLines(b []byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// ChangeHunks returns the hunks of lines that differ between the head
+// version of a file and its current version, as the non-equal [lines.Diffs]
+// from head to current.
+func ChangeHunks(head, cur []string) lines.Diffs {
+	var hunks lines.Diffs
+	for _, df := range lines.DiffLines(head, cur) {
+		if df.Tag != 'e' {
+			hunks = append(hunks, df)
+		}
+	}
+	return hunks
+}
+
+// HunkAt returns the hunk that applies to given current line (0-based),
+// including a deletion just before it, or just after it for a deletion
+// at the end of the given number of current lines, and false if there
+// is none.
+func HunkAt(hunks lines.Diffs, ln, n int) (difflib.OpCode, bool) {
+	for _, h := range hunks {
+		if (ln >= h.J1 && ln < h.J2) || (h.Tag == 'd' && (ln == h.J1 || (h.J1 == n && ln == n-1))) {
+			return h, true
+		}
+	}
+	return difflib.OpCode{}, false
+}
+
+// BlameForLines maps the blame for lines of the saved file, given
+// by saved, onto the current lines, which can differ from those.
+// Lines that differ have no blame, as they are not committed.
+func BlameForLines(blame []BlameLine, saved, cur []string) []BlameLine {
+	bl := make([]BlameLine, len(cur))
+	for _, df := range lines.DiffLines(saved, cur) {
+		if df.Tag != 'e' {
+			continue
+		}
+		for i := range df.I2 - df.I1 {
+			if df.I1+i < len(blame) {
+				bl[df.J1+i] = blame[df.I1+i]
+			}
+		}
+	}
+	return bl
+}
+
+// hunkContext is the number of lines of context around a hunk in a patch.
+const hunkContext = 3
+
+// HunkPatch returns a patch that applies the given hunk of changes from
+// old to cur to the old version of the given file, relative to the
+// repository root, with lines of context from old, for staging it.
+// The hunk is one of the [ChangeHunks] of the lines of old and cur.
+func HunkPatch(rel string, old, cur []byte, h difflib.OpCode) []byte {
+	ol, cl := splitLines(old), splitLines(cur)
+	oldNL := len(old) == 0 || old[len(old)-1] == '\n'
+	curNL := len(cur) == 0 || cur[len(cur)-1] == '\n'
+	if h.I2 == len(ol) && h.I1 > 0 && ((h.I1 == h.I2 && !oldNL) || (h.J1 == h.J2 && !curNL)) {
+		// the last line of a side without a final newline is
+		// followed by lines on the other side, so it is changed
+		h.I1--
+		h.J1--
+	}
+	a0, a1 := max(h.I1-hunkContext, 0), min(h.I2+hunkContext, len(ol))
+	on, nn := a1-a0, (h.I1-a0)+(h.J2-h.J1)+(a1-h.I2)
+	ost, nst := a0+1, a0+1
+	if on == 0 {
+		ost = a0
+	}
+	if nn == 0 {
+		nst = a0
+	}
+	var b bytes.Buffer
+	rel = filepath.ToSlash(rel)
+	fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", rel, rel, rel, rel)
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", ost, on, nst, nn)
+	line := func(prefix string, l string, last, nl bool) {
+		b.WriteString(prefix + l + "\n")
+		if last && !nl {
+			b.WriteString("\\ No newline at end of file\n")
+		}
+	}
+	for _, l := range ol[a0:h.I1] {
+		line(" ", l, false, true)
+	}
+	for i := h.I1; i < h.I2; i++ {
+		line("-", ol[i], i == len(ol)-1, oldNL)
+	}
+	for j := h.J1; j < h.J2; j++ {
+		line("+", cl[j], j == len(cl)-1, curNL)
+	}
+	for i := h.I2; i < a1; i++ {
+		line(" ", ol[i], i == len(ol)-1, oldNL)
+	}
+	return b.Bytes()
+}
+
+// StageHunk stages the hunk of changes to the given file at the given
+// line (0-based) of its current contents in the repository index, if
+// the repository supports it. The hunk is relative to the version of
+// the file in the index, which differs from HEAD for any changes that
+// have already been staged.
+func StageHunk(repo vcs.Repo, fname string, cur []byte, ln int) error {
+	gr, ok := repo.(*vcs.GitRepo)
+	if !ok {
+		return fmt.Errorf("staging hunks is not supported for %s repositories", repo.Type())
+	}
+	rel, err := filepath.Rel(repo.LocalPath(), fname)
+	if err != nil {
+		return err
+	}
+	rel = filepath.ToSlash(rel)
+	idx, err := gr.RunFromDir("git", "show", ":"+rel)
+	if err != nil {
+		return errors.New(strings.TrimSpace(string(idx)))
+	}
+	cl := splitLines(cur)
+	h, ok := HunkAt(ChangeHunks(splitLines(idx), cl), ln, len(cl))
+	if !ok {
+		return fmt.Errorf("no unstaged changes at line %d", ln+1)
+	}
+	pf, err := os.CreateTemp("", "code-hunk-*.patch")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(pf.Name())
+	_, err = pf.Write(HunkPatch(rel, idx, cur, h))
+	pf.Close()
+	if err != nil {
+		return err
+	}
+	out, err := gr.RunFromDir("git", "apply", "--cached", pf.Name())
+	if err != nil {
+		return errors.New(strings.TrimSpace(string(out)))
+	}
+	return nil
+}
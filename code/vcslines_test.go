@@ -0,0 +1,126 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/base/vcs"
+	"cogentcore.org/core/text/difflib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBlame(t *testing.T) {
+	git := `^2d3f6d5 (Jane Q. Doe 2023-05-01 12:34:56 -0700 1) package code
+8a1b2c3d4 old/name.go (Bob 2024-01-02 08:00:00 +0000 2)
+00000000 (Not Committed Yet 2025-03-04 10:00:00 +0100 3) // new (line)
+`
+	bl := ParseBlame(vcs.Git, []byte(git))
+	if assert.Len(t, bl, 3) {
+		assert.Equal(t, BlameLine{Rev: "2d3f6d5", Author: "Jane Q. Doe", Date: "2023-05-01"}, bl[0])
+		assert.Equal(t, BlameLine{Rev: "8a1b2c3d4", Author: "Bob", Date: "2024-01-02"}, bl[1])
+		assert.Equal(t, "", bl[2].Rev)
+		assert.Equal(t, "8a1b2c3d Bob        2024-01-02", bl[1].String())
+	}
+
+	svn := "    12     alice package code\n     -          - new\n"
+	bl = ParseBlame(vcs.Svn, []byte(svn))
+	assert.Equal(t, []BlameLine{{Rev: "12", Author: "alice"}, {}}, bl)
+}
+
+func TestChangeHunks(t *testing.T) {
+	head := []string{"a", "b", "c", "d", "e"}
+	cur := []string{"a", "B", "c", "e", "f"}
+	hunks := ChangeHunks(head, cur)
+	if !assert.Len(t, hunks, 3) {
+		return
+	}
+	h, ok := HunkAt(hunks, 1, len(cur))
+	assert.True(t, ok)
+	assert.Equal(t, byte('r'), h.Tag)
+	h, ok = HunkAt(hunks, 3, len(cur))
+	assert.True(t, ok)
+	assert.Equal(t, byte('d'), h.Tag)
+	assert.Equal(t, 3, h.I1)
+	h, ok = HunkAt(hunks, 4, len(cur))
+	assert.True(t, ok)
+	assert.Equal(t, byte('i'), h.Tag)
+	_, ok = HunkAt(hunks, 0, len(cur))
+	assert.False(t, ok)
+
+	trail := []string{"a", "b"}
+	th := ChangeHunks(head, trail)
+	h, ok = HunkAt(th, 1, len(trail))
+	assert.True(t, ok)
+	assert.Equal(t, byte('d'), h.Tag)
+	assert.Equal(t, 2, h.I1)
+	_, ok = HunkAt(th, 0, len(trail))
+	assert.False(t, ok)
+
+	hb, cb := []byte("a\nb\nc\nd\ne\n"), []byte("a\nB\nc\ne\nf\n")
+	assert.Equal(t, "diff --git a/x/f.go b/x/f.go\n--- a/x/f.go\n+++ b/x/f.go\n@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n",
+		string(HunkPatch("x/f.go", hb, cb, hunks[0])))
+	assert.Contains(t, string(HunkPatch("f.go", hb, cb, hunks[1])), "@@ -1,5 +1,4 @@\n a\n b\n c\n-d\n e\n")
+	assert.Contains(t, string(HunkPatch("f.go", hb, cb, hunks[2])), "@@ -3,3 +3,4 @@\n c\n d\n e\n+f\n")
+	assert.Contains(t, string(HunkPatch("f.go", hb[:len(hb)-1], cb, hunks[2])), "@@ -2,4 +2,5 @@\n b\n c\n d\n-e\n\\ No newline at end of file\n+e\n+f\n")
+	assert.Contains(t, string(HunkPatch("f.go", nil, []byte("a"), difflib.OpCode{Tag: 'i', J2: 1})), "@@ -0,0 +1,1 @@\n+a\n\\ No newline at end of file\n")
+
+	blame := []BlameLine{{Rev: "1"}, {Rev: "2"}, {Rev: "3"}}
+	bl := BlameForLines(blame, []string{"a", "b", "c"}, []string{"a", "x", "b", "c"})
+	assert.Equal(t, []BlameLine{{Rev: "1"}, {}, {Rev: "2"}, {Rev: "3"}}, bl)
+}
+
+func TestStageHunk(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+		return string(out)
+	}
+	git("init", "-q")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	git("remote", "add", "origin", "https://example.com/test.git") // needed to open the repo
+	var head []string
+	for i := range 20 {
+		head = append(head, strings.Repeat("x", i+1))
+	}
+	fname := filepath.Join(dir, "f.txt")
+	assert.NoError(t, os.WriteFile(fname, []byte(strings.Join(head, "\n")), 0666)) // no final newline
+	git("add", "f.txt")
+	git("commit", "-q", "-m", "initial")
+	repo, err := vcs.NewRepo("", dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cl := append([]string{}, head[:2]...)
+	cl = append(cl, "new 1", "new 2")
+	cl = append(cl, head[2:]...)
+	cl[11] = "ten"
+	cl[len(cl)-1] = "last"
+	cur := []byte(strings.Join(cl, "\n"))
+	assert.NoError(t, os.WriteFile(fname, cur, 0666))
+
+	assert.NoError(t, StageHunk(repo, fname, cur, 2))  // insertion, changes the line count
+	assert.NoError(t, StageHunk(repo, fname, cur, 11)) // after it
+	assert.Contains(t, git("diff", "--cached"), "@@ -1,5 +1,7 @@\n x\n xx\n+new 1\n+new 2\n xxx\n")
+	assert.Contains(t, git("diff", "--cached"), "-xxxxxxxxxx\n+ten\n")
+	assert.Contains(t, git("diff"), "-xxxxxxxxxxxxxxxxxxxx\n\\ No newline at end of file\n+last\n\\ No newline at end of file\n")
+	assert.ErrorContains(t, StageHunk(repo, fname, cur, 2), "no unstaged changes")
+
+	assert.NoError(t, StageHunk(repo, fname, cur, len(cl)-1)) // last line, without a newline
+	assert.Equal(t, string(cur), git("show", ":f.txt"))
+	assert.Empty(t, git("diff"))
+}
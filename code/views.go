@@ -66,8 +66,169 @@ func (cv *Code) Symbols() { //types:add
 	cv.FocusOnPanel(TabsIndex)
 }
 
-// Debug starts the debugger on the RunExec executable.
+// CallHierarchy shows the functions that call the Go function at the
+// cursor in the active editor, in the Hierarchy panel, where the functions
+// that it calls can also be shown.
+func (cv *Code) CallHierarchy() { //types:add
+	cv.showHierarchy(HierarchyIncomingCalls)
+}
+
+// TypeHierarchy shows the types that implement the Go interface at the
+// cursor in the active editor, or the interfaces that the type at the
+// cursor implements, in the Hierarchy panel.
+func (cv *Code) TypeHierarchy() { //types:add
+	cv.showHierarchy(HierarchyImplementers)
+}
+
+// showHierarchy shows the hierarchy of given kind for the function
+// or type at the cursor in the Hierarchy panel.
+func (cv *Code) showHierarchy(kind HierarchyKinds) {
+	if cv.remoteUnsupported("The hierarchy") {
+		return
+	}
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+
+	hp := core.RecycleTabWidget[HierarchyPanel](tv, "Hierarchy")
+	hp.Kind = kind
+	hp.Update()
+	hp.ShowAtCursor()
+	cv.FocusOnPanel(TabsIndex)
+}
+
+// ShowProblems displays the problems reported by build, vet, test and
+// other commands that have a ProblemRegexp.
+func (cv *Code) ShowProblems() { //types:add
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+
+	pv := core.RecycleTabWidget[ProblemsPanel](tv, "Problems")
+	pv.UpdateProblems()
+	cv.FocusOnPanel(TabsIndex)
+}
+
+// problemsPanel returns the Problems panel if it is open, else nil.
+func (cv *Code) problemsPanel() *ProblemsPanel {
+	tv := cv.Tabs()
+	if tv == nil {
+		return nil
+	}
+	fr := tv.TabByName("Problems")
+	if fr == nil || !fr.HasChildren() {
+		return nil
+	}
+	pv, _ := fr.Child(0).(*ProblemsPanel)
+	return pv
+}
+
+// ShowMerge displays the merge conflicts in the active file, left by
+// a version control pull, merge or rebase, to resolve them.
+func (cv *Code) ShowMerge() { //types:add
+	cv.showMerge(cv.ActiveEditor())
+}
+
+// showMerge displays the merge conflicts in the file in given editor.
+func (cv *Code) showMerge(txv *TextEditor) {
+	if txv == nil || txv.Lines == nil {
+		return
+	}
+	if len(LinesConflicts(txv.Lines)) == 0 {
+		core.MessageSnackbar(cv, "No merge conflicts in "+txv.Lines.Filename())
+		return
+	}
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+
+	mv := core.RecycleTabWidget[MergePanel](tv, "Merge")
+	mv.SetLines(txv.Lines)
+	cv.FocusOnPanel(TabsIndex)
+}
+
+// gitCommandDone updates the version control state in the editors
+// after a Git command run in given directory, and shows any merge
+// conflicts if it failed, e.g., from a pull or rebase.
+// It can be called from any goroutine.
+func (cv *Code) gitCommandDone(dir string, ok bool) {
+	if cv.Output != nil { // no GUI
+		return
+	}
+	go func() { // avoid deadlock when called from the event loop
+		cv.AsyncLock()
+		defer cv.AsyncUnlock()
+		cv.updateVCS()
+		if !ok {
+			cv.showConflicts(dir)
+		}
+	}()
+}
+
+// showConflicts displays the merge conflicts in the first file with
+// unresolved conflicts in the git repository containing given directory,
+// if there are any, after a pull, merge or rebase.
+func (cv *Code) showConflicts(dir string) {
+	if cv.IsRemote() || cv.Output != nil {
+		return
+	}
+	files := conflictedFiles(dir)
+	if len(files) == 0 {
+		return
+	}
+	txv, _, ok := cv.ViewFile(core.Filename(files[0]))
+	if !ok {
+		return
+	}
+	cv.showMerge(txv)
+	if len(files) > 1 {
+		core.MessageSnackbar(cv, fmt.Sprintf("%d files have merge conflicts, starting with %s", len(files), filepath.Base(files[0])))
+	}
+}
+
+// ShowTests displays the Go tests in the project, which can be
+// run and debugged from there.
+func (cv *Code) ShowTests() { //types:add
+	cv.showTests()
+}
+
+// showTests shows and returns the Tests panel, finding the tests
+// if it is new.
+func (cv *Code) showTests() *TestPanel {
+	if cv.remoteUnsupported("Testing") {
+		return nil
+	}
+	tv := cv.Tabs()
+	if tv == nil {
+		return nil
+	}
+
+	tp := core.RecycleTabWidget[TestPanel](tv, "Tests")
+	if tp.Tests == nil || !tp.Tests.HasChildren() {
+		tp.Refresh()
+	}
+	cv.FocusOnPanel(TabsIndex)
+	return tp
+}
+
+// RunTestAtCursor runs the Go test or subtest at the cursor
+// in the active editor, showing the results in the Tests panel.
+func (cv *Code) RunTestAtCursor() { //types:add
+	if tp := cv.showTests(); tp != nil {
+		tp.RunAtCursor()
+	}
+}
+
+// Debug starts the debugger on the RunExec executable, or with the
+// launch configuration chosen on the Debug button, if any.
 func (cv *Code) Debug() { //types:add
+	if cv.Settings.DebugLaunch != "" && cv.Settings.Launch(cv.Settings.DebugLaunch) != nil {
+		cv.DebugLaunch(cv.Settings.DebugLaunch)
+		return
+	}
 	tv := cv.Tabs()
 	if tv == nil {
 		return
@@ -77,7 +238,8 @@ func (cv *Code) Debug() { //types:add
 	exePath := string(cv.Settings.RunExec)
 	exe := filepath.Base(exePath)
 	dv := core.RecycleTabWidget[DebugPanel](tv, "Debug "+exe)
-	dv.Config(cv, fileinfo.Go, exePath)
+	dv.Config(cv, cv.debugLanguage(), exePath)
+	dv.Launch = nil
 	cv.FocusOnPanel(TabsIndex)
 	dv.Update()
 	dv.Start()
@@ -92,6 +254,12 @@ func (cv *Code) DebugTest(testName string) { //types:add
 	if txv == nil || txv.Lines == nil {
 		return
 	}
+	cv.DebugTestPath(txv.Lines.Filename(), testName)
+}
+
+// DebugTestPath runs the debugger using testing mode for the package
+// of the given test file (or directory), for the given test(s).
+func (cv *Code) DebugTestPath(tstPath, testName string) {
 	tv := cv.Tabs()
 	if tv == nil {
 		return
@@ -99,10 +267,10 @@ func (cv *Code) DebugTest(testName string) { //types:add
 
 	cv.Settings.Debug.Mode = cdebug.Test
 	cv.Settings.Debug.TestName = testName
-	tstPath := txv.Lines.Filename()
 	dir := filepath.Base(filepath.Dir(tstPath))
 	dv := core.RecycleTabWidget[DebugPanel](tv, "Debug "+dir)
-	dv.Config(cv, fileinfo.Go, tstPath)
+	dv.Config(cv, cv.debugLanguage(), tstPath)
+	dv.Launch = nil
 	cv.FocusOnPanel(TabsIndex)
 	dv.Update()
 	dv.Start()
@@ -122,13 +290,51 @@ func (cv *Code) DebugAttach(pid uint64) { //types:add
 	exePath := string(cv.Settings.RunExec)
 	exe := filepath.Base(exePath)
 	dv := core.RecycleTabWidget[DebugPanel](tv, "Debug "+exe)
-	dv.Config(cv, fileinfo.Go, exePath)
+	dv.Config(cv, cv.debugLanguage(), exePath)
+	dv.Launch = nil
+	cv.FocusOnPanel(TabsIndex)
+	dv.Update()
+	dv.Start()
+	cv.CurDbg = dv
+}
+
+// DebugCore runs the debugger on the given core dump of a crashed process
+// of the given executable, to inspect its stack, variables, tasks and threads
+// post-mortem. The program cannot be run, so the execution commands are disabled.
+// exe defaults to the RunExec of the project.
+func (cv *Code) DebugCore(coreFile, exe core.Filename) { //types:add
+	tv := cv.Tabs()
+	if tv == nil {
+		return
+	}
+	if exe == "" {
+		exe = cv.Settings.RunExec
+	}
+	cv.Settings.Debug.Mode = cdebug.Core
+	cv.Settings.Debug.CoreFile = string(coreFile)
+	exePath := string(exe)
+	dv := core.RecycleTabWidget[DebugPanel](tv, "Debug "+filepath.Base(string(coreFile)))
+	dv.Config(cv, cv.debugLanguage(), exePath)
+	dv.Launch = nil
 	cv.FocusOnPanel(TabsIndex)
 	dv.Update()
 	dv.Start()
 	cv.CurDbg = dv
 }
 
+// debugLanguage returns the language to debug, which is the MainLang
+// if there is a debugger for it, and Go otherwise.
+func (cv *Code) debugLanguage() fileinfo.Known {
+	lang := cv.Settings.MainLang
+	if lo, has := AvailableLanguages[lang]; has && lo.Debugger.IsValid() {
+		return lang
+	}
+	if _, has := cdebug.Debuggers[lang]; has {
+		return lang
+	}
+	return fileinfo.Go
+}
+
 // CurDebug returns the current debug view
 func (cv *Code) CurDebug() *DebugPanel {
 	return cv.CurDbg
@@ -140,10 +346,22 @@ func (cv *Code) ClearDebug() {
 }
 
 // VCSUpdateAll does an Update (e.g., Pull) on all VCS repositories within
-// the open tree nodes in FileTree.
+// the open tree nodes in the file trees of all project roots.
 func (cv *Code) VCSUpdateAll() { //types:add
-	cv.Files.UpdateAllVCS()
-	cv.Files.Update()
+	for _, ft := range cv.FileTrees() {
+		ft.UpdateAllVCS()
+		ft.Update()
+		cv.showConflicts(string(ft.Filepath))
+	}
+	cv.updateVCS()
+}
+
+// ToggleBlame toggles the blame gutter in the active editor, showing the
+// revision, author and date of the last change to each line.
+func (cv *Code) ToggleBlame() { //types:add
+	if ed := cv.ActiveEditor(); ed != nil && ed.Lines != nil {
+		ed.ToggleBlame()
+	}
 }
 
 // VCSLog shows the VCS log of commits in this project,
@@ -215,6 +433,9 @@ func (cv *Code) ChooseRunExec(exePath core.Filename) { //types:add
 // SetStatus sets the current status update message for the StatusBar next time it renders
 func (cv *Code) SetStatus(msg string) {
 	cv.StatusMessage = msg
+	if cv.Output != nil { // no GUI
+		return
+	}
 	cv.UpdateStatusText()
 	cv.UpdateTextButtons()
 }
@@ -235,7 +456,7 @@ func (cv *Code) UpdateStatusText() {
 		ln = tv.CursorPos.Line + 1
 		ch = tv.CursorPos.Char
 		if tv.Lines != nil {
-			fnm = cv.Files.RelativePathFrom(fsx.Filename(tv.Lines.Filename()))
+			fnm = cv.FileTreeFor(tv.Lines.Filename()).RelativePathFrom(fsx.Filename(tv.Lines.Filename()))
 			if tv.Lines.IsNotSaved() {
 				fnm += "*"
 			}
@@ -249,6 +470,9 @@ func (cv *Code) UpdateStatusText() {
 		if tv.QReplace.On {
 			msg = fmt.Sprintf("\tQReplace: %v -> %v (n=%v)", tv.QReplace.Find, tv.QReplace.Replace, len(tv.QReplace.Matches))
 		}
+		if vs := tv.vimStatus(); vs != "" {
+			msg = "\t" + vs + msg
+		}
 	}
 
 	str := fmt.Sprintf("%s\t%s\t<b>%s:</b>\t(%d,%d)\t%s", cv.Name, cv.ActiveVCSInfo, fnm, ln, ch, msg)
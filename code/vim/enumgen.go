@@ -0,0 +1,85 @@
+// Code generated by "core generate"; DO NOT EDIT.
+
+package vim
+
+import (
+	"cogentcore.org/core/enums"
+)
+
+var _KindsValues = []Kinds{0, 1, 2}
+
+// KindsN is the highest valid value for type Kinds, plus one.
+const KindsN Kinds = 3
+
+var _KindsValueMap = map[string]Kinds{`Exclusive`: 0, `Inclusive`: 1, `Linewise`: 2}
+
+var _KindsDescMap = map[Kinds]string{0: `Exclusive motions do not include the character at the end.`, 1: `Inclusive motions include the character at the end.`, 2: `Linewise motions include the whole lines.`}
+
+var _KindsMap = map[Kinds]string{0: `Exclusive`, 1: `Inclusive`, 2: `Linewise`}
+
+// String returns the string representation of this Kinds value.
+func (i Kinds) String() string { return enums.String(i, _KindsMap) }
+
+// SetString sets the Kinds value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Kinds) SetString(s string) error { return enums.SetString(i, s, _KindsValueMap, "Kinds") }
+
+// Int64 returns the Kinds value as an int64.
+func (i Kinds) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Kinds value from an int64.
+func (i *Kinds) SetInt64(in int64) { *i = Kinds(in) }
+
+// Desc returns the description of the Kinds value.
+func (i Kinds) Desc() string { return enums.Desc(i, _KindsDescMap) }
+
+// KindsValues returns all possible values for the type Kinds.
+func KindsValues() []Kinds { return _KindsValues }
+
+// Values returns all possible values for the type Kinds.
+func (i Kinds) Values() []enums.Enum { return enums.Values(_KindsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Kinds) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Kinds) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Kinds") }
+
+var _ModesValues = []Modes{0, 1, 2, 3, 4}
+
+// ModesN is the highest valid value for type Modes, plus one.
+const ModesN Modes = 5
+
+var _ModesValueMap = map[string]Modes{`Normal`: 0, `Insert`: 1, `Visual`: 2, `VisualLine`: 3, `Ex`: 4}
+
+var _ModesDescMap = map[Modes]string{0: `Normal is the mode where keys are commands.`, 1: `Insert is the mode where keys insert text.`, 2: `Visual is the mode where motions extend a selection of characters.`, 3: `VisualLine is the mode where motions extend a selection of whole lines.`, 4: `Ex is the mode where an ex command is typed after a colon.`}
+
+var _ModesMap = map[Modes]string{0: `Normal`, 1: `Insert`, 2: `Visual`, 3: `VisualLine`, 4: `Ex`}
+
+// String returns the string representation of this Modes value.
+func (i Modes) String() string { return enums.String(i, _ModesMap) }
+
+// SetString sets the Modes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *Modes) SetString(s string) error { return enums.SetString(i, s, _ModesValueMap, "Modes") }
+
+// Int64 returns the Modes value as an int64.
+func (i Modes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the Modes value from an int64.
+func (i *Modes) SetInt64(in int64) { *i = Modes(in) }
+
+// Desc returns the description of the Modes value.
+func (i Modes) Desc() string { return enums.Desc(i, _ModesDescMap) }
+
+// ModesValues returns all possible values for the type Modes.
+func ModesValues() []Modes { return _ModesValues }
+
+// Values returns all possible values for the type Modes.
+func (i Modes) Values() []enums.Enum { return enums.Values(_ModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i Modes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *Modes) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Modes") }
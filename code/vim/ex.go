@@ -0,0 +1,152 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExCommand is an ex command typed after a colon, e.g., :w.
+type ExCommand struct {
+
+	// Name is the name of the command: w, q, wq, x, or s,
+	// which is empty for a command that is only a line number.
+	Name string
+
+	// Bang is whether the name is followed by !, e.g., q!.
+	Bang bool
+
+	// Line is the line number, starting at 1, that a command
+	// that is only a line number goes to.
+	Line int
+
+	// All is whether the command has the % range for the whole file.
+	All bool
+
+	// Find and Replace are the regular expression and replacement of
+	// the s command, where the replacement uses $1 for the first group.
+	Find, Replace string
+
+	// Global is whether the s command has the g flag to replace
+	// all matches in each line.
+	Global bool
+
+	// IgnoreCase is whether the s command has the i flag to ignore case.
+	IgnoreCase bool
+}
+
+// exNames are the names of the ex commands.
+var exNames = []string{"w", "q", "wq", "x", "s"}
+
+// exGroup matches a reference to a group in a replacement, e.g., \1.
+var exGroup = regexp.MustCompile(`\\(\d)`)
+
+// ParseEx parses the given ex command, without the colon.
+func ParseEx(s string) (*ExCommand, error) {
+	s = strings.TrimSpace(s)
+	ex := &ExCommand{}
+	if ln, err := strconv.Atoi(s); err == nil {
+		ex.Line = ln
+		return ex, nil
+	}
+	if rest, ok := strings.CutPrefix(s, "%"); ok {
+		ex.All = true
+		s = rest
+	}
+	if rest, ok := strings.CutPrefix(s, "s"); ok && rest != "" {
+		if err := ex.parseSubstitute(rest); err != nil {
+			return nil, err
+		}
+		ex.Name = "s"
+		return ex, nil
+	}
+	s, ex.Bang = strings.CutSuffix(s, "!")
+	for _, nm := range exNames {
+		if s == nm && nm != "s" {
+			ex.Name = nm
+			return ex, nil
+		}
+	}
+	return nil, fmt.Errorf("vim: not an editor command: %s", s)
+}
+
+// parseSubstitute parses the arguments of the s command,
+// e.g., /find/replace/g, where the delimiter can be any
+// punctuation character, which is escaped with \.
+func (ex *ExCommand) parseSubstitute(s string) error {
+	delim, sz := utf8.DecodeRuneInString(s)
+	if !unicode.IsPunct(delim) && !unicode.IsSymbol(delim) {
+		return fmt.Errorf("vim: invalid substitute delimiter %q", delim)
+	}
+	var parts []string
+	var cur []rune
+	rs := []rune(s[sz:])
+	for i := 0; i < len(rs); i++ {
+		switch {
+		case rs[i] == '\\' && i+1 < len(rs) && rs[i+1] == delim:
+			i++
+			cur = append(cur, delim)
+		case rs[i] == delim && len(parts) < 2:
+			parts = append(parts, string(cur))
+			cur = nil
+		default:
+			cur = append(cur, rs[i])
+		}
+	}
+	parts = append(parts, string(cur))
+	if parts[0] == "" {
+		return fmt.Errorf("vim: substitute needs a pattern")
+	}
+	ex.Find = parts[0]
+	if len(parts) > 1 {
+		ex.Replace = exGroup.ReplaceAllString(parts[1], "$${$1}")
+	}
+	if len(parts) > 2 {
+		for _, f := range parts[2] {
+			switch f {
+			case 'g':
+				ex.Global = true
+			case 'i':
+				ex.IgnoreCase = true
+			default:
+				return fmt.Errorf("vim: invalid substitute flag %q", f)
+			}
+		}
+	}
+	return nil
+}
+
+// Regexp returns the regular expression of the s command.
+func (ex *ExCommand) Regexp() (*regexp.Regexp, error) {
+	find := ex.Find
+	if ex.IgnoreCase {
+		find = "(?i)" + find
+	}
+	return regexp.Compile(find)
+}
+
+// Substitute returns the given line with the matches of the given
+// [ExCommand.Regexp] replaced as in the s command, which only replaces
+// the first one unless it is [ExCommand.Global], and whether there
+// were any matches.
+func (ex *ExCommand) Substitute(re *regexp.Regexp, line string) (string, bool) {
+	if ex.Global {
+		if !re.MatchString(line) {
+			return line, false
+		}
+		return re.ReplaceAllString(line, ex.Replace), true
+	}
+	m := re.FindStringSubmatchIndex(line)
+	if m == nil {
+		return line, false
+	}
+	rep := re.ExpandString(nil, ex.Replace, line, m)
+	return line[:m[0]] + string(rep) + line[m[1]:], true
+}
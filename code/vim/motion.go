@@ -0,0 +1,316 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vim
+
+import (
+	"unicode"
+
+	"cogentcore.org/core/text/textpos"
+)
+
+// Kinds are the kinds of motions, which determine the text
+// that an operator applies to.
+type Kinds int32 //enums:enum
+
+const (
+	// Exclusive motions do not include the character at the end.
+	Exclusive Kinds = iota
+
+	// Inclusive motions include the character at the end.
+	Inclusive
+
+	// Linewise motions include the whole lines.
+	Linewise
+)
+
+// Move returns the position that the motion of the given command moves
+// to from the given position, along with the kind of the motion, and false
+// if it can not move, e.g., when the character to find is not found.
+// The position can be at the end of a line when there is an operator.
+func Move(buf Buffer, pos textpos.Pos, cmd Command) (textpos.Pos, Kinds, bool) {
+	nl := buf.NumLines()
+	if nl == 0 || pos.Line < 0 || pos.Line >= nl {
+		return pos, Exclusive, false
+	}
+	n := cmd.N()
+	line := buf.Line(pos.Line)
+	ll := len(line)
+	lastChar := func(ln int) int {
+		return max(len(buf.Line(ln))-1, 0)
+	}
+	switch cmd.Key {
+	case "h":
+		if pos.Char == 0 {
+			return pos, Exclusive, false
+		}
+		pos.Char = max(pos.Char-n, 0)
+		return pos, Exclusive, true
+	case "l":
+		end := ll - 1
+		if cmd.Operator != 0 {
+			end = ll
+		}
+		if pos.Char >= end {
+			return pos, Exclusive, false
+		}
+		pos.Char = min(pos.Char+n, end)
+		return pos, Exclusive, true
+	case "j", "k":
+		ln := pos.Line + n
+		if cmd.Key == "k" {
+			ln = pos.Line - n
+		}
+		ln = min(max(ln, 0), nl-1)
+		if ln == pos.Line {
+			return pos, Linewise, false
+		}
+		return textpos.Pos{Line: ln, Char: min(pos.Char, lastChar(ln))}, Linewise, true
+	case "0":
+		return textpos.Pos{Line: pos.Line}, Exclusive, true
+	case "^":
+		return textpos.Pos{Line: pos.Line, Char: FirstNonBlank(buf, pos.Line)}, Exclusive, true
+	case "$":
+		ln := min(pos.Line+n-1, nl-1)
+		return textpos.Pos{Line: ln, Char: lastChar(ln)}, Inclusive, true
+	case "gg", "G":
+		ln := nl - 1
+		if cmd.Key == "gg" {
+			ln = 0
+		}
+		if cmd.Count > 0 {
+			ln = min(cmd.Count-1, nl-1)
+		}
+		return textpos.Pos{Line: ln, Char: FirstNonBlank(buf, ln)}, Linewise, true
+	case "f", "t":
+		for i, cnt := pos.Char+1, 0; i < ll; i++ {
+			if line[i] != cmd.Char {
+				continue
+			}
+			if cnt++; cnt == n {
+				if cmd.Key == "t" {
+					i--
+				}
+				return textpos.Pos{Line: pos.Line, Char: i}, Inclusive, i > pos.Char
+			}
+		}
+		return pos, Inclusive, false
+	case "F", "T":
+		for i, cnt := min(pos.Char, ll)-1, 0; i >= 0; i-- {
+			if line[i] != cmd.Char {
+				continue
+			}
+			if cnt++; cnt == n {
+				if cmd.Key == "T" {
+					i++
+				}
+				return textpos.Pos{Line: pos.Line, Char: i}, Exclusive, i < pos.Char
+			}
+		}
+		return pos, Exclusive, false
+	case "w", "W":
+		big := cmd.Key == "W"
+		if cmd.Operator == 'c' && class(charAt(buf, pos), big) != 0 {
+			ecmd := cmd
+			ecmd.Key = "e"
+			if big {
+				ecmd.Key = "E"
+			}
+			return Move(buf, pos, ecmd)
+		}
+		p := pos
+		for range n {
+			p = wordForward(buf, p, big)
+		}
+		if cmd.Operator != 0 && p.Line > pos.Line && p.Char <= FirstNonBlank(buf, p.Line) {
+			p = textpos.Pos{Line: p.Line - 1, Char: len(buf.Line(p.Line - 1))}
+		} else if cmd.Operator == 0 {
+			p.Char = min(p.Char, lastChar(p.Line))
+		}
+		return p, Exclusive, p != pos
+	case "b", "B":
+		p := pos
+		for range n {
+			p = wordBack(buf, p, cmd.Key == "B")
+		}
+		return p, Exclusive, p != pos
+	case "e", "E":
+		p := pos
+		for range n {
+			p = wordEnd(buf, p, cmd.Key == "E")
+		}
+		p.Char = min(p.Char, lastChar(p.Line))
+		return p, Inclusive, p != pos
+	}
+	return pos, Exclusive, false
+}
+
+// Range returns the region of text between the given positions, in either
+// order, for a motion of the given kind. For a [Linewise] motion, it is the
+// [LinesRegion], which also includes the newline before the first line
+// if the last line is the last one in the buffer, so that the lines are
+// removed when it is deleted.
+func Range(buf Buffer, start, end textpos.Pos, kind Kinds) textpos.Region {
+	if end.IsLess(start) {
+		start, end = end, start
+	}
+	switch kind {
+	case Inclusive:
+		end.Char = min(end.Char+1, len(buf.Line(end.Line)))
+	case Linewise:
+		reg := LinesRegion(buf, start.Line, end.Line)
+		if reg.End.Line == end.Line && start.Line > 0 {
+			reg.Start = textpos.Pos{Line: start.Line - 1, Char: len(buf.Line(start.Line - 1))}
+		}
+		return reg
+	}
+	return textpos.Region{Start: start, End: end}
+}
+
+// LinesRegion returns the region of the given range of lines, inclusive,
+// including the newline after the last line if it is not the last one.
+func LinesRegion(buf Buffer, st, ed int) textpos.Region {
+	if st > ed {
+		st, ed = ed, st
+	}
+	reg := textpos.Region{Start: textpos.Pos{Line: st}, End: textpos.Pos{Line: ed + 1}}
+	if ed+1 >= buf.NumLines() {
+		reg.End = textpos.Pos{Line: ed, Char: len(buf.Line(ed))}
+	}
+	return reg
+}
+
+// LinesText returns the text of the given range of lines, inclusive,
+// ending in a newline, which is how text from a [Linewise] motion
+// is stored in a register.
+func LinesText(buf Buffer, st, ed int) string {
+	if st > ed {
+		st, ed = ed, st
+	}
+	var txt []rune
+	for ln := st; ln <= ed; ln++ {
+		txt = append(txt, buf.Line(ln)...)
+		txt = append(txt, '\n')
+	}
+	return string(txt)
+}
+
+// FirstNonBlank returns the position of the first character
+// in the given line that is not a space or tab.
+func FirstNonBlank(buf Buffer, ln int) int {
+	line := buf.Line(ln)
+	for i, r := range line {
+		if r != ' ' && r != '\t' {
+			return i
+		}
+	}
+	return max(len(line)-1, 0)
+}
+
+// class returns the class of the given rune for word motions: 0 for
+// space, 1 for word characters, and 2 for other characters, which are
+// all 1 for big words, which are separated only by space.
+func class(r rune, big bool) int {
+	switch {
+	case unicode.IsSpace(r):
+		return 0
+	case big || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return 1
+	}
+	return 2
+}
+
+// charAt returns the rune at the given position,
+// which is a newline at the end of the line.
+func charAt(buf Buffer, pos textpos.Pos) rune {
+	line := buf.Line(pos.Line)
+	if pos.Char >= len(line) {
+		return '\n'
+	}
+	return line[pos.Char]
+}
+
+// isEmptyLine returns whether the given position is on an empty line,
+// which is a word for word motions.
+func isEmptyLine(buf Buffer, pos textpos.Pos) bool {
+	return len(buf.Line(pos.Line)) == 0
+}
+
+// nextPos returns the position after the given one, including
+// the end of each line, and false if it is at the end of the buffer.
+func nextPos(buf Buffer, pos textpos.Pos) (textpos.Pos, bool) {
+	if pos.Char < len(buf.Line(pos.Line)) {
+		pos.Char++
+		return pos, true
+	}
+	if pos.Line+1 < buf.NumLines() {
+		return textpos.Pos{Line: pos.Line + 1}, true
+	}
+	return pos, false
+}
+
+// prevPos returns the position before the given one, including
+// the end of each line, and false if it is at the start of the buffer.
+func prevPos(buf Buffer, pos textpos.Pos) (textpos.Pos, bool) {
+	if pos.Char > 0 {
+		pos.Char--
+		return pos, true
+	}
+	if pos.Line > 0 {
+		return textpos.Pos{Line: pos.Line - 1, Char: len(buf.Line(pos.Line - 1))}, true
+	}
+	return pos, false
+}
+
+// wordForward returns the position of the start of the next word.
+func wordForward(buf Buffer, pos textpos.Pos, big bool) textpos.Pos {
+	p, ok := pos, true
+	if c := class(charAt(buf, p), big); c != 0 {
+		for ok && class(charAt(buf, p), big) == c {
+			p, ok = nextPos(buf, p)
+		}
+	}
+	for ok && class(charAt(buf, p), big) == 0 {
+		if p != pos && isEmptyLine(buf, p) {
+			break
+		}
+		p, ok = nextPos(buf, p)
+	}
+	return p
+}
+
+// wordEnd returns the position of the end of the current or next word.
+func wordEnd(buf Buffer, pos textpos.Pos, big bool) textpos.Pos {
+	p, ok := nextPos(buf, pos)
+	for ok && class(charAt(buf, p), big) == 0 {
+		p, ok = nextPos(buf, p)
+	}
+	c := class(charAt(buf, p), big)
+	for {
+		q, ok := nextPos(buf, p)
+		if !ok || class(charAt(buf, q), big) != c {
+			return p
+		}
+		p = q
+	}
+}
+
+// wordBack returns the position of the start of the current or previous word.
+func wordBack(buf Buffer, pos textpos.Pos, big bool) textpos.Pos {
+	p, ok := prevPos(buf, pos)
+	for ok && class(charAt(buf, p), big) == 0 {
+		if isEmptyLine(buf, p) {
+			return p
+		}
+		p, ok = prevPos(buf, p)
+	}
+	c := class(charAt(buf, p), big)
+	for {
+		q, ok := prevPos(buf, p)
+		if !ok || class(charAt(buf, q), big) != c {
+			return p
+		}
+		p = q
+	}
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vim provides the parsing of Vim key sequences into commands,
+// and the motions and ex commands that they use, for the Vim emulation
+// of the Code text editor, operating on any [Buffer] of lines of text.
+package vim
+
+//go:generate core generate
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Modes are the modes of Vim editing.
+type Modes int32 //enums:enum
+
+const (
+	// Normal is the mode where keys are commands.
+	Normal Modes = iota
+
+	// Insert is the mode where keys insert text.
+	Insert
+
+	// Visual is the mode where motions extend a selection of characters.
+	Visual
+
+	// VisualLine is the mode where motions extend a selection of whole lines.
+	VisualLine
+
+	// Ex is the mode where an ex command is typed after a colon.
+	Ex
+)
+
+// Buffer is the text that Vim commands operate on,
+// which is implemented by [lines.Lines].
+type Buffer interface {
+
+	// NumLines returns the number of lines.
+	NumLines() int
+
+	// Line returns the runes of the given line.
+	Line(ln int) []rune
+}
+
+// Command is a complete Vim command typed in normal or visual mode.
+type Command struct {
+
+	// Count is the count given for the command, or 0 if none;
+	// counts before and after an operator are multiplied.
+	Count int
+
+	// Register is the register given with ", or 0 if none.
+	Register rune
+
+	// Operator is the operator that applies to the motion,
+	// one of [Operators], or 0 if none.
+	Operator rune
+
+	// Key is the motion or action key, e.g., w, gg, or p, which is the
+	// operator itself for an operator on whole lines, e.g., dd, and empty
+	// for an operator on the selection in visual mode.
+	Key string
+
+	// Char is the character argument of f, F, t, T, and r.
+	Char rune
+}
+
+// N returns the count of the command, which is 1 if none was given.
+func (c *Command) N() int {
+	return max(c.Count, 1)
+}
+
+// IsMotion returns whether the command is a motion without an operator.
+func (c *Command) IsMotion() bool {
+	return c.Operator == 0 && slices.Contains(Motions, c.Key)
+}
+
+// Operators are the operator keys: delete, change, yank, and indent.
+const Operators = "dcy><"
+
+// Motions are the keys of the motions.
+var Motions = []string{"h", "j", "k", "l", "w", "b", "e", "W", "B", "E", "0", "^", "$", "gg", "G", "f", "F", "t", "T"}
+
+// Actions are the keys of the commands other than operators and motions.
+var Actions = []string{"i", "a", "I", "A", "o", "O", "p", "P", "r", "J", "u", "Control+R", "v", "V", ".", ":", "~"}
+
+// aliases are the keys in normal mode that are
+// shorthand for an operator and motion.
+var aliases = map[string]string{"x": "dl", "X": "dh", "D": "d$", "C": "c$", "Y": "yy", "s": "cl", "S": "cc"}
+
+// visualAliases are the keys in visual mode that
+// are the same as an operator on the selection.
+var visualAliases = map[string]string{"x": "d", "X": "d", "D": "d", "s": "c", "S": "c", "C": "c", "Y": "y"}
+
+// keyNames are the special keys that are the same as other keys
+// in normal and visual mode.
+var keyNames = map[string]string{"Backspace": "h", " ": "l", "ReturnEnter": "j", "KeypadEnter": "j", "Delete": "x"}
+
+// Handles returns whether the given key is handled in normal and visual
+// mode, which are the printable characters and some special keys, while
+// other keys, e.g., the arrow keys, are left for the editor to handle.
+func Handles(k string) bool {
+	_, isName := keyNames[k]
+	return utf8.RuneCountInString(k) == 1 || isName || k == "Escape" || k == "Control+R" || k == "Tab"
+}
+
+// Parse parses the given keys typed in normal or visual mode into a command,
+// returning false for done if more keys are needed, and an error if the keys
+// are not a valid command. Each key is a printable character or a key chord.
+func Parse(keys []string, visual bool) (cmd Command, done bool, err error) {
+	i, n := 0, len(keys)
+	next := func() (string, bool) {
+		if i >= n {
+			return "", false
+		}
+		k := keys[i]
+		i++
+		if nm, ok := keyNames[k]; ok {
+			k = nm
+		}
+		return k, true
+	}
+	char := func() (rune, bool) {
+		if i >= n {
+			return 0, false
+		}
+		k := keys[i]
+		i++
+		r, sz := utf8.DecodeRuneInString(k)
+		if sz != len(k) {
+			err = fmt.Errorf("vim: invalid command %q", strings.Join(keys, ""))
+		}
+		return r, true
+	}
+	count := func() int {
+		c := 0
+		for i < n && len(keys[i]) == 1 && keys[i][0] >= '0' && keys[i][0] <= '9' && (keys[i] != "0" || c > 0) {
+			c = c*10 + int(keys[i][0]-'0')
+			i++
+		}
+		return c
+	}
+	invalid := func() (Command, bool, error) {
+		return Command{}, false, fmt.Errorf("vim: invalid command %q", strings.Join(keys, ""))
+	}
+
+	if i < n && keys[i] == `"` {
+		i++
+		r, ok := char()
+		if err != nil {
+			return invalid()
+		}
+		if !ok {
+			return cmd, false, nil
+		}
+		cmd.Register = r
+	}
+	cmd.Count = count()
+	k, ok := next()
+	if !ok {
+		return cmd, false, nil
+	}
+	if visual {
+		if op, has := visualAliases[k]; has {
+			k = op
+		}
+	} else if al, has := aliases[k]; has {
+		cmd.Operator = rune(al[0])
+		cmd.Key = al[1:]
+		return cmd, true, nil
+	}
+	if len(k) == 1 && strings.Contains(Operators, k) {
+		cmd.Operator = rune(k[0])
+		if visual {
+			return cmd, true, nil
+		}
+		if c := count(); c > 0 {
+			cmd.Count = max(cmd.Count, 1) * c
+		}
+		if k, ok = next(); !ok {
+			return cmd, false, nil
+		}
+		if k == string(cmd.Operator) {
+			cmd.Key = k
+			return cmd, true, nil
+		}
+	}
+	if k == "g" {
+		k2, ok := next()
+		if !ok {
+			return cmd, false, nil
+		}
+		k += k2
+	}
+	cmd.Key = k
+	if !slices.Contains(Motions, k) && (cmd.Operator != 0 || !slices.Contains(Actions, k)) {
+		return invalid()
+	}
+	if len(k) == 1 && strings.Contains("fFtTr", k) {
+		r, ok := char()
+		if err != nil {
+			return invalid()
+		}
+		if !ok {
+			return cmd, false, nil
+		}
+		cmd.Char = r
+	}
+	if i < n {
+		return invalid()
+	}
+	return cmd, true, nil
+}
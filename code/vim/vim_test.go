@@ -0,0 +1,195 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vim
+
+import (
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+// buffer is a [Buffer] for testing.
+type buffer [][]rune
+
+func newBuffer(s string) buffer {
+	var buf buffer
+	for _, ln := range strings.Split(s, "\n") {
+		buf = append(buf, []rune(ln))
+	}
+	return buf
+}
+
+func (b buffer) NumLines() int      { return len(b) }
+func (b buffer) Line(ln int) []rune { return b[ln] }
+
+func keys(s string) []string {
+	var ks []string
+	for _, r := range s {
+		ks = append(ks, string(r))
+	}
+	return ks
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		keys   string
+		visual bool
+		cmd    Command
+		done   bool
+	}{
+		{"w", false, Command{Key: "w"}, true},
+		{"3w", false, Command{Count: 3, Key: "w"}, true},
+		{"2d3w", false, Command{Count: 6, Operator: 'd', Key: "w"}, true},
+		{"d", false, Command{Operator: 'd'}, false},
+		{"dd", false, Command{Operator: 'd', Key: "d"}, true},
+		{`"a2yy`, false, Command{Count: 2, Register: 'a', Operator: 'y', Key: "y"}, true},
+		{"x", false, Command{Operator: 'd', Key: "l"}, true},
+		{"x", true, Command{Operator: 'd'}, true},
+		{"ct", false, Command{Operator: 'c', Key: "t"}, false},
+		{"ct)", false, Command{Operator: 'c', Key: "t", Char: ')'}, true},
+		{"g", false, Command{}, false},
+		{"10gg", false, Command{Count: 10, Key: "gg"}, true},
+		{"0", false, Command{Key: "0"}, true},
+		{"rx", false, Command{Key: "r", Char: 'x'}, true},
+		{"f ", false, Command{Key: "f", Char: ' '}, true},
+		{"p", false, Command{Key: "p"}, true},
+	}
+	for _, test := range tests {
+		cmd, done, err := Parse(keys(test.keys), test.visual)
+		assert.NoError(t, err, test.keys)
+		assert.Equal(t, test.done, done, test.keys)
+		assert.Equal(t, test.cmd, cmd, test.keys)
+	}
+	for _, ks := range []string{"dp", "dr", "q", "gq", "d\"a"} {
+		_, _, err := Parse(keys(ks), false)
+		assert.Error(t, err, ks)
+	}
+	cmd, done, err := Parse([]string{"d", "Backspace"}, false)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Equal(t, Command{Operator: 'd', Key: "h"}, cmd)
+	assert.True(t, Handles("Escape"))
+	assert.False(t, Handles("ArrowDown"))
+}
+
+func TestMove(t *testing.T) {
+	buf := newBuffer("func (a *b) c() {\n\treturn x_y\n\n}")
+	move := func(pos textpos.Pos, ks string) (textpos.Pos, Kinds, bool) {
+		cmd, done, err := Parse(keys(ks), false)
+		assert.NoError(t, err)
+		assert.True(t, done)
+		return Move(buf, pos, cmd)
+	}
+	pos := func(ln, ch int) textpos.Pos { return textpos.Pos{Line: ln, Char: ch} }
+
+	p, kind, ok := move(pos(0, 0), "w")
+	assert.Equal(t, pos(0, 5), p)
+	assert.Equal(t, Exclusive, kind)
+	assert.True(t, ok)
+	p, _, _ = move(pos(0, 0), "3w")
+	assert.Equal(t, pos(0, 8), p)
+	p, _, _ = move(pos(0, 16), "w")
+	assert.Equal(t, pos(1, 1), p)
+	p, _, _ = move(pos(1, 8), "w")
+	assert.Equal(t, pos(2, 0), p)
+	p, _, _ = move(pos(0, 16), "dw")
+	assert.Equal(t, pos(0, 17), p)
+	p, _, _ = move(pos(0, 6), "W")
+	assert.Equal(t, pos(0, 8), p)
+	p, kind, _ = move(pos(1, 1), "cw")
+	assert.Equal(t, pos(1, 6), p)
+	assert.Equal(t, Inclusive, kind)
+
+	p, _, _ = move(pos(0, 0), "e")
+	assert.Equal(t, pos(0, 3), p)
+	p, _, _ = move(pos(1, 1), "b")
+	assert.Equal(t, pos(0, 16), p)
+	p, _, _ = move(pos(0, 8), "b")
+	assert.Equal(t, pos(0, 6), p)
+	p, _, _ = move(pos(3, 0), "b")
+	assert.Equal(t, pos(2, 0), p)
+
+	p, _, _ = move(pos(0, 0), "f(")
+	assert.Equal(t, pos(0, 5), p)
+	p, _, _ = move(pos(0, 0), "2f(")
+	assert.Equal(t, pos(0, 13), p)
+	p, _, _ = move(pos(0, 0), "t)")
+	assert.Equal(t, pos(0, 9), p)
+	p, _, _ = move(pos(0, 9), "F(")
+	assert.Equal(t, pos(0, 5), p)
+	_, _, ok = move(pos(0, 0), "fz")
+	assert.False(t, ok)
+
+	p, kind, _ = move(pos(0, 10), "j")
+	assert.Equal(t, pos(1, 10), p)
+	assert.Equal(t, Linewise, kind)
+	p, _, _ = move(pos(1, 10), "j")
+	assert.Equal(t, pos(2, 0), p)
+	_, _, ok = move(pos(0, 3), "k")
+	assert.False(t, ok)
+	p, _, _ = move(pos(0, 3), "G")
+	assert.Equal(t, pos(3, 0), p)
+	p, _, _ = move(pos(0, 3), "2gg")
+	assert.Equal(t, pos(1, 1), p)
+	p, _, _ = move(pos(1, 5), "^")
+	assert.Equal(t, pos(1, 1), p)
+	p, kind, _ = move(pos(1, 5), "$")
+	assert.Equal(t, pos(1, 10), p)
+	assert.Equal(t, Inclusive, kind)
+	_, _, ok = move(pos(1, 10), "l")
+	assert.False(t, ok)
+	p, _, _ = move(pos(1, 10), "dl")
+	assert.Equal(t, pos(1, 11), p)
+}
+
+func TestRange(t *testing.T) {
+	buf := newBuffer("ab\ncd\nef")
+	pos := func(ln, ch int) textpos.Pos { return textpos.Pos{Line: ln, Char: ch} }
+	assert.Equal(t, textpos.Region{Start: pos(0, 1), End: pos(1, 1)}, Range(buf, pos(1, 0), pos(0, 1), Inclusive))
+	assert.Equal(t, textpos.Region{Start: pos(0, 0), End: pos(2, 0)}, Range(buf, pos(1, 1), pos(0, 1), Linewise))
+	assert.Equal(t, textpos.Region{Start: pos(0, 2), End: pos(2, 2)}, Range(buf, pos(1, 1), pos(2, 0), Linewise))
+	assert.Equal(t, textpos.Region{Start: pos(0, 0), End: pos(2, 2)}, LinesRegion(buf, 0, 2))
+	assert.Equal(t, "cd\nef\n", LinesText(buf, 2, 1))
+}
+
+func TestParseEx(t *testing.T) {
+	ex, err := ParseEx("w")
+	assert.NoError(t, err)
+	assert.Equal(t, &ExCommand{Name: "w"}, ex)
+	ex, err = ParseEx("q!")
+	assert.NoError(t, err)
+	assert.Equal(t, &ExCommand{Name: "q", Bang: true}, ex)
+	ex, err = ParseEx("42")
+	assert.NoError(t, err)
+	assert.Equal(t, &ExCommand{Line: 42}, ex)
+	ex, err = ParseEx(`%s/a\/(b)/c\1/gi`)
+	assert.NoError(t, err)
+	assert.Equal(t, &ExCommand{Name: "s", All: true, Find: "a/(b)", Replace: "c${1}", Global: true, IgnoreCase: true}, ex)
+	ex, err = ParseEx("s#x#")
+	assert.NoError(t, err)
+	assert.Equal(t, &ExCommand{Name: "s", Find: "x"}, ex)
+	for _, s := range []string{"e", "s", "s//x/", "sxaxbx", "s/a/b/z"} {
+		_, err = ParseEx(s)
+		assert.Error(t, err, s)
+	}
+
+	ex, _ = ParseEx(`s/(o)/[\1]/`)
+	re, err := ex.Regexp()
+	assert.NoError(t, err)
+	ln, ok := ex.Substitute(re, "foo bo")
+	assert.True(t, ok)
+	assert.Equal(t, "f[o]o bo", ln)
+	ex.Global = true
+	ln, _ = ex.Substitute(re, "foo bo")
+	assert.Equal(t, "f[o][o] b[o]", ln)
+	_, ok = ex.Substitute(re, "bar")
+	assert.False(t, ok)
+	ex, _ = ParseEx("s/A/b/i")
+	re, _ = ex.Regexp()
+	ln, _ = ex.Substitute(re, "aA")
+	assert.Equal(t, "bA", ln)
+}
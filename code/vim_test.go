@@ -0,0 +1,90 @@
+// Copyright (c) 2025, Cogent Core. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package code
+
+import (
+	"strings"
+	"testing"
+
+	"cogentcore.org/cogent/code/vim"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/events"
+	"cogentcore.org/core/events/key"
+	"cogentcore.org/core/text/lines"
+	"cogentcore.org/core/text/textpos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVimKeys(t *testing.T) {
+	Settings.VimMode = true
+	defer func() { Settings.VimMode = false }()
+	b := core.NewBody()
+	ed := NewTextEditor(b)
+	ln := lines.NewLines()
+	ln.SetString("one two\nthree")
+	ed.SetLines(ln)
+	typ := func(chords ...key.Chord) {
+		for _, kc := range chords {
+			r, code, mods, err := kc.Decode()
+			assert.NoError(t, err)
+			ed.HandleEvent(events.NewKey(events.KeyChord, r, code, mods))
+		}
+	}
+	str := func(s string) {
+		for _, r := range s {
+			typ(key.Chord(string(r)))
+		}
+	}
+	text := func() string {
+		return strings.Join(ln.Strings(false), "\n")
+	}
+	str("dw")
+	assert.Equal(t, "two\nthree", text())
+	str("jcwfour")
+	typ("Escape")
+	assert.Equal(t, vim.Normal, ed.vim.mode)
+	assert.Equal(t, "two\nfour", text())
+	assert.Equal(t, textpos.Pos{Line: 1, Char: 3}, ed.CursorPos)
+	str("k0.")
+	assert.Equal(t, "four\nfour", text())
+	str(`"ayyjp`)
+	assert.Equal(t, "four\nfour\nfour", text())
+	assert.Equal(t, "four\n", vimRegisters["a"])
+	assert.NotContains(t, AvailableRegisters, "a")
+	str("u")
+	assert.Equal(t, "four\nfour", text())
+
+	str("ggvlly")
+	assert.Equal(t, vim.Normal, ed.vim.mode)
+	assert.Equal(t, "fou", vimRegisters[`"`])
+	assert.NotContains(t, AvailableRegisters, `"`)
+	str("Vjd")
+	assert.Equal(t, "", text())
+	str("ione two")
+	typ("Escape")
+	str("0>>")
+	assert.Equal(t, "\tone two", text())
+	str("$3~")
+	assert.Equal(t, "\tone twO", text())
+	str(":1")
+	assert.Equal(t, vim.Ex, ed.vim.mode)
+	assert.Equal(t, ":1", ed.vimStatus())
+	typ("ReturnEnter")
+	assert.Equal(t, vim.Normal, ed.vim.mode)
+	assert.Equal(t, textpos.Pos{Line: 0, Char: 1}, ed.CursorPos)
+
+	ed.Code = &Code{Output: &strings.Builder{}}
+	str("otwo two")
+	typ("Escape")
+	str(":s/two/2/")
+	typ("ReturnEnter")
+	assert.Equal(t, "\tone twO\n\t2 two", text())
+	str(":%s/o/0/")
+	typ("ReturnEnter")
+	assert.Equal(t, "\t0ne twO\n\t2 tw0", text())
+	str(":q")
+	typ("ReturnEnter")
+	assert.Contains(t, ed.Code.StatusMessage, "no write since last change")
+}